@@ -0,0 +1,22 @@
+package app
+
+import (
+	"avito-shop/internal/config"
+	"avito-shop/internal/lib/logger/sl"
+	"context"
+	"log/slog"
+)
+
+func mustPromoteAdmins(cfg *config.Config, log *slog.Logger, services *serviceProvider) {
+	if len(cfg.Registration.Admins) == 0 {
+		return
+	}
+
+	promoted, err := services.EmployeeService.PromoteAdmins(context.Background(), cfg.Registration.Admins)
+	if err != nil {
+		log.Error("failed to promote admins", sl.Err(err))
+		panic(err)
+	}
+
+	log.Info("admins promoted", slog.Int("count", promoted))
+}
@@ -19,9 +19,16 @@ func Run(envPath string) {
 	pg, trManager := mustSetupDatabase(cfg, log)
 	defer pg.Close()
 
-	services := newServiceProvider(cfg, pg, trManager)
-	router := setupRouter(cfg, log, services)
+	keys := mustLoadKeys(cfg, log)
+
+	services := newServiceProvider(cfg, pg, trManager, keys)
+	mustPromoteAdmins(cfg, log, services)
+	router := setupRouter(log, services, keys)
 	server := setupServer(cfg, router)
+	workers := setupWorkers(cfg, log, services)
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	workers.Start(workersCtx)
 
 	go func() {
 		log.Info("starting server", slog.String("addr", server.Addr))
@@ -37,6 +44,9 @@ func Run(envPath string) {
 	<-quit
 	log.Info("shutting down server...")
 
+	stopWorkers()
+	workers.Wait()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"avito-shop/internal/config"
+	"avito-shop/internal/lib/jwtkeys"
+	"avito-shop/internal/lib/logger/sl"
+	"errors"
+	"log/slog"
+)
+
+// mustLoadKeys refuses to start without a private key for JWT_SIGNING_KEY_ID: no key ships with the repository,
+// so it has to be generated on deploy, e.g. with make keys.
+func mustLoadKeys(cfg *config.Config, log *slog.Logger) *jwtkeys.KeySet {
+	keys, err := jwtkeys.LoadDir(cfg.JWT.KeysDir, cfg.JWT.SigningKeyId)
+	if errors.Is(err, jwtkeys.ErrNoSigningKey) {
+		log.Error("jwt signing key is missing, generate it with make keys",
+			slog.String("dir", cfg.JWT.KeysDir), slog.String("kid", cfg.JWT.SigningKeyId), sl.Err(err))
+		panic(err)
+	}
+	if err != nil {
+		log.Error("failed to load jwt keys", slog.String("dir", cfg.JWT.KeysDir), sl.Err(err))
+		panic(err)
+	}
+
+	log.Info("jwt keys loaded",
+		slog.Int("count", len(keys.PublicKeys())),
+		slog.String("signing_kid", cfg.JWT.SigningKeyId),
+	)
+
+	return keys
+}
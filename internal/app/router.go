@@ -1,28 +1,96 @@
 package app
 
 import (
-	"avito-shop/internal/config"
 	"avito-shop/internal/http-server/handlers"
 	mw "avito-shop/internal/http-server/middleware"
+	"avito-shop/internal/lib/jwtkeys"
+	"avito-shop/internal/model"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-playground/validator/v10"
 	"log/slog"
 )
 
-func setupRouter(cfg *config.Config, log *slog.Logger, services *serviceProvider) *chi.Mux {
+func setupRouter(log *slog.Logger, services *serviceProvider, keys *jwtkeys.KeySet) *chi.Mux {
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
 	router.Use(mw.NewLogger(log))
 
 	var validate = validator.New()
 
+	router.Get("/.well-known/jwks.json", handlers.NewJWKSHandlerFunc(log, keys))
 	router.Post("/api/auth", handlers.NewAuthHandlerFunc(log, services.AuthService, validate))
+	router.Post("/api/register", handlers.NewRegisterHandlerFunc(log, services.AuthService, validate))
+	router.Post("/api/auth/refresh", handlers.NewRefreshHandlerFunc(log, services.AuthService, validate))
 	router.Group(func(router chi.Router) {
-		router.Use(mw.NewJwtAuth(log, cfg.JWT.SignKey))
-		router.Post("/api/sendCoin", handlers.NewSendCoinsHandlerFunc(log, services.TransferService, validate))
-		router.Get("/api/buy/{item}", handlers.NewBuyItemHandlerFunc(log, services.BuyItemService))
+		router.Use(mw.NewJwtAuth(log, keys, services.AuthService, services.AuthService))
+		router.Post("/api/auth/logout", handlers.NewLogoutHandlerFunc(log, services.AuthService))
+		// every route that changes a balance or held coins accepts an idempotency key
+		router.Group(func(router chi.Router) {
+			router.Use(mw.NewIdempotency(log, services.IdempotencyService))
+			router.Post("/api/sendCoin", handlers.NewSendCoinsHandlerFunc(log, services.TransferService, validate))
+			router.Post("/api/sendCoin/batch",
+				handlers.NewSendCoinsBatchHandlerFunc(log, services.TransferService, validate))
+			router.Get("/api/buy/{item}", handlers.NewBuyItemHandlerFunc(log, services.BuyItemService))
+			router.Post("/api/orders", handlers.NewCheckoutHandlerFunc(log, services.BuyItemService, validate))
+			router.Post("/api/orders/{orderId}/cancel", handlers.NewCancelOrderHandlerFunc(log, services.OrderService))
+			router.Post("/api/returns", handlers.NewReturnHandlerFunc(log, services.OrderService, validate))
+			router.Post("/api/marketplace/listings/{listingId}/buy",
+				handlers.NewBuyListingHandlerFunc(log, services.MarketplaceService))
+			router.Post("/api/paymentRequests/{requestId}/accept",
+				handlers.NewAcceptPaymentRequestHandlerFunc(log, services.PaymentRequestService))
+			router.Post("/api/pendingTransfers/{transferId}/approve",
+				handlers.NewApprovePendingTransferHandlerFunc(log, services.TransferService))
+			router.Post("/api/pendingTransfers/{transferId}/reject",
+				handlers.NewRejectPendingTransferHandlerFunc(log, services.TransferService))
+		})
+		router.Post("/api/inventory/gift", handlers.NewGiftHandlerFunc(log, services.GiftService, validate))
+		router.Post("/api/marketplace/listings",
+			handlers.NewCreateListingHandlerFunc(log, services.MarketplaceService, validate))
+		router.Post("/api/schedules",
+			handlers.NewCreateScheduleHandlerFunc(log, services.ScheduleService, validate))
+		router.Post("/api/paymentRequests",
+			handlers.NewCreatePaymentRequestHandlerFunc(log, services.PaymentRequestService, validate))
 		router.Get("/api/info", handlers.NewInfoHandlerFunc(log, services.InfoService))
+		router.Get("/api/history", handlers.NewHistoryHandlerFunc(log, services.HistoryService))
+		router.Get("/api/kudos", handlers.NewKudosHandlerFunc(log, services.TransferService))
+		router.Get("/api/items", handlers.NewItemsHandlerFunc(log, services.BuyItemService))
+		router.Get("/api/orders", handlers.NewListOrdersHandlerFunc(log, services.OrderService))
+		router.Get("/api/marketplace/listings", handlers.NewListListingsHandlerFunc(log, services.MarketplaceService))
+		router.Post("/api/marketplace/listings/{listingId}/cancel",
+			handlers.NewCancelListingHandlerFunc(log, services.MarketplaceService))
+		router.Get("/api/schedules", handlers.NewListSchedulesHandlerFunc(log, services.ScheduleService))
+		router.Post("/api/schedules/{scheduleId}/cancel",
+			handlers.NewCancelScheduleHandlerFunc(log, services.ScheduleService))
+		router.Get("/api/schedules/{scheduleId}/runs",
+			handlers.NewScheduleRunsHandlerFunc(log, services.ScheduleService))
+		router.Get("/api/paymentRequests",
+			handlers.NewListPaymentRequestsHandlerFunc(log, services.PaymentRequestService))
+		router.Post("/api/paymentRequests/{requestId}/decline",
+			handlers.NewDeclinePaymentRequestHandlerFunc(log, services.PaymentRequestService))
+		router.Get("/api/pendingTransfers", handlers.NewListPendingTransfersHandlerFunc(log, services.TransferService))
+
+		router.Route("/api/admin", func(router chi.Router) {
+			router.Use(mw.NewRequireRole(log, model.RoleAdmin))
+			router.Post("/items", handlers.NewCreateItemHandlerFunc(log, services.BuyItemService, validate))
+			router.Patch("/items/{item}", handlers.NewUpdateItemHandlerFunc(log, services.BuyItemService, validate))
+			router.Post("/items/{item}/retire", handlers.NewRetireItemHandlerFunc(log, services.BuyItemService))
+			router.Get("/orders", handlers.NewAdminListOrdersHandlerFunc(log, services.OrderService))
+			router.Post("/employees/{username}/manager",
+				handlers.NewSetManagerHandlerFunc(log, services.EmployeeService))
+			router.Post("/employees/{username}/status",
+				handlers.NewSetStatusHandlerFunc(log, services.EmployeeService, validate))
+			router.Group(func(router chi.Router) {
+				router.Use(mw.NewIdempotency(log, services.IdempotencyService))
+				router.Post("/orders/{orderId}/status",
+					handlers.NewAdvanceOrderHandlerFunc(log, services.OrderService, validate))
+				router.Post("/employees/{username}/adjust",
+					handlers.NewAdjustBalanceHandlerFunc(log, services.EmployeeService, validate))
+				router.Post("/employees/{username}/sweep",
+					handlers.NewSweepBalanceHandlerFunc(log, services.EmployeeService))
+			})
+			router.Post("/invites", handlers.NewCreateInviteHandlerFunc(log, services.InviteService))
+		})
 	})
 
 	return router
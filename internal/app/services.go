@@ -2,6 +2,8 @@ package app
 
 import (
 	"avito-shop/internal/config"
+	"avito-shop/internal/lib/jwtkeys"
+	"avito-shop/internal/model"
 	"avito-shop/internal/repo/pgdb"
 	"avito-shop/internal/service"
 	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
@@ -9,22 +11,95 @@ import (
 )
 
 type serviceProvider struct {
+	LedgerService   *service.LedgerService
 	AuthService     *service.AuthService
 	TransferService *service.TransferService
 	BuyItemService  *service.ItemService
 	InfoService     *service.InfoService
+	HistoryService  *service.HistoryService
+	OrderService    *service.OrderService
+	GiftService     *service.GiftService
+	EmployeeService *service.EmployeeService
+
+	MarketplaceService *service.MarketplaceService
+	ScheduleService    *service.ScheduleService
+	AllowanceService   *service.AllowanceService
+	ExpiryService      *service.ExpiryService
+
+	PaymentRequestService *service.PaymentRequestService
+
+	IdempotencyService *service.IdempotencyService
+	InviteService      *service.InviteService
 }
 
-func newServiceProvider(cfg *config.Config, pg *pgdb.Postgres, trManager *manager.Manager) *serviceProvider {
+func newServiceProvider(
+	cfg *config.Config, pg *pgdb.Postgres, trManager *manager.Manager, keys *jwtkeys.KeySet) *serviceProvider {
 	pgEmployeeRepo := pgdb.NewPGEmployeeRepo(pg, trmpgx.DefaultCtxGetter)
 	pgTransferRepo := pgdb.NewPGTransferRepo(pg, trmpgx.DefaultCtxGetter)
 	pgItemRepo := pgdb.NewPGItemRepo(pg, trmpgx.DefaultCtxGetter)
 	pgInventoryRepo := pgdb.NewPgInventoryRepo(pg, trmpgx.DefaultCtxGetter)
+	pgLedgerRepo := pgdb.NewPGLedgerRepo(pg, trmpgx.DefaultCtxGetter)
+	pgPurchaseRepo := pgdb.NewPGPurchaseRepo(pg, trmpgx.DefaultCtxGetter)
+	pgOrderRepo := pgdb.NewPGOrderRepo(pg, trmpgx.DefaultCtxGetter)
+	pgPurchaseReturnRepo := pgdb.NewPGPurchaseReturnRepo(pg, trmpgx.DefaultCtxGetter)
+	pgGiftRepo := pgdb.NewPGGiftRepo(pg, trmpgx.DefaultCtxGetter)
+	pgListingRepo := pgdb.NewPGListingRepo(pg, trmpgx.DefaultCtxGetter)
+	pgScheduleRepo := pgdb.NewPGScheduleRepo(pg, trmpgx.DefaultCtxGetter)
+	pgPaymentRequestRepo := pgdb.NewPGPaymentRequestRepo(pg, trmpgx.DefaultCtxGetter)
+	pgPendingTransferRepo := pgdb.NewPGPendingTransferRepo(pg, trmpgx.DefaultCtxGetter)
+	pgAdjustmentRepo := pgdb.NewPGAdjustmentRepo(pg, trmpgx.DefaultCtxGetter)
+	pgCoinLotRepo := pgdb.NewPGCoinLotRepo(pg, trmpgx.DefaultCtxGetter)
+	pgAllowanceRepo := pgdb.NewPGAllowanceRepo(pg, trmpgx.DefaultCtxGetter)
+	pgInviteRepo := pgdb.NewPGInviteRepo(pg, trmpgx.DefaultCtxGetter)
+	pgSessionRepo := pgdb.NewPGSessionRepo(pg, trmpgx.DefaultCtxGetter)
+	pgRefreshTokenRepo := pgdb.NewPGRefreshTokenRepo(pg, trmpgx.DefaultCtxGetter)
+	pgHistoryRepo := pgdb.NewPGHistoryRepo(pg, trmpgx.DefaultCtxGetter)
+	pgIdempotencyRepo := pgdb.NewPGIdempotencyRepo(pg, trmpgx.DefaultCtxGetter)
+
+	coinExpiry := model.CoinExpiryPolicy{TTL: cfg.CoinExpiry.TTL, Notice: cfg.CoinExpiry.Notice}
+
+	ledgerService := service.NewLedgerService(pgEmployeeRepo, pgLedgerRepo, pgCoinLotRepo)
+	transferService := service.NewTransferService(trManager, pgEmployeeRepo, pgTransferRepo, pgPendingTransferRepo,
+		ledgerService, cfg.Transfers.ApprovalThreshold, model.TransferLimits{
+			DailyAmount:            cfg.Transfers.DailyLimit,
+			HourlyCount:            cfg.Transfers.HourlyCountLimit,
+			MonthlyRecipientAmount: cfg.Transfers.MonthlyRecipientLimit,
+		})
 
 	return &serviceProvider{
-		AuthService:     service.NewAuthService(trManager, pgEmployeeRepo, cfg.JWT.SignKey, cfg.JWT.TokenTTL),
-		TransferService: service.NewTransferService(trManager, pgEmployeeRepo, pgTransferRepo),
-		BuyItemService:  service.NewItemService(trManager, pgItemRepo, pgEmployeeRepo, pgInventoryRepo),
-		InfoService:     service.NewInfoService(trManager, pgEmployeeRepo, pgInventoryRepo, pgTransferRepo, pgItemRepo),
+		LedgerService: ledgerService,
+		AuthService: service.NewAuthService(
+			trManager, pgEmployeeRepo, pgInviteRepo, pgSessionRepo, pgRefreshTokenRepo, ledgerService,
+			keys, cfg.JWT.TokenTTL, cfg.JWT.RefreshTTL, cfg.Registration.Mode),
+		TransferService: transferService,
+		BuyItemService: service.NewItemService(
+			trManager, pgItemRepo, pgEmployeeRepo, pgInventoryRepo, pgPurchaseRepo, pgOrderRepo, ledgerService),
+		InfoService: service.NewInfoService(
+			trManager, pgEmployeeRepo, pgInventoryRepo, pgTransferRepo, pgItemRepo, pgCoinLotRepo, coinExpiry),
+		HistoryService: service.NewHistoryService(pgEmployeeRepo, pgHistoryRepo),
+		OrderService: service.NewOrderService(
+			trManager, pgOrderRepo, pgPurchaseRepo, pgPurchaseReturnRepo, pgEmployeeRepo, pgItemRepo, pgInventoryRepo,
+			ledgerService, cfg.Shop.ReturnWindow),
+		GiftService:     service.NewGiftService(trManager, pgEmployeeRepo, pgItemRepo, pgInventoryRepo, pgGiftRepo),
+		EmployeeService: service.NewEmployeeService(trManager, pgEmployeeRepo, pgAdjustmentRepo, ledgerService),
+
+		MarketplaceService: service.NewMarketplaceService(
+			trManager, pgEmployeeRepo, pgItemRepo, pgInventoryRepo, pgListingRepo, ledgerService, transferService,
+			cfg.Marketplace.ListingTTL),
+		ScheduleService: service.NewScheduleService(
+			trManager, pgEmployeeRepo, pgScheduleRepo, transferService, cfg.Schedules.RetryInterval,
+			cfg.Schedules.MaxAttempts),
+		AllowanceService: service.NewAllowanceService(trManager, pgAllowanceRepo, ledgerService, model.AllowancePolicy{
+			Amount:     cfg.Allowance.Amount,
+			MaxBalance: cfg.Allowance.MaxBalance,
+			Period:     cfg.Allowance.Period,
+		}),
+		ExpiryService: service.NewExpiryService(trManager, pgEmployeeRepo, pgCoinLotRepo, ledgerService, coinExpiry),
+
+		PaymentRequestService: service.NewPaymentRequestService(
+			trManager, pgEmployeeRepo, pgPaymentRequestRepo, transferService, cfg.PaymentRequests.TTL),
+
+		IdempotencyService: service.NewIdempotencyService(trManager, pgIdempotencyRepo),
+		InviteService:      service.NewInviteService(pgInviteRepo, cfg.Registration.InviteTTL),
 	}
 }
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"avito-shop/internal/config"
+	"avito-shop/internal/worker"
+	"context"
+	"log/slog"
+)
+
+const (
+	listingExpiryBatch = 100
+	scheduleRunBatch   = 100
+	allowanceBatch     = 100
+	coinExpiryBatch    = 100
+)
+
+func setupWorkers(cfg *config.Config, log *slog.Logger, services *serviceProvider) *worker.Runner {
+	jobs := []worker.Job{
+		{
+			Name:     "expire-listings",
+			Interval: cfg.Marketplace.ExpiryInterval,
+			Run: func(ctx context.Context) error {
+				released, err := services.MarketplaceService.ExpireListings(ctx, listingExpiryBatch)
+				if released > 0 {
+					log.Info("released expired listings", slog.Int("count", released))
+				}
+				return err
+			},
+		},
+		{
+			Name:     "run-scheduled-transfers",
+			Interval: cfg.Schedules.RunInterval,
+			Run: func(ctx context.Context) error {
+				processed, err := services.ScheduleService.RunDue(ctx, scheduleRunBatch)
+				if processed > 0 {
+					log.Info("ran scheduled transfers", slog.Int("count", processed))
+				}
+				return err
+			},
+		},
+		{
+			Name:     "reconcile-ledger",
+			Interval: cfg.Ledger.ReconcileInterval,
+			Run:      services.LedgerService.Reconcile,
+		},
+	}
+
+	if cfg.Allowance.Amount > 0 {
+		jobs = append(jobs, worker.Job{
+			Name:     "emit-allowances",
+			Interval: cfg.Allowance.Interval,
+			Run: func(ctx context.Context) error {
+				processed, err := services.AllowanceService.EmitDue(ctx, allowanceBatch)
+				if processed > 0 {
+					log.Info("emitted allowances", slog.Int("count", processed))
+				}
+				return err
+			},
+		})
+	}
+
+	if cfg.CoinExpiry.TTL > 0 {
+		jobs = append(jobs, worker.Job{
+			Name:     "expire-coins",
+			Interval: cfg.CoinExpiry.Interval,
+			Run: func(ctx context.Context) error {
+				processed, err := services.ExpiryService.ExpireDue(ctx, coinExpiryBatch)
+				if processed > 0 {
+					log.Info("expired stale coins", slog.Int("employees", processed))
+				}
+				return err
+			},
+		})
+	}
+
+	return worker.NewRunner(log, jobs...)
+}
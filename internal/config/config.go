@@ -1,10 +1,12 @@
 package config
 
 import (
+	"avito-shop/internal/model"
 	"fmt"
 	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -15,6 +17,15 @@ type Config struct {
 	JWT
 	Log
 	PG
+	Shop
+	Marketplace
+	Schedules
+	PaymentRequests
+	Transfers
+	Allowance
+	CoinExpiry
+	Registration
+	Ledger
 }
 
 type HTTP struct {
@@ -29,15 +40,107 @@ func (h HTTP) Address() string {
 	return net.JoinHostPort(h.Host, h.Port)
 }
 
+const defaultRefreshTTL = 30 * 24 * time.Hour
+
+// JWT.TokenTTL is the lifetime of access tokens; clients renew them with refresh tokens living RefreshTTL.
+// KeysDir holds one <kid>.pem file per key, and tokens are signed with the key SigningKeyId.
 type JWT struct {
-	SignKey  string
-	TokenTTL time.Duration
+	KeysDir      string
+	SigningKeyId string
+	TokenTTL     time.Duration
+	RefreshTTL   time.Duration
 }
 
 type Log struct {
 	Level string
 }
 
+type Shop struct {
+	ReturnWindow time.Duration
+}
+
+const (
+	defaultListingTTL            = 7 * 24 * time.Hour
+	defaultListingExpiryInterval = time.Minute
+)
+
+type Marketplace struct {
+	ListingTTL     time.Duration
+	ExpiryInterval time.Duration
+}
+
+const (
+	defaultScheduleRunInterval   = time.Minute
+	defaultScheduleRetryInterval = time.Hour
+	defaultScheduleMaxAttempts   = 3
+)
+
+type Schedules struct {
+	RunInterval   time.Duration
+	RetryInterval time.Duration
+	MaxAttempts   int
+}
+
+const defaultPaymentRequestTTL = 72 * time.Hour
+
+type PaymentRequests struct {
+	TTL time.Duration
+}
+
+// Transfers.ApprovalThreshold of zero disables approval: every transfer settles immediately.
+// A zero limit is not enforced.
+type Transfers struct {
+	ApprovalThreshold     int
+	DailyLimit            int
+	HourlyCountLimit      int
+	MonthlyRecipientLimit int
+}
+
+const (
+	defaultAllowancePeriod   = model.AllowanceMonthly
+	defaultAllowanceInterval = time.Hour
+)
+
+// Allowance.Amount of zero disables the periodic allowance; a zero MaxBalance leaves balances uncapped.
+type Allowance struct {
+	Amount     int
+	MaxBalance int
+	Period     model.AllowancePeriod
+	Interval   time.Duration
+}
+
+const (
+	defaultCoinExpiryNotice   = 30 * 24 * time.Hour
+	defaultCoinExpiryInterval = time.Hour
+)
+
+// CoinExpiry.TTL of zero disables expiry; coins are tracked in lots either way.
+type CoinExpiry struct {
+	TTL      time.Duration
+	Notice   time.Duration
+	Interval time.Duration
+}
+
+const (
+	defaultRegistrationMode = model.RegistrationAuto
+	defaultInviteTTL        = 7 * 24 * time.Hour
+)
+
+// Registration.Admins are the usernames that get the admin role, so the first admin can be created
+// without touching the database.
+type Registration struct {
+	Mode      model.RegistrationMode
+	InviteTTL time.Duration
+	Admins    []string
+}
+
+const defaultLedgerReconcileInterval = time.Hour
+
+// Ledger.ReconcileInterval is how often every balance is checked against the ledger entries.
+type Ledger struct {
+	ReconcileInterval time.Duration
+}
+
 type PG struct {
 	Host        string
 	Port        string
@@ -76,6 +179,42 @@ func MustLoad(envPath string) *Config {
 	if err != nil {
 		panic(fmt.Errorf("failed to load pg config: %w", err))
 	}
+	cfg.Shop, err = loadShopConfig()
+	if err != nil {
+		panic(fmt.Errorf("failed to load shop config: %w", err))
+	}
+	cfg.Marketplace, err = loadMarketplaceConfig()
+	if err != nil {
+		panic(fmt.Errorf("failed to load marketplace config: %w", err))
+	}
+	cfg.Schedules, err = loadSchedulesConfig()
+	if err != nil {
+		panic(fmt.Errorf("failed to load schedules config: %w", err))
+	}
+	cfg.PaymentRequests, err = loadPaymentRequestsConfig()
+	if err != nil {
+		panic(fmt.Errorf("failed to load payment requests config: %w", err))
+	}
+	cfg.Transfers, err = loadTransfersConfig()
+	if err != nil {
+		panic(fmt.Errorf("failed to load transfers config: %w", err))
+	}
+	cfg.Allowance, err = loadAllowanceConfig()
+	if err != nil {
+		panic(fmt.Errorf("failed to load allowance config: %w", err))
+	}
+	cfg.CoinExpiry, err = loadCoinExpiryConfig()
+	if err != nil {
+		panic(fmt.Errorf("failed to load coin expiry config: %w", err))
+	}
+	cfg.Registration, err = loadRegistrationConfig()
+	if err != nil {
+		panic(fmt.Errorf("failed to load registration config: %w", err))
+	}
+	cfg.Ledger, err = loadLedgerConfig()
+	if err != nil {
+		panic(fmt.Errorf("failed to load ledger config: %w", err))
+	}
 
 	return cfg
 }
@@ -112,18 +251,31 @@ func loadHTTPConfig() (HTTP, error) {
 }
 
 func loadJWTConfig() (JWT, error) {
-	signKey, err := getEnv("JWT_SIGN_KEY")
+	keysDir, err := getEnv("JWT_KEYS_DIR")
+	if err != nil {
+		return JWT{}, fmt.Errorf("missing JWT_KEYS_DIR: %w", err)
+	}
+	signingKeyId, err := getEnv("JWT_SIGNING_KEY_ID")
 	if err != nil {
-		return JWT{}, fmt.Errorf("missing JWT_SIGN_KEY: %w", err)
+		return JWT{}, fmt.Errorf("missing JWT_SIGNING_KEY_ID: %w", err)
 	}
 	tokenTTL, err := parseDuration("JWT_TOKEN_TTL")
 	if err != nil {
 		return JWT{}, fmt.Errorf("invalid or missing JWT_TOKEN_TTL: %w", err)
 	}
+	refreshTTL, err := parseOptionalDuration("JWT_REFRESH_TTL")
+	if err != nil {
+		return JWT{}, fmt.Errorf("invalid JWT_REFRESH_TTL: %w", err)
+	}
+	if refreshTTL == 0 {
+		refreshTTL = defaultRefreshTTL
+	}
 
 	return JWT{
-		SignKey:  signKey,
-		TokenTTL: tokenTTL,
+		KeysDir:      keysDir,
+		SigningKeyId: signingKeyId,
+		TokenTTL:     tokenTTL,
+		RefreshTTL:   refreshTTL,
 	}, nil
 }
 
@@ -179,6 +331,198 @@ func loadPGConfig() (PG, error) {
 	}, nil
 }
 
+func loadShopConfig() (Shop, error) {
+	returnWindow, err := parseOptionalDuration("SHOP_RETURN_WINDOW")
+	if err != nil {
+		return Shop{}, fmt.Errorf("invalid SHOP_RETURN_WINDOW: %w", err)
+	}
+
+	return Shop{
+		ReturnWindow: returnWindow,
+	}, nil
+}
+
+func loadMarketplaceConfig() (Marketplace, error) {
+	listingTTL, err := parseOptionalDuration("MARKETPLACE_LISTING_TTL")
+	if err != nil {
+		return Marketplace{}, fmt.Errorf("invalid MARKETPLACE_LISTING_TTL: %w", err)
+	}
+	if listingTTL == 0 {
+		listingTTL = defaultListingTTL
+	}
+	expiryInterval, err := parseOptionalDuration("MARKETPLACE_EXPIRY_INTERVAL")
+	if err != nil {
+		return Marketplace{}, fmt.Errorf("invalid MARKETPLACE_EXPIRY_INTERVAL: %w", err)
+	}
+	if expiryInterval == 0 {
+		expiryInterval = defaultListingExpiryInterval
+	}
+
+	return Marketplace{
+		ListingTTL:     listingTTL,
+		ExpiryInterval: expiryInterval,
+	}, nil
+}
+
+func loadSchedulesConfig() (Schedules, error) {
+	runInterval, err := parseOptionalDuration("SCHEDULE_RUN_INTERVAL")
+	if err != nil {
+		return Schedules{}, fmt.Errorf("invalid SCHEDULE_RUN_INTERVAL: %w", err)
+	}
+	if runInterval == 0 {
+		runInterval = defaultScheduleRunInterval
+	}
+	retryInterval, err := parseOptionalDuration("SCHEDULE_RETRY_INTERVAL")
+	if err != nil {
+		return Schedules{}, fmt.Errorf("invalid SCHEDULE_RETRY_INTERVAL: %w", err)
+	}
+	if retryInterval == 0 {
+		retryInterval = defaultScheduleRetryInterval
+	}
+	maxAttempts := defaultScheduleMaxAttempts
+	if value := os.Getenv("SCHEDULE_MAX_ATTEMPTS"); value != "" {
+		maxAttempts, err = strconv.Atoi(value)
+		if err != nil || maxAttempts <= 0 {
+			return Schedules{}, fmt.Errorf("invalid SCHEDULE_MAX_ATTEMPTS: %s", value)
+		}
+	}
+
+	return Schedules{
+		RunInterval:   runInterval,
+		RetryInterval: retryInterval,
+		MaxAttempts:   maxAttempts,
+	}, nil
+}
+
+func loadPaymentRequestsConfig() (PaymentRequests, error) {
+	ttl, err := parseOptionalDuration("PAYMENT_REQUEST_TTL")
+	if err != nil {
+		return PaymentRequests{}, fmt.Errorf("invalid PAYMENT_REQUEST_TTL: %w", err)
+	}
+	if ttl == 0 {
+		ttl = defaultPaymentRequestTTL
+	}
+
+	return PaymentRequests{
+		TTL: ttl,
+	}, nil
+}
+
+func loadTransfersConfig() (Transfers, error) {
+	approvalThreshold, err := parseOptionalCount("TRANSFER_APPROVAL_THRESHOLD")
+	if err != nil {
+		return Transfers{}, err
+	}
+	dailyLimit, err := parseOptionalCount("TRANSFER_DAILY_LIMIT")
+	if err != nil {
+		return Transfers{}, err
+	}
+	hourlyCountLimit, err := parseOptionalCount("TRANSFER_HOURLY_COUNT_LIMIT")
+	if err != nil {
+		return Transfers{}, err
+	}
+	monthlyRecipientLimit, err := parseOptionalCount("TRANSFER_MONTHLY_RECIPIENT_LIMIT")
+	if err != nil {
+		return Transfers{}, err
+	}
+
+	return Transfers{
+		ApprovalThreshold:     approvalThreshold,
+		DailyLimit:            dailyLimit,
+		HourlyCountLimit:      hourlyCountLimit,
+		MonthlyRecipientLimit: monthlyRecipientLimit,
+	}, nil
+}
+
+func loadAllowanceConfig() (Allowance, error) {
+	amount, err := parseOptionalCount("ALLOWANCE_AMOUNT")
+	if err != nil {
+		return Allowance{}, err
+	}
+	maxBalance, err := parseOptionalCount("ALLOWANCE_MAX_BALANCE")
+	if err != nil {
+		return Allowance{}, err
+	}
+	period := defaultAllowancePeriod
+	if value := os.Getenv("ALLOWANCE_PERIOD"); value != "" {
+		period = model.AllowancePeriod(value)
+		if !period.Valid() {
+			return Allowance{}, fmt.Errorf("invalid ALLOWANCE_PERIOD: %s", value)
+		}
+	}
+	interval, err := parseOptionalDuration("ALLOWANCE_INTERVAL")
+	if err != nil {
+		return Allowance{}, fmt.Errorf("invalid ALLOWANCE_INTERVAL: %w", err)
+	}
+	if interval == 0 {
+		interval = defaultAllowanceInterval
+	}
+
+	return Allowance{
+		Amount:     amount,
+		MaxBalance: maxBalance,
+		Period:     period,
+		Interval:   interval,
+	}, nil
+}
+
+func loadCoinExpiryConfig() (CoinExpiry, error) {
+	ttl, err := parseOptionalDuration("COIN_EXPIRY_TTL")
+	if err != nil {
+		return CoinExpiry{}, fmt.Errorf("invalid COIN_EXPIRY_TTL: %w", err)
+	}
+	notice, err := parseOptionalDuration("COIN_EXPIRY_NOTICE")
+	if err != nil {
+		return CoinExpiry{}, fmt.Errorf("invalid COIN_EXPIRY_NOTICE: %w", err)
+	}
+	if notice == 0 {
+		notice = defaultCoinExpiryNotice
+	}
+	interval, err := parseOptionalDuration("COIN_EXPIRY_INTERVAL")
+	if err != nil {
+		return CoinExpiry{}, fmt.Errorf("invalid COIN_EXPIRY_INTERVAL: %w", err)
+	}
+	if interval == 0 {
+		interval = defaultCoinExpiryInterval
+	}
+
+	return CoinExpiry{
+		TTL:      ttl,
+		Notice:   notice,
+		Interval: interval,
+	}, nil
+}
+
+func loadRegistrationConfig() (Registration, error) {
+	mode := defaultRegistrationMode
+	if value := os.Getenv("REGISTRATION_MODE"); value != "" {
+		mode = model.RegistrationMode(value)
+		if !mode.Valid() {
+			return Registration{}, fmt.Errorf("invalid REGISTRATION_MODE: %s", value)
+		}
+	}
+	inviteTTL, err := parseOptionalDuration("REGISTRATION_INVITE_TTL")
+	if err != nil {
+		return Registration{}, fmt.Errorf("invalid REGISTRATION_INVITE_TTL: %w", err)
+	}
+	if inviteTTL == 0 {
+		inviteTTL = defaultInviteTTL
+	}
+
+	var admins []string
+	for _, username := range strings.Split(os.Getenv("ADMIN_USERNAMES"), ",") {
+		if username = strings.TrimSpace(username); username != "" {
+			admins = append(admins, username)
+		}
+	}
+
+	return Registration{
+		Mode:      mode,
+		InviteTTL: inviteTTL,
+		Admins:    admins,
+	}, nil
+}
+
 func getEnv(key string) (string, error) {
 	value := os.Getenv(key)
 	if value == "" {
@@ -197,5 +541,40 @@ func parseDuration(key string) (time.Duration, error) {
 	if err != nil {
 		return 0, fmt.Errorf("invalid duration format for %s: %w", key, err)
 	}
+	if duration < 0 {
+		return 0, fmt.Errorf("negative duration for %s: %s", key, value)
+	}
 	return duration, nil
 }
+
+func parseOptionalCount(key string) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return count, nil
+}
+
+func loadLedgerConfig() (Ledger, error) {
+	interval, err := parseOptionalDuration("LEDGER_RECONCILE_INTERVAL")
+	if err != nil {
+		return Ledger{}, fmt.Errorf("invalid LEDGER_RECONCILE_INTERVAL: %w", err)
+	}
+	if interval == 0 {
+		interval = defaultLedgerReconcileInterval
+	}
+
+	return Ledger{ReconcileInterval: interval}, nil
+}
+
+func parseOptionalDuration(key string) (time.Duration, error) {
+	if os.Getenv(key) == "" {
+		return 0, nil
+	}
+	return parseDuration(key)
+}
@@ -1,13 +1,22 @@
 package dto
 
 import (
+	req "avito-shop/internal/http-server/dto/request"
 	resp "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/lib/jwtkeys"
 	"avito-shop/internal/model"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
 )
 
 func ToInfoResponse(employeeInfo model.EmployeeInfo) resp.InfoResponse {
 	return resp.InfoResponse{
-		Coins: employeeInfo.Coins,
+		Coins:     employeeInfo.Coins,
+		Available: employeeInfo.Available,
+		Held:      employeeInfo.Held,
+		Expiring:  convertExpiring(employeeInfo.Expiring),
 		CoinHistory: resp.CoinHistory{
 			Received: convertTransactions(employeeInfo.CoinHistory.Received),
 			Sent:     convertTransactions(employeeInfo.CoinHistory.Sent),
@@ -27,6 +36,17 @@ func convertTransactions(transactions []model.CoinTransaction) []resp.CoinTransa
 	return converted
 }
 
+func convertExpiring(expiring []model.ExpiringCoins) []resp.ExpiringCoins {
+	converted := make([]resp.ExpiringCoins, len(expiring))
+	for i := range expiring {
+		converted[i] = resp.ExpiringCoins{
+			Amount:    expiring[i].Amount,
+			ExpiresAt: expiring[i].ExpiresAt,
+		}
+	}
+	return converted
+}
+
 func convertInventory(inventory []model.InventoryItem) []resp.InventoryItem {
 	converted := make([]resp.InventoryItem, len(inventory))
 	for i := range inventory {
@@ -37,3 +57,326 @@ func convertInventory(inventory []model.InventoryItem) []resp.InventoryItem {
 	}
 	return converted
 }
+
+func ToHistoryResponse(page model.HistoryPage) resp.HistoryResponse {
+	entries := make([]resp.HistoryEntry, len(page.Entries))
+	for i := range page.Entries {
+		entries[i] = resp.HistoryEntry{
+			Id:           page.Entries[i].Id.String(),
+			Type:         string(page.Entries[i].Type),
+			Direction:    string(page.Entries[i].Direction),
+			Counterparty: page.Entries[i].Counterparty,
+			Item:         page.Entries[i].Item,
+			Quantity:     page.Entries[i].Quantity,
+			Amount:       page.Entries[i].Amount,
+			CreatedAt:    page.Entries[i].CreatedAt,
+			Message:      page.Entries[i].Message,
+			Category:     string(page.Entries[i].Category),
+		}
+	}
+
+	return resp.HistoryResponse{
+		Entries:    entries,
+		NextCursor: EncodeCursor(page.NextCursor),
+	}
+}
+
+func ToItemsResponse(items []model.CatalogItem) resp.ItemsResponse {
+	converted := make([]resp.CatalogItem, len(items))
+	for i := range items {
+		converted[i] = resp.CatalogItem{
+			Name:      items[i].Name,
+			Price:     items[i].Price,
+			Stock:     items[i].Stock,
+			Available: items[i].Available,
+		}
+	}
+	return resp.ItemsResponse{Items: converted}
+}
+
+func ToItemResponse(item model.Item) resp.ItemResponse {
+	return resp.ItemResponse{
+		Id:             item.Id.String(),
+		Name:           item.Name,
+		Price:          item.Price,
+		Retired:        item.Retired,
+		Stock:          item.Stock,
+		MaxPerEmployee: item.MaxPerEmployee,
+		NonReturnable:  item.NonReturnable,
+	}
+}
+
+func ToOrderLines(request req.OrderRequest) []model.OrderLine {
+	lines := make([]model.OrderLine, len(request.Items))
+	for i := range request.Items {
+		lines[i] = model.OrderLine{
+			Item:     request.Items[i].Item,
+			Quantity: request.Items[i].Quantity,
+		}
+	}
+	return lines
+}
+
+func ToTransferLines(request req.SendCoinBatchRequest) ([]model.TransferLine, error) {
+	if len(request.ToUsers) > 0 {
+		return model.SplitEvenly(request.ToUsers, request.TotalAmount)
+	}
+
+	lines := make([]model.TransferLine, len(request.Recipients))
+	for i := range request.Recipients {
+		lines[i] = model.TransferLine{
+			ToUser: request.Recipients[i].ToUser,
+			Amount: request.Recipients[i].Amount,
+		}
+	}
+	return lines, nil
+}
+
+func ToSendCoinBatchResponse(lines []model.TransferLine) resp.SendCoinBatchResponse {
+	transfers := make([]resp.BatchTransfer, len(lines))
+	total := 0
+	for i := range lines {
+		transfers[i] = resp.BatchTransfer{
+			ToUser:  lines[i].ToUser,
+			Amount:  lines[i].Amount,
+			Pending: lines[i].Pending,
+		}
+		total += lines[i].Amount
+	}
+	return resp.SendCoinBatchResponse{Total: total, Transfers: transfers}
+}
+
+func ToOrderResponse(order model.Order) resp.OrderResponse {
+	items := make([]resp.OrderItem, len(order.Items))
+	for i := range order.Items {
+		items[i] = resp.OrderItem{
+			PurchaseId: order.Items[i].PurchaseId.String(),
+			Item:       order.Items[i].Item,
+			Quantity:   order.Items[i].Quantity,
+			Returned:   order.Items[i].Returned,
+			Price:      order.Items[i].Price,
+			Amount:     order.Items[i].Amount(),
+		}
+	}
+
+	return resp.OrderResponse{
+		OrderId:     order.Id.String(),
+		Employee:    order.Employee,
+		Status:      string(order.Status),
+		Items:       items,
+		Total:       order.Total,
+		CreatedAt:   order.CreatedAt,
+		DeliveredAt: order.DeliveredAt,
+	}
+}
+
+func ToOrdersResponse(orders []model.Order) resp.OrdersResponse {
+	converted := make([]resp.OrderResponse, len(orders))
+	for i := range orders {
+		converted[i] = ToOrderResponse(orders[i])
+	}
+	return resp.OrdersResponse{Orders: converted}
+}
+
+func ToReturnResponse(purchaseReturn model.PurchaseReturn) resp.ReturnResponse {
+	return resp.ReturnResponse{
+		ReturnId:   purchaseReturn.Id.String(),
+		PurchaseId: purchaseReturn.PurchaseId.String(),
+		Item:       purchaseReturn.Item,
+		Quantity:   purchaseReturn.Quantity,
+		Amount:     purchaseReturn.Amount,
+		CreatedAt:  purchaseReturn.CreatedAt,
+	}
+}
+
+func ToGiftResponse(gift model.Gift) resp.GiftResponse {
+	return resp.GiftResponse{
+		GiftId:    gift.Id.String(),
+		ToUser:    gift.Receiver,
+		Item:      gift.Item,
+		Quantity:  gift.Quantity,
+		CreatedAt: gift.CreatedAt,
+	}
+}
+
+func ToListingResponse(listing model.Listing) resp.ListingResponse {
+	return resp.ListingResponse{
+		ListingId: listing.Id.String(),
+		Seller:    listing.Seller,
+		Item:      listing.Item,
+		Quantity:  listing.Quantity,
+		Price:     listing.Price,
+		Status:    string(listing.Status),
+		ExpiresAt: listing.ExpiresAt,
+		CreatedAt: listing.CreatedAt,
+	}
+}
+
+func ToListingsResponse(listings []model.Listing) resp.ListingsResponse {
+	converted := make([]resp.ListingResponse, len(listings))
+	for i := range listings {
+		converted[i] = ToListingResponse(listings[i])
+	}
+	return resp.ListingsResponse{Listings: converted}
+}
+
+func ToKudosResponse(page model.KudosPage) resp.KudosResponse {
+	entries := make([]resp.KudosEntry, len(page.Entries))
+	for i := range page.Entries {
+		entries[i] = resp.KudosEntry{
+			Id:        page.Entries[i].Id.String(),
+			FromUser:  page.Entries[i].From,
+			ToUser:    page.Entries[i].To,
+			Amount:    page.Entries[i].Amount,
+			Message:   page.Entries[i].Message,
+			Category:  string(page.Entries[i].Category),
+			CreatedAt: page.Entries[i].CreatedAt,
+		}
+	}
+
+	return resp.KudosResponse{
+		Entries:    entries,
+		NextCursor: EncodeCursor(page.NextCursor),
+	}
+}
+
+func ToScheduleResponse(schedule model.ScheduledTransfer) resp.ScheduleResponse {
+	return resp.ScheduleResponse{
+		ScheduleId:    schedule.Id.String(),
+		ToUser:        schedule.To,
+		Amount:        schedule.Amount,
+		Message:       schedule.Message,
+		Category:      string(schedule.Category),
+		Recurrence:    string(schedule.Recurrence),
+		Status:        string(schedule.Status),
+		NextRunAt:     schedule.NextRunAt,
+		NextAttemptAt: schedule.NextAttemptAt,
+		Attempts:      schedule.Attempts,
+		LastError:     schedule.LastError,
+		CreatedAt:     schedule.CreatedAt,
+	}
+}
+
+func ToSchedulesResponse(schedules []model.ScheduledTransfer) resp.SchedulesResponse {
+	converted := make([]resp.ScheduleResponse, len(schedules))
+	for i := range schedules {
+		converted[i] = ToScheduleResponse(schedules[i])
+	}
+	return resp.SchedulesResponse{Schedules: converted}
+}
+
+func ToScheduleRunsResponse(runs []model.ScheduledTransferRun) resp.ScheduleRunsResponse {
+	converted := make([]resp.ScheduleRunResponse, len(runs))
+	for i := range runs {
+		converted[i] = resp.ScheduleRunResponse{
+			RunId:       runs[i].Id.String(),
+			ScheduledAt: runs[i].ScheduledAt,
+			Attempt:     runs[i].Attempt,
+			Status:      string(runs[i].Status),
+			Reason:      runs[i].Reason,
+			CreatedAt:   runs[i].CreatedAt,
+		}
+	}
+	return resp.ScheduleRunsResponse{Runs: converted}
+}
+
+func ToPaymentRequestResponse(request model.PaymentRequest) resp.PaymentRequestResponse {
+	return resp.PaymentRequestResponse{
+		RequestId:  request.Id.String(),
+		Requester:  request.Requester,
+		Payer:      request.Payer,
+		Amount:     request.Amount,
+		Message:    request.Message,
+		Status:     string(request.Status),
+		ExpiresAt:  request.ExpiresAt,
+		ResolvedAt: request.ResolvedAt,
+		CreatedAt:  request.CreatedAt,
+	}
+}
+
+func ToPaymentRequestsResponse(requests []model.PaymentRequest) resp.PaymentRequestsResponse {
+	converted := make([]resp.PaymentRequestResponse, len(requests))
+	for i := range requests {
+		converted[i] = ToPaymentRequestResponse(requests[i])
+	}
+	return resp.PaymentRequestsResponse{Requests: converted}
+}
+
+func ToPendingTransferResponse(transfer model.PendingTransfer) resp.PendingTransferResponse {
+	return resp.PendingTransferResponse{
+		TransferId: transfer.Id.String(),
+		FromUser:   transfer.From,
+		ToUser:     transfer.To,
+		Amount:     transfer.Amount,
+		Message:    transfer.Message,
+		Category:   string(transfer.Category),
+		Status:     string(transfer.Status),
+		CreatedAt:  transfer.CreatedAt,
+		ResolvedAt: transfer.ResolvedAt,
+	}
+}
+
+func ToPendingTransfersResponse(transfers []model.PendingTransfer) resp.PendingTransfersResponse {
+	converted := make([]resp.PendingTransferResponse, len(transfers))
+	for i := range transfers {
+		converted[i] = ToPendingTransferResponse(transfers[i])
+	}
+	return resp.PendingTransfersResponse{Transfers: converted}
+}
+
+func ToAdjustmentResponse(adjustment model.BalanceAdjustment) resp.AdjustmentResponse {
+	return resp.AdjustmentResponse{
+		AdjustmentId: adjustment.Id.String(),
+		User:         adjustment.Employee,
+		Amount:       adjustment.Amount,
+		Reason:       adjustment.Reason,
+		Balance:      adjustment.Balance,
+		CreatedAt:    adjustment.CreatedAt,
+	}
+}
+
+func ToSweepResponse(employee model.Employee, amount int) resp.SweepResponse {
+	return resp.SweepResponse{
+		User:    employee.Username,
+		Amount:  amount,
+		Balance: employee.Balance,
+	}
+}
+
+func ToInviteResponse(invite model.Invite) resp.InviteResponse {
+	return resp.InviteResponse{
+		Code:      invite.Code,
+		ExpiresAt: invite.ExpiresAt,
+	}
+}
+
+func ToAuthResponse(tokens model.AuthTokens) resp.AuthResponse {
+	return resp.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
+}
+
+func ToJWKSResponse(keys []jwtkeys.Key) resp.JWKSResponse {
+	jwks := make([]resp.JWK, 0, len(keys))
+	for _, key := range keys {
+		jwk := resp.JWK{Kid: key.Id, Use: "sig", Alg: key.Method.Alg()}
+
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+
+		jwks = append(jwks, jwk)
+	}
+
+	return resp.JWKSResponse{Keys: jwks}
+}
@@ -0,0 +1,7 @@
+package request
+
+// AdjustBalanceRequest.Amount is signed: a negative amount takes coins away.
+type AdjustBalanceRequest struct {
+	Amount int    `json:"amount" validate:"required"`
+	Reason string `json:"reason" validate:"required,max=500"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type GiftRequest struct {
+	ToUser   string `json:"toUser" validate:"required"`
+	Item     string `json:"item" validate:"required"`
+	Quantity int    `json:"quantity" validate:"required,min=1,max=1000"`
+}
@@ -0,0 +1,17 @@
+package request
+
+type CreateItemRequest struct {
+	Name           string `json:"name" validate:"required"`
+	Price          int    `json:"price" validate:"required"`
+	Stock          *int   `json:"stock"`
+	MaxPerEmployee *int   `json:"maxPerEmployee"`
+	NonReturnable  bool   `json:"nonReturnable"`
+}
+
+type UpdateItemRequest struct {
+	Name           *string `json:"name" validate:"omitempty,min=1"`
+	Price          *int    `json:"price"`
+	Stock          *int    `json:"stock"`
+	MaxPerEmployee *int    `json:"maxPerEmployee"`
+	NonReturnable  *bool   `json:"nonReturnable"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type ListingRequest struct {
+	Item     string `json:"item" validate:"required"`
+	Quantity int    `json:"quantity" validate:"required,min=1,max=1000"`
+	Price    int    `json:"price" validate:"required,min=1"`
+}
@@ -0,0 +1,6 @@
+package request
+
+// SetManagerRequest with an empty manager removes the employee's manager.
+type SetManagerRequest struct {
+	Manager string `json:"manager"`
+}
@@ -0,0 +1,14 @@
+package request
+
+type OrderRequest struct {
+	Items []OrderLine `json:"items" validate:"required,min=1,max=50,dive"`
+}
+
+type OrderLine struct {
+	Item     string `json:"item" validate:"required"`
+	Quantity int    `json:"quantity" validate:"required,min=1,max=1000"`
+}
+
+type OrderStatusRequest struct {
+	Status string `json:"status" validate:"required"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type PaymentRequestRequest struct {
+	FromUser string `json:"fromUser" validate:"required"`
+	Amount   int    `json:"amount" validate:"required"`
+	Message  string `json:"message,omitempty" validate:"omitempty,max=200"`
+}
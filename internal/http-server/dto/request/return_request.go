@@ -0,0 +1,6 @@
+package request
+
+type ReturnRequest struct {
+	PurchaseId string `json:"purchaseId" validate:"required,uuid"`
+	Quantity   int    `json:"quantity" validate:"required,min=1,max=1000"`
+}
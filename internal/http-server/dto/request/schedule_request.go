@@ -0,0 +1,12 @@
+package request
+
+import "time"
+
+type ScheduleRequest struct {
+	ToUser     string    `json:"toUser" validate:"required"`
+	Amount     int       `json:"amount" validate:"required"`
+	RunAt      time.Time `json:"runAt" validate:"required"`
+	Recurrence string    `json:"recurrence,omitempty" validate:"omitempty,oneof=once weekly monthly"`
+	Message    string    `json:"message,omitempty" validate:"omitempty,max=200"`
+	Category   string    `json:"category,omitempty" validate:"omitempty,oneof=thanks help teamwork"`
+}
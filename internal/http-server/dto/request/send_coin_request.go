@@ -1,6 +1,22 @@
 package request
 
 type SendCoinRequest struct {
+	ToUser   string `json:"toUser" validate:"required"`
+	Amount   int    `json:"amount" validate:"required"`
+	Message  string `json:"message,omitempty" validate:"omitempty,max=200"`
+	Category string `json:"category,omitempty" validate:"omitempty,oneof=thanks help teamwork"`
+}
+
+// SendCoinBatchRequest takes either explicit recipients or toUsers with a totalAmount to split evenly.
+type SendCoinBatchRequest struct {
+	Recipients  []BatchRecipient `json:"recipients" validate:"required_without=ToUsers,max=100,dive"`
+	ToUsers     []string         `json:"toUsers" validate:"required_without=Recipients,max=100,dive,required"`
+	TotalAmount int              `json:"totalAmount" validate:"required_with=ToUsers,excluded_with=Recipients"`
+	Message     string           `json:"message,omitempty" validate:"omitempty,max=200"`
+	Category    string           `json:"category,omitempty" validate:"omitempty,oneof=thanks help teamwork"`
+}
+
+type BatchRecipient struct {
 	ToUser string `json:"toUser" validate:"required"`
 	Amount int    `json:"amount" validate:"required"`
 }
@@ -0,0 +1,5 @@
+package request
+
+type SetStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=active frozen deactivated"`
+}
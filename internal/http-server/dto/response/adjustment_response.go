@@ -0,0 +1,12 @@
+package response
+
+import "time"
+
+type AdjustmentResponse struct {
+	AdjustmentId string    `json:"adjustmentId"`
+	User         string    `json:"user"`
+	Amount       int       `json:"amount"`
+	Reason       string    `json:"reason"`
+	Balance      int       `json:"balance"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
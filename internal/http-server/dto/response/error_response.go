@@ -2,4 +2,13 @@ package response
 
 type ErrorResponse struct {
 	Errors string `json:"errors"`
+	Code   string `json:"code,omitempty"`
+}
+
+// TransferLimitErrorResponse tells which transfer limit was hit and what is left of it.
+type TransferLimitErrorResponse struct {
+	Errors    string `json:"errors"`
+	Code      string `json:"code"`
+	Limit     string `json:"limit"`
+	Remaining int    `json:"remaining"`
 }
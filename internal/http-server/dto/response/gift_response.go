@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+type GiftResponse struct {
+	GiftId    string    `json:"giftId"`
+	ToUser    string    `json:"toUser"`
+	Item      string    `json:"item"`
+	Quantity  int       `json:"quantity"`
+	CreatedAt time.Time `json:"createdAt"`
+}
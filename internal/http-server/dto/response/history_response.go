@@ -0,0 +1,21 @@
+package response
+
+import "time"
+
+type HistoryResponse struct {
+	Entries    []HistoryEntry `json:"entries"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+type HistoryEntry struct {
+	Id           string    `json:"id"`
+	Type         string    `json:"type"`
+	Direction    string    `json:"direction"`
+	Counterparty string    `json:"counterparty,omitempty"`
+	Item         string    `json:"item,omitempty"`
+	Quantity     int       `json:"quantity,omitempty"`
+	Amount       int       `json:"amount"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Message      string    `json:"message,omitempty"`
+	Category     string    `json:"category,omitempty"`
+}
@@ -1,11 +1,21 @@
 package response
 
+import "time"
+
 type InfoResponse struct {
 	Coins       int             `json:"coins"`
+	Available   int             `json:"available"`
+	Held        int             `json:"held"`
+	Expiring    []ExpiringCoins `json:"expiring"`
 	Inventory   []InventoryItem `json:"inventory"`
 	CoinHistory CoinHistory     `json:"coin_history"`
 }
 
+type ExpiringCoins struct {
+	Amount    int       `json:"amount"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
 type InventoryItem struct {
 	Type     string `json:"type"`
 	Quantity int    `json:"quantity"`
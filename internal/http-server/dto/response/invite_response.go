@@ -0,0 +1,8 @@
+package response
+
+import "time"
+
+type InviteResponse struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,11 @@
+package response
+
+type ItemResponse struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	Price          int    `json:"price"`
+	Retired        bool   `json:"retired"`
+	Stock          *int   `json:"stock,omitempty"`
+	MaxPerEmployee *int   `json:"maxPerEmployee,omitempty"`
+	NonReturnable  bool   `json:"nonReturnable"`
+}
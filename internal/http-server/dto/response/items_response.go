@@ -0,0 +1,12 @@
+package response
+
+type ItemsResponse struct {
+	Items []CatalogItem `json:"items"`
+}
+
+type CatalogItem struct {
+	Name      string `json:"name"`
+	Price     int    `json:"price"`
+	Stock     *int   `json:"stock,omitempty"`
+	Available bool   `json:"available"`
+}
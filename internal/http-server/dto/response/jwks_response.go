@@ -0,0 +1,17 @@
+package response
+
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK carries n and e for RSA keys and crv and x for Ed25519 keys (RFC 7517, RFC 8037).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
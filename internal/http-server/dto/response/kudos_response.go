@@ -0,0 +1,18 @@
+package response
+
+import "time"
+
+type KudosResponse struct {
+	Entries    []KudosEntry `json:"entries"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+type KudosEntry struct {
+	Id        string    `json:"id"`
+	FromUser  string    `json:"fromUser"`
+	ToUser    string    `json:"toUser"`
+	Amount    int       `json:"amount"`
+	Message   string    `json:"message,omitempty"`
+	Category  string    `json:"category,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,18 @@
+package response
+
+import "time"
+
+type ListingResponse struct {
+	ListingId string    `json:"listingId"`
+	Seller    string    `json:"seller"`
+	Item      string    `json:"item"`
+	Quantity  int       `json:"quantity"`
+	Price     int       `json:"price"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ListingsResponse struct {
+	Listings []ListingResponse `json:"listings"`
+}
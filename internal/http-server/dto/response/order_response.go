@@ -0,0 +1,26 @@
+package response
+
+import "time"
+
+type OrderResponse struct {
+	OrderId     string      `json:"orderId"`
+	Employee    string      `json:"employee,omitempty"`
+	Status      string      `json:"status"`
+	Items       []OrderItem `json:"items"`
+	Total       int         `json:"total"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	DeliveredAt *time.Time  `json:"deliveredAt,omitempty"`
+}
+
+type OrdersResponse struct {
+	Orders []OrderResponse `json:"orders"`
+}
+
+type OrderItem struct {
+	PurchaseId string `json:"purchaseId"`
+	Item       string `json:"item"`
+	Quantity   int    `json:"quantity"`
+	Returned   int    `json:"returned,omitempty"`
+	Price      int    `json:"price"`
+	Amount     int    `json:"amount"`
+}
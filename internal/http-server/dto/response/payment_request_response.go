@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+type PaymentRequestResponse struct {
+	RequestId  string     `json:"requestId"`
+	Requester  string     `json:"requester"`
+	Payer      string     `json:"payer"`
+	Amount     int        `json:"amount"`
+	Message    string     `json:"message,omitempty"`
+	Status     string     `json:"status"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type PaymentRequestsResponse struct {
+	Requests []PaymentRequestResponse `json:"requests"`
+}
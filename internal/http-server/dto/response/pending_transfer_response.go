@@ -0,0 +1,20 @@
+package response
+
+import "time"
+
+// PendingTransferResponse is returned by sendCoin with 202 when the transfer waits for approval.
+type PendingTransferResponse struct {
+	TransferId string     `json:"transferId"`
+	FromUser   string     `json:"fromUser"`
+	ToUser     string     `json:"toUser"`
+	Amount     int        `json:"amount"`
+	Message    string     `json:"message,omitempty"`
+	Category   string     `json:"category,omitempty"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+}
+
+type PendingTransfersResponse struct {
+	Transfers []PendingTransferResponse `json:"transfers"`
+}
@@ -0,0 +1,12 @@
+package response
+
+import "time"
+
+type ReturnResponse struct {
+	ReturnId   string    `json:"returnId"`
+	PurchaseId string    `json:"purchaseId"`
+	Item       string    `json:"item"`
+	Quantity   int       `json:"quantity"`
+	Amount     int       `json:"amount"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
@@ -0,0 +1,35 @@
+package response
+
+import "time"
+
+type ScheduleResponse struct {
+	ScheduleId    string    `json:"scheduleId"`
+	ToUser        string    `json:"toUser"`
+	Amount        int       `json:"amount"`
+	Message       string    `json:"message,omitempty"`
+	Category      string    `json:"category,omitempty"`
+	Recurrence    string    `json:"recurrence"`
+	Status        string    `json:"status"`
+	NextRunAt     time.Time `json:"nextRunAt"`
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"lastError,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type SchedulesResponse struct {
+	Schedules []ScheduleResponse `json:"schedules"`
+}
+
+type ScheduleRunResponse struct {
+	RunId       string    `json:"runId"`
+	ScheduledAt time.Time `json:"scheduledAt"`
+	Attempt     int       `json:"attempt"`
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type ScheduleRunsResponse struct {
+	Runs []ScheduleRunResponse `json:"runs"`
+}
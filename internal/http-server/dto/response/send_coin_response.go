@@ -0,0 +1,12 @@
+package response
+
+type SendCoinBatchResponse struct {
+	Total     int             `json:"total"`
+	Transfers []BatchTransfer `json:"transfers"`
+}
+
+type BatchTransfer struct {
+	ToUser  string `json:"toUser"`
+	Amount  int    `json:"amount"`
+	Pending bool   `json:"pending,omitempty"`
+}
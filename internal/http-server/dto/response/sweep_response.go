@@ -0,0 +1,8 @@
+package response
+
+// SweepResponse.Balance still includes coins held for pending transfers, which are not swept.
+type SweepResponse struct {
+	User    string `json:"user"`
+	Amount  int    `json:"amount"`
+	Balance int    `json:"balance"`
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	req "avito-shop/internal/http-server/dto/request"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+const usernameParam = "username"
+
+type EmployeeAdmin interface {
+	SetManager(ctx context.Context, username string, manager string) error
+	Adjust(
+		ctx context.Context, adminId uuid.UUID, username string, amount int, reason string,
+	) (*model.BalanceAdjustment, error)
+	SetStatus(ctx context.Context, username string, status model.EmployeeStatus) error
+	Sweep(ctx context.Context, username string) (*model.Employee, int, error)
+}
+
+func NewSetManagerHandlerFunc(log *slog.Logger, employeeService EmployeeAdmin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewSetManagerHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		username, ok := getURLParam(r, usernameParam, log)
+		if !ok {
+			renderError(w, r, http.StatusBadRequest, "empty username")
+			return
+		}
+
+		var request req.SetManagerRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := employeeService.SetManager(r.Context(), username, request.Manager); err != nil {
+			handleEmployeeAdminError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+	}
+}
+
+func NewAdjustBalanceHandlerFunc(
+	log *slog.Logger, employeeService EmployeeAdmin, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewAdjustBalanceHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		username, ok := getURLParam(r, usernameParam, log)
+		if !ok {
+			renderError(w, r, http.StatusBadRequest, "empty username")
+			return
+		}
+
+		var request req.AdjustBalanceRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		adjustment, err := employeeService.Adjust(
+			r.Context(), claims.EmployeeId, username, request.Amount, request.Reason)
+		if err != nil {
+			handleEmployeeAdminError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToAdjustmentResponse(*adjustment))
+	}
+}
+
+func NewSetStatusHandlerFunc(
+	log *slog.Logger, employeeService EmployeeAdmin, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewSetStatusHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		username, ok := getURLParam(r, usernameParam, log)
+		if !ok {
+			renderError(w, r, http.StatusBadRequest, "empty username")
+			return
+		}
+
+		var request req.SetStatusRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		if err := employeeService.SetStatus(r.Context(), username, model.EmployeeStatus(request.Status)); err != nil {
+			handleEmployeeAdminError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+	}
+}
+
+func NewSweepBalanceHandlerFunc(log *slog.Logger, employeeService EmployeeAdmin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewSweepBalanceHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		username, ok := getURLParam(r, usernameParam, log)
+		if !ok {
+			renderError(w, r, http.StatusBadRequest, "empty username")
+			return
+		}
+
+		employee, amount, err := employeeService.Sweep(r.Context(), username)
+		if err != nil {
+			handleEmployeeAdminError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToSweepResponse(*employee, amount))
+	}
+}
+
+func handleEmployeeAdminError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrEmployeeNotFound):
+		status, code, message = http.StatusNotFound, "employee_not_found", "employee not found"
+	case errors.Is(err, service.ErrManagerNotFound):
+		status, code, message = http.StatusBadRequest, "manager_not_found", "manager not found"
+	case errors.Is(err, service.ErrSelfManager):
+		status, code, message = http.StatusBadRequest, "self_manager", "employee can't manage themselves"
+	case errors.Is(err, service.ErrNotEnoughCoins):
+		status, code, message = http.StatusBadRequest, "not_enough_coins", "not enough coins"
+	case errors.Is(err, service.ErrZeroAdjustment):
+		status, code, message = http.StatusBadRequest, "zero_adjustment", "adjustment amount must not be zero"
+	case errors.Is(err, service.ErrAdjustmentReasonRequired), errors.Is(err, service.ErrAdjustmentReasonTooLong):
+		status, code, message = http.StatusBadRequest, "invalid_reason", "reason must be 1 to 500 characters"
+	case errors.Is(err, service.ErrInvalidEmployeeStatus):
+		status, code, message = http.StatusBadRequest, "invalid_status", "invalid employee status"
+	case errors.Is(err, service.ErrEmployeeNotDeactivated):
+		status, code, message = http.StatusConflict, "not_deactivated", "only deactivated accounts can be swept"
+	default:
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+		log.Error("Employee update failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Employee update failed", sl.Err(err))
+	}
+
+	renderErrorWithCode(w, r, status, code, message)
+}
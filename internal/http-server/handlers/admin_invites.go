@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"context"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type InviteIssuer interface {
+	Create(ctx context.Context, adminId uuid.UUID) (*model.Invite, error)
+}
+
+func NewCreateInviteHandlerFunc(log *slog.Logger, inviteService InviteIssuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewCreateInviteHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		invite, err := inviteService.Create(r.Context(), claims.EmployeeId)
+		if err != nil {
+			log.Error("Invite creation failed", sl.Err(err))
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dto.ToInviteResponse(*invite))
+	}
+}
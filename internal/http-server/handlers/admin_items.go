@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	req "avito-shop/internal/http-server/dto/request"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type ItemAdmin interface {
+	Create(ctx context.Context, item *model.Item) error
+	Update(ctx context.Context, name string, update model.ItemUpdate) (*model.Item, error)
+	Retire(ctx context.Context, name string) error
+}
+
+func NewCreateItemHandlerFunc(log *slog.Logger, itemService ItemAdmin, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewCreateItemHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		var request req.CreateItemRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		item := &model.Item{
+			Name:           request.Name,
+			Price:          request.Price,
+			Stock:          request.Stock,
+			MaxPerEmployee: request.MaxPerEmployee,
+			NonReturnable:  request.NonReturnable,
+		}
+
+		if err := itemService.Create(r.Context(), item); err != nil {
+			handleItemAdminError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dto.ToItemResponse(*item))
+	}
+}
+
+func NewUpdateItemHandlerFunc(log *slog.Logger, itemService ItemAdmin, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewUpdateItemHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		itemName, ok := getURLParam(r, itemParam, log)
+		if !ok {
+			renderError(w, r, http.StatusBadRequest, "empty item name")
+			return
+		}
+
+		var request req.UpdateItemRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		item, err := itemService.Update(r.Context(), itemName, model.ItemUpdate{
+			Name:           request.Name,
+			Price:          request.Price,
+			Stock:          request.Stock,
+			MaxPerEmployee: request.MaxPerEmployee,
+			NonReturnable:  request.NonReturnable,
+		})
+		if err != nil {
+			handleItemAdminError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToItemResponse(*item))
+	}
+}
+
+func NewRetireItemHandlerFunc(log *slog.Logger, itemService ItemAdmin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewRetireItemHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		itemName, ok := getURLParam(r, itemParam, log)
+		if !ok {
+			renderError(w, r, http.StatusBadRequest, "empty item name")
+			return
+		}
+
+		if err := itemService.Retire(r.Context(), itemName); err != nil {
+			handleItemAdminError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+	}
+}
+
+func handleItemAdminError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	var status int
+	var message string
+
+	switch {
+	case errors.Is(err, service.ErrItemNotFound):
+		status, message = http.StatusNotFound, "item not found"
+	case errors.Is(err, service.ErrItemExists):
+		status, message = http.StatusConflict, "item already exists"
+	case errors.Is(err, service.ErrInvalidItemPrice):
+		status, message = http.StatusBadRequest, "price must be positive"
+	case errors.Is(err, service.ErrInvalidItemLimit):
+		status, message = http.StatusBadRequest, "stock must not be negative and purchase limit must be positive"
+	default:
+		status, message = http.StatusInternalServerError, internalServerError
+		log.Error("Item management failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Item management failed", sl.Err(err))
+	}
+
+	renderError(w, r, status, message)
+}
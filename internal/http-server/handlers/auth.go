@@ -1,20 +1,34 @@
 package handlers
 
 import (
+	"avito-shop/internal/http-server/dto"
 	req "avito-shop/internal/http-server/dto/request"
-	resp "avito-shop/internal/http-server/dto/response"
 	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
 	"avito-shop/internal/service"
 	"context"
 	"errors"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"log/slog"
 	"net/http"
 )
 
 type Auth interface {
-	Authorize(ctx context.Context, username string, password string) (string, error)
+	Authorize(ctx context.Context, username string, password string) (*model.AuthTokens, error)
+}
+
+type Registrar interface {
+	Register(ctx context.Context, username string, password string, inviteCode string) (*model.AuthTokens, error)
+}
+
+type TokenRefresher interface {
+	Refresh(ctx context.Context, refreshToken string) (*model.AuthTokens, error)
+}
+
+type SessionCloser interface {
+	Logout(ctx context.Context, sessionId uuid.UUID) error
 }
 
 func NewAuthHandlerFunc(log *slog.Logger, authService Auth, validate *validator.Validate) http.HandlerFunc {
@@ -40,13 +54,18 @@ func NewAuthHandlerFunc(log *slog.Logger, authService Auth, validate *validator.
 
 		log.Debug("Validation passed", slog.String("username", request.Username))
 
-		token, err := authService.Authorize(r.Context(), request.Username, request.Password)
+		tokens, err := authService.Authorize(r.Context(), request.Username, request.Password)
 		if err != nil {
 			if errors.Is(err, service.ErrInvalidCredentials) {
 				log.Info("Invalid login attempt", slog.String("username", request.Username))
 				renderError(w, r, http.StatusUnauthorized, "invalid credentials")
 				return
 			}
+			if errors.Is(err, service.ErrAccountDeactivated) {
+				log.Info("Deactivated account login attempt", slog.String("username", request.Username))
+				renderError(w, r, http.StatusForbidden, "account is deactivated")
+				return
+			}
 
 			log.Error("Authorization failed", sl.Err(err))
 			renderError(w, r, http.StatusInternalServerError, "internal error")
@@ -55,6 +74,136 @@ func NewAuthHandlerFunc(log *slog.Logger, authService Auth, validate *validator.
 
 		log.Info("User authenticated", slog.String("username", request.Username))
 		render.Status(r, http.StatusOK)
-		render.JSON(w, r, resp.AuthResponse{Token: token})
+		render.JSON(w, r, dto.ToAuthResponse(*tokens))
+	}
+}
+
+func NewRegisterHandlerFunc(log *slog.Logger, registrar Registrar, validate *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewRegisterHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		var request req.RegisterRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := validate.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		tokens, err := registrar.Register(r.Context(), request.Username, request.Password, request.InviteCode)
+		if err != nil {
+			handleRegisterError(w, r, log, err)
+			return
+		}
+
+		log.Info("User registered", slog.String("username", request.Username))
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dto.ToAuthResponse(*tokens))
+	}
+}
+
+func handleRegisterError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrUsernameTaken):
+		status, code, message = http.StatusConflict, "username_taken", "username is already taken"
+	case errors.Is(err, service.ErrInviteRequired):
+		status, code, message = http.StatusForbidden, "invite_required", "invite code is required"
+	case errors.Is(err, service.ErrInvalidInvite):
+		status, code, message = http.StatusForbidden, "invalid_invite", "invite code is invalid, used or expired"
+	default:
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+		log.Error("Registration failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Registration failed", sl.Err(err))
+	}
+
+	renderErrorWithCode(w, r, status, code, message)
+}
+
+func NewRefreshHandlerFunc(log *slog.Logger, refresher TokenRefresher, validate *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewRefreshHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		var request req.RefreshRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := validate.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		tokens, err := refresher.Refresh(r.Context(), request.RefreshToken)
+		if err != nil {
+			handleRefreshError(w, r, log, err)
+			return
+		}
+
+		log.Info("Tokens refreshed")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToAuthResponse(*tokens))
 	}
 }
+
+func NewLogoutHandlerFunc(log *slog.Logger, closer SessionCloser) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewLogoutHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		if err := closer.Logout(r.Context(), claims.SessionId); err != nil {
+			log.Error("Logout failed", sl.Err(err))
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		log.Info("User logged out", slog.String("username", claims.Username))
+		render.Status(r, http.StatusOK)
+	}
+}
+
+func handleRefreshError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrInvalidRefreshToken):
+		status, code, message = http.StatusUnauthorized, "invalid_refresh_token", "refresh token is invalid or expired"
+	case errors.Is(err, service.ErrRefreshTokenReused):
+		status, code, message = http.StatusUnauthorized, "refresh_token_reused", "refresh token was already used"
+	case errors.Is(err, service.ErrAccountDeactivated):
+		status, code, message = http.StatusForbidden, "account_deactivated", "account is deactivated"
+	default:
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+		log.Error("Refresh failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Refresh failed", sl.Err(err))
+	}
+
+	renderErrorWithCode(w, r, status, code, message)
+}
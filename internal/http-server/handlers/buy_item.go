@@ -43,18 +43,31 @@ func NewBuyItemHandlerFunc(log *slog.Logger, buyItemService BuyItem) http.Handle
 }
 
 func handleBuyError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	if renderAccountStatusError(w, r, log, err) {
+		return
+	}
+
 	var status int
-	var message string
+	var code, message string
 
 	switch {
 	case errors.Is(err, service.ErrEmployeeNotFound):
-		status, message = http.StatusUnauthorized, "employee not found"
+		status, code, message = http.StatusUnauthorized, "employee_not_found", "employee not found"
 	case errors.Is(err, service.ErrNotEnoughCoins):
-		status, message = http.StatusBadRequest, "not enough coins"
+		status, code, message = http.StatusBadRequest, "not_enough_coins", "not enough coins"
 	case errors.Is(err, service.ErrItemNotFound):
-		status, message = http.StatusBadRequest, "item not found"
+		status, code, message = http.StatusBadRequest, "item_not_found", "item not found"
+	case errors.Is(err, service.ErrItemRetired):
+		status, code, message = http.StatusBadRequest, "item_retired", "item is no longer sold"
+	case errors.Is(err, service.ErrEmptyOrder), errors.Is(err, service.ErrInvalidQuantity):
+		status, code, message = http.StatusBadRequest, "invalid_order", err.Error()
+	case errors.Is(err, service.ErrOutOfStock):
+		status, code, message = http.StatusConflict, "out_of_stock", "item is out of stock"
+	case errors.Is(err, service.ErrPurchaseLimitReached):
+		status, code, message =
+			http.StatusUnprocessableEntity, "purchase_limit_reached", "purchase limit for item reached"
 	default:
-		status, message = http.StatusInternalServerError, "internal server error"
+		status, code, message = http.StatusInternalServerError, "", "internal server error"
 		log.Error("Buy operation failed", sl.Err(err))
 	}
 
@@ -62,5 +75,5 @@ func handleBuyError(w http.ResponseWriter, r *http.Request, log *slog.Logger, er
 		log.Info("Buy operation failed", sl.Err(err))
 	}
 
-	renderError(w, r, status, message)
+	renderErrorWithCode(w, r, status, code, message)
 }
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	req "avito-shop/internal/http-server/dto/request"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type Gift interface {
+	GiftItem(ctx context.Context, from string, to string, item string, quantity int) (*model.Gift, error)
+}
+
+func NewGiftHandlerFunc(log *slog.Logger, giftService Gift, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewGiftHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		var request req.GiftRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		gift, err := giftService.GiftItem(r.Context(), claims.Username, request.ToUser, request.Item, request.Quantity)
+		if err != nil {
+			handleGiftError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dto.ToGiftResponse(*gift))
+	}
+}
+
+func handleGiftError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	if renderAccountStatusError(w, r, log, err) {
+		return
+	}
+
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrTransferToSameEmployee):
+		status, code, message = http.StatusBadRequest, "same_employee", "can't gift items to yourself"
+	case errors.Is(err, service.ErrInvalidQuantity):
+		status, code, message = http.StatusBadRequest, "invalid_quantity", "item quantity must be positive"
+	case errors.Is(err, service.ErrReceiverNotFound):
+		status, code, message = http.StatusBadRequest, "receiver_not_found", "receiver not found"
+	case errors.Is(err, service.ErrItemNotFound):
+		status, code, message = http.StatusBadRequest, "item_not_found", "item not found"
+	case errors.Is(err, service.ErrNotEnoughItems):
+		status, code, message = http.StatusBadRequest, "not_enough_items", "not enough items in inventory"
+	case errors.Is(err, service.ErrSenderNotFound):
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+	default:
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+		log.Error("Gift failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Gift failed", sl.Err(err))
+	}
+
+	renderErrorWithCode(w, r, status, code, message)
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	resp "avito-shop/internal/http-server/dto/response"
 	mw "avito-shop/internal/http-server/middleware"
+	"avito-shop/internal/lib/logger/sl"
 	"avito-shop/internal/service"
+	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
@@ -20,6 +22,51 @@ func renderError(w http.ResponseWriter, r *http.Request, status int, message str
 	render.JSON(w, r, resp.ErrorResponse{Errors: message})
 }
 
+func renderErrorWithCode(w http.ResponseWriter, r *http.Request, status int, code string, message string) {
+	render.Status(r, status)
+	render.JSON(w, r, resp.ErrorResponse{Errors: message, Code: code})
+}
+
+// renderTransferLimitError answers 429 with the remaining allowance when err is a *service.TransferLimitError.
+func renderTransferLimitError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) bool {
+	var limitErr *service.TransferLimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+
+	log.Info("Transfer limit exceeded", sl.Err(err))
+	render.Status(r, http.StatusTooManyRequests)
+	render.JSON(w, r, resp.TransferLimitErrorResponse{
+		Errors:    "transfer limit exceeded",
+		Code:      "transfer_limit_exceeded",
+		Limit:     string(limitErr.Limit),
+		Remaining: limitErr.Remaining,
+	})
+	return true
+}
+
+// renderAccountStatusError answers 403 when the caller's account is frozen or deactivated and 409 when the other
+// party's account is.
+func renderAccountStatusError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) bool {
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrAccountFrozen):
+		status, code, message = http.StatusForbidden, "account_frozen", "account is frozen"
+	case errors.Is(err, service.ErrAccountDeactivated):
+		status, code, message = http.StatusForbidden, "account_deactivated", "account is deactivated"
+	case errors.Is(err, service.ErrReceiverNotActive):
+		status, code, message = http.StatusConflict, "receiver_not_active", "receiver account is not active"
+	default:
+		return false
+	}
+
+	log.Info("Account is not active", sl.Err(err))
+	renderErrorWithCode(w, r, status, code, message)
+	return true
+}
+
 func getClaimsFromContext(r *http.Request, log *slog.Logger) (*service.TokenClaims, bool) {
 	claims, ok := r.Context().Value(mw.UserContextKey).(*service.TokenClaims)
 	if !ok || claims == nil {
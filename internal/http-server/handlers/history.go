@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type History interface {
+	Get(ctx context.Context, username string, filter model.HistoryFilter) (*model.HistoryPage, error)
+}
+
+func NewHistoryHandlerFunc(log *slog.Logger, historyService History) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewHistoryHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		filter, err := parseHistoryFilter(r)
+		if err != nil {
+			log.Info("Invalid history query", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		page, err := historyService.Get(r.Context(), claims.Username, filter)
+		if err != nil {
+			handleHistoryError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToHistoryResponse(*page))
+	}
+}
+
+func parseHistoryFilter(r *http.Request) (model.HistoryFilter, error) {
+	query := r.URL.Query()
+	filter := model.HistoryFilter{
+		Counterparty: query.Get("counterparty"),
+	}
+
+	switch direction := model.HistoryDirection(query.Get("direction")); direction {
+	case "", model.DirectionIn, model.DirectionOut:
+		filter.Direction = direction
+	default:
+		return filter, fmt.Errorf("invalid direction %q", direction)
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return filter, fmt.Errorf("invalid limit %q", limit)
+		}
+		filter.Limit = value
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		value, err := dto.DecodeCursor(cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = value
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		return filter, fmt.Errorf("invalid from: %w", err)
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		return filter, fmt.Errorf("invalid to: %w", err)
+	}
+
+	return filter, nil
+}
+
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func handleHistoryError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, service.ErrEmployeeNotFound):
+		log.Error("Employee not found", sl.Err(err))
+		renderError(w, r, http.StatusUnauthorized, "employee not found")
+	case errors.Is(err, service.ErrInvalidDateRange):
+		log.Info("History retrieval failed", sl.Err(err))
+		renderError(w, r, http.StatusBadRequest, "invalid date range")
+	default:
+		log.Error("History retrieval failed", sl.Err(err))
+		renderError(w, r, http.StatusInternalServerError, internalServerError)
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Catalog interface {
+	List(
+		ctx context.Context, username string, filter model.ItemFilter, affordableOnly bool) ([]model.CatalogItem, error)
+}
+
+func NewItemsHandlerFunc(log *slog.Logger, catalogService Catalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewItemsHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		filter, affordableOnly, err := parseItemFilter(r)
+		if err != nil {
+			log.Info("Invalid items query", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		items, err := catalogService.List(r.Context(), claims.Username, filter, affordableOnly)
+		if err != nil {
+			handleItemsError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToItemsResponse(items))
+	}
+}
+
+func parseItemFilter(r *http.Request) (model.ItemFilter, bool, error) {
+	query := r.URL.Query()
+	var filter model.ItemFilter
+
+	switch sortBy := model.ItemSortField(query.Get("sort")); sortBy {
+	case "", model.ItemSortName, model.ItemSortPrice:
+		filter.SortBy = sortBy
+	default:
+		return filter, false, fmt.Errorf("invalid sort %q", sortBy)
+	}
+
+	switch order := model.SortOrder(query.Get("order")); order {
+	case "", model.SortAsc, model.SortDesc:
+		filter.Order = order
+	default:
+		return filter, false, fmt.Errorf("invalid order %q", order)
+	}
+
+	affordableOnly := false
+	if affordable := query.Get("affordable"); affordable != "" {
+		value, err := strconv.ParseBool(affordable)
+		if err != nil {
+			return filter, false, fmt.Errorf("invalid affordable %q", affordable)
+		}
+		affordableOnly = value
+	}
+
+	return filter, affordableOnly, nil
+}
+
+func handleItemsError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	if errors.Is(err, service.ErrEmployeeNotFound) {
+		log.Error("Employee not found", sl.Err(err))
+		renderError(w, r, http.StatusUnauthorized, "employee not found")
+		return
+	}
+
+	log.Error("Items retrieval failed", sl.Err(err))
+	renderError(w, r, http.StatusInternalServerError, internalServerError)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	"avito-shop/internal/lib/jwtkeys"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+// jwksMaxAge lets verifiers cache the key set; a key added during rotation must be published at least
+// this long before it starts signing.
+const jwksMaxAge = "public, max-age=300"
+
+type PublicKeySource interface {
+	PublicKeys() []jwtkeys.Key
+}
+
+func NewJWKSHandlerFunc(log *slog.Logger, keys PublicKeySource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewJWKSHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		log.Debug("Serving JWKS")
+		w.Header().Set("Cache-Control", jwksMaxAge)
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToJWKSResponse(keys.PublicKeys()))
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type KudosWall interface {
+	Kudos(ctx context.Context, filter model.KudosFilter) (*model.KudosPage, error)
+}
+
+func NewKudosHandlerFunc(log *slog.Logger, kudosService KudosWall) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewKudosHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		filter, err := parseKudosFilter(r)
+		if err != nil {
+			log.Info("Invalid kudos query", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		page, err := kudosService.Kudos(r.Context(), filter)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidTransferCategory) {
+				log.Info("Kudos retrieval failed", sl.Err(err))
+				renderError(w, r, http.StatusBadRequest, "invalid category")
+				return
+			}
+			log.Error("Kudos retrieval failed", sl.Err(err))
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToKudosResponse(*page))
+	}
+}
+
+func parseKudosFilter(r *http.Request) (model.KudosFilter, error) {
+	query := r.URL.Query()
+	filter := model.KudosFilter{
+		Category: model.TransferCategory(query.Get("category")),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return filter, fmt.Errorf("invalid limit %q", limit)
+		}
+		filter.Limit = value
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		value, err := dto.DecodeCursor(cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = value
+	}
+
+	return filter, nil
+}
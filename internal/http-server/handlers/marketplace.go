@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	req "avito-shop/internal/http-server/dto/request"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+const listingIdParam = "listingId"
+
+type Marketplace interface {
+	Create(ctx context.Context, username string, item string, quantity int, price int) (*model.Listing, error)
+	List(ctx context.Context, filter model.ListingFilter) ([]model.Listing, error)
+	Buy(ctx context.Context, username string, listingId uuid.UUID) (*model.Listing, error)
+	Cancel(ctx context.Context, username string, listingId uuid.UUID) (*model.Listing, error)
+}
+
+func NewCreateListingHandlerFunc(
+	log *slog.Logger, marketplaceService Marketplace, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewCreateListingHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		var request req.ListingRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		listing, err := marketplaceService.Create(
+			r.Context(), claims.Username, request.Item, request.Quantity, request.Price)
+		if err != nil {
+			handleMarketplaceError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dto.ToListingResponse(*listing))
+	}
+}
+
+func NewListListingsHandlerFunc(log *slog.Logger, marketplaceService Marketplace) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewListListingsHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		filter := model.ListingFilter{Item: r.URL.Query().Get("item")}
+
+		listings, err := marketplaceService.List(r.Context(), filter)
+		if err != nil {
+			handleMarketplaceError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToListingsResponse(listings))
+	}
+}
+
+func NewBuyListingHandlerFunc(log *slog.Logger, marketplaceService Marketplace) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewBuyListingHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		listingId, ok := getListingId(w, r, log)
+		if !ok {
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		listing, err := marketplaceService.Buy(r.Context(), claims.Username, listingId)
+		if err != nil {
+			handleMarketplaceError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToListingResponse(*listing))
+	}
+}
+
+func NewCancelListingHandlerFunc(log *slog.Logger, marketplaceService Marketplace) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewCancelListingHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		listingId, ok := getListingId(w, r, log)
+		if !ok {
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		listing, err := marketplaceService.Cancel(r.Context(), claims.Username, listingId)
+		if err != nil {
+			handleMarketplaceError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToListingResponse(*listing))
+	}
+}
+
+func getListingId(w http.ResponseWriter, r *http.Request, log *slog.Logger) (uuid.UUID, bool) {
+	value, ok := getURLParam(r, listingIdParam, log)
+	if !ok {
+		renderError(w, r, http.StatusBadRequest, "empty listing id")
+		return uuid.Nil, false
+	}
+
+	listingId, err := uuid.Parse(value)
+	if err != nil {
+		log.Info("Invalid listing id", sl.Err(err))
+		renderError(w, r, http.StatusBadRequest, "invalid listing id")
+		return uuid.Nil, false
+	}
+
+	return listingId, true
+}
+
+func handleMarketplaceError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	if renderTransferLimitError(w, r, log, err) || renderAccountStatusError(w, r, log, err) {
+		return
+	}
+
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrEmployeeNotFound):
+		status, code, message = http.StatusUnauthorized, "employee_not_found", "employee not found"
+	case errors.Is(err, service.ErrListingNotFound):
+		status, code, message = http.StatusNotFound, "listing_not_found", "listing not found"
+	case errors.Is(err, service.ErrListingNotActive):
+		status, code, message = http.StatusConflict, "listing_not_active", "listing is no longer active"
+	case errors.Is(err, service.ErrSaleRequiresApproval):
+		status, code, message = http.StatusConflict, "sale_requires_approval",
+			"sale amount is above the transfer approval threshold"
+	case errors.Is(err, service.ErrOwnListing):
+		status, code, message = http.StatusBadRequest, "own_listing", "can't buy your own listing"
+	case errors.Is(err, service.ErrInvalidListingPrice):
+		status, code, message = http.StatusBadRequest, "invalid_price", "listing price must be positive"
+	case errors.Is(err, service.ErrInvalidQuantity):
+		status, code, message = http.StatusBadRequest, "invalid_quantity", "item quantity must be positive"
+	case errors.Is(err, service.ErrItemNotFound):
+		status, code, message = http.StatusBadRequest, "item_not_found", "item not found"
+	case errors.Is(err, service.ErrNotEnoughItems):
+		status, code, message = http.StatusBadRequest, "not_enough_items", "not enough items in inventory"
+	case errors.Is(err, service.ErrNotEnoughCoins):
+		status, code, message = http.StatusBadRequest, "not_enough_coins", "not enough coins"
+	default:
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+		log.Error("Marketplace operation failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Marketplace operation failed", sl.Err(err))
+	}
+
+	renderErrorWithCode(w, r, status, code, message)
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	req "avito-shop/internal/http-server/dto/request"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type Checkout interface {
+	Checkout(ctx context.Context, username string, lines []model.OrderLine) (*model.Order, error)
+}
+
+func NewCheckoutHandlerFunc(log *slog.Logger, checkoutService Checkout, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewCheckoutHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		var request req.OrderRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		order, err := checkoutService.Checkout(r.Context(), claims.Username, dto.ToOrderLines(request))
+		if err != nil {
+			handleBuyError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dto.ToOrderResponse(*order))
+	}
+}
+
+const orderIdParam = "orderId"
+
+type Orders interface {
+	ListForEmployee(ctx context.Context, username string, status model.OrderStatus) ([]model.Order, error)
+	Cancel(ctx context.Context, username string, orderId uuid.UUID) (*model.Order, error)
+}
+
+type OrderAdmin interface {
+	List(ctx context.Context, status model.OrderStatus) ([]model.Order, error)
+	Advance(ctx context.Context, orderId uuid.UUID, status model.OrderStatus) (*model.Order, error)
+}
+
+func NewListOrdersHandlerFunc(log *slog.Logger, orderService Orders) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewListOrdersHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		status := model.OrderStatus(r.URL.Query().Get("status"))
+
+		orders, err := orderService.ListForEmployee(r.Context(), claims.Username, status)
+		if err != nil {
+			handleOrderError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToOrdersResponse(orders))
+	}
+}
+
+func NewCancelOrderHandlerFunc(log *slog.Logger, orderService Orders) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewCancelOrderHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		orderId, ok := getOrderId(w, r, log)
+		if !ok {
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		order, err := orderService.Cancel(r.Context(), claims.Username, orderId)
+		if err != nil {
+			handleOrderError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToOrderResponse(*order))
+	}
+}
+
+func NewAdminListOrdersHandlerFunc(log *slog.Logger, orderService OrderAdmin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewAdminListOrdersHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		status := model.OrderStatus(r.URL.Query().Get("status"))
+
+		orders, err := orderService.List(r.Context(), status)
+		if err != nil {
+			handleOrderError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToOrdersResponse(orders))
+	}
+}
+
+func NewAdvanceOrderHandlerFunc(log *slog.Logger, orderService OrderAdmin, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewAdvanceOrderHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		orderId, ok := getOrderId(w, r, log)
+		if !ok {
+			return
+		}
+
+		var request req.OrderStatusRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		order, err := orderService.Advance(r.Context(), orderId, model.OrderStatus(request.Status))
+		if err != nil {
+			handleOrderError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToOrderResponse(*order))
+	}
+}
+
+func getOrderId(w http.ResponseWriter, r *http.Request, log *slog.Logger) (uuid.UUID, bool) {
+	value, ok := getURLParam(r, orderIdParam, log)
+	if !ok {
+		renderError(w, r, http.StatusBadRequest, "empty order id")
+		return uuid.Nil, false
+	}
+
+	orderId, err := uuid.Parse(value)
+	if err != nil {
+		log.Info("Invalid order id", sl.Err(err))
+		renderError(w, r, http.StatusBadRequest, "invalid order id")
+		return uuid.Nil, false
+	}
+
+	return orderId, true
+}
+
+func handleOrderError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrEmployeeNotFound):
+		status, code, message = http.StatusUnauthorized, "employee_not_found", "employee not found"
+	case errors.Is(err, service.ErrOrderNotFound):
+		status, code, message = http.StatusNotFound, "order_not_found", "order not found"
+	case errors.Is(err, service.ErrInvalidOrderStatus):
+		status, code, message = http.StatusBadRequest, "invalid_status", "invalid order status"
+	case errors.Is(err, service.ErrOrderTransition):
+		status, code, message = http.StatusConflict, "invalid_transition", "order status transition not allowed"
+	case errors.Is(err, service.ErrOrderItemsNotAvailable):
+		status, code, message = http.StatusConflict, "items_not_available", "order items are no longer in inventory"
+	default:
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+		log.Error("Order operation failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Order operation failed", sl.Err(err))
+	}
+
+	renderErrorWithCode(w, r, status, code, message)
+}
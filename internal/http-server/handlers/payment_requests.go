@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	req "avito-shop/internal/http-server/dto/request"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+const paymentRequestIdParam = "requestId"
+
+type PaymentRequests interface {
+	Create(
+		ctx context.Context, requester string, payer string, amount int, message string) (*model.PaymentRequest, error)
+	List(ctx context.Context, username string, filter model.PaymentRequestFilter) ([]model.PaymentRequest, error)
+	Accept(ctx context.Context, username string, requestId uuid.UUID) (*model.PaymentRequest, error)
+	Decline(ctx context.Context, username string, requestId uuid.UUID) (*model.PaymentRequest, error)
+}
+
+func NewCreatePaymentRequestHandlerFunc(
+	log *slog.Logger, paymentRequestService PaymentRequests, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewCreatePaymentRequestHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		var request req.PaymentRequestRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		paymentRequest, err := paymentRequestService.Create(
+			r.Context(), claims.Username, request.FromUser, request.Amount, request.Message)
+		if err != nil {
+			handlePaymentRequestError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dto.ToPaymentRequestResponse(*paymentRequest))
+	}
+}
+
+func NewListPaymentRequestsHandlerFunc(log *slog.Logger, paymentRequestService PaymentRequests) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewListPaymentRequestsHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		filter := model.PaymentRequestFilter{
+			Direction: model.PaymentRequestDirection(r.URL.Query().Get("direction")),
+			Status:    model.PaymentRequestStatus(r.URL.Query().Get("status")),
+		}
+
+		requests, err := paymentRequestService.List(r.Context(), claims.Username, filter)
+		if err != nil {
+			handlePaymentRequestError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToPaymentRequestsResponse(requests))
+	}
+}
+
+func NewAcceptPaymentRequestHandlerFunc(log *slog.Logger, paymentRequestService PaymentRequests) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewAcceptPaymentRequestHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		requestId, ok := getPaymentRequestId(w, r, log)
+		if !ok {
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		paymentRequest, err := paymentRequestService.Accept(r.Context(), claims.Username, requestId)
+		if err != nil {
+			handlePaymentRequestError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToPaymentRequestResponse(*paymentRequest))
+	}
+}
+
+func NewDeclinePaymentRequestHandlerFunc(log *slog.Logger, paymentRequestService PaymentRequests) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewDeclinePaymentRequestHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		requestId, ok := getPaymentRequestId(w, r, log)
+		if !ok {
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		paymentRequest, err := paymentRequestService.Decline(r.Context(), claims.Username, requestId)
+		if err != nil {
+			handlePaymentRequestError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToPaymentRequestResponse(*paymentRequest))
+	}
+}
+
+func getPaymentRequestId(w http.ResponseWriter, r *http.Request, log *slog.Logger) (uuid.UUID, bool) {
+	value, ok := getURLParam(r, paymentRequestIdParam, log)
+	if !ok {
+		renderError(w, r, http.StatusBadRequest, "empty request id")
+		return uuid.Nil, false
+	}
+
+	requestId, err := uuid.Parse(value)
+	if err != nil {
+		log.Info("Invalid payment request id", sl.Err(err))
+		renderError(w, r, http.StatusBadRequest, "invalid request id")
+		return uuid.Nil, false
+	}
+
+	return requestId, true
+}
+
+func handlePaymentRequestError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	if renderTransferLimitError(w, r, log, err) || renderAccountStatusError(w, r, log, err) {
+		return
+	}
+
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrEmployeeNotFound):
+		status, code, message = http.StatusUnauthorized, "employee_not_found", "employee not found"
+	case errors.Is(err, service.ErrPaymentRequestNotFound):
+		status, code, message = http.StatusNotFound, "request_not_found", "payment request not found"
+	case errors.Is(err, service.ErrPaymentRequestNotPending):
+		status, code, message = http.StatusConflict, "request_not_pending", "payment request is no longer pending"
+	case errors.Is(err, service.ErrPaymentRequestExpired):
+		status, code, message = http.StatusConflict, "request_expired", "payment request expired"
+	case errors.Is(err, service.ErrPayerNotFound):
+		status, code, message = http.StatusBadRequest, "payer_not_found", "payer not found"
+	case errors.Is(err, service.ErrInvalidRequestFilter):
+		status, code, message = http.StatusBadRequest, "invalid_filter", "invalid direction or status"
+	case errors.Is(err, service.ErrTransferToSameEmployee):
+		status, code, message = http.StatusBadRequest, "same_employee", "can't request coins from yourself"
+	case errors.Is(err, service.ErrNonPositiveTransferAmount):
+		status, code, message = http.StatusBadRequest, "non_positive_amount", "amount must be positive"
+	case errors.Is(err, service.ErrTransferMessageTooLong):
+		status, code, message = http.StatusBadRequest, "message_too_long", "message is too long"
+	case errors.Is(err, service.ErrNotEnoughCoins):
+		status, code, message = http.StatusBadRequest, "not_enough_coins", "not enough coins"
+	default:
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+		log.Error("Payment request operation failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Payment request operation failed", sl.Err(err))
+	}
+
+	renderErrorWithCode(w, r, status, code, message)
+}
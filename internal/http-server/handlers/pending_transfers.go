@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+const pendingTransferIdParam = "transferId"
+
+type PendingTransfers interface {
+	ListPending(ctx context.Context, approver string) ([]model.PendingTransfer, error)
+	ApprovePending(ctx context.Context, approver string, transferId uuid.UUID) (*model.PendingTransfer, error)
+	RejectPending(ctx context.Context, approver string, transferId uuid.UUID) (*model.PendingTransfer, error)
+}
+
+func NewListPendingTransfersHandlerFunc(log *slog.Logger, transferService PendingTransfers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewListPendingTransfersHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		transfers, err := transferService.ListPending(r.Context(), claims.Username)
+		if err != nil {
+			handlePendingTransferError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToPendingTransfersResponse(transfers))
+	}
+}
+
+func NewApprovePendingTransferHandlerFunc(log *slog.Logger, transferService PendingTransfers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewApprovePendingTransferHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		transferId, ok := getPendingTransferId(w, r, log)
+		if !ok {
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		transfer, err := transferService.ApprovePending(r.Context(), claims.Username, transferId)
+		if err != nil {
+			handlePendingTransferError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToPendingTransferResponse(*transfer))
+	}
+}
+
+func NewRejectPendingTransferHandlerFunc(log *slog.Logger, transferService PendingTransfers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewRejectPendingTransferHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		transferId, ok := getPendingTransferId(w, r, log)
+		if !ok {
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		transfer, err := transferService.RejectPending(r.Context(), claims.Username, transferId)
+		if err != nil {
+			handlePendingTransferError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToPendingTransferResponse(*transfer))
+	}
+}
+
+func getPendingTransferId(w http.ResponseWriter, r *http.Request, log *slog.Logger) (uuid.UUID, bool) {
+	value, ok := getURLParam(r, pendingTransferIdParam, log)
+	if !ok {
+		renderError(w, r, http.StatusBadRequest, "empty transfer id")
+		return uuid.Nil, false
+	}
+
+	transferId, err := uuid.Parse(value)
+	if err != nil {
+		log.Info("Invalid pending transfer id", sl.Err(err))
+		renderError(w, r, http.StatusBadRequest, "invalid transfer id")
+		return uuid.Nil, false
+	}
+
+	return transferId, true
+}
+
+func handlePendingTransferError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	if renderTransferLimitError(w, r, log, err) || renderAccountStatusError(w, r, log, err) {
+		return
+	}
+
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrEmployeeNotFound):
+		status, code, message = http.StatusUnauthorized, "employee_not_found", "employee not found"
+	case errors.Is(err, service.ErrPendingTransferNotFound):
+		status, code, message = http.StatusNotFound, "transfer_not_found", "pending transfer not found"
+	case errors.Is(err, service.ErrPendingTransferNotPending):
+		status, code, message = http.StatusConflict, "transfer_not_pending", "transfer is already resolved"
+	case errors.Is(err, service.ErrNotApprover):
+		status, code, message = http.StatusForbidden, "not_approver", "only an admin or the sender's manager can decide"
+	case errors.Is(err, service.ErrNotEnoughCoins):
+		status, code, message = http.StatusBadRequest, "not_enough_coins", "not enough coins"
+	default:
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+		log.Error("Pending transfer operation failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Pending transfer operation failed", sl.Err(err))
+	}
+
+	renderErrorWithCode(w, r, status, code, message)
+}
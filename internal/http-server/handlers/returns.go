@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	req "avito-shop/internal/http-server/dto/request"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type Returns interface {
+	Return(ctx context.Context, username string, purchaseId uuid.UUID, quantity int) (*model.PurchaseReturn, error)
+}
+
+func NewReturnHandlerFunc(log *slog.Logger, returnService Returns, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewReturnHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		var request req.ReturnRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		purchaseReturn, err := returnService.Return(
+			r.Context(), claims.Username, uuid.MustParse(request.PurchaseId), request.Quantity)
+		if err != nil {
+			handleReturnError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dto.ToReturnResponse(*purchaseReturn))
+	}
+}
+
+func handleReturnError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrPurchaseNotFound):
+		status, code, message = http.StatusNotFound, "purchase_not_found", "purchase not found"
+	case errors.Is(err, service.ErrInvalidQuantity):
+		status, code, message = http.StatusBadRequest, "invalid_quantity", "item quantity must be positive"
+	case errors.Is(err, service.ErrOrderNotDelivered):
+		status, code, message = http.StatusConflict, "order_not_delivered",
+			"order is not delivered yet, cancel it instead"
+	case errors.Is(err, service.ErrOrderItemsNotAvailable):
+		status, code, message = http.StatusConflict, "items_not_available", "items are no longer in inventory"
+	case errors.Is(err, service.ErrItemNotReturnable):
+		status, code, message = http.StatusUnprocessableEntity, "item_not_returnable", "item cannot be returned"
+	case errors.Is(err, service.ErrReturnWindowExpired):
+		status, code, message = http.StatusUnprocessableEntity, "return_window_expired", "return window expired"
+	case errors.Is(err, service.ErrReturnQuantityExceeded):
+		status, code, message = http.StatusUnprocessableEntity, "return_quantity_exceeded",
+			"return quantity exceeds purchased quantity"
+	default:
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+		log.Error("Return failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Return failed", sl.Err(err))
+	}
+
+	renderErrorWithCode(w, r, status, code, message)
+}
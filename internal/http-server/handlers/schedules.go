@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	req "avito-shop/internal/http-server/dto/request"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const scheduleIdParam = "scheduleId"
+
+type Scheduler interface {
+	Create(ctx context.Context, fromUser string, toUser string, amount int, runAt time.Time,
+		recurrence model.Recurrence, memo model.TransferMemo) (*model.ScheduledTransfer, error)
+	List(ctx context.Context, username string) ([]model.ScheduledTransfer, error)
+	Cancel(ctx context.Context, username string, scheduleId uuid.UUID) (*model.ScheduledTransfer, error)
+	Runs(ctx context.Context, username string, scheduleId uuid.UUID) ([]model.ScheduledTransferRun, error)
+}
+
+func NewCreateScheduleHandlerFunc(
+	log *slog.Logger, scheduleService Scheduler, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewCreateScheduleHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		var request req.ScheduleRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		memo := model.TransferMemo{Message: request.Message, Category: model.TransferCategory(request.Category)}
+		schedule, err := scheduleService.Create(r.Context(), claims.Username, request.ToUser, request.Amount,
+			request.RunAt, model.Recurrence(request.Recurrence), memo)
+		if err != nil {
+			handleScheduleError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dto.ToScheduleResponse(*schedule))
+	}
+}
+
+func NewListSchedulesHandlerFunc(log *slog.Logger, scheduleService Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewListSchedulesHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		schedules, err := scheduleService.List(r.Context(), claims.Username)
+		if err != nil {
+			handleScheduleError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToSchedulesResponse(schedules))
+	}
+}
+
+func NewCancelScheduleHandlerFunc(log *slog.Logger, scheduleService Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewCancelScheduleHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		scheduleId, ok := getScheduleId(w, r, log)
+		if !ok {
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		schedule, err := scheduleService.Cancel(r.Context(), claims.Username, scheduleId)
+		if err != nil {
+			handleScheduleError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToScheduleResponse(*schedule))
+	}
+}
+
+func NewScheduleRunsHandlerFunc(log *slog.Logger, scheduleService Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewScheduleRunsHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		scheduleId, ok := getScheduleId(w, r, log)
+		if !ok {
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		runs, err := scheduleService.Runs(r.Context(), claims.Username, scheduleId)
+		if err != nil {
+			handleScheduleError(w, r, log, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToScheduleRunsResponse(runs))
+	}
+}
+
+func getScheduleId(w http.ResponseWriter, r *http.Request, log *slog.Logger) (uuid.UUID, bool) {
+	value, ok := getURLParam(r, scheduleIdParam, log)
+	if !ok {
+		renderError(w, r, http.StatusBadRequest, "empty schedule id")
+		return uuid.Nil, false
+	}
+
+	scheduleId, err := uuid.Parse(value)
+	if err != nil {
+		log.Info("Invalid schedule id", sl.Err(err))
+		renderError(w, r, http.StatusBadRequest, "invalid schedule id")
+		return uuid.Nil, false
+	}
+
+	return scheduleId, true
+}
+
+func handleScheduleError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	var status int
+	var code, message string
+
+	switch {
+	case errors.Is(err, service.ErrEmployeeNotFound):
+		status, code, message = http.StatusUnauthorized, "employee_not_found", "employee not found"
+	case errors.Is(err, service.ErrScheduleNotFound):
+		status, code, message = http.StatusNotFound, "schedule_not_found", "schedule not found"
+	case errors.Is(err, service.ErrScheduleNotActive):
+		status, code, message = http.StatusConflict, "schedule_not_active", "schedule is no longer active"
+	case errors.Is(err, service.ErrScheduleInPast):
+		status, code, message = http.StatusBadRequest, "schedule_in_past", "runAt must be in the future"
+	case errors.Is(err, service.ErrInvalidRecurrence):
+		status, code, message = http.StatusBadRequest, "invalid_recurrence", "invalid recurrence"
+	case errors.Is(err, service.ErrTransferToSameEmployee):
+		status, code, message = http.StatusBadRequest, "same_employee", "can't send coins to yourself"
+	case errors.Is(err, service.ErrNonPositiveTransferAmount):
+		status, code, message = http.StatusBadRequest, "non_positive_amount", "amount must be positive"
+	case errors.Is(err, service.ErrTransferMessageTooLong):
+		status, code, message = http.StatusBadRequest, "message_too_long", "message is too long"
+	case errors.Is(err, service.ErrInvalidTransferCategory):
+		status, code, message = http.StatusBadRequest, "invalid_category", "invalid category"
+	case errors.Is(err, service.ErrReceiverNotFound):
+		status, code, message = http.StatusBadRequest, "receiver_not_found", "receiver not found"
+	default:
+		status, code, message = http.StatusInternalServerError, "", internalServerError
+		log.Error("Schedule operation failed", sl.Err(err))
+	}
+
+	if status != http.StatusInternalServerError {
+		log.Info("Schedule operation failed", sl.Err(err))
+	}
+
+	renderErrorWithCode(w, r, status, code, message)
+}
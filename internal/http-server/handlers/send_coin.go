@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"avito-shop/internal/http-server/dto"
 	req "avito-shop/internal/http-server/dto/request"
 	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
 	"avito-shop/internal/service"
 	"context"
 	"errors"
@@ -13,7 +15,9 @@ import (
 )
 
 type Transfer interface {
-	SendCoins(ctx context.Context, from string, to string, amount int) error
+	SendCoins(
+		ctx context.Context, from string, to string, amount int, memo model.TransferMemo,
+	) (*model.PendingTransfer, error)
 }
 
 func NewSendCoinsHandlerFunc(log *slog.Logger, transferService Transfer, vld *validator.Validate) http.HandlerFunc {
@@ -41,16 +45,81 @@ func NewSendCoinsHandlerFunc(log *slog.Logger, transferService Transfer, vld *va
 			return
 		}
 
-		if err := transferService.SendCoins(r.Context(), claims.Username, request.ToUser, request.Amount); err != nil {
+		memo := model.TransferMemo{Message: request.Message, Category: model.TransferCategory(request.Category)}
+
+		pending, err := transferService.SendCoins(r.Context(), claims.Username, request.ToUser, request.Amount, memo)
+		if err != nil {
+			handleTransferError(w, r, log, err)
+			return
+		}
+
+		if pending != nil {
+			render.Status(r, http.StatusAccepted)
+			render.JSON(w, r, dto.ToPendingTransferResponse(*pending))
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+	}
+}
+
+type BatchTransfer interface {
+	SendCoinsBatch(
+		ctx context.Context, from string, lines []model.TransferLine, memo model.TransferMemo,
+	) ([]model.TransferLine, error)
+}
+
+func NewSendCoinsBatchHandlerFunc(
+	log *slog.Logger, transferService BatchTransfer, vld *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.NewSendCoinsBatchHandlerFunc"
+		log = setupLogger(log, op, r)
+
+		var request req.SendCoinBatchRequest
+
+		if err := render.DecodeJSON(r.Body, &request); err != nil {
+			log.Error("Failed to parse request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if err := vld.Struct(request); err != nil {
+			log.Error("Invalid request", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		claims, ok := getClaimsFromContext(r, log)
+		if !ok {
+			renderError(w, r, http.StatusInternalServerError, internalServerError)
+			return
+		}
+
+		lines, err := dto.ToTransferLines(request)
+		if err != nil {
+			log.Info("Invalid split", sl.Err(err))
+			renderError(w, r, http.StatusBadRequest, "total amount is too small to split between recipients")
+			return
+		}
+
+		memo := model.TransferMemo{Message: request.Message, Category: model.TransferCategory(request.Category)}
+
+		lines, err = transferService.SendCoinsBatch(r.Context(), claims.Username, lines, memo)
+		if err != nil {
 			handleTransferError(w, r, log, err)
 			return
 		}
 
 		render.Status(r, http.StatusOK)
+		render.JSON(w, r, dto.ToSendCoinBatchResponse(lines))
 	}
 }
 
 func handleTransferError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	if renderTransferLimitError(w, r, log, err) || renderAccountStatusError(w, r, log, err) {
+		return
+	}
+
 	var status int
 	var message string
 
@@ -59,8 +128,14 @@ func handleTransferError(w http.ResponseWriter, r *http.Request, log *slog.Logge
 		status, message = http.StatusBadRequest, "can't send coins to yourself"
 	case errors.Is(err, service.ErrNotEnoughCoins):
 		status, message = http.StatusBadRequest, "not enough coins to send"
-	case errors.Is(err, service.ErrNegativeTransferAmount):
-		status, message = http.StatusBadRequest, "negative amount"
+	case errors.Is(err, service.ErrEmptyTransferBatch):
+		status, message = http.StatusBadRequest, "no recipients"
+	case errors.Is(err, service.ErrNonPositiveTransferAmount):
+		status, message = http.StatusBadRequest, "amount must be positive"
+	case errors.Is(err, service.ErrTransferMessageTooLong):
+		status, message = http.StatusBadRequest, "message is too long"
+	case errors.Is(err, service.ErrInvalidTransferCategory):
+		status, message = http.StatusBadRequest, "invalid category"
 	case errors.Is(err, service.ErrReceiverNotFound):
 		status, message = http.StatusBadRequest, "receiver not found"
 	case errors.Is(err, service.ErrSenderNotFound):
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+var errResponseNotStored = errors.New("response is not stored")
+
+type Idempotency interface {
+	Do(
+		ctx context.Context,
+		employeeId uuid.UUID,
+		key string,
+		fingerprint string,
+		fn func(ctx context.Context) (int, []byte, error),
+	) (*model.IdempotencyRecord, bool, error)
+}
+
+// NewIdempotency must be mounted after NewJwtAuth: keys are scoped to the authenticated employee.
+// Only successful responses are stored; failed requests roll back together with their key.
+func NewIdempotency(log *slog.Logger, idempotency Idempotency) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log = log.With(slog.String("component", "middleware/idempotency"))
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			requestId := middleware.GetReqID(r.Context())
+			const requestIdKey = "request_id"
+
+			if len(key) > maxIdempotencyKeyLength {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "idempotency key is too long"})
+				return
+			}
+
+			claims, ok := r.Context().Value(UserContextKey).(*service.TokenClaims)
+			if !ok || claims == nil {
+				log.Error("failed to get claims from context", slog.String(requestIdKey, requestId))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "internal server error"})
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				log.Error("failed to read request body", slog.String(requestIdKey, requestId), sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "failed to read request"})
+				return
+			}
+
+			var recorder *responseRecorder
+			record, replayed, err := idempotency.Do(r.Context(), claims.EmployeeId, key, fingerprint(r, body),
+				func(ctx context.Context) (int, []byte, error) {
+					recorder = newResponseRecorder()
+					req := r.WithContext(ctx)
+					req.Body = io.NopCloser(bytes.NewReader(body))
+
+					next.ServeHTTP(recorder, req)
+
+					if recorder.status >= http.StatusBadRequest {
+						return 0, nil, errResponseNotStored
+					}
+					return recorder.status, recorder.body.Bytes(), nil
+				})
+
+			switch {
+			case errors.Is(err, errResponseNotStored):
+				recorder.writeTo(w)
+			case errors.Is(err, service.ErrIdempotencyKeyReused):
+				log.Info("idempotency key reused", slog.String(requestIdKey, requestId), slog.String("key", key))
+
+				render.Status(r, http.StatusUnprocessableEntity)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "idempotency key reused with different request"})
+			case err != nil:
+				log.Error("idempotent request failed", slog.String(requestIdKey, requestId), sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "internal server error"})
+			case replayed:
+				log.Info("replaying stored response", slog.String(requestIdKey, requestId), slog.String("key", key))
+
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				writeStoredResponse(w, record)
+			default:
+				recorder.writeTo(w)
+			}
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func writeStoredResponse(w http.ResponseWriter, record *model.IdempotencyRecord) {
+	if len(record.ResponseBody) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(record.ResponseStatus)
+	_, _ = w.Write(record.ResponseBody)
+}
+
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: http.Header{}, status: http.StatusOK}
+}
+
+func (rr *responseRecorder) Header() http.Header {
+	return rr.header
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	return rr.body.Write(b)
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	rr.status = status
+}
+
+func (rr *responseRecorder) writeTo(w http.ResponseWriter) {
+	for key, values := range rr.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(rr.status)
+	_, _ = w.Write(rr.body.Bytes())
+}
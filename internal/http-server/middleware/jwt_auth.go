@@ -3,12 +3,14 @@ package middleware
 import (
 	"avito-shop/internal/http-server/dto/response"
 	"avito-shop/internal/lib/logger/sl"
+	"avito-shop/internal/model"
 	"avito-shop/internal/service"
 	"context"
 	"errors"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -19,7 +21,26 @@ type contextKey string
 
 const UserContextKey contextKey = "user"
 
-func NewJwtAuth(log *slog.Logger, signKey string) func(next http.Handler) http.Handler {
+type KeySource interface {
+	VerificationKey(token *jwt.Token) (interface{}, error)
+}
+
+type SessionChecker interface {
+	SessionActive(ctx context.Context, sessionId uuid.UUID) (bool, error)
+}
+
+type AccountChecker interface {
+	AccountStatus(ctx context.Context, employeeId uuid.UUID) (model.EmployeeStatus, error)
+}
+
+// NewJwtAuth verifies tokens with the key named by their kid header. Deactivated employees are rejected even with
+// a valid token; frozen ones pass, since they keep read access and the services refuse their writes.
+func NewJwtAuth(
+	log *slog.Logger,
+	keys KeySource,
+	sessions SessionChecker,
+	accounts AccountChecker,
+) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		log = log.With(slog.String("component", "middleware/jwt_auth"))
 
@@ -39,18 +60,7 @@ func NewJwtAuth(log *slog.Logger, signKey string) func(next http.Handler) http.H
 			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 			claims := &service.TokenClaims{}
 
-			token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					log.Error("unexpected signing method",
-						slog.String(requestIdKey, requestId),
-						slog.String("method", token.Method.Alg()),
-					)
-
-					return nil, errors.New("unexpected signing method")
-				}
-
-				return []byte(signKey), nil
-			})
+			token, err := jwt.ParseWithClaims(tokenString, claims, keys.VerificationKey)
 
 			if err != nil || !token.Valid {
 				log.Error("invalid token", slog.String(requestIdKey, requestId), sl.Err(err))
@@ -68,6 +78,48 @@ func NewJwtAuth(log *slog.Logger, signKey string) func(next http.Handler) http.H
 				return
 			}
 
+			active, err := sessions.SessionActive(r.Context(), claims.SessionId)
+			if err != nil {
+				log.Error("failed to check session", slog.String(requestIdKey, requestId), sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "internal error"})
+				return
+			}
+
+			if !active {
+				log.Error("revoked session", slog.String(requestIdKey, requestId))
+
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "session revoked"})
+				return
+			}
+
+			status, err := accounts.AccountStatus(r.Context(), claims.EmployeeId)
+			if err != nil {
+				if errors.Is(err, service.ErrEmployeeNotFound) {
+					log.Error("unknown employee", slog.String(requestIdKey, requestId))
+
+					render.Status(r, http.StatusUnauthorized)
+					render.JSON(w, r, &response.ErrorResponse{Errors: "invalid token"})
+					return
+				}
+
+				log.Error("failed to check account", slog.String(requestIdKey, requestId), sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "internal error"})
+				return
+			}
+
+			if status == model.EmployeeDeactivated {
+				log.Error("deactivated account", slog.String(requestIdKey, requestId))
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "account is deactivated"})
+				return
+			}
+
 			log.Info("successful authentication",
 				slog.String("user_id", claims.Username),
 				slog.String(requestIdKey, requestId),
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+// NewRequireRole must be mounted after NewJwtAuth.
+func NewRequireRole(log *slog.Logger, roles ...model.Role) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log = log.With(slog.String("component", "middleware/require_role"))
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			requestId := middleware.GetReqID(r.Context())
+			const requestIdKey = "request_id"
+
+			claims, ok := r.Context().Value(UserContextKey).(*service.TokenClaims)
+			if !ok || claims == nil {
+				log.Error("failed to get claims from context", slog.String(requestIdKey, requestId))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "internal server error"})
+				return
+			}
+
+			if !slices.Contains(roles, claims.Role) {
+				log.Info("insufficient role",
+					slog.String("user_id", claims.Username),
+					slog.String("role", string(claims.Role)),
+					slog.String(requestIdKey, requestId),
+				)
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, &response.ErrorResponse{Errors: "forbidden"})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,177 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	keyFileExt    = ".pem"
+	minRSAKeyBits = 2048
+)
+
+var (
+	ErrMissingKeyId   = errors.New("token has no kid header")
+	ErrUnknownKey     = errors.New("unknown signing key")
+	ErrAlgMismatch    = errors.New("token algorithm does not match the key")
+	ErrUnsupportedKey = errors.New("unsupported key type")
+	ErrNoSigningKey   = errors.New("no private key to sign with")
+)
+
+// Key is a signing key identified by kid. Private is nil for retiring keys that only verify tokens.
+type Key struct {
+	Id      string
+	Method  jwt.SigningMethod
+	Private crypto.PrivateKey
+	Public  crypto.PublicKey
+}
+
+// KeySet signs tokens with one key and verifies tokens signed by any of its keys.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+func NewKeySet(signingKeyId string, keys ...Key) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*Key, len(keys))}
+	for i := range keys {
+		if _, ok := set.keys[keys[i].Id]; ok {
+			return nil, fmt.Errorf("duplicate key %q", keys[i].Id)
+		}
+		set.keys[keys[i].Id] = &keys[i]
+	}
+
+	signing, ok := set.keys[signingKeyId]
+	if !ok {
+		return nil, fmt.Errorf("%w: signing key %q not found", ErrNoSigningKey, signingKeyId)
+	}
+	if signing.Private == nil {
+		return nil, fmt.Errorf("%w: signing key %q has no private part", ErrNoSigningKey, signingKeyId)
+	}
+	set.signing = signing
+
+	return set, nil
+}
+
+// LoadDir reads every <kid>.pem file in dir. A file holding a private key makes a key that can sign;
+// a file holding only a public key makes a key that still verifies tokens during rotation. A missing dir
+// is reported as ErrNoSigningKey.
+func LoadDir(dir string, signingKeyId string) (*KeySet, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %w", ErrNoSigningKey, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []Key
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != keyFileExt {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ParseKey(strings.TrimSuffix(entry.Name(), keyFileExt), data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(signingKeyId, keys...)
+}
+
+// ParseKey accepts PKCS#8 and PKCS#1 private keys and PKIX public keys, RSA or Ed25519.
+func ParseKey(id string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return Key{}, fmt.Errorf("rsa key is shorter than %d bits", minRSAKeyBits)
+		}
+		return Key{Id: id, Method: jwt.SigningMethodRS256, Private: k, Public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return Key{}, fmt.Errorf("rsa key is shorter than %d bits", minRSAKeyBits)
+		}
+		return Key{Id: id, Method: jwt.SigningMethodRS256, Public: k}, nil
+	case ed25519.PrivateKey:
+		return Key{Id: id, Method: jwt.SigningMethodEdDSA, Private: k, Public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return Key{Id: id, Method: jwt.SigningMethodEdDSA, Public: k}, nil
+	default:
+		return Key{}, ErrUnsupportedKey
+	}
+}
+
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signing.Method, claims)
+	token.Header["kid"] = s.signing.Id
+	return token.SignedString(s.signing.Private)
+}
+
+// VerificationKey is a jwt.Keyfunc. Checking the algorithm against the key stops a token from choosing
+// how its signature is checked.
+func (s *KeySet) VerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrMissingKeyId
+	}
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrAlgMismatch
+	}
+
+	return key.Public, nil
+}
+
+// PublicKeys returns the keys ordered by kid, for publishing in JWKS.
+func (s *KeySet) PublicKeys() []Key {
+	keys := make([]Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, Key{Id: key.Id, Method: key.Method, Public: key.Public})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Id < keys[j].Id })
+	return keys
+}
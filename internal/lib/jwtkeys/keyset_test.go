@@ -0,0 +1,120 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edPrivate)
+	require.NoError(t, err)
+	writePEM(t, dir, "2026-10.pem", "PRIVATE KEY", edDER)
+
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaDER, err := x509.MarshalPKIXPublicKey(&rsaPrivate.PublicKey)
+	require.NoError(t, err)
+	writePEM(t, dir, "2026-04.pem", "PUBLIC KEY", rsaDER)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("not a key"), 0o600))
+
+	keys, err := LoadDir(dir, "2026-10")
+	require.NoError(t, err)
+
+	public := keys.PublicKeys()
+	require.Len(t, public, 2)
+	assert.Equal(t, "2026-04", public[0].Id)
+	assert.Equal(t, jwt.SigningMethodRS256, public[0].Method)
+	assert.Nil(t, public[0].Private)
+	assert.Equal(t, "2026-10", public[1].Id)
+	assert.Equal(t, jwt.SigningMethodEdDSA, public[1].Method)
+
+	t.Run("retiring key can't sign", func(t *testing.T) {
+		_, err := LoadDir(dir, "2026-04")
+		assert.ErrorIs(t, err, ErrNoSigningKey)
+	})
+
+	t.Run("unknown signing key", func(t *testing.T) {
+		_, err := LoadDir(dir, "2027-01")
+		assert.ErrorIs(t, err, ErrNoSigningKey)
+	})
+
+	t.Run("missing dir", func(t *testing.T) {
+		_, err := LoadDir(filepath.Join(dir, "missing"), "2026-10")
+		assert.ErrorIs(t, err, ErrNoSigningKey)
+	})
+}
+
+func TestParseKey_RejectsShortRSAKey(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+	_, err = ParseKey("short", data)
+	assert.Error(t, err)
+}
+
+func TestKeySet_SignAndVerify(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	claims := &jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()}
+
+	oldKeys, err := NewKeySet("old", Key{Id: "old", Method: jwt.SigningMethodRS256,
+		Private: rsaPrivate, Public: &rsaPrivate.PublicKey})
+	require.NoError(t, err)
+	oldToken, err := oldKeys.Sign(claims)
+	require.NoError(t, err)
+
+	keys, err := NewKeySet("new",
+		Key{Id: "new", Method: jwt.SigningMethodEdDSA, Private: private, Public: public},
+		Key{Id: "old", Method: jwt.SigningMethodRS256, Public: &rsaPrivate.PublicKey})
+	require.NoError(t, err)
+	newToken, err := keys.Sign(claims)
+	require.NoError(t, err)
+
+	for _, tokenString := range []string{newToken, oldToken} {
+		token, err := jwt.ParseWithClaims(tokenString, &jwt.StandardClaims{}, keys.VerificationKey)
+		require.NoError(t, err)
+		assert.True(t, token.Valid)
+	}
+
+	t.Run("missing kid", func(t *testing.T) {
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(private)
+		require.NoError(t, err)
+
+		_, err = jwt.ParseWithClaims(tokenString, &jwt.StandardClaims{}, keys.VerificationKey)
+		assert.ErrorIs(t, err.(*jwt.ValidationError).Inner, ErrMissingKeyId)
+	})
+
+	t.Run("algorithm mismatch", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "new"
+		tokenString, err := token.SignedString(rsaPrivate)
+		require.NoError(t, err)
+
+		_, err = jwt.ParseWithClaims(tokenString, &jwt.StandardClaims{}, keys.VerificationKey)
+		assert.ErrorIs(t, err.(*jwt.ValidationError).Inner, ErrAlgMismatch)
+	})
+}
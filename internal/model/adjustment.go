@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const MaxAdjustmentReasonLength = 500
+
+// BalanceAdjustment is a manual correction of an employee's balance made by an admin. A negative Amount takes
+// coins away. Balance is the employee's balance right after the adjustment.
+type BalanceAdjustment struct {
+	Id         uuid.UUID
+	EmployeeId uuid.UUID
+	Employee   string
+	AdminId    uuid.UUID
+	Amount     int
+	Reason     string
+	Balance    int
+	CreatedAt  time.Time
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type AllowancePeriod string
+
+const (
+	AllowanceDaily   AllowancePeriod = "daily"
+	AllowanceWeekly  AllowancePeriod = "weekly"
+	AllowanceMonthly AllowancePeriod = "monthly"
+)
+
+func (p AllowancePeriod) Valid() bool {
+	switch p {
+	case AllowanceDaily, AllowanceWeekly, AllowanceMonthly:
+		return true
+	}
+	return false
+}
+
+// Key names the period that contains t, such as 2026-10 for a monthly allowance or 2026-W42 for a weekly one.
+// Periods are counted in UTC so that every replica agrees on them.
+func (p AllowancePeriod) Key(t time.Time) string {
+	t = t.UTC()
+	switch p {
+	case AllowanceDaily:
+		return t.Format(time.DateOnly)
+	case AllowanceWeekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return t.Format("2006-01")
+}
+
+// AllowancePolicy credits Amount coins once per Period. A positive MaxBalance caps the balance an allowance
+// may top an employee up to.
+type AllowancePolicy struct {
+	Amount     int
+	MaxBalance int
+	Period     AllowancePeriod
+}
+
+func (p AllowancePolicy) Enabled() bool {
+	return p.Amount > 0
+}
+
+// Allowance records that an employee was processed for a period. Amount is zero when the employee was already
+// at the balance cap.
+type Allowance struct {
+	Id         uuid.UUID
+	EmployeeId uuid.UUID
+	Period     string
+	Amount     int
+	CreatedAt  time.Time
+}
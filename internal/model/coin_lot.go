@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// CoinLot is a batch of coins an employee received at GrantedAt. Coins are spent oldest lot first and expire with
+// their lot; coins passed on to another employee keep the grant date of the lot they were taken from.
+type CoinLot struct {
+	Id         uuid.UUID
+	EmployeeId uuid.UUID
+	Amount     int
+	Remaining  int
+	GrantedAt  time.Time
+}
+
+// SpentCoins are the coins an operation took from one lot. Refunds and returns of the operation give them back with
+// their original grant date, so getting coins back doesn't restart their lifetime.
+type SpentCoins struct {
+	Id          uuid.UUID
+	OperationId uuid.UUID
+	Amount      int
+	Remaining   int
+	GrantedAt   time.Time
+}
+
+// CoinExpiryPolicy expires coins TTL after they were granted and reports them Notice ahead of time.
+// A zero TTL disables expiry.
+type CoinExpiryPolicy struct {
+	TTL    time.Duration
+	Notice time.Duration
+}
+
+func (p CoinExpiryPolicy) Enabled() bool {
+	return p.TTL > 0
+}
+
+type ExpiringCoins struct {
+	Amount    int
+	ExpiresAt time.Time
+}
@@ -0,0 +1,11 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Cursor struct {
+	CreatedAt time.Time
+	Id        uuid.UUID
+}
@@ -2,9 +2,41 @@ package model
 
 import "github.com/google/uuid"
 
+type Role string
+
+const (
+	RoleEmployee Role = "employee"
+	RoleAdmin    Role = "admin"
+)
+
+type EmployeeStatus string
+
+const (
+	EmployeeActive      EmployeeStatus = "active"
+	EmployeeFrozen      EmployeeStatus = "frozen"
+	EmployeeDeactivated EmployeeStatus = "deactivated"
+)
+
+func (s EmployeeStatus) Valid() bool {
+	switch s {
+	case EmployeeActive, EmployeeFrozen, EmployeeDeactivated:
+		return true
+	}
+	return false
+}
+
+// Employee.Balance includes Held: coins reserved by transfers waiting for approval, which can't be spent.
 type Employee struct {
 	Id           uuid.UUID
 	Username     string
 	Balance      int
+	Held         int
 	PasswordHash string
+	Role         Role
+	ManagerId    *uuid.UUID
+	Status       EmployeeStatus
+}
+
+func (e *Employee) Available() int {
+	return e.Balance - e.Held
 }
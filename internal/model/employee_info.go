@@ -1,7 +1,12 @@
 package model
 
+// EmployeeInfo.Coins is the whole balance; Held of it is reserved by transfers waiting for approval.
+// Expiring lists the coins that expire soon, earliest first.
 type EmployeeInfo struct {
 	Coins       int
+	Available   int
+	Held        int
+	Expiring    []ExpiringCoins
 	Inventory   []InventoryItem
 	CoinHistory CoinHistory
 }
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Gift struct {
+	Id           uuid.UUID
+	FromEmployee uuid.UUID
+	ToEmployee   uuid.UUID
+	Receiver     string
+	ItemId       uuid.UUID
+	Item         string
+	Quantity     int
+	CreatedAt    time.Time
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type HistoryEntryType string
+
+const (
+	HistoryTransfer   HistoryEntryType = "transfer"
+	HistoryPurchase   HistoryEntryType = "purchase"
+	HistoryRefund     HistoryEntryType = "refund"
+	HistoryReturn     HistoryEntryType = "return"
+	HistoryGift       HistoryEntryType = "gift"
+	HistorySale       HistoryEntryType = "sale"
+	HistoryAllowance  HistoryEntryType = "allowance"
+	HistoryExpiry     HistoryEntryType = "expiry"
+	HistoryAdjustment HistoryEntryType = "adjustment"
+	HistorySweep      HistoryEntryType = "sweep"
+)
+
+type HistoryDirection string
+
+const (
+	DirectionIn  HistoryDirection = "in"
+	DirectionOut HistoryDirection = "out"
+)
+
+type HistoryEntry struct {
+	Id           uuid.UUID
+	Type         HistoryEntryType
+	Direction    HistoryDirection
+	Counterparty string
+	Item         string
+	Quantity     int
+	Amount       int
+	CreatedAt    time.Time
+	Message      string
+	Category     TransferCategory
+}
+
+type HistoryFilter struct {
+	Direction    HistoryDirection
+	Counterparty string
+	From         *time.Time
+	To           *time.Time
+	Cursor       *Cursor
+	Limit        int
+}
+
+type HistoryPage struct {
+	Entries    []HistoryEntry
+	NextCursor *Cursor
+}
@@ -0,0 +1,11 @@
+package model
+
+import "github.com/google/uuid"
+
+type IdempotencyRecord struct {
+	EmployeeId     uuid.UUID
+	Key            string
+	Fingerprint    string
+	ResponseStatus int
+	ResponseBody   []byte
+}
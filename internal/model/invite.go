@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type RegistrationMode string
+
+const (
+	// RegistrationAuto creates an account the first time an unknown username logs in.
+	RegistrationAuto       RegistrationMode = "auto"
+	RegistrationExplicit   RegistrationMode = "explicit"
+	RegistrationInviteOnly RegistrationMode = "invite-only"
+)
+
+func (m RegistrationMode) Valid() bool {
+	switch m {
+	case RegistrationAuto, RegistrationExplicit, RegistrationInviteOnly:
+		return true
+	}
+	return false
+}
+
+// Invite is a single-use registration code issued by an admin. UsedBy is set once somebody registers with it.
+type Invite struct {
+	Id        uuid.UUID
+	Code      string
+	CreatedBy uuid.UUID
+	UsedBy    *uuid.UUID
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (i *Invite) Expired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
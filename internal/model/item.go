@@ -3,7 +3,46 @@ package model
 import "github.com/google/uuid"
 
 type Item struct {
-	Id    uuid.UUID
-	Name  string
-	Price int
+	Id             uuid.UUID
+	Name           string
+	Price          int
+	Retired        bool
+	Stock          *int
+	MaxPerEmployee *int
+	NonReturnable  bool
+}
+
+type ItemUpdate struct {
+	Name           *string
+	Price          *int
+	Stock          *int
+	MaxPerEmployee *int
+	NonReturnable  *bool
+}
+
+type ItemSortField string
+
+const (
+	ItemSortName  ItemSortField = "name"
+	ItemSortPrice ItemSortField = "price"
+)
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+type ItemFilter struct {
+	SortBy   ItemSortField
+	Order    SortOrder
+	MaxPrice *int
+}
+
+type CatalogItem struct {
+	Name      string
+	Price     int
+	Stock     *int
+	Available bool
 }
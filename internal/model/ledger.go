@@ -0,0 +1,54 @@
+package model
+
+import "github.com/google/uuid"
+
+type OperationType string
+
+const (
+	OperationTransfer     OperationType = "transfer"
+	OperationPurchase     OperationType = "purchase"
+	OperationRefund       OperationType = "refund"
+	OperationReturn       OperationType = "return"
+	OperationSale         OperationType = "sale"
+	OperationInitialGrant OperationType = "initial_grant"
+	OperationAdjustment   OperationType = "adjustment"
+	OperationAllowance    OperationType = "allowance"
+	OperationExpiry       OperationType = "expiry"
+	OperationSweep        OperationType = "sweep"
+)
+
+type LedgerAccount string
+
+const (
+	AccountEmployee   LedgerAccount = "employee"
+	AccountShop       LedgerAccount = "shop"
+	AccountEmission   LedgerAccount = "emission"
+	AccountAdjustment LedgerAccount = "adjustment"
+	AccountExpiry     LedgerAccount = "expiry"
+	AccountPool       LedgerAccount = "company_pool"
+)
+
+type EntryDirection string
+
+const (
+	Debit  EntryDirection = "debit"
+	Credit EntryDirection = "credit"
+)
+
+type LedgerEntry struct {
+	Id            uuid.UUID
+	OperationId   uuid.UUID
+	OperationType OperationType
+	Account       LedgerAccount
+	EmployeeId    *uuid.UUID
+	Direction     EntryDirection
+	Amount        int
+}
+
+// LedgerMismatch is an employee whose stored balance differs from the sum of their ledger entries.
+type LedgerMismatch struct {
+	EmployeeId    uuid.UUID
+	Username      string
+	Balance       int
+	LedgerBalance int
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ListingStatus string
+
+const (
+	ListingActive    ListingStatus = "active"
+	ListingSold      ListingStatus = "sold"
+	ListingCancelled ListingStatus = "cancelled"
+	ListingExpired   ListingStatus = "expired"
+)
+
+// Listing offers Quantity units of an item for Price coins in total; the units stay in escrow until it closes.
+type Listing struct {
+	Id        uuid.UUID
+	SellerId  uuid.UUID
+	Seller    string
+	ItemId    uuid.UUID
+	Item      string
+	Quantity  int
+	Price     int
+	Status    ListingStatus
+	BuyerId   *uuid.UUID
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+type ListingFilter struct {
+	Item string
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderPlaced         OrderStatus = "placed"
+	OrderReadyForPickup OrderStatus = "ready_for_pickup"
+	OrderDelivered      OrderStatus = "delivered"
+	OrderCancelled      OrderStatus = "cancelled"
+)
+
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderPlaced:         {OrderReadyForPickup, OrderCancelled},
+	OrderReadyForPickup: {OrderDelivered, OrderCancelled},
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	return slices.Contains(orderTransitions[s], next)
+}
+
+func (s OrderStatus) Valid() bool {
+	switch s {
+	case OrderPlaced, OrderReadyForPickup, OrderDelivered, OrderCancelled:
+		return true
+	}
+	return false
+}
+
+type OrderLine struct {
+	Item     string
+	Quantity int
+}
+
+type OrderItem struct {
+	PurchaseId uuid.UUID
+	ItemId     uuid.UUID
+	Item       string
+	Quantity   int
+	Returned   int
+	Price      int
+}
+
+func (i OrderItem) Amount() int {
+	return i.Price * i.Quantity
+}
+
+// Order.DeliveredAt is set once the order is handed out; the return window starts then.
+type Order struct {
+	Id          uuid.UUID
+	EmployeeId  uuid.UUID
+	Employee    string
+	Status      OrderStatus
+	Items       []OrderItem
+	Total       int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeliveredAt *time.Time
+}
+
+type OrderFilter struct {
+	EmployeeId *uuid.UUID
+	Status     OrderStatus
+}
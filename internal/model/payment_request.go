@@ -0,0 +1,56 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type PaymentRequestStatus string
+
+// PaymentRequestExpired is never stored: a pending request past its ExpiresAt is reported as expired.
+const (
+	PaymentRequestPending  PaymentRequestStatus = "pending"
+	PaymentRequestAccepted PaymentRequestStatus = "accepted"
+	PaymentRequestDeclined PaymentRequestStatus = "declined"
+	PaymentRequestExpired  PaymentRequestStatus = "expired"
+)
+
+func (s PaymentRequestStatus) Valid() bool {
+	switch s {
+	case PaymentRequestPending, PaymentRequestAccepted, PaymentRequestDeclined, PaymentRequestExpired:
+		return true
+	}
+	return false
+}
+
+// PaymentRequest asks Payer to send Amount coins to Requester.
+type PaymentRequest struct {
+	Id          uuid.UUID
+	RequesterId uuid.UUID
+	Requester   string
+	PayerId     uuid.UUID
+	Payer       string
+	Amount      int
+	Message     string
+	Status      PaymentRequestStatus
+	ExpiresAt   time.Time
+	ResolvedAt  *time.Time
+	CreatedAt   time.Time
+}
+
+type PaymentRequestDirection string
+
+const (
+	PaymentRequestsIncoming PaymentRequestDirection = "incoming"
+	PaymentRequestsOutgoing PaymentRequestDirection = "outgoing"
+)
+
+func (d PaymentRequestDirection) Valid() bool {
+	return d == PaymentRequestsIncoming || d == PaymentRequestsOutgoing
+}
+
+// PaymentRequestFilter lists the requests addressed to the employee (incoming) or made by them (outgoing).
+type PaymentRequestFilter struct {
+	Direction PaymentRequestDirection
+	Status    PaymentRequestStatus
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type PendingTransferStatus string
+
+const (
+	PendingTransferPending  PendingTransferStatus = "pending"
+	PendingTransferApproved PendingTransferStatus = "approved"
+	PendingTransferRejected PendingTransferStatus = "rejected"
+)
+
+// PendingTransfer is a transfer above the approval threshold. Its amount stays held on the sender
+// until an admin or the sender's manager approves or rejects it.
+type PendingTransfer struct {
+	Id           uuid.UUID
+	FromEmployee uuid.UUID
+	From         string
+	ToEmployee   uuid.UUID
+	To           string
+	Amount       int
+	Message      string
+	Category     TransferCategory
+	Status       PendingTransferStatus
+	ApproverId   *uuid.UUID
+	CreatedAt    time.Time
+	ResolvedAt   *time.Time
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Purchase struct {
+	Id               uuid.UUID
+	OrderId          uuid.UUID
+	EmployeeId       uuid.UUID
+	ItemId           uuid.UUID
+	Quantity         int
+	ReturnedQuantity int
+	Price            int
+	CreatedAt        time.Time
+}
+
+type PurchaseReturn struct {
+	Id         uuid.UUID
+	PurchaseId uuid.UUID
+	EmployeeId uuid.UUID
+	Item       string
+	Quantity   int
+	Amount     int
+	CreatedAt  time.Time
+}
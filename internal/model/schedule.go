@@ -0,0 +1,84 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Recurrence string
+
+const (
+	RecurrenceOnce    Recurrence = "once"
+	RecurrenceWeekly  Recurrence = "weekly"
+	RecurrenceMonthly Recurrence = "monthly"
+)
+
+func (r Recurrence) Valid() bool {
+	switch r {
+	case RecurrenceOnce, RecurrenceWeekly, RecurrenceMonthly:
+		return true
+	}
+	return false
+}
+
+// At returns the n-th occurrence counted from start, starting at zero. Monthly occurrences keep the day of month
+// of start and fall back to the last day of shorter months, so a schedule started on the 31st never drifts.
+func (r Recurrence) At(start time.Time, n int) time.Time {
+	switch r {
+	case RecurrenceWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case RecurrenceMonthly:
+		firstOfMonth := time.Date(start.Year(), start.Month()+time.Month(n), 1,
+			start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+		lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+		return firstOfMonth.AddDate(0, 0, min(start.Day(), lastDay)-1)
+	}
+	return start
+}
+
+type ScheduleStatus string
+
+const (
+	ScheduleActive    ScheduleStatus = "active"
+	ScheduleCompleted ScheduleStatus = "completed"
+	ScheduleCancelled ScheduleStatus = "cancelled"
+	ScheduleFailed    ScheduleStatus = "failed"
+)
+
+type ScheduledTransfer struct {
+	Id           uuid.UUID
+	FromEmployee uuid.UUID
+	From         string
+	ToEmployee   uuid.UUID
+	To           string
+	Amount       int
+	Message      string
+	Category     TransferCategory
+	Recurrence   Recurrence
+	Status       ScheduleStatus
+	StartAt      time.Time
+	// Occurrence counts the occurrences already paid or skipped; NextRunAt is occurrence number Occurrence.
+	Occurrence    int
+	NextRunAt     time.Time
+	NextAttemptAt time.Time
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+}
+
+type RunStatus string
+
+const (
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+)
+
+type ScheduledTransferRun struct {
+	Id          uuid.UUID
+	ScheduleId  uuid.UUID
+	ScheduledAt time.Time
+	Attempt     int
+	Status      RunStatus
+	Reason      string
+	CreatedAt   time.Time
+}
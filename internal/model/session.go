@@ -0,0 +1,33 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Session is one login. Its refresh tokens form a family: every refresh replaces the token with a new one, and
+// revoking the session invalidates all of them together with the access tokens issued for it.
+type Session struct {
+	Id         uuid.UUID
+	EmployeeId uuid.UUID
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// RefreshToken keeps only the hash of the token handed to the client. UsedAt is set once it has been exchanged.
+type RefreshToken struct {
+	Id        uuid.UUID
+	SessionId uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+func (t *RefreshToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+}
@@ -1,10 +1,121 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+const MaxTransferMessageLength = 200
+
+var ErrSplitTooSmall = errors.New("total amount is less than the number of recipients")
+
+type TransferCategory string
+
+const (
+	CategoryThanks   TransferCategory = "thanks"
+	CategoryHelp     TransferCategory = "help"
+	CategoryTeamwork TransferCategory = "teamwork"
+)
+
+func (c TransferCategory) Valid() bool {
+	switch c {
+	case CategoryThanks, CategoryHelp, CategoryTeamwork:
+		return true
+	}
+	return false
+}
+
+// TransferMemo is the optional note a sender attaches to a transfer; both fields may be empty.
+type TransferMemo struct {
+	Message  string
+	Category TransferCategory
+}
 
 type Transfer struct {
 	Id           uuid.UUID
 	FromEmployee uuid.UUID
 	ToEmployee   uuid.UUID
 	Amount       int
+	Message      string
+	Category     TransferCategory
+}
+
+// TransferLine.Pending is set by SendCoinsBatch when the line was held for approval instead of settled.
+type TransferLine struct {
+	ToUser  string
+	Amount  int
+	Pending bool
+}
+
+// SplitEvenly divides total between recipients; the remainder goes one coin each to the first recipients.
+// Every recipient has to get at least one coin, so a total smaller than the recipient count is rejected.
+func SplitEvenly(recipients []string, total int) ([]TransferLine, error) {
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+
+	if total < len(recipients) {
+		return nil, ErrSplitTooSmall
+	}
+
+	share, remainder := total/len(recipients), total%len(recipients)
+	lines := make([]TransferLine, len(recipients))
+	for i, recipient := range recipients {
+		lines[i] = TransferLine{ToUser: recipient, Amount: share}
+		if i < remainder {
+			lines[i].Amount++
+		}
+	}
+	return lines, nil
+}
+
+// TransferLimits caps what an employee can send over rolling windows; a zero limit is not enforced.
+type TransferLimits struct {
+	DailyAmount            int
+	HourlyCount            int
+	MonthlyRecipientAmount int
+}
+
+func (l TransferLimits) Enabled() bool {
+	return l.DailyAmount > 0 || l.HourlyCount > 0 || l.MonthlyRecipientAmount > 0
+}
+
+// TransferVelocity is what the sender already sent in each limit window, including transfers awaiting approval and
+// marketplace purchases.
+type TransferVelocity struct {
+	DailyAmount            int
+	HourlyCount            int
+	MonthlyRecipientAmount int
+}
+
+// VelocityQuery.ExcludePending leaves out the pending transfer being approved, which is checked as a new amount.
+type VelocityQuery struct {
+	FromEmployee   uuid.UUID
+	ToEmployee     uuid.UUID
+	DaySince       time.Time
+	HourSince      time.Time
+	MonthSince     time.Time
+	ExcludePending uuid.UUID
+}
+
+type Kudos struct {
+	Id        uuid.UUID
+	From      string
+	To        string
+	Amount    int
+	Message   string
+	Category  TransferCategory
+	CreatedAt time.Time
+}
+
+type KudosFilter struct {
+	Category TransferCategory
+	Cursor   *Cursor
+	Limit    int
+}
+
+type KudosPage struct {
+	Entries    []Kudos
+	NextCursor *Cursor
 }
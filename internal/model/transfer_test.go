@@ -0,0 +1,57 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSplitEvenly(t *testing.T) {
+	tests := []struct {
+		name          string
+		recipients    []string
+		total         int
+		expected      []TransferLine
+		expectedError error
+	}{
+		{
+			name:       "remainder goes to the first recipients",
+			recipients: []string{"bob", "carol", "dave"},
+			total:      100,
+			expected: []TransferLine{
+				{ToUser: "bob", Amount: 34}, {ToUser: "carol", Amount: 33}, {ToUser: "dave", Amount: 33},
+			},
+		},
+		{
+			name:       "one coin each",
+			recipients: []string{"bob", "carol"},
+			total:      2,
+			expected:   []TransferLine{{ToUser: "bob", Amount: 1}, {ToUser: "carol", Amount: 1}},
+		},
+		{
+			name:          "total smaller than recipient count",
+			recipients:    []string{"bob", "carol", "dave"},
+			total:         2,
+			expectedError: ErrSplitTooSmall,
+		},
+		{
+			name:          "zero total",
+			recipients:    []string{"bob"},
+			total:         0,
+			expectedError: ErrSplitTooSmall,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lines, err := SplitEvenly(tc.recipients, tc.total)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, lines)
+		})
+	}
+}
@@ -6,8 +6,23 @@ var (
 	ErrEmployeeExists   = errors.New("employee already exists")
 	ErrEmployeeNotFound = errors.New("employee not found")
 
+	ErrItemExists                = errors.New("item already exists")
 	ErrItemNotFound              = errors.New("item not found")
 	ErrEmployeeInventoryNotFound = errors.New("employee inventory not found")
 
 	ErrInventoryNotFound = errors.New("inventory not found")
+
+	ErrOrderNotFound    = errors.New("order not found")
+	ErrPurchaseNotFound = errors.New("purchase not found")
+	ErrListingNotFound  = errors.New("listing not found")
+	ErrScheduleNotFound = errors.New("schedule not found")
+
+	ErrPaymentRequestNotFound  = errors.New("payment request not found")
+	ErrPendingTransferNotFound = errors.New("pending transfer not found")
+
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+
+	ErrInviteNotFound       = errors.New("invite not found")
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
 )
@@ -0,0 +1,41 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+)
+
+type PGAdjustmentRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGAdjustmentRepo(p *Postgres, c *trmpgx.CtxGetter) *PGAdjustmentRepo {
+	return &PGAdjustmentRepo{p, c}
+}
+
+func (r *PGAdjustmentRepo) Save(ctx context.Context, adjustment *model.BalanceAdjustment) error {
+	const op = "repo.pgdb.PGAdjustmentRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("balance_adjustments").
+		Columns("id, employee_id, admin_id, amount, reason").
+		Values(adjustment.Id, adjustment.EmployeeId, adjustment.AdminId, adjustment.Amount, adjustment.Reason).
+		Suffix("RETURNING created_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	err = conn.QueryRow(ctx, query, args...).Scan(&adjustment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+)
+
+type PGAllowanceRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGAllowanceRepo(p *Postgres, c *trmpgx.CtxGetter) *PGAllowanceRepo {
+	return &PGAllowanceRepo{p, c}
+}
+
+// Save reports false when the employee already has an allowance for the period.
+func (r *PGAllowanceRepo) Save(ctx context.Context, allowance *model.Allowance) (bool, error) {
+	const op = "repo.pgdb.PGAllowanceRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("allowances").
+		Columns("id, employee_id, period, amount").
+		Values(allowance.Id, allowance.EmployeeId, allowance.Period, allowance.Amount).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// FindEmployeesDueForUpdate locks active employees without an allowance for the period, skipping rows another
+// transaction holds instead of waiting for them.
+func (r *PGAllowanceRepo) FindEmployeesDueForUpdate(
+	ctx context.Context, period string, limit int) ([]model.Employee, error) {
+	const op = "repo.pgdb.PGAllowanceRepo.FindEmployeesDueForUpdate"
+
+	query, args, err := r.Builder.
+		Select("e.id, e.username, e.password_hash, e.balance, e.held, e.role, e.manager_id, e.status").
+		From("employees e").
+		Where("e.status = ?", model.EmployeeActive).
+		Where("not exists (select 1 from allowances a where a.employee_id = e.id and a.period = ?)", period).
+		OrderBy("e.id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE OF e SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var employees []model.Employee
+	for rows.Next() {
+		var employee model.Employee
+		err = rows.Scan(
+			&employee.Id,
+			&employee.Username,
+			&employee.PasswordHash,
+			&employee.Balance,
+			&employee.Held,
+			&employee.Role,
+			&employee.ManagerId,
+			&employee.Status,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		employees = append(employees, employee)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return employees, nil
+}
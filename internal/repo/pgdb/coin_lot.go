@@ -0,0 +1,272 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PGCoinLotRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGCoinLotRepo(p *Postgres, c *trmpgx.CtxGetter) *PGCoinLotRepo {
+	return &PGCoinLotRepo{p, c}
+}
+
+func (r *PGCoinLotRepo) SaveAll(ctx context.Context, lots []model.CoinLot) error {
+	const op = "repo.pgdb.PGCoinLotRepo.SaveAll"
+
+	builder := r.Builder.
+		Insert("coin_lots").
+		Columns("id, employee_id, amount, remaining, granted_at")
+
+	for _, lot := range lots {
+		builder = builder.Values(lot.Id, lot.EmployeeId, lot.Amount, lot.Remaining, lot.GrantedAt)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FindActiveByEmployee returns the lots that still hold coins, oldest first.
+func (r *PGCoinLotRepo) FindActiveByEmployee(ctx context.Context, employeeId uuid.UUID) ([]model.CoinLot, error) {
+	const op = "repo.pgdb.PGCoinLotRepo.FindActiveByEmployee"
+
+	lots, err := r.findActive(ctx, employeeId, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return lots, nil
+}
+
+// FindActiveGrantedBefore returns the lots granted at or before the given time that still hold coins, oldest first.
+func (r *PGCoinLotRepo) FindActiveGrantedBefore(
+	ctx context.Context, employeeId uuid.UUID, before time.Time) ([]model.CoinLot, error) {
+	const op = "repo.pgdb.PGCoinLotRepo.FindActiveGrantedBefore"
+
+	lots, err := r.findActive(ctx, employeeId, &before)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return lots, nil
+}
+
+func (r *PGCoinLotRepo) UpdateRemaining(ctx context.Context, lotId uuid.UUID, remaining int) error {
+	const op = "repo.pgdb.PGCoinLotRepo.UpdateRemaining"
+
+	query, args, err := r.Builder.
+		Update("coin_lots").
+		Set("remaining", remaining).
+		Where("id = ?", lotId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FindEmployeesWithExpired returns employees who have coins granted at or before the given time and can still lose
+// them: coins held for a pending transfer don't expire until the transfer is resolved.
+func (r *PGCoinLotRepo) FindEmployeesWithExpired(
+	ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	const op = "repo.pgdb.PGCoinLotRepo.FindEmployeesWithExpired"
+
+	query, args, err := r.Builder.
+		Select("l.employee_id").
+		From("coin_lots l").
+		Join("employees e on e.id = l.employee_id").
+		Where("l.remaining > 0").
+		Where("l.granted_at <= ?", before).
+		Where("e.balance > e.held").
+		GroupBy("l.employee_id").
+		OrderBy("min(l.granted_at)").
+		Limit(uint64(limit)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var employeeIds []uuid.UUID
+	for rows.Next() {
+		var employeeId uuid.UUID
+		if err = rows.Scan(&employeeId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		employeeIds = append(employeeIds, employeeId)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return employeeIds, nil
+}
+
+func (r *PGCoinLotRepo) findActive(
+	ctx context.Context, employeeId uuid.UUID, grantedBefore *time.Time) ([]model.CoinLot, error) {
+	builder := r.Builder.
+		Select("id, employee_id, amount, remaining, granted_at").
+		From("coin_lots").
+		Where("employee_id = ?", employeeId).
+		Where("remaining > 0")
+
+	if grantedBefore != nil {
+		builder = builder.Where("granted_at <= ?", *grantedBefore)
+	}
+
+	query, args, err := builder.
+		OrderBy("granted_at", "id").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []model.CoinLot
+	for rows.Next() {
+		var lot model.CoinLot
+		err = rows.Scan(&lot.Id, &lot.EmployeeId, &lot.Amount, &lot.Remaining, &lot.GrantedAt)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+
+	return lots, rows.Err()
+}
+
+func (r *PGCoinLotRepo) SaveSpent(ctx context.Context, spent []model.SpentCoins) error {
+	const op = "repo.pgdb.PGCoinLotRepo.SaveSpent"
+
+	builder := r.Builder.
+		Insert("spent_coins").
+		Columns("id, operation_id, amount, remaining, granted_at")
+
+	for _, part := range spent {
+		builder = builder.Values(part.Id, part.OperationId, part.Amount, part.Remaining, part.GrantedAt)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FindSpentByOperationForUpdate returns the coins of the operation that haven't been given back yet, newest first.
+func (r *PGCoinLotRepo) FindSpentByOperationForUpdate(
+	ctx context.Context, operationId uuid.UUID) ([]model.SpentCoins, error) {
+	const op = "repo.pgdb.PGCoinLotRepo.FindSpentByOperationForUpdate"
+
+	query, args, err := r.Builder.
+		Select("id, operation_id, amount, remaining, granted_at").
+		From("spent_coins").
+		Where("operation_id = ?", operationId).
+		Where("remaining > 0").
+		OrderBy("granted_at desc", "id").
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var spent []model.SpentCoins
+	for rows.Next() {
+		var part model.SpentCoins
+		err = rows.Scan(&part.Id, &part.OperationId, &part.Amount, &part.Remaining, &part.GrantedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		spent = append(spent, part)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return spent, nil
+}
+
+func (r *PGCoinLotRepo) UpdateSpentRemaining(ctx context.Context, spentId uuid.UUID, remaining int) error {
+	const op = "repo.pgdb.PGCoinLotRepo.UpdateSpentRemaining"
+
+	query, args, err := r.Builder.
+		Update("spent_coins").
+		Set("remaining", remaining).
+		Where("id = ?", spentId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
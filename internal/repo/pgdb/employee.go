@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -25,8 +26,8 @@ func (r *PGEmployeeRepo) Save(ctx context.Context, employee *model.Employee) err
 
 	query, args, err := r.Builder.
 		Insert("employees").
-		Columns("id, username, password_hash, balance").
-		Values(employee.Id, employee.Username, employee.PasswordHash, employee.Balance).
+		Columns("id, username, password_hash, balance, role").
+		Values(employee.Id, employee.Username, employee.PasswordHash, employee.Balance, employee.Role).
 		ToSql()
 
 	if err != nil {
@@ -53,7 +54,7 @@ func (r *PGEmployeeRepo) FindByUsername(ctx context.Context, username string) (*
 	const op = "repo.pgdb.PGEmployeeRepo.FindByUsername"
 
 	query, args, err := r.Builder.
-		Select("id, username, password_hash, balance").
+		Select("id, username, password_hash, balance, held, role, manager_id, status").
 		From("employees").
 		Where("username = ?", username).
 		ToSql()
@@ -71,6 +72,10 @@ func (r *PGEmployeeRepo) FindByUsername(ctx context.Context, username string) (*
 			&employee.Username,
 			&employee.PasswordHash,
 			&employee.Balance,
+			&employee.Held,
+			&employee.Role,
+			&employee.ManagerId,
+			&employee.Status,
 		)
 
 	if err != nil {
@@ -83,6 +88,122 @@ func (r *PGEmployeeRepo) FindByUsername(ctx context.Context, username string) (*
 	return &employee, nil
 }
 
+func (r *PGEmployeeRepo) FindByUsernameForUpdate(ctx context.Context, username string) (*model.Employee, error) {
+	const op = "repo.pgdb.PGEmployeeRepo.FindByUsernameForUpdate"
+
+	query, args, err := r.Builder.
+		Select("id, username, password_hash, balance, held, role, manager_id, status").
+		From("employees").
+		Where("username = ?", username).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var employee model.Employee
+	err = conn.QueryRow(ctx, query, args...).
+		Scan(
+			&employee.Id,
+			&employee.Username,
+			&employee.PasswordHash,
+			&employee.Balance,
+			&employee.Held,
+			&employee.Role,
+			&employee.ManagerId,
+			&employee.Status,
+		)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &employee, nil
+}
+
+func (r *PGEmployeeRepo) FindByIdForUpdate(ctx context.Context, employeeId uuid.UUID) (*model.Employee, error) {
+	const op = "repo.pgdb.PGEmployeeRepo.FindByIdForUpdate"
+
+	query, args, err := r.Builder.
+		Select("id, username, password_hash, balance, held, role, manager_id, status").
+		From("employees").
+		Where("id = ?", employeeId).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var employee model.Employee
+	err = conn.QueryRow(ctx, query, args...).
+		Scan(
+			&employee.Id,
+			&employee.Username,
+			&employee.PasswordHash,
+			&employee.Balance,
+			&employee.Held,
+			&employee.Role,
+			&employee.ManagerId,
+			&employee.Status,
+		)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &employee, nil
+}
+
+func (r *PGEmployeeRepo) FindById(ctx context.Context, employeeId uuid.UUID) (*model.Employee, error) {
+	const op = "repo.pgdb.PGEmployeeRepo.FindById"
+
+	query, args, err := r.Builder.
+		Select("id, username, password_hash, balance, held, role, manager_id, status").
+		From("employees").
+		Where("id = ?", employeeId).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var employee model.Employee
+	err = conn.QueryRow(ctx, query, args...).
+		Scan(
+			&employee.Id,
+			&employee.Username,
+			&employee.PasswordHash,
+			&employee.Balance,
+			&employee.Held,
+			&employee.Role,
+			&employee.ManagerId,
+			&employee.Status,
+		)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &employee, nil
+}
+
 func (r *PGEmployeeRepo) UpdateByUsername(ctx context.Context, username string, employee *model.Employee) error {
 	const op = "repo.pgdb.PGEmployeeRepo.UpdateByUsername"
 
@@ -90,6 +211,7 @@ func (r *PGEmployeeRepo) UpdateByUsername(ctx context.Context, username string,
 		Update("employees").
 		Set("password_hash", employee.PasswordHash).
 		Set("balance", employee.Balance).
+		Set("held", employee.Held).
 		Where("username = ?", username).
 		ToSql()
 
@@ -107,3 +229,84 @@ func (r *PGEmployeeRepo) UpdateByUsername(ctx context.Context, username string,
 
 	return nil
 }
+
+func (r *PGEmployeeRepo) UpdateManager(ctx context.Context, employeeId uuid.UUID, managerId *uuid.UUID) error {
+	const op = "repo.pgdb.PGEmployeeRepo.UpdateManager"
+
+	query, args, err := r.Builder.
+		Update("employees").
+		Set("manager_id", managerId).
+		Where("id = ?", employeeId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrEmployeeNotFound
+	}
+
+	return nil
+}
+
+func (r *PGEmployeeRepo) UpdateStatus(ctx context.Context, employeeId uuid.UUID, status model.EmployeeStatus) error {
+	const op = "repo.pgdb.PGEmployeeRepo.UpdateStatus"
+
+	query, args, err := r.Builder.
+		Update("employees").
+		Set("status", status).
+		Where("id = ?", employeeId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrEmployeeNotFound
+	}
+
+	return nil
+}
+
+func (r *PGEmployeeRepo) UpdateRole(ctx context.Context, employeeId uuid.UUID, role model.Role) error {
+	const op = "repo.pgdb.PGEmployeeRepo.UpdateRole"
+
+	query, args, err := r.Builder.
+		Update("employees").
+		Set("role", role).
+		Where("id = ?", employeeId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrEmployeeNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+)
+
+type PGGiftRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGGiftRepo(p *Postgres, c *trmpgx.CtxGetter) *PGGiftRepo {
+	return &PGGiftRepo{p, c}
+}
+
+func (r *PGGiftRepo) Save(ctx context.Context, gift *model.Gift) error {
+	const op = "repo.pgdb.PGGiftRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("item_gifts").
+		Columns("id, from_employee, to_employee, item_id, quantity").
+		Values(gift.Id, gift.FromEmployee, gift.ToEmployee, gift.ItemId, gift.Quantity).
+		Suffix("RETURNING created_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&gift.CreatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+)
+
+type PGHistoryRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGHistoryRepo(p *Postgres, c *trmpgx.CtxGetter) *PGHistoryRepo {
+	return &PGHistoryRepo{p, c}
+}
+
+func (r *PGHistoryRepo) FindByEmployee(
+	ctx context.Context, employeeId uuid.UUID, filter model.HistoryFilter) ([]model.HistoryEntry, error) {
+	const op = "repo.pgdb.PGHistoryRepo.FindByEmployee"
+
+	builder := r.Builder.
+		Select("id, type, direction, coalesce(counterparty, ''), coalesce(item, ''), quantity, amount, "+
+			"created_at, coalesce(message, ''), coalesce(category, '')").
+		From("employee_history").
+		Where("employee_id = ?", employeeId)
+
+	if filter.Direction != "" {
+		builder = builder.Where("direction = ?", filter.Direction)
+	}
+	if filter.Counterparty != "" {
+		builder = builder.Where("counterparty = ?", filter.Counterparty)
+	}
+	if filter.From != nil {
+		builder = builder.Where(squirrel.GtOrEq{"created_at": *filter.From})
+	}
+	if filter.To != nil {
+		builder = builder.Where(squirrel.Lt{"created_at": *filter.To})
+	}
+	if filter.Cursor != nil {
+		builder = builder.Where("(created_at, id) < (?, ?)", filter.Cursor.CreatedAt, filter.Cursor.Id)
+	}
+
+	query, args, err := builder.
+		OrderBy("created_at desc", "id desc").
+		Limit(uint64(filter.Limit)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var entries []model.HistoryEntry
+	for rows.Next() {
+		var entry model.HistoryEntry
+		err = rows.Scan(
+			&entry.Id,
+			&entry.Type,
+			&entry.Direction,
+			&entry.Counterparty,
+			&entry.Item,
+			&entry.Quantity,
+			&entry.Amount,
+			&entry.CreatedAt,
+			&entry.Message,
+			&entry.Category,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
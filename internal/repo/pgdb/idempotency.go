@@ -0,0 +1,104 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PGIdempotencyRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGIdempotencyRepo(p *Postgres, c *trmpgx.CtxGetter) *PGIdempotencyRepo {
+	return &PGIdempotencyRepo{p, c}
+}
+
+func (r *PGIdempotencyRepo) Reserve(ctx context.Context, record *model.IdempotencyRecord) (bool, error) {
+	const op = "repo.pgdb.PGIdempotencyRepo.Reserve"
+
+	query, args, err := r.Builder.
+		Insert("idempotency_keys").
+		Columns("employee_id, key, fingerprint").
+		Values(record.EmployeeId, record.Key, record.Fingerprint).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *PGIdempotencyRepo) FindByEmployeeAndKey(
+	ctx context.Context, employeeId uuid.UUID, key string) (*model.IdempotencyRecord, error) {
+	const op = "repo.pgdb.PGIdempotencyRepo.FindByEmployeeAndKey"
+
+	query, args, err := r.Builder.
+		Select("employee_id, key, fingerprint, coalesce(response_status, 0), response_body").
+		From("idempotency_keys").
+		Where("employee_id = ? AND key = ?", employeeId, key).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var record model.IdempotencyRecord
+	err = conn.QueryRow(ctx, query, args...).Scan(
+		&record.EmployeeId,
+		&record.Key,
+		&record.Fingerprint,
+		&record.ResponseStatus,
+		&record.ResponseBody,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrIdempotencyKeyNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &record, nil
+}
+
+func (r *PGIdempotencyRepo) SaveResponse(ctx context.Context, record *model.IdempotencyRecord) error {
+	const op = "repo.pgdb.PGIdempotencyRepo.SaveResponse"
+
+	query, args, err := r.Builder.
+		Update("idempotency_keys").
+		Set("response_status", record.ResponseStatus).
+		Set("response_body", record.ResponseBody).
+		Where("employee_id = ? AND key = ?", record.EmployeeId, record.Key).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
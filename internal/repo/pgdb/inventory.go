@@ -53,6 +53,7 @@ func (r *PgInventoryRepo) FindAllInventoryItemsByEmployee(
 		LeftJoin("items on items.id = employee_inventory.item_id").
 		Where("employee_inventory.employee_id = ?", employeeId).
 		GroupBy("items.name").
+		Having("sum(employee_inventory.amount) > 0").
 		ToSql()
 
 	if err != nil {
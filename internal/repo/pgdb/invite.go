@@ -0,0 +1,107 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PGInviteRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGInviteRepo(p *Postgres, c *trmpgx.CtxGetter) *PGInviteRepo {
+	return &PGInviteRepo{p, c}
+}
+
+func (r *PGInviteRepo) Save(ctx context.Context, invite *model.Invite) error {
+	const op = "repo.pgdb.PGInviteRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("invites").
+		Columns("id, code, created_by, expires_at").
+		Values(invite.Id, invite.Code, invite.CreatedBy, invite.ExpiresAt).
+		Suffix("RETURNING created_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	err = conn.QueryRow(ctx, query, args...).Scan(&invite.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGInviteRepo) FindByCodeForUpdate(ctx context.Context, code string) (*model.Invite, error) {
+	const op = "repo.pgdb.PGInviteRepo.FindByCodeForUpdate"
+
+	query, args, err := r.Builder.
+		Select("id, code, created_by, used_by, expires_at, created_at").
+		From("invites").
+		Where("code = ?", code).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var invite model.Invite
+	err = conn.QueryRow(ctx, query, args...).
+		Scan(
+			&invite.Id,
+			&invite.Code,
+			&invite.CreatedBy,
+			&invite.UsedBy,
+			&invite.ExpiresAt,
+			&invite.CreatedAt,
+		)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrInviteNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &invite, nil
+}
+
+func (r *PGInviteRepo) MarkUsed(ctx context.Context, inviteId uuid.UUID, employeeId uuid.UUID) error {
+	const op = "repo.pgdb.PGInviteRepo.MarkUsed"
+
+	query, args, err := r.Builder.
+		Update("invites").
+		Set("used_by", employeeId).
+		Set("used_at", squirrel.Expr("now()")).
+		Where("id = ?", inviteId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -6,9 +6,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/Masterminds/squirrel"
 	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type PGItemRepo struct {
@@ -20,13 +22,70 @@ func NewPGItemRepo(p *Postgres, c *trmpgx.CtxGetter) *PGItemRepo {
 	return &PGItemRepo{p, c}
 }
 
+func (r *PGItemRepo) Save(ctx context.Context, item *model.Item) error {
+	const op = "repo.pgdb.PGItemRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("items").
+		Columns("id, name, price, retired, stock, max_per_employee, non_returnable").
+		Values(item.Id, item.Name, item.Price, item.Retired, item.Stock, item.MaxPerEmployee, item.NonReturnable).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return repo.ErrItemExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 func (r *PGItemRepo) FindByName(ctx context.Context, itemName string) (*model.Item, error) {
 	const op = "repo.pgdb.PGItemRepo.FindByName"
 
 	query, args, err := r.Builder.
-		Select("id, name, price").
+		Select("id, name, price, retired, stock, max_per_employee, non_returnable").
+		From("items").
+		Where("name = ?", itemName).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var item model.Item
+	err = conn.QueryRow(ctx, query, args...).
+		Scan(&item.Id, &item.Name, &item.Price, &item.Retired, &item.Stock, &item.MaxPerEmployee, &item.NonReturnable)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrItemNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &item, nil
+}
+
+func (r *PGItemRepo) FindByNameForUpdate(ctx context.Context, itemName string) (*model.Item, error) {
+	const op = "repo.pgdb.PGItemRepo.FindByNameForUpdate"
+
+	query, args, err := r.Builder.
+		Select("id, name, price, retired, stock, max_per_employee, non_returnable").
 		From("items").
 		Where("name = ?", itemName).
+		Suffix("FOR UPDATE").
 		ToSql()
 
 	if err != nil {
@@ -37,7 +96,7 @@ func (r *PGItemRepo) FindByName(ctx context.Context, itemName string) (*model.It
 
 	var item model.Item
 	err = conn.QueryRow(ctx, query, args...).
-		Scan(&item.Id, &item.Name, &item.Price)
+		Scan(&item.Id, &item.Name, &item.Price, &item.Retired, &item.Stock, &item.MaxPerEmployee, &item.NonReturnable)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -53,7 +112,7 @@ func (r *PGItemRepo) FindById(ctx context.Context, itemId uuid.UUID) (*model.Ite
 	const op = "repo.pgdb.PGItemRepo.FindById"
 
 	query, args, err := r.Builder.
-		Select("id, name, price").
+		Select("id, name, price, retired, stock, max_per_employee, non_returnable").
 		From("items").
 		Where("id = ?", itemId).
 		ToSql()
@@ -66,7 +125,7 @@ func (r *PGItemRepo) FindById(ctx context.Context, itemId uuid.UUID) (*model.Ite
 
 	var item model.Item
 	err = conn.QueryRow(ctx, query, args...).
-		Scan(&item.Id, &item.Name, &item.Price)
+		Scan(&item.Id, &item.Name, &item.Price, &item.Retired, &item.Stock, &item.MaxPerEmployee, &item.NonReturnable)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -77,3 +136,138 @@ func (r *PGItemRepo) FindById(ctx context.Context, itemId uuid.UUID) (*model.Ite
 
 	return &item, nil
 }
+
+func (r *PGItemRepo) FindAll(ctx context.Context, filter model.ItemFilter) ([]model.Item, error) {
+	const op = "repo.pgdb.PGItemRepo.FindAll"
+
+	builder := r.Builder.
+		Select("id, name, price, retired, stock, max_per_employee, non_returnable").
+		From("items").
+		Where("retired = false")
+
+	if filter.MaxPrice != nil {
+		builder = builder.Where(squirrel.LtOrEq{"price": *filter.MaxPrice})
+	}
+
+	order := "asc"
+	if filter.Order == model.SortDesc {
+		order = "desc"
+	}
+
+	if filter.SortBy == model.ItemSortPrice {
+		builder = builder.OrderBy("price "+order, "name asc")
+	} else {
+		builder = builder.OrderBy("name " + order)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var items []model.Item
+	for rows.Next() {
+		var item model.Item
+		err = rows.Scan(
+			&item.Id, &item.Name, &item.Price, &item.Retired, &item.Stock, &item.MaxPerEmployee, &item.NonReturnable)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, nil
+}
+
+func (r *PGItemRepo) UpdateById(ctx context.Context, itemId uuid.UUID, item *model.Item) error {
+	const op = "repo.pgdb.PGItemRepo.UpdateById"
+
+	query, args, err := r.Builder.
+		Update("items").
+		Set("name", item.Name).
+		Set("price", item.Price).
+		Set("retired", item.Retired).
+		Set("stock", item.Stock).
+		Set("max_per_employee", item.MaxPerEmployee).
+		Set("non_returnable", item.NonReturnable).
+		Where("id = ?", itemId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return repo.ErrItemExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrItemNotFound
+	}
+
+	return nil
+}
+
+func (r *PGItemRepo) DecrementStock(ctx context.Context, itemId uuid.UUID, quantity int) (bool, error) {
+	const op = "repo.pgdb.PGItemRepo.DecrementStock"
+
+	query, args, err := r.Builder.
+		Update("items").
+		Set("stock", squirrel.Expr("stock - ?", quantity)).
+		Where("id = ? AND stock >= ?", itemId, quantity).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *PGItemRepo) IncrementStock(ctx context.Context, itemId uuid.UUID, quantity int) error {
+	const op = "repo.pgdb.PGItemRepo.IncrementStock"
+
+	query, args, err := r.Builder.
+		Update("items").
+		Set("stock", squirrel.Expr("stock + ?", quantity)).
+		Where("id = ? AND stock IS NOT NULL", itemId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	if _, err = conn.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
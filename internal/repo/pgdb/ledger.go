@@ -0,0 +1,167 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+)
+
+type PGLedgerRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGLedgerRepo(p *Postgres, c *trmpgx.CtxGetter) *PGLedgerRepo {
+	return &PGLedgerRepo{p, c}
+}
+
+func (r *PGLedgerRepo) SaveAll(ctx context.Context, entries []model.LedgerEntry) error {
+	const op = "repo.pgdb.PGLedgerRepo.SaveAll"
+
+	builder := r.Builder.
+		Insert("ledger_entries").
+		Columns("id, operation_id, operation_type, account, employee_id, direction, amount")
+
+	for _, entry := range entries {
+		builder = builder.Values(
+			entry.Id,
+			entry.OperationId,
+			entry.OperationType,
+			entry.Account,
+			entry.EmployeeId,
+			entry.Direction,
+			entry.Amount,
+		)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGLedgerRepo) BalanceByEmployee(ctx context.Context, employeeId uuid.UUID) (int, error) {
+	const op = "repo.pgdb.PGLedgerRepo.BalanceByEmployee"
+
+	query, args, err := r.Builder.
+		Select("coalesce(sum(case when direction = 'credit' then amount else -amount end), 0)").
+		From("ledger_entries").
+		Where("account = ? AND employee_id = ?", model.AccountEmployee, employeeId).
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var balance int
+	err = conn.QueryRow(ctx, query, args...).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return balance, nil
+}
+
+// FindMismatches compares every employee's balance with their ledger entries in one statement, so it sees a single
+// snapshot and needs no locks.
+func (r *PGLedgerRepo) FindMismatches(ctx context.Context) ([]model.LedgerMismatch, error) {
+	const op = "repo.pgdb.PGLedgerRepo.FindMismatches"
+
+	query, args, err := r.Builder.
+		Select("e.id, e.username, e.balance, "+
+			"coalesce(sum(case when l.direction = 'credit' then l.amount else -l.amount end), 0) as ledger_balance").
+		From("employees e").
+		LeftJoin("ledger_entries l on l.employee_id = e.id and l.account = ?", model.AccountEmployee).
+		GroupBy("e.id").
+		Having("e.balance <> coalesce(sum(case when l.direction = 'credit' then l.amount else -l.amount end), 0)").
+		OrderBy("e.username").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var mismatches []model.LedgerMismatch
+	for rows.Next() {
+		var mismatch model.LedgerMismatch
+		err = rows.Scan(&mismatch.EmployeeId, &mismatch.Username, &mismatch.Balance, &mismatch.LedgerBalance)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return mismatches, nil
+}
+
+func (r *PGLedgerRepo) FindAllByOperation(ctx context.Context, operationId uuid.UUID) ([]model.LedgerEntry, error) {
+	const op = "repo.pgdb.PGLedgerRepo.FindAllByOperation"
+
+	query, args, err := r.Builder.
+		Select("id, operation_id, operation_type, account, employee_id, direction, amount").
+		From("ledger_entries").
+		Where("operation_id = ?", operationId).
+		OrderBy("direction desc").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var entries []model.LedgerEntry
+	for rows.Next() {
+		var entry model.LedgerEntry
+		err = rows.Scan(
+			&entry.Id,
+			&entry.OperationId,
+			&entry.OperationType,
+			&entry.Account,
+			&entry.EmployeeId,
+			&entry.Direction,
+			&entry.Amount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
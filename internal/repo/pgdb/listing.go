@@ -0,0 +1,207 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PGListingRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGListingRepo(p *Postgres, c *trmpgx.CtxGetter) *PGListingRepo {
+	return &PGListingRepo{p, c}
+}
+
+func (r *PGListingRepo) Save(ctx context.Context, listing *model.Listing) error {
+	const op = "repo.pgdb.PGListingRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("listings").
+		Columns("id, seller_id, item_id, quantity, price, status, expires_at").
+		Values(listing.Id, listing.SellerId, listing.ItemId, listing.Quantity, listing.Price, listing.Status,
+			listing.ExpiresAt).
+		Suffix("RETURNING created_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&listing.CreatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGListingRepo) FindByIdForUpdate(ctx context.Context, listingId uuid.UUID) (*model.Listing, error) {
+	const op = "repo.pgdb.PGListingRepo.FindByIdForUpdate"
+
+	query, args, err := r.selectListings().
+		Where("l.id = ?", listingId).
+		Suffix("FOR UPDATE OF l").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	listing, err := scanListing(conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrListingNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listing, nil
+}
+
+func (r *PGListingRepo) FindActive(ctx context.Context, filter model.ListingFilter) ([]model.Listing, error) {
+	const op = "repo.pgdb.PGListingRepo.FindActive"
+
+	builder := r.selectListings().
+		Where("l.status = ?", model.ListingActive).
+		Where("l.expires_at > now()")
+
+	if filter.Item != "" {
+		builder = builder.Where("i.name = ?", filter.Item)
+	}
+
+	query, args, err := builder.
+		OrderBy("l.created_at desc", "l.id desc").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	listings, err := r.query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listings, nil
+}
+
+// FindExpiredForUpdate skips rows locked by a concurrent buyer or another sweeper instead of waiting for them.
+func (r *PGListingRepo) FindExpiredForUpdate(ctx context.Context, limit int) ([]model.Listing, error) {
+	const op = "repo.pgdb.PGListingRepo.FindExpiredForUpdate"
+
+	query, args, err := r.selectListings().
+		Where("l.status = ?", model.ListingActive).
+		Where("l.expires_at <= now()").
+		OrderBy("l.expires_at").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE OF l SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	listings, err := r.query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listings, nil
+}
+
+func (r *PGListingRepo) Close(
+	ctx context.Context, listingId uuid.UUID, status model.ListingStatus, buyerId *uuid.UUID) error {
+	const op = "repo.pgdb.PGListingRepo.Close"
+
+	query, args, err := r.Builder.
+		Update("listings").
+		Set("status", status).
+		Set("buyer_id", buyerId).
+		Set("closed_at", squirrel.Expr("now()")).
+		Where("id = ?", listingId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrListingNotFound
+	}
+
+	return nil
+}
+
+func (r *PGListingRepo) selectListings() squirrel.SelectBuilder {
+	return r.Builder.
+		Select("l.id, l.seller_id, e.username, l.item_id, i.name, l.quantity, l.price, l.status, l.buyer_id, " +
+			"l.expires_at, l.created_at").
+		From("listings l").
+		Join("employees e on e.id = l.seller_id").
+		Join("items i on i.id = l.item_id")
+}
+
+func (r *PGListingRepo) query(ctx context.Context, query string, args []any) ([]model.Listing, error) {
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listings []model.Listing
+	for rows.Next() {
+		listing, err := scanListing(rows)
+		if err != nil {
+			return nil, err
+		}
+		listings = append(listings, *listing)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return listings, nil
+}
+
+func scanListing(row pgx.Row) (*model.Listing, error) {
+	var listing model.Listing
+	err := row.Scan(
+		&listing.Id,
+		&listing.SellerId,
+		&listing.Seller,
+		&listing.ItemId,
+		&listing.Item,
+		&listing.Quantity,
+		&listing.Price,
+		&listing.Status,
+		&listing.BuyerId,
+		&listing.ExpiresAt,
+		&listing.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &listing, nil
+}
@@ -0,0 +1,232 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PGOrderRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGOrderRepo(p *Postgres, c *trmpgx.CtxGetter) *PGOrderRepo {
+	return &PGOrderRepo{p, c}
+}
+
+func (r *PGOrderRepo) Save(ctx context.Context, order *model.Order) error {
+	const op = "repo.pgdb.PGOrderRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("orders").
+		Columns("id, employee_id, total, status, delivered_at").
+		Values(order.Id, order.EmployeeId, order.Total, order.Status, order.DeliveredAt).
+		Suffix("RETURNING created_at, updated_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&order.CreatedAt, &order.UpdatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGOrderRepo) FindByIdForUpdate(ctx context.Context, orderId uuid.UUID) (*model.Order, error) {
+	const op = "repo.pgdb.PGOrderRepo.FindByIdForUpdate"
+
+	query, args, err := r.selectOrders().
+		Where("o.id = ?", orderId).
+		Suffix("FOR UPDATE OF o").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var order model.Order
+	err = conn.QueryRow(ctx, query, args...).Scan(
+		&order.Id,
+		&order.EmployeeId,
+		&order.Employee,
+		&order.Status,
+		&order.Total,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+		&order.DeliveredAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	orders := []model.Order{order}
+	if err = r.loadItems(ctx, orders); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &orders[0], nil
+}
+
+func (r *PGOrderRepo) FindAll(ctx context.Context, filter model.OrderFilter) ([]model.Order, error) {
+	const op = "repo.pgdb.PGOrderRepo.FindAll"
+
+	builder := r.selectOrders()
+
+	if filter.EmployeeId != nil {
+		builder = builder.Where("o.employee_id = ?", *filter.EmployeeId)
+	}
+	if filter.Status != "" {
+		builder = builder.Where("o.status = ?", filter.Status)
+	}
+
+	query, args, err := builder.
+		OrderBy("o.created_at desc", "o.id desc").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var orders []model.Order
+	for rows.Next() {
+		var order model.Order
+		err = rows.Scan(
+			&order.Id,
+			&order.EmployeeId,
+			&order.Employee,
+			&order.Status,
+			&order.Total,
+			&order.CreatedAt,
+			&order.UpdatedAt,
+			&order.DeliveredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = r.loadItems(ctx, orders); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orders, nil
+}
+
+func (r *PGOrderRepo) UpdateStatus(ctx context.Context, orderId uuid.UUID, status model.OrderStatus) error {
+	const op = "repo.pgdb.PGOrderRepo.UpdateStatus"
+
+	builder := r.Builder.
+		Update("orders").
+		Set("status", status).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("id = ?", orderId)
+
+	switch status {
+	case model.OrderCancelled:
+		builder = builder.Set("cancelled_at", squirrel.Expr("now()"))
+	case model.OrderDelivered:
+		builder = builder.Set("delivered_at", squirrel.Expr("now()"))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrOrderNotFound
+	}
+
+	return nil
+}
+
+func (r *PGOrderRepo) selectOrders() squirrel.SelectBuilder {
+	return r.Builder.
+		Select("o.id, o.employee_id, e.username, o.status, o.total, o.created_at, o.updated_at, o.delivered_at").
+		From("orders o").
+		Join("employees e on e.id = o.employee_id")
+}
+
+func (r *PGOrderRepo) loadItems(ctx context.Context, orders []model.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	orderIds := make([]uuid.UUID, len(orders))
+	byId := make(map[uuid.UUID]*model.Order, len(orders))
+	for i := range orders {
+		orderIds[i] = orders[i].Id
+		byId[orders[i].Id] = &orders[i]
+	}
+
+	query, args, err := r.Builder.
+		Select("p.order_id, p.id, p.item_id, i.name, p.quantity, p.returned_quantity, p.price").
+		From("purchases p").
+		Join("items i on i.id = p.item_id").
+		Where(squirrel.Eq{"p.order_id": orderIds}).
+		OrderBy("i.name").
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderId uuid.UUID
+		var item model.OrderItem
+		err = rows.Scan(
+			&orderId, &item.PurchaseId, &item.ItemId, &item.Item, &item.Quantity, &item.Returned, &item.Price)
+		if err != nil {
+			return err
+		}
+		byId[orderId].Items = append(byId[orderId].Items, item)
+	}
+
+	return rows.Err()
+}
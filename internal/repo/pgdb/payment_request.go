@@ -0,0 +1,186 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PGPaymentRequestRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGPaymentRequestRepo(p *Postgres, c *trmpgx.CtxGetter) *PGPaymentRequestRepo {
+	return &PGPaymentRequestRepo{p, c}
+}
+
+func (r *PGPaymentRequestRepo) Save(ctx context.Context, request *model.PaymentRequest) error {
+	const op = "repo.pgdb.PGPaymentRequestRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("payment_requests").
+		Columns("id, requester_id, payer_id, amount, message, status, expires_at").
+		Values(request.Id, request.RequesterId, request.PayerId, request.Amount, nullIfEmpty(request.Message),
+			request.Status, request.ExpiresAt).
+		Suffix("RETURNING created_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&request.CreatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGPaymentRequestRepo) FindByIdForUpdate(
+	ctx context.Context, requestId uuid.UUID) (*model.PaymentRequest, error) {
+	const op = "repo.pgdb.PGPaymentRequestRepo.FindByIdForUpdate"
+
+	query, args, err := r.selectPaymentRequests().
+		Where("p.id = ?", requestId).
+		Suffix("FOR UPDATE OF p").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	request, err := scanPaymentRequest(conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrPaymentRequestNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return request, nil
+}
+
+func (r *PGPaymentRequestRepo) FindByEmployee(
+	ctx context.Context, employeeId uuid.UUID, filter model.PaymentRequestFilter) ([]model.PaymentRequest, error) {
+	const op = "repo.pgdb.PGPaymentRequestRepo.FindByEmployee"
+
+	builder := r.selectPaymentRequests()
+
+	if filter.Direction == model.PaymentRequestsOutgoing {
+		builder = builder.Where("p.requester_id = ?", employeeId)
+	} else {
+		builder = builder.Where("p.payer_id = ?", employeeId)
+	}
+
+	switch filter.Status {
+	case "":
+	case model.PaymentRequestPending:
+		builder = builder.Where("p.status = ?", model.PaymentRequestPending).Where("p.expires_at > now()")
+	case model.PaymentRequestExpired:
+		builder = builder.Where("p.status = ?", model.PaymentRequestPending).Where("p.expires_at <= now()")
+	default:
+		builder = builder.Where("p.status = ?", filter.Status)
+	}
+
+	query, args, err := builder.
+		OrderBy("p.created_at desc", "p.id desc").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var requests []model.PaymentRequest
+	for rows.Next() {
+		request, err := scanPaymentRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		requests = append(requests, *request)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return requests, nil
+}
+
+func (r *PGPaymentRequestRepo) Resolve(
+	ctx context.Context, requestId uuid.UUID, status model.PaymentRequestStatus) error {
+	const op = "repo.pgdb.PGPaymentRequestRepo.Resolve"
+
+	query, args, err := r.Builder.
+		Update("payment_requests").
+		Set("status", status).
+		Set("resolved_at", squirrel.Expr("now()")).
+		Where("id = ?", requestId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrPaymentRequestNotFound
+	}
+
+	return nil
+}
+
+func (r *PGPaymentRequestRepo) selectPaymentRequests() squirrel.SelectBuilder {
+	return r.Builder.
+		Select("p.id, p.requester_id, q.username, p.payer_id, e.username, p.amount, coalesce(p.message, ''), " +
+			"case when p.status = 'pending' and p.expires_at <= now() then 'expired' else p.status end, " +
+			"p.expires_at, p.resolved_at, p.created_at").
+		From("payment_requests p").
+		Join("employees q on q.id = p.requester_id").
+		Join("employees e on e.id = p.payer_id")
+}
+
+func scanPaymentRequest(row pgx.Row) (*model.PaymentRequest, error) {
+	var request model.PaymentRequest
+	err := row.Scan(
+		&request.Id,
+		&request.RequesterId,
+		&request.Requester,
+		&request.PayerId,
+		&request.Payer,
+		&request.Amount,
+		&request.Message,
+		&request.Status,
+		&request.ExpiresAt,
+		&request.ResolvedAt,
+		&request.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
@@ -0,0 +1,178 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PGPendingTransferRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGPendingTransferRepo(p *Postgres, c *trmpgx.CtxGetter) *PGPendingTransferRepo {
+	return &PGPendingTransferRepo{p, c}
+}
+
+func (r *PGPendingTransferRepo) Save(ctx context.Context, transfer *model.PendingTransfer) error {
+	const op = "repo.pgdb.PGPendingTransferRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("pending_transfers").
+		Columns("id, from_employee, to_employee, amount, message, category, status").
+		Values(transfer.Id, transfer.FromEmployee, transfer.ToEmployee, transfer.Amount,
+			nullIfEmpty(transfer.Message), nullIfEmpty(string(transfer.Category)), transfer.Status).
+		Suffix("RETURNING created_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&transfer.CreatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGPendingTransferRepo) FindByIdForUpdate(
+	ctx context.Context, transferId uuid.UUID) (*model.PendingTransfer, error) {
+	const op = "repo.pgdb.PGPendingTransferRepo.FindByIdForUpdate"
+
+	query, args, err := r.selectPendingTransfers().
+		Where("p.id = ?", transferId).
+		Suffix("FOR UPDATE OF p").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	transfer, err := scanPendingTransfer(conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrPendingTransferNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transfer, nil
+}
+
+// FindPending returns the transfers waiting for approval, oldest first.
+// A nil managerId returns every pending transfer, otherwise only those sent by the manager's reports.
+func (r *PGPendingTransferRepo) FindPending(
+	ctx context.Context, managerId *uuid.UUID) ([]model.PendingTransfer, error) {
+	const op = "repo.pgdb.PGPendingTransferRepo.FindPending"
+
+	builder := r.selectPendingTransfers().
+		Where("p.status = ?", model.PendingTransferPending)
+
+	if managerId != nil {
+		builder = builder.Where("f.manager_id = ?", *managerId)
+	}
+
+	query, args, err := builder.
+		OrderBy("p.created_at", "p.id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var transfers []model.PendingTransfer
+	for rows.Next() {
+		transfer, err := scanPendingTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		transfers = append(transfers, *transfer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transfers, nil
+}
+
+func (r *PGPendingTransferRepo) Resolve(
+	ctx context.Context, transferId uuid.UUID, status model.PendingTransferStatus, approverId uuid.UUID) error {
+	const op = "repo.pgdb.PGPendingTransferRepo.Resolve"
+
+	query, args, err := r.Builder.
+		Update("pending_transfers").
+		Set("status", status).
+		Set("approver_id", approverId).
+		Set("resolved_at", squirrel.Expr("now()")).
+		Where("id = ?", transferId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrPendingTransferNotFound
+	}
+
+	return nil
+}
+
+func (r *PGPendingTransferRepo) selectPendingTransfers() squirrel.SelectBuilder {
+	return r.Builder.
+		Select("p.id, p.from_employee, f.username, p.to_employee, t.username, p.amount, coalesce(p.message, ''), " +
+			"coalesce(p.category, ''), p.status, p.approver_id, p.created_at, p.resolved_at").
+		From("pending_transfers p").
+		Join("employees f on f.id = p.from_employee").
+		Join("employees t on t.id = p.to_employee")
+}
+
+func scanPendingTransfer(row pgx.Row) (*model.PendingTransfer, error) {
+	var transfer model.PendingTransfer
+	err := row.Scan(
+		&transfer.Id,
+		&transfer.FromEmployee,
+		&transfer.From,
+		&transfer.ToEmployee,
+		&transfer.To,
+		&transfer.Amount,
+		&transfer.Message,
+		&transfer.Category,
+		&transfer.Status,
+		&transfer.ApproverId,
+		&transfer.CreatedAt,
+		&transfer.ResolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
@@ -53,3 +53,11 @@ func (p *Postgres) Close() {
 		p.Pool.Close()
 	}
 }
+
+// nullIfEmpty stores optional text columns as NULL rather than as an empty string.
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
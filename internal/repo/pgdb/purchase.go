@@ -0,0 +1,135 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PGPurchaseRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGPurchaseRepo(p *Postgres, c *trmpgx.CtxGetter) *PGPurchaseRepo {
+	return &PGPurchaseRepo{p, c}
+}
+
+func (r *PGPurchaseRepo) Save(ctx context.Context, purchase *model.Purchase) error {
+	const op = "repo.pgdb.PGPurchaseRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("purchases").
+		Columns("id, order_id, employee_id, item_id, quantity, price").
+		Values(purchase.Id, purchase.OrderId, purchase.EmployeeId, purchase.ItemId, purchase.Quantity, purchase.Price).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGPurchaseRepo) CountByEmployeeAndItem(
+	ctx context.Context, employeeId uuid.UUID, itemId uuid.UUID) (int, error) {
+	const op = "repo.pgdb.PGPurchaseRepo.CountByEmployeeAndItem"
+
+	query, args, err := r.Builder.
+		Select("coalesce(sum(p.quantity - p.returned_quantity), 0)").
+		From("purchases p").
+		Join("orders o on o.id = p.order_id").
+		Where("p.employee_id = ? AND p.item_id = ?", employeeId, itemId).
+		Where("o.status <> ?", model.OrderCancelled).
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var count int
+	if err = conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *PGPurchaseRepo) FindByIdForUpdate(ctx context.Context, purchaseId uuid.UUID) (*model.Purchase, error) {
+	const op = "repo.pgdb.PGPurchaseRepo.FindByIdForUpdate"
+
+	query, args, err := r.Builder.
+		Select("id, order_id, employee_id, item_id, quantity, returned_quantity, price, created_at").
+		From("purchases").
+		Where("id = ?", purchaseId).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var purchase model.Purchase
+	err = conn.QueryRow(ctx, query, args...).Scan(
+		&purchase.Id,
+		&purchase.OrderId,
+		&purchase.EmployeeId,
+		&purchase.ItemId,
+		&purchase.Quantity,
+		&purchase.ReturnedQuantity,
+		&purchase.Price,
+		&purchase.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrPurchaseNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &purchase, nil
+}
+
+func (r *PGPurchaseRepo) UpdateReturnedQuantity(ctx context.Context, purchaseId uuid.UUID, returned int) error {
+	const op = "repo.pgdb.PGPurchaseRepo.UpdateReturnedQuantity"
+
+	query, args, err := r.Builder.
+		Update("purchases").
+		Set("returned_quantity", returned).
+		Where("id = ?", purchaseId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrPurchaseNotFound
+	}
+
+	return nil
+}
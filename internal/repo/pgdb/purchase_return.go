@@ -0,0 +1,46 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+)
+
+type PGPurchaseReturnRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGPurchaseReturnRepo(p *Postgres, c *trmpgx.CtxGetter) *PGPurchaseReturnRepo {
+	return &PGPurchaseReturnRepo{p, c}
+}
+
+func (r *PGPurchaseReturnRepo) Save(ctx context.Context, purchaseReturn *model.PurchaseReturn) error {
+	const op = "repo.pgdb.PGPurchaseReturnRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("purchase_returns").
+		Columns("id, purchase_id, employee_id, quantity, amount").
+		Values(
+			purchaseReturn.Id,
+			purchaseReturn.PurchaseId,
+			purchaseReturn.EmployeeId,
+			purchaseReturn.Quantity,
+			purchaseReturn.Amount,
+		).
+		Suffix("RETURNING created_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&purchaseReturn.CreatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PGRefreshTokenRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGRefreshTokenRepo(p *Postgres, c *trmpgx.CtxGetter) *PGRefreshTokenRepo {
+	return &PGRefreshTokenRepo{p, c}
+}
+
+func (r *PGRefreshTokenRepo) Save(ctx context.Context, token *model.RefreshToken) error {
+	const op = "repo.pgdb.PGRefreshTokenRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("refresh_tokens").
+		Columns("id, session_id, token_hash, expires_at").
+		Values(token.Id, token.SessionId, token.TokenHash, token.ExpiresAt).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGRefreshTokenRepo) FindByHashForUpdate(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	const op = "repo.pgdb.PGRefreshTokenRepo.FindByHashForUpdate"
+
+	query, args, err := r.Builder.
+		Select("id, session_id, token_hash, expires_at, used_at").
+		From("refresh_tokens").
+		Where("token_hash = ?", tokenHash).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var token model.RefreshToken
+	err = conn.QueryRow(ctx, query, args...).
+		Scan(
+			&token.Id,
+			&token.SessionId,
+			&token.TokenHash,
+			&token.ExpiresAt,
+			&token.UsedAt,
+		)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRefreshTokenNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &token, nil
+}
+
+func (r *PGRefreshTokenRepo) MarkUsed(ctx context.Context, tokenId uuid.UUID) error {
+	const op = "repo.pgdb.PGRefreshTokenRepo.MarkUsed"
+
+	query, args, err := r.Builder.
+		Update("refresh_tokens").
+		Set("used_at", squirrel.Expr("now()")).
+		Where("id = ?", tokenId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PGScheduleRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGScheduleRepo(p *Postgres, c *trmpgx.CtxGetter) *PGScheduleRepo {
+	return &PGScheduleRepo{p, c}
+}
+
+func (r *PGScheduleRepo) Save(ctx context.Context, schedule *model.ScheduledTransfer) error {
+	const op = "repo.pgdb.PGScheduleRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("scheduled_transfers").
+		Columns("id, from_employee, to_employee, amount, message, category, recurrence, status, start_at, "+
+			"occurrence, next_run_at, next_attempt_at").
+		Values(schedule.Id, schedule.FromEmployee, schedule.ToEmployee, schedule.Amount,
+			nullIfEmpty(schedule.Message), nullIfEmpty(string(schedule.Category)), schedule.Recurrence,
+			schedule.Status, schedule.StartAt, schedule.Occurrence, schedule.NextRunAt, schedule.NextAttemptAt).
+		Suffix("RETURNING created_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&schedule.CreatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGScheduleRepo) FindByEmployee(
+	ctx context.Context, employeeId uuid.UUID) ([]model.ScheduledTransfer, error) {
+	const op = "repo.pgdb.PGScheduleRepo.FindByEmployee"
+
+	query, args, err := r.selectSchedules().
+		Where("s.from_employee = ?", employeeId).
+		OrderBy("s.created_at desc", "s.id desc").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	schedules, err := r.query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return schedules, nil
+}
+
+func (r *PGScheduleRepo) FindById(ctx context.Context, scheduleId uuid.UUID) (*model.ScheduledTransfer, error) {
+	const op = "repo.pgdb.PGScheduleRepo.FindById"
+
+	schedule, err := r.findOne(ctx, r.selectSchedules().Where("s.id = ?", scheduleId))
+	if err != nil {
+		if errors.Is(err, repo.ErrScheduleNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return schedule, nil
+}
+
+func (r *PGScheduleRepo) FindByIdForUpdate(
+	ctx context.Context, scheduleId uuid.UUID) (*model.ScheduledTransfer, error) {
+	const op = "repo.pgdb.PGScheduleRepo.FindByIdForUpdate"
+
+	schedule, err := r.findOne(ctx, r.selectSchedules().Where("s.id = ?", scheduleId).Suffix("FOR UPDATE OF s"))
+	if err != nil {
+		if errors.Is(err, repo.ErrScheduleNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return schedule, nil
+}
+
+// FindDueForUpdate skips schedules another replica is already running instead of waiting for them.
+func (r *PGScheduleRepo) FindDueForUpdate(ctx context.Context, limit int) ([]model.ScheduledTransfer, error) {
+	const op = "repo.pgdb.PGScheduleRepo.FindDueForUpdate"
+
+	query, args, err := r.selectSchedules().
+		Where("s.status = ?", model.ScheduleActive).
+		Where("s.next_attempt_at <= now()").
+		OrderBy("s.next_attempt_at").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE OF s SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	schedules, err := r.query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return schedules, nil
+}
+
+func (r *PGScheduleRepo) Update(ctx context.Context, schedule *model.ScheduledTransfer) error {
+	const op = "repo.pgdb.PGScheduleRepo.Update"
+
+	query, args, err := r.Builder.
+		Update("scheduled_transfers").
+		Set("status", schedule.Status).
+		Set("occurrence", schedule.Occurrence).
+		Set("next_run_at", schedule.NextRunAt).
+		Set("next_attempt_at", schedule.NextAttemptAt).
+		Set("attempts", schedule.Attempts).
+		Set("last_error", nullIfEmpty(schedule.LastError)).
+		Where("id = ?", schedule.Id).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrScheduleNotFound
+	}
+
+	return nil
+}
+
+func (r *PGScheduleRepo) SaveRun(ctx context.Context, run *model.ScheduledTransferRun) error {
+	const op = "repo.pgdb.PGScheduleRepo.SaveRun"
+
+	query, args, err := r.Builder.
+		Insert("scheduled_transfer_runs").
+		Columns("id, schedule_id, scheduled_at, attempt, status, reason").
+		Values(run.Id, run.ScheduleId, run.ScheduledAt, run.Attempt, run.Status, nullIfEmpty(run.Reason)).
+		Suffix("RETURNING created_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&run.CreatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGScheduleRepo) FindRuns(ctx context.Context, scheduleId uuid.UUID) ([]model.ScheduledTransferRun, error) {
+	const op = "repo.pgdb.PGScheduleRepo.FindRuns"
+
+	query, args, err := r.Builder.
+		Select("id, schedule_id, scheduled_at, attempt, status, coalesce(reason, ''), created_at").
+		From("scheduled_transfer_runs").
+		Where("schedule_id = ?", scheduleId).
+		OrderBy("created_at desc", "id desc").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var runs []model.ScheduledTransferRun
+	for rows.Next() {
+		var run model.ScheduledTransferRun
+		err = rows.Scan(&run.Id, &run.ScheduleId, &run.ScheduledAt, &run.Attempt, &run.Status, &run.Reason,
+			&run.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return runs, nil
+}
+
+func (r *PGScheduleRepo) selectSchedules() squirrel.SelectBuilder {
+	return r.Builder.
+		Select("s.id, s.from_employee, f.username, s.to_employee, t.username, s.amount, coalesce(s.message, ''), " +
+			"coalesce(s.category, ''), s.recurrence, s.status, s.start_at, s.occurrence, s.next_run_at, " +
+			"s.next_attempt_at, s.attempts, coalesce(s.last_error, ''), s.created_at").
+		From("scheduled_transfers s").
+		Join("employees f on f.id = s.from_employee").
+		Join("employees t on t.id = s.to_employee")
+}
+
+func (r *PGScheduleRepo) findOne(
+	ctx context.Context, builder squirrel.SelectBuilder) (*model.ScheduledTransfer, error) {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	schedule, err := scanSchedule(conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (r *PGScheduleRepo) query(ctx context.Context, query string, args []any) ([]model.ScheduledTransfer, error) {
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []model.ScheduledTransfer
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *schedule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func scanSchedule(row pgx.Row) (*model.ScheduledTransfer, error) {
+	var schedule model.ScheduledTransfer
+	err := row.Scan(
+		&schedule.Id,
+		&schedule.FromEmployee,
+		&schedule.From,
+		&schedule.ToEmployee,
+		&schedule.To,
+		&schedule.Amount,
+		&schedule.Message,
+		&schedule.Category,
+		&schedule.Recurrence,
+		&schedule.Status,
+		&schedule.StartAt,
+		&schedule.Occurrence,
+		&schedule.NextRunAt,
+		&schedule.NextAttemptAt,
+		&schedule.Attempts,
+		&schedule.LastError,
+		&schedule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
@@ -0,0 +1,135 @@
+package pgdb
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PGSessionRepo struct {
+	*Postgres
+	getter *trmpgx.CtxGetter
+}
+
+func NewPGSessionRepo(p *Postgres, c *trmpgx.CtxGetter) *PGSessionRepo {
+	return &PGSessionRepo{p, c}
+}
+
+func (r *PGSessionRepo) Save(ctx context.Context, session *model.Session) error {
+	const op = "repo.pgdb.PGSessionRepo.Save"
+
+	query, args, err := r.Builder.
+		Insert("sessions").
+		Columns("id, employee_id").
+		Values(session.Id, session.EmployeeId).
+		Suffix("RETURNING created_at").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	err = conn.QueryRow(ctx, query, args...).Scan(&session.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PGSessionRepo) FindByIdForUpdate(ctx context.Context, sessionId uuid.UUID) (*model.Session, error) {
+	const op = "repo.pgdb.PGSessionRepo.FindByIdForUpdate"
+
+	query, args, err := r.Builder.
+		Select("id, employee_id, revoked_at, created_at").
+		From("sessions").
+		Where("id = ?", sessionId).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var session model.Session
+	err = conn.QueryRow(ctx, query, args...).
+		Scan(
+			&session.Id,
+			&session.EmployeeId,
+			&session.RevokedAt,
+			&session.CreatedAt,
+		)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &session, nil
+}
+
+// Revoke keeps the first revocation time when the session is already revoked.
+func (r *PGSessionRepo) Revoke(ctx context.Context, sessionId uuid.UUID) error {
+	const op = "repo.pgdb.PGSessionRepo.Revoke"
+
+	query, args, err := r.Builder.
+		Update("sessions").
+		Set("revoked_at", squirrel.Expr("coalesce(revoked_at, now())")).
+		Where("id = ?", sessionId).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// IsActive reports whether the session exists, is not revoked and belongs to an employee who may still log in.
+func (r *PGSessionRepo) IsActive(ctx context.Context, sessionId uuid.UUID) (bool, error) {
+	const op = "repo.pgdb.PGSessionRepo.IsActive"
+
+	query, args, err := r.Builder.
+		Select("s.id").
+		From("sessions s").
+		Join("employees e ON e.id = s.employee_id").
+		Where("s.id = ?", sessionId).
+		Where("s.revoked_at IS NULL").
+		Where("e.status <> ?", model.EmployeeDeactivated).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var id uuid.UUID
+	if err = conn.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return true, nil
+}
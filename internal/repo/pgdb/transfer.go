@@ -4,8 +4,10 @@ import (
 	"avito-shop/internal/model"
 	"context"
 	"fmt"
+	"github.com/Masterminds/squirrel"
 	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
 	"github.com/google/uuid"
+	"time"
 )
 
 type PGTransferRepo struct {
@@ -22,8 +24,9 @@ func (r *PGTransferRepo) Save(ctx context.Context, transfer *model.Transfer) err
 
 	query, args, err := r.Builder.
 		Insert("transfers").
-		Columns("id, from_employee, to_employee, amount").
-		Values(transfer.Id, transfer.FromEmployee, transfer.ToEmployee, transfer.Amount).
+		Columns("id, from_employee, to_employee, amount, message, category").
+		Values(transfer.Id, transfer.FromEmployee, transfer.ToEmployee, transfer.Amount,
+			nullIfEmpty(transfer.Message), nullIfEmpty(string(transfer.Category))).
 		ToSql()
 
 	if err != nil {
@@ -121,3 +124,106 @@ func (r *PGTransferRepo) FindAllForSenderGroupedByReceivers(
 
 	return transactions, nil
 }
+
+// FindKudos returns transfers that carry a message or a category, newest first.
+func (r *PGTransferRepo) FindKudos(ctx context.Context, filter model.KudosFilter) ([]model.Kudos, error) {
+	const op = "repo.PGTransferRepo.FindKudos"
+
+	builder := r.Builder.
+		Select("t.id, s.username, rc.username, t.amount, coalesce(t.message, ''), coalesce(t.category, ''), " +
+			"t.created_at").
+		From("transfers t").
+		Join("employees s on s.id = t.from_employee").
+		Join("employees rc on rc.id = t.to_employee").
+		Where("(t.message is not null or t.category is not null)")
+
+	if filter.Category != "" {
+		builder = builder.Where("t.category = ?", filter.Category)
+	}
+	if filter.Cursor != nil {
+		builder = builder.Where("(t.created_at, t.id) < (?, ?)", filter.Cursor.CreatedAt, filter.Cursor.Id)
+	}
+
+	query, args, err := builder.
+		OrderBy("t.created_at desc", "t.id desc").
+		Limit(uint64(filter.Limit)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var kudos []model.Kudos
+	for rows.Next() {
+		var k model.Kudos
+		err = rows.Scan(&k.Id, &k.From, &k.To, &k.Amount, &k.Message, &k.Category, &k.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		kudos = append(kudos, k)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return kudos, nil
+}
+
+// FindVelocity sums the sender's outgoing transfers and marketplace purchases in each limit window. Transfers still
+// waiting for approval count as sent now, whenever they were created: they can settle no earlier than that, and
+// otherwise large amounts could be queued past the limits.
+func (r *PGTransferRepo) FindVelocity(ctx context.Context, q model.VelocityQuery) (*model.TransferVelocity, error) {
+	const op = "repo.PGTransferRepo.FindVelocity"
+
+	since := q.DaySince
+	for _, t := range []time.Time{q.HourSince, q.MonthSince} {
+		if t.Before(since) {
+			since = t
+		}
+	}
+
+	outgoing := squirrel.
+		Select("to_employee, amount, created_at").
+		From("transfers").
+		Where("from_employee = ?", q.FromEmployee).
+		Where("created_at >= ?", since).
+		Suffix("union all select to_employee, amount, now() from pending_transfers "+
+			"where from_employee = ? and status = ? and id <> ? "+
+			"union all select seller_id, price, closed_at from listings "+
+			"where buyer_id = ? and status = ? and closed_at >= ?",
+			q.FromEmployee, model.PendingTransferPending, q.ExcludePending,
+			q.FromEmployee, model.ListingSold, since)
+
+	query, args, err := r.Builder.
+		Select().
+		Column(squirrel.Expr("coalesce(sum(amount) filter (where created_at >= ?), 0)", q.DaySince)).
+		Column(squirrel.Expr("count(*) filter (where created_at >= ?)", q.HourSince)).
+		Column(squirrel.Expr("coalesce(sum(amount) filter (where to_employee = ? and created_at >= ?), 0)",
+			q.ToEmployee, q.MonthSince)).
+		FromSelect(outgoing, "t").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.Pool)
+
+	var velocity model.TransferVelocity
+	err = conn.QueryRow(ctx, query, args...).
+		Scan(&velocity.DailyAmount, &velocity.HourlyCount, &velocity.MonthlyRecipientAmount)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &velocity, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type AllowanceService struct {
+	trManager     TransactionManager
+	allowanceRepo AllowanceRepo
+	ledger        Ledger
+	policy        model.AllowancePolicy
+}
+
+func NewAllowanceService(
+	trManager TransactionManager,
+	allowanceRepo AllowanceRepo,
+	ledger Ledger,
+	policy model.AllowancePolicy,
+) *AllowanceService {
+	return &AllowanceService{
+		trManager:     trManager,
+		allowanceRepo: allowanceRepo,
+		ledger:        ledger,
+		policy:        policy,
+	}
+}
+
+// EmitDue credits the current period's allowance to up to limit employees who have not received it yet and
+// reports how many it processed. Each employee is credited in its own transaction, and the allowance row is
+// unique per employee and period, so a restart or a second replica never credits anyone twice.
+func (s *AllowanceService) EmitDue(ctx context.Context, limit int) (int, error) {
+	const op = "service.AllowanceService.EmitDue"
+
+	if !s.policy.Enabled() {
+		return 0, nil
+	}
+
+	period := s.policy.Period.Key(time.Now())
+
+	processed := 0
+	for processed < limit {
+		found := false
+		err := s.trManager.Do(ctx, func(ctx context.Context) error {
+			employees, err := s.allowanceRepo.FindEmployeesDueForUpdate(ctx, period, 1)
+			if err != nil || len(employees) == 0 {
+				return err
+			}
+
+			found = true
+			return s.emit(ctx, &employees[0], period)
+		})
+
+		if err != nil {
+			return processed, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !found {
+			break
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// emit records the allowance of a locked employee. An employee already at the balance cap gets an empty allowance,
+// which marks the period as done without crediting anything.
+func (s *AllowanceService) emit(ctx context.Context, employee *model.Employee, period string) error {
+	allowance := &model.Allowance{
+		Id:         uuid.New(),
+		EmployeeId: employee.Id,
+		Period:     period,
+		Amount:     s.amountFor(employee),
+	}
+
+	saved, err := s.allowanceRepo.Save(ctx, allowance)
+	if err != nil || !saved || allowance.Amount == 0 {
+		return err
+	}
+
+	return s.ledger.Allowance(ctx, allowance.Id, employee, allowance.Amount)
+}
+
+func (s *AllowanceService) amountFor(employee *model.Employee) int {
+	if s.policy.MaxBalance <= 0 {
+		return s.policy.Amount
+	}
+	return max(min(s.policy.Amount, s.policy.MaxBalance-employee.Balance), 0)
+}
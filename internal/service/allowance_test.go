@@ -0,0 +1,100 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestAllowanceService_EmitDue(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	period := model.AllowanceMonthly.Key(time.Now())
+
+	tests := []struct {
+		name           string
+		maxBalance     int
+		balance        int
+		saved          bool
+		expectedAmount int
+		expectGrant    bool
+	}{
+		{
+			name:           "uncapped allowance is credited in full",
+			balance:        5000,
+			saved:          true,
+			expectedAmount: 100,
+			expectGrant:    true,
+		},
+		{
+			name:           "allowance tops up to the cap",
+			maxBalance:     1030,
+			balance:        1000,
+			saved:          true,
+			expectedAmount: 30,
+			expectGrant:    true,
+		},
+		{
+			name:           "employee at the cap gets an empty allowance",
+			maxBalance:     1000,
+			balance:        1200,
+			saved:          true,
+			expectedAmount: 0,
+		},
+		{
+			name:           "allowance already granted is not credited again",
+			balance:        1000,
+			saved:          false,
+			expectedAmount: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAllowanceRepo := new(mockAllowanceRepo)
+			mockLedger := new(mockLedger)
+			allowanceService := NewAllowanceService(mockTrManager, mockAllowanceRepo, mockLedger,
+				model.AllowancePolicy{Amount: 100, MaxBalance: tt.maxBalance, Period: model.AllowanceMonthly})
+
+			employee := model.Employee{Id: uuid.New(), Username: "employee", Balance: tt.balance}
+			mockAllowanceRepo.On("FindEmployeesDueForUpdate", mock.Anything, period, 1).
+				Return([]model.Employee{employee}, nil).Once()
+			mockAllowanceRepo.On("FindEmployeesDueForUpdate", mock.Anything, period, 1).
+				Return([]model.Employee{}, nil).Once()
+
+			var allowanceId uuid.UUID
+			mockAllowanceRepo.On("Save", mock.Anything, mock.MatchedBy(func(a *model.Allowance) bool {
+				return a.EmployeeId == employee.Id && a.Period == period && a.Amount == tt.expectedAmount
+			})).Run(func(args mock.Arguments) {
+				allowanceId = args.Get(1).(*model.Allowance).Id
+			}).Return(tt.saved, nil)
+			if tt.expectGrant {
+				mockLedger.On("Allowance", mock.Anything, mock.MatchedBy(func(id uuid.UUID) bool {
+					return id == allowanceId
+				}), mock.Anything, tt.expectedAmount).Return(nil)
+			}
+
+			processed, err := allowanceService.EmitDue(context.Background(), 10)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, processed)
+			mockAllowanceRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAllowanceService_EmitDue_Disabled(t *testing.T) {
+	mockAllowanceRepo := new(mockAllowanceRepo)
+	allowanceService := NewAllowanceService(new(mockTransactionManager), mockAllowanceRepo, new(mockLedger),
+		model.AllowancePolicy{Period: model.AllowanceMonthly})
+
+	processed, err := allowanceService.EmitDue(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, processed)
+	mockAllowanceRepo.AssertNotCalled(t, "FindEmployeesDueForUpdate", mock.Anything, mock.Anything, mock.Anything)
+}
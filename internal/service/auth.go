@@ -4,6 +4,10 @@ import (
 	"avito-shop/internal/model"
 	"avito-shop/internal/repo"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt"
@@ -12,38 +16,70 @@ import (
 	"time"
 )
 
-const newEmployeeInitialBalance = 1000
+const (
+	newEmployeeInitialBalance = 1000
+	refreshTokenBytes         = 32
+)
 
 type TokenClaims struct {
 	jwt.StandardClaims
 	EmployeeId uuid.UUID
+	SessionId  uuid.UUID
 	Username   string
+	Role       model.Role
 }
 
 type AuthService struct {
-	employeeRepo EmployeeRepo
-	signKey      string
-	tokenTTL     time.Duration
-	trManager    TransactionManager
+	employeeRepo     EmployeeRepo
+	inviteRepo       InviteRepo
+	sessionRepo      SessionRepo
+	refreshTokenRepo RefreshTokenRepo
+	ledger           Ledger
+	signer           TokenSigner
+	tokenTTL         time.Duration
+	refreshTTL       time.Duration
+	registration     model.RegistrationMode
+	trManager        TransactionManager
 }
 
 func NewAuthService(
-	trManager TransactionManager, employeeRepo EmployeeRepo, signKey string, tokenTTL time.Duration) *AuthService {
+	trManager TransactionManager,
+	employeeRepo EmployeeRepo,
+	inviteRepo InviteRepo,
+	sessionRepo SessionRepo,
+	refreshTokenRepo RefreshTokenRepo,
+	ledger Ledger,
+	signer TokenSigner,
+	tokenTTL time.Duration,
+	refreshTTL time.Duration,
+	registration model.RegistrationMode,
+) *AuthService {
 	return &AuthService{
-		employeeRepo: employeeRepo,
-		signKey:      signKey,
-		tokenTTL:     tokenTTL,
-		trManager:    trManager,
+		employeeRepo:     employeeRepo,
+		inviteRepo:       inviteRepo,
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		ledger:           ledger,
+		signer:           signer,
+		tokenTTL:         tokenTTL,
+		refreshTTL:       refreshTTL,
+		registration:     registration,
+		trManager:        trManager,
 	}
 }
 
-func (s *AuthService) Authorize(ctx context.Context, username string, password string) (string, error) {
+// Authorize creates the account of an unknown username only in the auto registration mode; in the other modes
+// unknown usernames get ErrInvalidCredentials.
+func (s *AuthService) Authorize(ctx context.Context, username string, password string) (*model.AuthTokens, error) {
 	const op = "service.AuthService.Authorize"
 
-	var token string
+	var tokens *model.AuthTokens
 	err := s.trManager.Do(ctx, func(ctx context.Context) error {
-		employee, err := s.getOrCreateEmployee(ctx, username, password)
+		employee, err := s.findEmployee(ctx, username, password)
 		if err != nil {
+			if errors.Is(err, ErrInvalidCredentials) {
+				return err
+			}
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
@@ -51,7 +87,121 @@ func (s *AuthService) Authorize(ctx context.Context, username string, password s
 			return ErrInvalidCredentials
 		}
 
-		token, err = s.generateJWT(employee.Id, employee.Username)
+		if employee.Status == model.EmployeeDeactivated {
+			return ErrAccountDeactivated
+		}
+
+		tokens, err = s.startSession(ctx, employee)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Register creates an account and logs it in. In the invite-only mode it consumes inviteCode.
+func (s *AuthService) Register(
+	ctx context.Context, username string, password string, inviteCode string) (*model.AuthTokens, error) {
+	const op = "service.AuthService.Register"
+
+	var tokens *model.AuthTokens
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var invite *model.Invite
+		if s.registration == model.RegistrationInviteOnly {
+			var err error
+			if invite, err = s.findValidInvite(ctx, inviteCode); err != nil {
+				return err
+			}
+		}
+
+		employee, err := s.createNewEmployee(ctx, username, password)
+		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeExists) {
+				return ErrUsernameTaken
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if invite != nil {
+			if err = s.inviteRepo.MarkUsed(ctx, invite.Id, employee.Id); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		tokens, err = s.startSession(ctx, employee)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens in the same session. A refresh token works once:
+// presenting an already exchanged one means it leaked, so the whole session is revoked.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*model.AuthTokens, error) {
+	const op = "service.AuthService.Refresh"
+
+	var tokens *model.AuthTokens
+	reused := false
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		token, err := s.refreshTokenRepo.FindByHashForUpdate(ctx, hashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, repo.ErrRefreshTokenNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		session, err := s.sessionRepo.FindByIdForUpdate(ctx, token.SessionId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if session.RevokedAt != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		// the revocation must be committed, so the error is returned only after the transaction
+		if token.UsedAt != nil {
+			reused = true
+			if err = s.sessionRepo.Revoke(ctx, session.Id); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			return nil
+		}
+
+		if token.Expired(time.Now()) {
+			return ErrInvalidRefreshToken
+		}
+
+		employee, err := s.employeeRepo.FindById(ctx, session.EmployeeId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if employee.Status == model.EmployeeDeactivated {
+			return ErrAccountDeactivated
+		}
+
+		if err = s.refreshTokenRepo.MarkUsed(ctx, token.Id); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		tokens, err = s.issueTokens(ctx, employee, session.Id)
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
@@ -59,7 +209,100 @@ func (s *AuthService) Authorize(ctx context.Context, username string, password s
 		return nil
 	})
 
-	return token, err
+	if err != nil {
+		return nil, err
+	}
+
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes the session, which invalidates its refresh token and every access token issued for it.
+func (s *AuthService) Logout(ctx context.Context, sessionId uuid.UUID) error {
+	const op = "service.AuthService.Logout"
+
+	if err := s.sessionRepo.Revoke(ctx, sessionId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AccountStatus is checked on every request, so a deactivated employee loses access without waiting for their
+// tokens to expire.
+func (s *AuthService) AccountStatus(ctx context.Context, employeeId uuid.UUID) (model.EmployeeStatus, error) {
+	const op = "service.AuthService.AccountStatus"
+
+	employee, err := s.employeeRepo.FindById(ctx, employeeId)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return "", ErrEmployeeNotFound
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if employee.Status == "" {
+		return model.EmployeeActive, nil
+	}
+
+	return employee.Status, nil
+}
+
+func (s *AuthService) SessionActive(ctx context.Context, sessionId uuid.UUID) (bool, error) {
+	const op = "service.AuthService.SessionActive"
+
+	// tokens issued before sessions existed carry no session and are no longer accepted
+	if sessionId == uuid.Nil {
+		return false, nil
+	}
+
+	active, err := s.sessionRepo.IsActive(ctx, sessionId)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return active, nil
+}
+
+func (s *AuthService) findEmployee(ctx context.Context, username, password string) (*model.Employee, error) {
+	if s.registration == model.RegistrationAuto {
+		return s.getOrCreateEmployee(ctx, username, password)
+	}
+
+	employee, err := s.employeeRepo.FindByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	return employee, nil
+}
+
+// findValidInvite locks the invite, so two registrations can't both use it.
+func (s *AuthService) findValidInvite(ctx context.Context, code string) (*model.Invite, error) {
+	const op = "service.AuthService.findValidInvite"
+
+	if code == "" {
+		return nil, ErrInviteRequired
+	}
+
+	invite, err := s.inviteRepo.FindByCodeForUpdate(ctx, code)
+	if err != nil {
+		if errors.Is(err, repo.ErrInviteNotFound) {
+			return nil, ErrInvalidInvite
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if invite.UsedBy != nil || invite.Expired(time.Now()) {
+		return nil, ErrInvalidInvite
+	}
+
+	return invite, nil
 }
 
 func (s *AuthService) getOrCreateEmployee(ctx context.Context, username, password string) (*model.Employee, error) {
@@ -83,13 +326,18 @@ func (s *AuthService) createNewEmployee(ctx context.Context, username, password
 		Id:           uuid.New(),
 		Username:     username,
 		PasswordHash: string(hashedPassword),
-		Balance:      newEmployeeInitialBalance,
+		Role:         model.RoleEmployee,
+		Status:       model.EmployeeActive,
 	}
 
 	if err = s.employeeRepo.Save(ctx, newEmployee); err != nil {
 		return nil, err
 	}
 
+	if err = s.ledger.Grant(ctx, uuid.New(), newEmployee, newEmployeeInitialBalance); err != nil {
+		return nil, err
+	}
+
 	return newEmployee, nil
 }
 
@@ -97,16 +345,65 @@ func (s *AuthService) verifyPassword(storedHash, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(password))
 }
 
-func (s *AuthService) generateJWT(id uuid.UUID, username string) (string, error) {
+func (s *AuthService) startSession(ctx context.Context, employee *model.Employee) (*model.AuthTokens, error) {
+	session := &model.Session{Id: uuid.New(), EmployeeId: employee.Id}
+	if err := s.sessionRepo.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, employee, session.Id)
+}
+
+func (s *AuthService) issueTokens(
+	ctx context.Context, employee *model.Employee, sessionId uuid.UUID) (*model.AuthTokens, error) {
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.refreshTokenRepo.Save(ctx, &model.RefreshToken{
+		Id:        uuid.New(),
+		SessionId: sessionId,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.generateJWT(employee, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuthTokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+func (s *AuthService) generateJWT(employee *model.Employee, sessionId uuid.UUID) (string, error) {
 	expirationTime := time.Now().Add(s.tokenTTL)
 	claims := &TokenClaims{
-		Username:   username,
-		EmployeeId: id,
+		Username:   employee.Username,
+		EmployeeId: employee.Id,
+		SessionId:  sessionId,
+		Role:       employee.Role,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.signKey))
+	return s.signer.Sign(claims)
+}
+
+func newRefreshToken() (string, error) {
+	token := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashToken is enough for refresh tokens: they are random, so unlike passwords they need no slow hash.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"avito-shop/internal/lib/jwtkeys"
 	"avito-shop/internal/model"
 	"avito-shop/internal/repo"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -17,11 +20,18 @@ func TestAuthService_Authorize(t *testing.T) {
 	t.Parallel()
 
 	mockRepo := new(mockEmployeeRepo)
+	mockLedger := new(mockLedger)
 	mockTrManager := new(mockTransactionManager)
-	signKey := "test_key"
+	mockSessionRepo := new(mockSessionRepo)
+	mockRefreshTokenRepo := new(mockRefreshTokenRepo)
+	keys := newTestKeySet(t)
 	tokenTTL := time.Hour
 
-	authService := NewAuthService(mockTrManager, mockRepo, signKey, tokenTTL)
+	mockSessionRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockRefreshTokenRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	authService := NewAuthService(mockTrManager, mockRepo, nil, mockSessionRepo, mockRefreshTokenRepo, mockLedger,
+		keys, tokenTTL, time.Hour, model.RegistrationAuto)
 
 	existingUserID := uuid.New()
 	existingUsername := "existing_user"
@@ -67,6 +77,21 @@ func TestAuthService_Authorize(t *testing.T) {
 			expectedError: ErrInvalidCredentials,
 			expectToken:   false,
 		},
+		{
+			name: "deactivated account",
+			setup: func() {
+				mockRepo.ExpectedCalls = nil
+
+				mockRepo.On("FindByUsername", mock.Anything, existingUsername).
+					Return(&model.Employee{
+						Id: existingUserID, Username: existingUsername, PasswordHash: string(hashedPassword),
+						Status: model.EmployeeDeactivated}, nil)
+			},
+			username:      existingUsername,
+			password:      password,
+			expectedError: ErrAccountDeactivated,
+			expectToken:   false,
+		},
 		{
 			name: "creating new user",
 			setup: func() {
@@ -75,7 +100,12 @@ func TestAuthService_Authorize(t *testing.T) {
 				mockRepo.On("FindByUsername", mock.Anything, newUsername).
 					Return(nil, repo.ErrEmployeeNotFound)
 
-				mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*model.Employee")).
+				mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(e *model.Employee) bool {
+					return e.Role == model.RoleEmployee
+				})).
+					Return(nil)
+				mockLedger.On("Grant", mock.Anything, mock.Anything,
+					mock.AnythingOfType("*model.Employee"), newEmployeeInitialBalance).
 					Return(nil)
 			},
 			username:      newUsername,
@@ -88,7 +118,7 @@ func TestAuthService_Authorize(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setup()
-			token, err := authService.Authorize(context.Background(), tc.username, tc.password)
+			tokens, err := authService.Authorize(context.Background(), tc.username, tc.password)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -98,19 +128,325 @@ func TestAuthService_Authorize(t *testing.T) {
 			}
 
 			if tc.expectToken {
-				assert.NotEmpty(t, token)
+				assert.NotNil(t, tokens)
+				assert.NotEmpty(t, tokens.RefreshToken)
 
-				parsedToken, _ := jwt.ParseWithClaims(token, &TokenClaims{},
-					func(token *jwt.Token) (interface{}, error) {
-						return []byte(signKey), nil
-					})
+				claims := &TokenClaims{}
+				parsedToken, err := jwt.ParseWithClaims(tokens.AccessToken, claims, keys.VerificationKey)
 
-				assert.NotNil(t, parsedToken)
+				assert.NoError(t, err)
+				assert.Equal(t, "test-key", parsedToken.Header["kid"])
+				assert.NotEqual(t, uuid.Nil, claims.SessionId)
 			} else {
-				assert.Empty(t, token)
+				assert.Nil(t, tokens)
 			}
 
 			mockRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_Authorize_UnknownUserWithoutAutoRegistration(t *testing.T) {
+	mockRepo := new(mockEmployeeRepo)
+	mockLedger := new(mockLedger)
+	authService := NewAuthService(new(mockTransactionManager), mockRepo, nil, nil, nil, mockLedger,
+		newTestKeySet(t), time.Hour, time.Hour, model.RegistrationExplicit)
+
+	mockRepo.On("FindByUsername", mock.Anything, "typo_user").Return(nil, repo.ErrEmployeeNotFound)
+
+	tokens, err := authService.Authorize(context.Background(), "typo_user", "password")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Nil(t, tokens)
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestAuthService_Register(t *testing.T) {
+	validInvite := &model.Invite{Id: uuid.New(), Code: "valid", ExpiresAt: time.Now().Add(time.Hour)}
+	usedBy := uuid.New()
+
+	tests := []struct {
+		name          string
+		mode          model.RegistrationMode
+		inviteCode    string
+		setup         func(*mockEmployeeRepo, *mockInviteRepo, *mockLedger)
+		expectedError error
+	}{
+		{
+			name: "explicit registration",
+			mode: model.RegistrationExplicit,
+			setup: func(mer *mockEmployeeRepo, mir *mockInviteRepo, ml *mockLedger) {
+				mer.On("Save", mock.Anything, mock.MatchedBy(func(e *model.Employee) bool {
+					return e.Username == "new_user" && e.Status == model.EmployeeActive
+				})).Return(nil)
+				ml.On("Grant", mock.Anything, mock.Anything, mock.Anything, newEmployeeInitialBalance).Return(nil)
+			},
+		},
+		{
+			name: "username taken",
+			mode: model.RegistrationExplicit,
+			setup: func(mer *mockEmployeeRepo, mir *mockInviteRepo, ml *mockLedger) {
+				mer.On("Save", mock.Anything, mock.Anything).Return(repo.ErrEmployeeExists)
+			},
+			expectedError: ErrUsernameTaken,
+		},
+		{
+			name:       "registration with invite",
+			mode:       model.RegistrationInviteOnly,
+			inviteCode: "valid",
+			setup: func(mer *mockEmployeeRepo, mir *mockInviteRepo, ml *mockLedger) {
+				mir.On("FindByCodeForUpdate", mock.Anything, "valid").Return(validInvite, nil)
+				mer.On("Save", mock.Anything, mock.Anything).Return(nil)
+				ml.On("Grant", mock.Anything, mock.Anything, mock.Anything, newEmployeeInitialBalance).Return(nil)
+				mir.On("MarkUsed", mock.Anything, validInvite.Id, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:          "missing invite",
+			mode:          model.RegistrationInviteOnly,
+			setup:         func(*mockEmployeeRepo, *mockInviteRepo, *mockLedger) {},
+			expectedError: ErrInviteRequired,
+		},
+		{
+			name:       "unknown invite",
+			mode:       model.RegistrationInviteOnly,
+			inviteCode: "unknown",
+			setup: func(mer *mockEmployeeRepo, mir *mockInviteRepo, ml *mockLedger) {
+				mir.On("FindByCodeForUpdate", mock.Anything, "unknown").Return(nil, repo.ErrInviteNotFound)
+			},
+			expectedError: ErrInvalidInvite,
+		},
+		{
+			name:       "used invite",
+			mode:       model.RegistrationInviteOnly,
+			inviteCode: "used",
+			setup: func(mer *mockEmployeeRepo, mir *mockInviteRepo, ml *mockLedger) {
+				mir.On("FindByCodeForUpdate", mock.Anything, "used").Return(&model.Invite{
+					Id: uuid.New(), Code: "used", UsedBy: &usedBy, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+			},
+			expectedError: ErrInvalidInvite,
+		},
+		{
+			name:       "expired invite",
+			mode:       model.RegistrationInviteOnly,
+			inviteCode: "expired",
+			setup: func(mer *mockEmployeeRepo, mir *mockInviteRepo, ml *mockLedger) {
+				mir.On("FindByCodeForUpdate", mock.Anything, "expired").Return(&model.Invite{
+					Id: uuid.New(), Code: "expired", ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+			},
+			expectedError: ErrInvalidInvite,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockInviteRepo := new(mockInviteRepo)
+			mockLedger := new(mockLedger)
+			mockSessionRepo := new(mockSessionRepo)
+			mockRefreshTokenRepo := new(mockRefreshTokenRepo)
+			authService := NewAuthService(new(mockTransactionManager), mockEmployeeRepo, mockInviteRepo,
+				mockSessionRepo, mockRefreshTokenRepo, mockLedger, newTestKeySet(t), time.Hour, time.Hour, tc.mode)
+
+			mockSessionRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockRefreshTokenRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Maybe()
+			tc.setup(mockEmployeeRepo, mockInviteRepo, mockLedger)
+
+			tokens, err := authService.Register(context.Background(), "new_user", "password", tc.inviteCode)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, tokens)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, tokens.AccessToken)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockInviteRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
 		})
 	}
 }
+
+func TestAuthService_Refresh(t *testing.T) {
+	employee := &model.Employee{Id: uuid.New(), Username: "user", Status: model.EmployeeActive}
+	sessionId := uuid.New()
+	tokenId := uuid.New()
+	now := time.Now()
+
+	activeSession := &model.Session{Id: sessionId, EmployeeId: employee.Id}
+	freshToken := &model.RefreshToken{Id: tokenId, SessionId: sessionId, ExpiresAt: now.Add(time.Hour)}
+
+	tests := []struct {
+		name          string
+		setup         func(*mockEmployeeRepo, *mockSessionRepo, *mockRefreshTokenRepo)
+		expectedError error
+	}{
+		{
+			name: "rotation",
+			setup: func(mer *mockEmployeeRepo, msr *mockSessionRepo, mrr *mockRefreshTokenRepo) {
+				mrr.On("FindByHashForUpdate", mock.Anything, hashToken("refresh")).Return(freshToken, nil)
+				msr.On("FindByIdForUpdate", mock.Anything, sessionId).Return(activeSession, nil)
+				mer.On("FindById", mock.Anything, employee.Id).Return(employee, nil)
+				mrr.On("MarkUsed", mock.Anything, tokenId).Return(nil)
+				mrr.On("Save", mock.Anything, mock.MatchedBy(func(token *model.RefreshToken) bool {
+					return token.SessionId == sessionId && token.TokenHash != hashToken("refresh")
+				})).Return(nil)
+			},
+		},
+		{
+			name: "unknown token",
+			setup: func(mer *mockEmployeeRepo, msr *mockSessionRepo, mrr *mockRefreshTokenRepo) {
+				mrr.On("FindByHashForUpdate", mock.Anything, hashToken("refresh")).
+					Return(nil, repo.ErrRefreshTokenNotFound)
+			},
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "reused token revokes the session",
+			setup: func(mer *mockEmployeeRepo, msr *mockSessionRepo, mrr *mockRefreshTokenRepo) {
+				mrr.On("FindByHashForUpdate", mock.Anything, hashToken("refresh")).Return(&model.RefreshToken{
+					Id: tokenId, SessionId: sessionId, ExpiresAt: now.Add(time.Hour), UsedAt: &now}, nil)
+				msr.On("FindByIdForUpdate", mock.Anything, sessionId).Return(activeSession, nil)
+				msr.On("Revoke", mock.Anything, sessionId).Return(nil)
+			},
+			expectedError: ErrRefreshTokenReused,
+		},
+		{
+			name: "expired token",
+			setup: func(mer *mockEmployeeRepo, msr *mockSessionRepo, mrr *mockRefreshTokenRepo) {
+				mrr.On("FindByHashForUpdate", mock.Anything, hashToken("refresh")).Return(&model.RefreshToken{
+					Id: tokenId, SessionId: sessionId, ExpiresAt: now.Add(-time.Minute)}, nil)
+				msr.On("FindByIdForUpdate", mock.Anything, sessionId).Return(activeSession, nil)
+			},
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "revoked session",
+			setup: func(mer *mockEmployeeRepo, msr *mockSessionRepo, mrr *mockRefreshTokenRepo) {
+				mrr.On("FindByHashForUpdate", mock.Anything, hashToken("refresh")).Return(freshToken, nil)
+				msr.On("FindByIdForUpdate", mock.Anything, sessionId).Return(&model.Session{
+					Id: sessionId, EmployeeId: employee.Id, RevokedAt: &now}, nil)
+			},
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "deactivated employee",
+			setup: func(mer *mockEmployeeRepo, msr *mockSessionRepo, mrr *mockRefreshTokenRepo) {
+				mrr.On("FindByHashForUpdate", mock.Anything, hashToken("refresh")).Return(freshToken, nil)
+				msr.On("FindByIdForUpdate", mock.Anything, sessionId).Return(activeSession, nil)
+				mer.On("FindById", mock.Anything, employee.Id).Return(&model.Employee{
+					Id: employee.Id, Username: "user", Status: model.EmployeeDeactivated}, nil)
+			},
+			expectedError: ErrAccountDeactivated,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockSessionRepo := new(mockSessionRepo)
+			mockRefreshTokenRepo := new(mockRefreshTokenRepo)
+			authService := NewAuthService(new(mockTransactionManager), mockEmployeeRepo, nil, mockSessionRepo,
+				mockRefreshTokenRepo, nil, newTestKeySet(t), time.Hour, time.Hour, model.RegistrationAuto)
+
+			tc.setup(mockEmployeeRepo, mockSessionRepo, mockRefreshTokenRepo)
+
+			tokens, err := authService.Refresh(context.Background(), "refresh")
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, tokens)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, tokens.AccessToken)
+				assert.NotEqual(t, "refresh", tokens.RefreshToken)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockSessionRepo.AssertExpectations(t)
+			mockRefreshTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_SessionActive(t *testing.T) {
+	mockSessionRepo := new(mockSessionRepo)
+	authService := NewAuthService(new(mockTransactionManager), nil, nil, mockSessionRepo, nil, nil,
+		newTestKeySet(t), time.Hour, time.Hour, model.RegistrationAuto)
+
+	sessionId := uuid.New()
+	mockSessionRepo.On("IsActive", mock.Anything, sessionId).Return(true, nil)
+
+	active, err := authService.SessionActive(context.Background(), sessionId)
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	active, err = authService.SessionActive(context.Background(), uuid.Nil)
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_AccountStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		employee       *model.Employee
+		findErr        error
+		expectedStatus model.EmployeeStatus
+		expectedError  error
+	}{
+		{
+			name:           "deactivated employee",
+			employee:       &model.Employee{Status: model.EmployeeDeactivated},
+			expectedStatus: model.EmployeeDeactivated,
+		},
+		{
+			name:           "employee without status is active",
+			employee:       &model.Employee{},
+			expectedStatus: model.EmployeeActive,
+		},
+		{
+			name:          "unknown employee",
+			findErr:       repo.ErrEmployeeNotFound,
+			expectedError: ErrEmployeeNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			authService := NewAuthService(new(mockTransactionManager), mockEmployeeRepo, nil, nil, nil, nil,
+				newTestKeySet(t), time.Hour, time.Hour, model.RegistrationAuto)
+
+			employeeId := uuid.New()
+			mockEmployeeRepo.On("FindById", mock.Anything, employeeId).Return(tc.employee, tc.findErr)
+
+			status, err := authService.AccountStatus(context.Background(), employeeId)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedStatus, status)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func newTestKeySet(t *testing.T) *jwtkeys.KeySet {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	keys, err := jwtkeys.NewKeySet("test-key",
+		jwtkeys.Key{Id: "test-key", Method: jwt.SigningMethodEdDSA, Private: private, Public: public})
+	assert.NoError(t, err)
+
+	return keys
+}
@@ -3,24 +3,42 @@ package service
 import (
 	"avito-shop/internal/model"
 	"context"
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/settings"
+	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
+	"time"
 )
 
 type EmployeeRepo interface {
 	Save(ctx context.Context, employee *model.Employee) error
 	FindByUsername(ctx context.Context, username string) (*model.Employee, error)
+	FindByUsernameForUpdate(ctx context.Context, username string) (*model.Employee, error)
+	FindById(ctx context.Context, employeeId uuid.UUID) (*model.Employee, error)
+	FindByIdForUpdate(ctx context.Context, employeeId uuid.UUID) (*model.Employee, error)
 	UpdateByUsername(ctx context.Context, username string, employee *model.Employee) error
+	UpdateManager(ctx context.Context, employeeId uuid.UUID, managerId *uuid.UUID) error
+	UpdateStatus(ctx context.Context, employeeId uuid.UUID, status model.EmployeeStatus) error
+	UpdateRole(ctx context.Context, employeeId uuid.UUID, role model.Role) error
 }
 
 type TransferRepo interface {
 	Save(ctx context.Context, transfer *model.Transfer) error
 	FindAllForReceiverGroupedBySenders(ctx context.Context, receiverId uuid.UUID) ([]model.CoinTransaction, error)
 	FindAllForSenderGroupedByReceivers(ctx context.Context, senderId uuid.UUID) ([]model.CoinTransaction, error)
+	FindKudos(ctx context.Context, filter model.KudosFilter) ([]model.Kudos, error)
+	FindVelocity(ctx context.Context, query model.VelocityQuery) (*model.TransferVelocity, error)
 }
 
 type ItemRepo interface {
+	Save(ctx context.Context, item *model.Item) error
 	FindByName(ctx context.Context, itemName string) (*model.Item, error)
+	FindByNameForUpdate(ctx context.Context, itemName string) (*model.Item, error)
 	FindById(ctx context.Context, itemId uuid.UUID) (*model.Item, error)
+	FindAll(ctx context.Context, filter model.ItemFilter) ([]model.Item, error)
+	UpdateById(ctx context.Context, itemId uuid.UUID, item *model.Item) error
+	DecrementStock(ctx context.Context, itemId uuid.UUID, quantity int) (bool, error)
+	IncrementStock(ctx context.Context, itemId uuid.UUID, quantity int) error
 }
 
 type InventoryRepo interface {
@@ -30,6 +48,152 @@ type InventoryRepo interface {
 	UpdateById(ctx context.Context, id uuid.UUID, employeeInventory *model.EmployeeInventory) error
 }
 
+type PurchaseRepo interface {
+	Save(ctx context.Context, purchase *model.Purchase) error
+	FindByIdForUpdate(ctx context.Context, purchaseId uuid.UUID) (*model.Purchase, error)
+	UpdateReturnedQuantity(ctx context.Context, purchaseId uuid.UUID, returned int) error
+	CountByEmployeeAndItem(ctx context.Context, employeeId uuid.UUID, itemId uuid.UUID) (int, error)
+}
+
+type OrderRepo interface {
+	Save(ctx context.Context, order *model.Order) error
+	FindByIdForUpdate(ctx context.Context, orderId uuid.UUID) (*model.Order, error)
+	FindAll(ctx context.Context, filter model.OrderFilter) ([]model.Order, error)
+	UpdateStatus(ctx context.Context, orderId uuid.UUID, status model.OrderStatus) error
+}
+
+type PurchaseReturnRepo interface {
+	Save(ctx context.Context, purchaseReturn *model.PurchaseReturn) error
+}
+
+type GiftRepo interface {
+	Save(ctx context.Context, gift *model.Gift) error
+}
+
+type ListingRepo interface {
+	Save(ctx context.Context, listing *model.Listing) error
+	FindByIdForUpdate(ctx context.Context, listingId uuid.UUID) (*model.Listing, error)
+	FindActive(ctx context.Context, filter model.ListingFilter) ([]model.Listing, error)
+	FindExpiredForUpdate(ctx context.Context, limit int) ([]model.Listing, error)
+	Close(ctx context.Context, listingId uuid.UUID, status model.ListingStatus, buyerId *uuid.UUID) error
+}
+
+type ScheduleRepo interface {
+	Save(ctx context.Context, schedule *model.ScheduledTransfer) error
+	FindById(ctx context.Context, scheduleId uuid.UUID) (*model.ScheduledTransfer, error)
+	FindByIdForUpdate(ctx context.Context, scheduleId uuid.UUID) (*model.ScheduledTransfer, error)
+	FindByEmployee(ctx context.Context, employeeId uuid.UUID) ([]model.ScheduledTransfer, error)
+	FindDueForUpdate(ctx context.Context, limit int) ([]model.ScheduledTransfer, error)
+	Update(ctx context.Context, schedule *model.ScheduledTransfer) error
+	SaveRun(ctx context.Context, run *model.ScheduledTransferRun) error
+	FindRuns(ctx context.Context, scheduleId uuid.UUID) ([]model.ScheduledTransferRun, error)
+}
+
+type PaymentRequestRepo interface {
+	Save(ctx context.Context, request *model.PaymentRequest) error
+	FindByIdForUpdate(ctx context.Context, requestId uuid.UUID) (*model.PaymentRequest, error)
+	FindByEmployee(
+		ctx context.Context, employeeId uuid.UUID, filter model.PaymentRequestFilter) ([]model.PaymentRequest, error)
+	Resolve(ctx context.Context, requestId uuid.UUID, status model.PaymentRequestStatus) error
+}
+
+type PendingTransferRepo interface {
+	Save(ctx context.Context, transfer *model.PendingTransfer) error
+	FindByIdForUpdate(ctx context.Context, transferId uuid.UUID) (*model.PendingTransfer, error)
+	FindPending(ctx context.Context, managerId *uuid.UUID) ([]model.PendingTransfer, error)
+	Resolve(
+		ctx context.Context, transferId uuid.UUID, status model.PendingTransferStatus, approverId uuid.UUID) error
+}
+
+type AdjustmentRepo interface {
+	Save(ctx context.Context, adjustment *model.BalanceAdjustment) error
+}
+
+type InviteRepo interface {
+	Save(ctx context.Context, invite *model.Invite) error
+	FindByCodeForUpdate(ctx context.Context, code string) (*model.Invite, error)
+	MarkUsed(ctx context.Context, inviteId uuid.UUID, employeeId uuid.UUID) error
+}
+
+type SessionRepo interface {
+	Save(ctx context.Context, session *model.Session) error
+	FindByIdForUpdate(ctx context.Context, sessionId uuid.UUID) (*model.Session, error)
+	Revoke(ctx context.Context, sessionId uuid.UUID) error
+	IsActive(ctx context.Context, sessionId uuid.UUID) (bool, error)
+}
+
+type RefreshTokenRepo interface {
+	Save(ctx context.Context, token *model.RefreshToken) error
+	FindByHashForUpdate(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	MarkUsed(ctx context.Context, tokenId uuid.UUID) error
+}
+
+type AllowanceRepo interface {
+	Save(ctx context.Context, allowance *model.Allowance) (bool, error)
+	FindEmployeesDueForUpdate(ctx context.Context, period string, limit int) ([]model.Employee, error)
+}
+
+type HistoryRepo interface {
+	FindByEmployee(ctx context.Context, employeeId uuid.UUID, filter model.HistoryFilter) ([]model.HistoryEntry, error)
+}
+
+type IdempotencyRepo interface {
+	Reserve(ctx context.Context, record *model.IdempotencyRecord) (bool, error)
+	FindByEmployeeAndKey(ctx context.Context, employeeId uuid.UUID, key string) (*model.IdempotencyRecord, error)
+	SaveResponse(ctx context.Context, record *model.IdempotencyRecord) error
+}
+
+type LedgerRepo interface {
+	SaveAll(ctx context.Context, entries []model.LedgerEntry) error
+	BalanceByEmployee(ctx context.Context, employeeId uuid.UUID) (int, error)
+	FindMismatches(ctx context.Context) ([]model.LedgerMismatch, error)
+}
+
+type CoinLotRepo interface {
+	SaveAll(ctx context.Context, lots []model.CoinLot) error
+	FindActiveByEmployee(ctx context.Context, employeeId uuid.UUID) ([]model.CoinLot, error)
+	FindActiveGrantedBefore(ctx context.Context, employeeId uuid.UUID, before time.Time) ([]model.CoinLot, error)
+	UpdateRemaining(ctx context.Context, lotId uuid.UUID, remaining int) error
+	FindEmployeesWithExpired(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+	SaveSpent(ctx context.Context, spent []model.SpentCoins) error
+	FindSpentByOperationForUpdate(ctx context.Context, operationId uuid.UUID) ([]model.SpentCoins, error)
+	UpdateSpentRemaining(ctx context.Context, spentId uuid.UUID, remaining int) error
+}
+
+type Ledger interface {
+	Transfer(ctx context.Context, operationId uuid.UUID, from *model.Employee, to *model.Employee, amount int) error
+	Sale(ctx context.Context, operationId uuid.UUID, buyer *model.Employee, seller *model.Employee, amount int) error
+	Purchase(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error
+	Refund(ctx context.Context, operationId uuid.UUID, orderId uuid.UUID, employee *model.Employee, amount int) error
+	Return(ctx context.Context, operationId uuid.UUID, orderId uuid.UUID, employee *model.Employee, amount int) error
+	Grant(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error
+	Adjust(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error
+	Allowance(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error
+	Expire(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error
+	Sweep(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error
+}
+
+// CoinSender returns a non-nil PendingTransfer when the amount needs approval and the coins were only held.
+type CoinSender interface {
+	SendCoins(
+		ctx context.Context, fromUsername string, toUsername string, amount int, memo model.TransferMemo,
+	) (*model.PendingTransfer, error)
+}
+
+// SalePolicy holds marketplace sales to the same limits as transfers.
+type SalePolicy interface {
+	CheckSale(ctx context.Context, buyer *model.Employee, seller *model.Employee, amount int) error
+}
+
+type TokenSigner interface {
+	Sign(claims jwt.Claims) (string, error)
+}
+
 type TransactionManager interface {
 	Do(ctx context.Context, fn func(context.Context) error) error
+	DoWithSettings(ctx context.Context, s trm.Settings, fn func(context.Context) error) error
 }
+
+// savepoint runs a closure in a nested transaction: when it fails, its writes are rolled back and the outer
+// transaction can still record the failure.
+var savepoint = settings.Must(settings.WithPropagation(trm.PropagationNested))
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"unicode/utf8"
+)
+
+type EmployeeService struct {
+	trManager      TransactionManager
+	employeeRepo   EmployeeRepo
+	adjustmentRepo AdjustmentRepo
+	ledger         Ledger
+}
+
+func NewEmployeeService(
+	trManager TransactionManager,
+	employeeRepo EmployeeRepo,
+	adjustmentRepo AdjustmentRepo,
+	ledger Ledger,
+) *EmployeeService {
+	return &EmployeeService{
+		trManager:      trManager,
+		employeeRepo:   employeeRepo,
+		adjustmentRepo: adjustmentRepo,
+		ledger:         ledger,
+	}
+}
+
+// SetManager makes managerUsername the approver of the employee's large transfers; an empty manager clears it.
+func (s *EmployeeService) SetManager(ctx context.Context, username string, managerUsername string) error {
+	const op = "service.EmployeeService.SetManager"
+
+	if username == managerUsername {
+		return ErrSelfManager
+	}
+
+	return s.trManager.Do(ctx, func(ctx context.Context) error {
+		employee, err := s.employeeRepo.FindByUsername(ctx, username)
+		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeNotFound) {
+				return ErrEmployeeNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		var managerId *uuid.UUID
+		if managerUsername != "" {
+			manager, err := s.employeeRepo.FindByUsername(ctx, managerUsername)
+			if err != nil {
+				if errors.Is(err, repo.ErrEmployeeNotFound) {
+					return ErrManagerNotFound
+				}
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			managerId = &manager.Id
+		}
+
+		if err = s.employeeRepo.UpdateManager(ctx, employee.Id, managerId); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+}
+
+// Adjust corrects the employee's balance by a signed amount on behalf of an admin and records who did it and why.
+// Coins held for pending transfers can't be taken away.
+func (s *EmployeeService) Adjust(
+	ctx context.Context, adminId uuid.UUID, username string, amount int, reason string,
+) (*model.BalanceAdjustment, error) {
+	const op = "service.EmployeeService.Adjust"
+
+	if amount == 0 {
+		return nil, ErrZeroAdjustment
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrAdjustmentReasonRequired
+	}
+	if utf8.RuneCountInString(reason) > model.MaxAdjustmentReasonLength {
+		return nil, ErrAdjustmentReasonTooLong
+	}
+
+	var adjustment *model.BalanceAdjustment
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		employee, err := s.employeeRepo.FindByUsernameForUpdate(ctx, username)
+		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeNotFound) {
+				return ErrEmployeeNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		adjustment = &model.BalanceAdjustment{
+			Id:         uuid.New(),
+			EmployeeId: employee.Id,
+			Employee:   employee.Username,
+			AdminId:    adminId,
+			Amount:     amount,
+			Reason:     reason,
+		}
+
+		if err = s.ledger.Adjust(ctx, adjustment.Id, employee, amount); err != nil {
+			return err
+		}
+
+		if err = s.adjustmentRepo.Save(ctx, adjustment); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		adjustment.Balance = employee.Balance
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustment, nil
+}
+
+// SetStatus freezes, deactivates or reactivates the employee. Frozen employees keep read access but can't move coins,
+// deactivated ones can't log in either.
+func (s *EmployeeService) SetStatus(ctx context.Context, username string, status model.EmployeeStatus) error {
+	const op = "service.EmployeeService.SetStatus"
+
+	if !status.Valid() {
+		return ErrInvalidEmployeeStatus
+	}
+
+	return s.trManager.Do(ctx, func(ctx context.Context) error {
+		employee, err := s.employeeRepo.FindByUsernameForUpdate(ctx, username)
+		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeNotFound) {
+				return ErrEmployeeNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = s.employeeRepo.UpdateStatus(ctx, employee.Id, status); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+}
+
+// PromoteAdmins gives the admin role to the listed employees that have already registered and returns how many
+// were promoted. Unregistered names are skipped: registering under one of them must not grant the role.
+func (s *EmployeeService) PromoteAdmins(ctx context.Context, usernames []string) (int, error) {
+	const op = "service.EmployeeService.PromoteAdmins"
+
+	promoted := 0
+	for _, username := range usernames {
+		employee, err := s.employeeRepo.FindByUsername(ctx, username)
+		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeNotFound) {
+				continue
+			}
+			return promoted, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if employee.Role == model.RoleAdmin {
+			continue
+		}
+
+		if err = s.employeeRepo.UpdateRole(ctx, employee.Id, model.RoleAdmin); err != nil {
+			return promoted, fmt.Errorf("%s: %w", op, err)
+		}
+		promoted++
+	}
+
+	return promoted, nil
+}
+
+// Sweep moves the available balance of a deactivated employee to the company pool and returns the swept amount.
+// Coins held for pending transfers stay until the transfers are resolved.
+func (s *EmployeeService) Sweep(ctx context.Context, username string) (*model.Employee, int, error) {
+	const op = "service.EmployeeService.Sweep"
+
+	var employee *model.Employee
+	var amount int
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		employee, err = s.employeeRepo.FindByUsernameForUpdate(ctx, username)
+		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeNotFound) {
+				return ErrEmployeeNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if employee.Status != model.EmployeeDeactivated {
+			return ErrEmployeeNotDeactivated
+		}
+
+		amount = employee.Available()
+		if amount == 0 {
+			return nil
+		}
+
+		return s.ledger.Sweep(ctx, uuid.New(), employee, amount)
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return employee, amount, nil
+}
+
+// checkActive rejects coin operations of frozen and deactivated employees.
+func checkActive(employee *model.Employee) error {
+	switch employee.Status {
+	case model.EmployeeFrozen:
+		return ErrAccountFrozen
+	case model.EmployeeDeactivated:
+		return ErrAccountDeactivated
+	}
+	return nil
+}
+
+// checkParties rejects a coin movement unless both the sender and the receiver are active.
+func checkParties(from *model.Employee, to *model.Employee) error {
+	if err := checkActive(from); err != nil {
+		return err
+	}
+	if checkActive(to) != nil {
+		return fmt.Errorf("%w: %s", ErrReceiverNotActive, to.Username)
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+)
+
+func TestEmployeeService_SetManager(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	employee := &model.Employee{Id: uuid.New(), Username: "employee"}
+	manager := &model.Employee{Id: uuid.New(), Username: "manager"}
+
+	tests := []struct {
+		name          string
+		manager       string
+		setup         func(*mockEmployeeRepo)
+		expectedError error
+	}{
+		{
+			name:    "assign manager",
+			manager: "manager",
+			setup: func(mer *mockEmployeeRepo) {
+				mer.On("FindByUsername", mock.Anything, "employee").Return(employee, nil)
+				mer.On("FindByUsername", mock.Anything, "manager").Return(manager, nil)
+				mer.On("UpdateManager", mock.Anything, employee.Id, &manager.Id).Return(nil)
+			},
+		},
+		{
+			name: "clear manager",
+			setup: func(mer *mockEmployeeRepo) {
+				mer.On("FindByUsername", mock.Anything, "employee").Return(employee, nil)
+				mer.On("UpdateManager", mock.Anything, employee.Id, (*uuid.UUID)(nil)).Return(nil)
+			},
+		},
+		{
+			name:          "self manager",
+			manager:       "employee",
+			setup:         func(*mockEmployeeRepo) {},
+			expectedError: ErrSelfManager,
+		},
+		{
+			name:    "unknown manager",
+			manager: "manager",
+			setup: func(mer *mockEmployeeRepo) {
+				mer.On("FindByUsername", mock.Anything, "employee").Return(employee, nil)
+				mer.On("FindByUsername", mock.Anything, "manager").Return(nil, repo.ErrEmployeeNotFound)
+			},
+			expectedError: ErrManagerNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			employeeService := NewEmployeeService(mockTrManager, mockEmployeeRepo, nil, nil)
+
+			tc.setup(mockEmployeeRepo)
+
+			err := employeeService.SetManager(context.Background(), "employee", tc.manager)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmployeeService_Adjust(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	adminId := uuid.New()
+
+	tests := []struct {
+		name            string
+		amount          int
+		reason          string
+		setup           func(*mockEmployeeRepo, *mockAdjustmentRepo, *mockLedger)
+		expectedError   error
+		expectedBalance int
+	}{
+		{
+			name:   "credit with reason",
+			amount: 150,
+			reason: "  refund for broken mug ",
+			setup: func(mer *mockEmployeeRepo, mar *mockAdjustmentRepo, ml *mockLedger) {
+				employee := &model.Employee{Id: uuid.New(), Username: "employee", Balance: 500}
+				mer.On("FindByUsernameForUpdate", mock.Anything, "employee").Return(employee, nil)
+				ml.On("Adjust", mock.Anything, mock.Anything, employee, 150).
+					Run(func(args mock.Arguments) { employee.Balance += 150 }).
+					Return(nil)
+				mar.On("Save", mock.Anything, mock.MatchedBy(func(a *model.BalanceAdjustment) bool {
+					return a.EmployeeId == employee.Id && a.AdminId == adminId && a.Amount == 150 &&
+						a.Reason == "refund for broken mug"
+				})).Return(nil)
+			},
+			expectedBalance: 650,
+		},
+		{
+			name:   "debit beyond available coins",
+			amount: -600,
+			reason: "duplicate grant",
+			setup: func(mer *mockEmployeeRepo, mar *mockAdjustmentRepo, ml *mockLedger) {
+				employee := &model.Employee{Id: uuid.New(), Username: "employee", Balance: 500}
+				mer.On("FindByUsernameForUpdate", mock.Anything, "employee").Return(employee, nil)
+				ml.On("Adjust", mock.Anything, mock.Anything, employee, -600).Return(ErrNotEnoughCoins)
+			},
+			expectedError: ErrNotEnoughCoins,
+		},
+		{
+			name:          "zero amount",
+			reason:        "nothing",
+			setup:         func(*mockEmployeeRepo, *mockAdjustmentRepo, *mockLedger) {},
+			expectedError: ErrZeroAdjustment,
+		},
+		{
+			name:          "blank reason",
+			amount:        10,
+			reason:        "   ",
+			setup:         func(*mockEmployeeRepo, *mockAdjustmentRepo, *mockLedger) {},
+			expectedError: ErrAdjustmentReasonRequired,
+		},
+		{
+			name:          "reason too long",
+			amount:        10,
+			reason:        strings.Repeat("a", model.MaxAdjustmentReasonLength+1),
+			setup:         func(*mockEmployeeRepo, *mockAdjustmentRepo, *mockLedger) {},
+			expectedError: ErrAdjustmentReasonTooLong,
+		},
+		{
+			name:   "unknown employee",
+			amount: 10,
+			reason: "bonus",
+			setup: func(mer *mockEmployeeRepo, mar *mockAdjustmentRepo, ml *mockLedger) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "employee").Return(nil, repo.ErrEmployeeNotFound)
+			},
+			expectedError: ErrEmployeeNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockAdjustmentRepo := new(mockAdjustmentRepo)
+			mockLedger := new(mockLedger)
+			employeeService := NewEmployeeService(mockTrManager, mockEmployeeRepo, mockAdjustmentRepo, mockLedger)
+
+			tc.setup(mockEmployeeRepo, mockAdjustmentRepo, mockLedger)
+
+			adjustment, err := employeeService.Adjust(context.Background(), adminId, "employee", tc.amount, tc.reason)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBalance, adjustment.Balance)
+				assert.Equal(t, "employee", adjustment.Employee)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockAdjustmentRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmployeeService_SetStatus(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	employee := &model.Employee{Id: uuid.New(), Username: "employee"}
+
+	tests := []struct {
+		name          string
+		status        model.EmployeeStatus
+		setup         func(*mockEmployeeRepo)
+		expectedError error
+	}{
+		{
+			name:   "freeze",
+			status: model.EmployeeFrozen,
+			setup: func(mer *mockEmployeeRepo) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "employee").Return(employee, nil)
+				mer.On("UpdateStatus", mock.Anything, employee.Id, model.EmployeeFrozen).Return(nil)
+			},
+		},
+		{
+			name:          "unknown status",
+			status:        "banned",
+			setup:         func(*mockEmployeeRepo) {},
+			expectedError: ErrInvalidEmployeeStatus,
+		},
+		{
+			name:   "unknown employee",
+			status: model.EmployeeDeactivated,
+			setup: func(mer *mockEmployeeRepo) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "employee").Return(nil, repo.ErrEmployeeNotFound)
+			},
+			expectedError: ErrEmployeeNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			employeeService := NewEmployeeService(mockTrManager, mockEmployeeRepo, nil, nil)
+
+			tc.setup(mockEmployeeRepo)
+
+			err := employeeService.SetStatus(context.Background(), "employee", tc.status)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmployeeService_PromoteAdmins(t *testing.T) {
+	mockEmployeeRepo := new(mockEmployeeRepo)
+	employeeService := NewEmployeeService(new(mockTransactionManager), mockEmployeeRepo, nil, nil)
+
+	employee := &model.Employee{Id: uuid.New(), Username: "lead", Role: model.RoleEmployee}
+	admin := &model.Employee{Id: uuid.New(), Username: "admin", Role: model.RoleAdmin}
+
+	mockEmployeeRepo.On("FindByUsername", mock.Anything, "lead").Return(employee, nil)
+	mockEmployeeRepo.On("FindByUsername", mock.Anything, "admin").Return(admin, nil)
+	mockEmployeeRepo.On("FindByUsername", mock.Anything, "newcomer").Return(nil, repo.ErrEmployeeNotFound)
+	mockEmployeeRepo.On("UpdateRole", mock.Anything, employee.Id, model.RoleAdmin).Return(nil)
+
+	promoted, err := employeeService.PromoteAdmins(context.Background(), []string{"lead", "admin", "newcomer"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, promoted)
+
+	mockEmployeeRepo.AssertExpectations(t)
+}
+
+func TestEmployeeService_Sweep(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+
+	tests := []struct {
+		name           string
+		employee       *model.Employee
+		setup          func(*mockLedger, *model.Employee)
+		expectedError  error
+		expectedAmount int
+	}{
+		{
+			name: "sweep available coins",
+			employee: &model.Employee{
+				Id: uuid.New(), Username: "employee", Balance: 900, Held: 200, Status: model.EmployeeDeactivated},
+			setup: func(ml *mockLedger, employee *model.Employee) {
+				ml.On("Sweep", mock.Anything, mock.Anything, employee, 700).Return(nil)
+			},
+			expectedAmount: 700,
+		},
+		{
+			name: "nothing to sweep",
+			employee: &model.Employee{
+				Id: uuid.New(), Username: "employee", Balance: 200, Held: 200, Status: model.EmployeeDeactivated},
+			setup: func(*mockLedger, *model.Employee) {},
+		},
+		{
+			name: "frozen employee",
+			employee: &model.Employee{
+				Id: uuid.New(), Username: "employee", Balance: 900, Status: model.EmployeeFrozen},
+			setup:         func(*mockLedger, *model.Employee) {},
+			expectedError: ErrEmployeeNotDeactivated,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockLedger := new(mockLedger)
+			employeeService := NewEmployeeService(mockTrManager, mockEmployeeRepo, nil, mockLedger)
+
+			mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "employee").Return(tc.employee, nil)
+			tc.setup(mockLedger, tc.employee)
+
+			_, amount, err := employeeService.Sweep(context.Background(), "employee")
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedAmount, amount)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
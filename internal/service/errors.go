@@ -1,16 +1,116 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUsernameTaken      = errors.New("username is already taken")
+	ErrInviteRequired     = errors.New("invite code is required")
+	ErrInvalidInvite      = errors.New("invite code is invalid, used or expired")
+
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+
+	ErrAccountFrozen          = errors.New("account is frozen")
+	ErrAccountDeactivated     = errors.New("account is deactivated")
+	ErrReceiverNotActive      = errors.New("receiver account is not active")
+	ErrInvalidEmployeeStatus  = errors.New("invalid employee status")
+	ErrEmployeeNotDeactivated = errors.New("employee is not deactivated")
 
-	ErrNotEnoughCoins         = errors.New("not enough coins")
-	ErrNegativeTransferAmount = errors.New("negative transfer amount")
-	ErrReceiverNotFound       = errors.New("receiver not found")
-	ErrSenderNotFound         = errors.New("sender not found")
-	ErrTransferToSameEmployee = errors.New("transfer to same employee")
+	ErrNotEnoughCoins            = errors.New("not enough coins")
+	ErrNonPositiveTransferAmount = errors.New("transfer amount must be positive")
+	ErrReceiverNotFound          = errors.New("receiver not found")
+	ErrSenderNotFound            = errors.New("sender not found")
+	ErrTransferToSameEmployee    = errors.New("transfer to same employee")
+	ErrTransferMessageTooLong    = errors.New("transfer message is too long")
+	ErrInvalidTransferCategory   = errors.New("invalid transfer category")
+	ErrEmptyTransferBatch        = errors.New("transfer batch has no recipients")
+	ErrTransferLimitExceeded     = errors.New("transfer limit exceeded")
+
+	ErrPendingTransferNotFound   = errors.New("pending transfer not found")
+	ErrPendingTransferNotPending = errors.New("pending transfer is already resolved")
+	ErrNotApprover               = errors.New("employee can't approve this transfer")
+	ErrManagerNotFound           = errors.New("manager not found")
+	ErrSelfManager               = errors.New("employee can't be their own manager")
+
+	ErrZeroAdjustment           = errors.New("adjustment amount must not be zero")
+	ErrAdjustmentReasonRequired = errors.New("adjustment reason is required")
+	ErrAdjustmentReasonTooLong  = errors.New("adjustment reason is too long")
 
 	ErrEmployeeNotFound = errors.New("employee not found")
 	ErrItemNotFound     = errors.New("item not found")
+	ErrItemExists       = errors.New("item already exists")
+	ErrInvalidItemPrice = errors.New("item price must be positive")
+	ErrItemRetired      = errors.New("item retired")
+	ErrInvalidItemLimit = errors.New("invalid item stock or purchase limit")
+
+	ErrNotEnoughItems = errors.New("not enough items in inventory")
+
+	ErrEmptyOrder      = errors.New("order has no items")
+	ErrInvalidQuantity = errors.New("item quantity must be positive")
+
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrInvalidOrderStatus     = errors.New("invalid order status")
+	ErrOrderTransition        = errors.New("order status transition not allowed")
+	ErrOrderItemsNotAvailable = errors.New("order items are no longer in inventory")
+
+	ErrPurchaseNotFound       = errors.New("purchase not found")
+	ErrOrderNotDelivered      = errors.New("order is not delivered yet")
+	ErrItemNotReturnable      = errors.New("item cannot be returned")
+	ErrReturnWindowExpired    = errors.New("return window expired")
+	ErrReturnQuantityExceeded = errors.New("return quantity exceeds purchased quantity")
+
+	ErrListingNotFound      = errors.New("listing not found")
+	ErrListingNotActive     = errors.New("listing is no longer active")
+	ErrOwnListing           = errors.New("can't buy own listing")
+	ErrInvalidListingPrice  = errors.New("listing price must be positive")
+	ErrSaleRequiresApproval = errors.New("sale amount is above the transfer approval threshold")
+
+	ErrScheduleNotFound  = errors.New("schedule not found")
+	ErrScheduleNotActive = errors.New("schedule is no longer active")
+	ErrInvalidRecurrence = errors.New("invalid recurrence")
+	ErrScheduleInPast    = errors.New("schedule time must be in the future")
+
+	ErrPaymentRequestNotFound   = errors.New("payment request not found")
+	ErrPaymentRequestNotPending = errors.New("payment request is no longer pending")
+	ErrPaymentRequestExpired    = errors.New("payment request expired")
+	ErrPayerNotFound            = errors.New("payer not found")
+	ErrInvalidRequestFilter     = errors.New("invalid payment request direction or status")
+
+	ErrOutOfStock           = errors.New("item out of stock")
+	ErrPurchaseLimitReached = errors.New("purchase limit reached")
+
+	ErrInvalidDateRange = errors.New("invalid date range")
+
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with different request")
+
+	ErrNonPositiveLedgerAmount = errors.New("ledger amount must be positive")
+	ErrUnbalancedPosting       = errors.New("ledger debits do not equal credits")
+	ErrLedgerMismatch          = errors.New("balance does not match ledger")
 )
+
+type TransferLimit string
+
+const (
+	LimitDailyAmount            TransferLimit = "daily_amount"
+	LimitHourlyCount            TransferLimit = "hourly_count"
+	LimitMonthlyRecipientAmount TransferLimit = "monthly_recipient_amount"
+)
+
+// TransferLimitError is an ErrTransferLimitExceeded that tells which limit was hit and how much of it is left:
+// coins for the amount limits, transfers for the hourly count.
+type TransferLimitError struct {
+	Limit     TransferLimit
+	Remaining int
+}
+
+func (e *TransferLimitError) Error() string {
+	return fmt.Sprintf("%s: %s, %d remaining", ErrTransferLimitExceeded, e.Limit, e.Remaining)
+}
+
+func (e *TransferLimitError) Unwrap() error {
+	return ErrTransferLimitExceeded
+}
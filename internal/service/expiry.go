@@ -0,0 +1,110 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type ExpiryService struct {
+	trManager    TransactionManager
+	employeeRepo EmployeeRepo
+	coinLotRepo  CoinLotRepo
+	ledger       Ledger
+	policy       model.CoinExpiryPolicy
+}
+
+func NewExpiryService(
+	trManager TransactionManager,
+	employeeRepo EmployeeRepo,
+	coinLotRepo CoinLotRepo,
+	ledger Ledger,
+	policy model.CoinExpiryPolicy,
+) *ExpiryService {
+	return &ExpiryService{
+		trManager:    trManager,
+		employeeRepo: employeeRepo,
+		coinLotRepo:  coinLotRepo,
+		ledger:       ledger,
+		policy:       policy,
+	}
+}
+
+// ExpireDue takes away the coins whose lots outlived the TTL from up to limit employees and reports how many
+// employees it processed. Each employee is handled in its own transaction under its row lock.
+func (s *ExpiryService) ExpireDue(ctx context.Context, limit int) (int, error) {
+	const op = "service.ExpiryService.ExpireDue"
+
+	if !s.policy.Enabled() {
+		return 0, nil
+	}
+
+	grantedBefore := time.Now().Add(-s.policy.TTL)
+
+	processed := 0
+	for processed < limit {
+		found := false
+		err := s.trManager.Do(ctx, func(ctx context.Context) error {
+			employeeIds, err := s.coinLotRepo.FindEmployeesWithExpired(ctx, grantedBefore, 1)
+			if err != nil || len(employeeIds) == 0 {
+				return err
+			}
+
+			found = true
+			return s.expire(ctx, employeeIds[0], grantedBefore)
+		})
+
+		if err != nil {
+			return processed, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !found {
+			break
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// expire spends the employee's stale lots. Coins held for a pending transfer stay until the transfer is resolved:
+// an approved transfer spends them as the oldest coins, a rejected one lets the next sweep expire them.
+func (s *ExpiryService) expire(ctx context.Context, employeeId uuid.UUID, grantedBefore time.Time) error {
+	employee, err := s.employeeRepo.FindByIdForUpdate(ctx, employeeId)
+	if err != nil {
+		return err
+	}
+
+	lots, err := s.coinLotRepo.FindActiveGrantedBefore(ctx, employee.Id, grantedBefore)
+	if err != nil {
+		return err
+	}
+
+	stale := 0
+	for _, lot := range lots {
+		stale += lot.Remaining
+	}
+
+	amount := min(stale, employee.Available())
+	if amount <= 0 {
+		return nil
+	}
+
+	return s.ledger.Expire(ctx, uuid.New(), employee, amount)
+}
+
+// expiringCoins groups lots by the time they expire, earliest first. The lots must be ordered by grant date.
+func expiringCoins(lots []model.CoinLot, ttl time.Duration) []model.ExpiringCoins {
+	var expiring []model.ExpiringCoins
+	for _, lot := range lots {
+		expiresAt := lot.GrantedAt.Add(ttl)
+		if n := len(expiring); n > 0 && expiring[n-1].ExpiresAt.Equal(expiresAt) {
+			expiring[n-1].Amount += lot.Remaining
+			continue
+		}
+		expiring = append(expiring, model.ExpiringCoins{Amount: lot.Remaining, ExpiresAt: expiresAt})
+	}
+	return expiring
+}
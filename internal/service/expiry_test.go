@@ -0,0 +1,87 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestExpiryService_ExpireDue(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	policy := model.CoinExpiryPolicy{TTL: 365 * 24 * time.Hour}
+	grantedAt := time.Now().AddDate(-2, 0, 0)
+
+	tests := []struct {
+		name           string
+		employee       model.Employee
+		lots           []model.CoinLot
+		expectedAmount int
+	}{
+		{
+			name:     "stale lots expire",
+			employee: model.Employee{Balance: 1000},
+			lots: []model.CoinLot{
+				{Remaining: 300, GrantedAt: grantedAt},
+				{Remaining: 200, GrantedAt: grantedAt},
+			},
+			expectedAmount: 500,
+		},
+		{
+			name:           "held coins do not expire",
+			employee:       model.Employee{Balance: 1000, Held: 800},
+			lots:           []model.CoinLot{{Remaining: 500, GrantedAt: grantedAt}},
+			expectedAmount: 200,
+		},
+		{
+			name:     "nothing left to expire",
+			employee: model.Employee{Balance: 1000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockCoinLotRepo := new(mockCoinLotRepo)
+			mockLedger := new(mockLedger)
+			expiryService := NewExpiryService(mockTrManager, mockEmployeeRepo, mockCoinLotRepo, mockLedger, policy)
+
+			employee := tt.employee
+			employee.Id = uuid.New()
+			staleBefore := mock.MatchedBy(func(before time.Time) bool {
+				return before.Before(time.Now().Add(-policy.TTL + time.Minute))
+			})
+			mockCoinLotRepo.On("FindEmployeesWithExpired", mock.Anything, staleBefore, 1).
+				Return([]uuid.UUID{employee.Id}, nil).Once()
+			mockCoinLotRepo.On("FindEmployeesWithExpired", mock.Anything, staleBefore, 1).
+				Return([]uuid.UUID{}, nil).Once()
+			mockEmployeeRepo.On("FindByIdForUpdate", mock.Anything, employee.Id).Return(&employee, nil)
+			mockCoinLotRepo.On("FindActiveGrantedBefore", mock.Anything, employee.Id, staleBefore).Return(tt.lots, nil)
+			if tt.expectedAmount > 0 {
+				mockLedger.On("Expire", mock.Anything, mock.Anything, &employee, tt.expectedAmount).Return(nil)
+			}
+
+			processed, err := expiryService.ExpireDue(context.Background(), 10)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, processed)
+			mockCoinLotRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExpiryService_ExpireDue_Disabled(t *testing.T) {
+	mockCoinLotRepo := new(mockCoinLotRepo)
+	expiryService := NewExpiryService(new(mockTransactionManager), new(mockEmployeeRepo), mockCoinLotRepo,
+		new(mockLedger), model.CoinExpiryPolicy{})
+
+	processed, err := expiryService.ExpireDue(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, processed)
+	mockCoinLotRepo.AssertNotCalled(t, "FindEmployeesWithExpired", mock.Anything, mock.Anything, mock.Anything)
+}
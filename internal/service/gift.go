@@ -0,0 +1,97 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type GiftService struct {
+	trManager     TransactionManager
+	employeeRepo  EmployeeRepo
+	itemRepo      ItemRepo
+	inventoryRepo InventoryRepo
+	giftRepo      GiftRepo
+}
+
+func NewGiftService(
+	trManager TransactionManager,
+	employeeRepo EmployeeRepo,
+	itemRepo ItemRepo,
+	inventoryRepo InventoryRepo,
+	giftRepo GiftRepo,
+) *GiftService {
+	return &GiftService{
+		trManager:     trManager,
+		employeeRepo:  employeeRepo,
+		itemRepo:      itemRepo,
+		inventoryRepo: inventoryRepo,
+		giftRepo:      giftRepo,
+	}
+}
+
+func (s *GiftService) GiftItem(
+	ctx context.Context, fromUsername string, toUsername string, itemName string, quantity int) (*model.Gift, error) {
+	const op = "service.GiftService.GiftItem"
+
+	if fromUsername == toUsername {
+		return nil, ErrTransferToSameEmployee
+	}
+
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	var gift *model.Gift
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		fromEmployee, toEmployee, err := lockEmployees(ctx, s.employeeRepo, fromUsername, toUsername)
+		if err != nil {
+			return err
+		}
+
+		if err = checkParties(fromEmployee, toEmployee); err != nil {
+			return err
+		}
+
+		item, err := s.itemRepo.FindByName(ctx, itemName)
+		if err != nil {
+			if errors.Is(err, repo.ErrItemNotFound) {
+				return ErrItemNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = removeFromInventory(ctx, s.inventoryRepo, fromEmployee.Id, item.Id, quantity); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = addToInventory(ctx, s.inventoryRepo, toEmployee.Id, item.Id, quantity); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		gift = &model.Gift{
+			Id:           uuid.New(),
+			FromEmployee: fromEmployee.Id,
+			ToEmployee:   toEmployee.Id,
+			Receiver:     toEmployee.Username,
+			ItemId:       item.Id,
+			Item:         item.Name,
+			Quantity:     quantity,
+		}
+
+		if err = s.giftRepo.Save(ctx, gift); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return gift, nil
+}
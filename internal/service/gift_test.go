@@ -0,0 +1,165 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestGiftService_GiftItem(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	sender := &model.Employee{Id: uuid.New(), Username: "sender"}
+	receiver := &model.Employee{Id: uuid.New(), Username: "receiver"}
+	item := &model.Item{Id: uuid.New(), Name: "cup", Price: 20}
+
+	lockBoth := func(mer *mockEmployeeRepo) {
+		mer.On("FindByUsernameForUpdate", mock.Anything, "receiver").Return(receiver, nil)
+		mer.On("FindByUsernameForUpdate", mock.Anything, "sender").Return(sender, nil)
+	}
+
+	tests := []struct {
+		name          string
+		from          string
+		to            string
+		quantity      int
+		setup         func(*mockEmployeeRepo, *mockItemRepo, *mockInventoryRepo, *mockGiftRepo)
+		expectedError error
+	}{
+		{
+			name:     "gift to colleague without the item",
+			from:     "sender",
+			to:       "receiver",
+			quantity: 2,
+			setup: func(mer *mockEmployeeRepo, mir *mockItemRepo, minv *mockInventoryRepo, mgr *mockGiftRepo) {
+				lockBoth(mer)
+				mir.On("FindByName", mock.Anything, "cup").Return(item, nil)
+				minv.On("FindByEmployeeAndItem", mock.Anything, sender.Id, item.Id).
+					Return(&model.EmployeeInventory{
+						Id: uuid.New(), EmployeeId: sender.Id, ItemId: item.Id, Amount: 3}, nil)
+				minv.On("UpdateById", mock.Anything, mock.Anything, mock.MatchedBy(
+					func(i *model.EmployeeInventory) bool { return i.EmployeeId == sender.Id && i.Amount == 1 })).
+					Return(nil)
+				minv.On("FindByEmployeeAndItem", mock.Anything, receiver.Id, item.Id).
+					Return(nil, repo.ErrEmployeeInventoryNotFound)
+				minv.On("Save", mock.Anything, mock.MatchedBy(
+					func(i *model.EmployeeInventory) bool { return i.EmployeeId == receiver.Id && i.Amount == 2 })).
+					Return(nil)
+				mgr.On("Save", mock.Anything, mock.MatchedBy(func(g *model.Gift) bool {
+					return g.FromEmployee == sender.Id && g.ToEmployee == receiver.Id && g.Quantity == 2
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "gift to yourself",
+			from:          "sender",
+			to:            "sender",
+			quantity:      1,
+			setup:         func(*mockEmployeeRepo, *mockItemRepo, *mockInventoryRepo, *mockGiftRepo) {},
+			expectedError: ErrTransferToSameEmployee,
+		},
+		{
+			name:          "non-positive quantity",
+			from:          "sender",
+			to:            "receiver",
+			quantity:      0,
+			setup:         func(*mockEmployeeRepo, *mockItemRepo, *mockInventoryRepo, *mockGiftRepo) {},
+			expectedError: ErrInvalidQuantity,
+		},
+		{
+			name:     "receiver not found",
+			from:     "sender",
+			to:       "receiver",
+			quantity: 1,
+			setup: func(mer *mockEmployeeRepo, mir *mockItemRepo, minv *mockInventoryRepo, mgr *mockGiftRepo) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "receiver").Return(nil, repo.ErrEmployeeNotFound)
+			},
+			expectedError: ErrReceiverNotFound,
+		},
+		{
+			name:     "item not found",
+			from:     "sender",
+			to:       "receiver",
+			quantity: 1,
+			setup: func(mer *mockEmployeeRepo, mir *mockItemRepo, minv *mockInventoryRepo, mgr *mockGiftRepo) {
+				lockBoth(mer)
+				mir.On("FindByName", mock.Anything, "cup").Return(nil, repo.ErrItemNotFound)
+			},
+			expectedError: ErrItemNotFound,
+		},
+		{
+			name:     "not owned",
+			from:     "sender",
+			to:       "receiver",
+			quantity: 1,
+			setup: func(mer *mockEmployeeRepo, mir *mockItemRepo, minv *mockInventoryRepo, mgr *mockGiftRepo) {
+				lockBoth(mer)
+				mir.On("FindByName", mock.Anything, "cup").Return(item, nil)
+				minv.On("FindByEmployeeAndItem", mock.Anything, sender.Id, item.Id).
+					Return(nil, repo.ErrEmployeeInventoryNotFound)
+			},
+			expectedError: ErrNotEnoughItems,
+		},
+		{
+			name:     "not enough units",
+			from:     "sender",
+			to:       "receiver",
+			quantity: 5,
+			setup: func(mer *mockEmployeeRepo, mir *mockItemRepo, minv *mockInventoryRepo, mgr *mockGiftRepo) {
+				lockBoth(mer)
+				mir.On("FindByName", mock.Anything, "cup").Return(item, nil)
+				minv.On("FindByEmployeeAndItem", mock.Anything, sender.Id, item.Id).
+					Return(&model.EmployeeInventory{Id: uuid.New(), Amount: 3}, nil)
+			},
+			expectedError: ErrNotEnoughItems,
+		},
+		{
+			name:     "gift save error",
+			from:     "sender",
+			to:       "receiver",
+			quantity: 1,
+			setup: func(mer *mockEmployeeRepo, mir *mockItemRepo, minv *mockInventoryRepo, mgr *mockGiftRepo) {
+				lockBoth(mer)
+				mir.On("FindByName", mock.Anything, "cup").Return(item, nil)
+				minv.On("FindByEmployeeAndItem", mock.Anything, mock.Anything, item.Id).
+					Return(&model.EmployeeInventory{Id: uuid.New(), Amount: 3}, nil)
+				minv.On("UpdateById", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				mgr.On("Save", mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockItemRepo := new(mockItemRepo)
+			mockInventoryRepo := new(mockInventoryRepo)
+			mockGiftRepo := new(mockGiftRepo)
+			giftService := NewGiftService(
+				mockTrManager, mockEmployeeRepo, mockItemRepo, mockInventoryRepo, mockGiftRepo)
+
+			tc.setup(mockEmployeeRepo, mockItemRepo, mockInventoryRepo, mockGiftRepo)
+
+			gift, err := giftService.GiftItem(context.Background(), tc.from, tc.to, "cup", tc.quantity)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "receiver", gift.Receiver)
+				assert.Equal(t, "cup", gift.Item)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockItemRepo.AssertExpectations(t)
+			mockInventoryRepo.AssertExpectations(t)
+			mockGiftRepo.AssertExpectations(t)
+		})
+	}
+}
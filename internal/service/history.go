@@ -0,0 +1,71 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	defaultHistoryPageSize = 20
+	maxHistoryPageSize     = 100
+)
+
+type HistoryService struct {
+	employeeRepo EmployeeRepo
+	historyRepo  HistoryRepo
+}
+
+func NewHistoryService(employeeRepo EmployeeRepo, historyRepo HistoryRepo) *HistoryService {
+	return &HistoryService{
+		employeeRepo: employeeRepo,
+		historyRepo:  historyRepo,
+	}
+}
+
+func (s *HistoryService) Get(
+	ctx context.Context, username string, filter model.HistoryFilter) (*model.HistoryPage, error) {
+	const op = "service.HistoryService.Get"
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	limit := pageLimit(filter.Limit)
+
+	employee, err := s.employeeRepo.FindByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// one extra row tells whether there is a next page
+	filter.Limit = limit + 1
+	entries, err := s.historyRepo.FindByEmployee(ctx, employee.Id, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	page := &model.HistoryPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		last := page.Entries[limit-1]
+		page.NextCursor = &model.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}
+	}
+
+	return page, nil
+}
+
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultHistoryPageSize
+	}
+	if limit > maxHistoryPageSize {
+		return maxHistoryPageSize
+	}
+	return limit
+}
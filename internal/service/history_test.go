@@ -0,0 +1,134 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestHistoryService_Get(t *testing.T) {
+	employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 1000}
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	entries := func(n int) []model.HistoryEntry {
+		result := make([]model.HistoryEntry, n)
+		for i := range result {
+			result[i] = model.HistoryEntry{
+				Id:        uuid.New(),
+				Type:      model.HistoryTransfer,
+				Direction: model.DirectionIn,
+				Amount:    10,
+				CreatedAt: now.Add(-time.Duration(i) * time.Minute),
+			}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name               string
+		filter             model.HistoryFilter
+		setup              func(*mockEmployeeRepo, *mockHistoryRepo)
+		expectedError      error
+		expectedEntries    int
+		expectedNextCursor bool
+	}{
+		{
+			name:   "last page without cursor",
+			filter: model.HistoryFilter{Limit: 5},
+			setup: func(mer *mockEmployeeRepo, mhr *mockHistoryRepo) {
+				mer.On("FindByUsername", mock.Anything, "test_user").
+					Return(employee, nil)
+				mhr.On("FindByEmployee", mock.Anything, employee.Id, model.HistoryFilter{Limit: 6}).
+					Return(entries(3), nil)
+			},
+			expectedEntries:    3,
+			expectedNextCursor: false,
+		},
+		{
+			name:   "full page returns next cursor",
+			filter: model.HistoryFilter{Limit: 5},
+			setup: func(mer *mockEmployeeRepo, mhr *mockHistoryRepo) {
+				mer.On("FindByUsername", mock.Anything, "test_user").
+					Return(employee, nil)
+				mhr.On("FindByEmployee", mock.Anything, employee.Id, model.HistoryFilter{Limit: 6}).
+					Return(entries(6), nil)
+			},
+			expectedEntries:    5,
+			expectedNextCursor: true,
+		},
+		{
+			name:   "default page size",
+			filter: model.HistoryFilter{},
+			setup: func(mer *mockEmployeeRepo, mhr *mockHistoryRepo) {
+				mer.On("FindByUsername", mock.Anything, "test_user").
+					Return(employee, nil)
+				mhr.On("FindByEmployee", mock.Anything, employee.Id,
+					model.HistoryFilter{Limit: defaultHistoryPageSize + 1}).
+					Return(entries(0), nil)
+			},
+			expectedEntries: 0,
+		},
+		{
+			name:          "invalid date range",
+			filter:        model.HistoryFilter{From: &now, To: &earlier},
+			setup:         func(mer *mockEmployeeRepo, mhr *mockHistoryRepo) {},
+			expectedError: ErrInvalidDateRange,
+		},
+		{
+			name:   "employee not found",
+			filter: model.HistoryFilter{},
+			setup: func(mer *mockEmployeeRepo, mhr *mockHistoryRepo) {
+				mer.On("FindByUsername", mock.Anything, "test_user").
+					Return(nil, repo.ErrEmployeeNotFound)
+			},
+			expectedError: ErrEmployeeNotFound,
+		},
+		{
+			name:   "history retrieval error",
+			filter: model.HistoryFilter{},
+			setup: func(mer *mockEmployeeRepo, mhr *mockHistoryRepo) {
+				mer.On("FindByUsername", mock.Anything, "test_user").
+					Return(employee, nil)
+				mhr.On("FindByEmployee", mock.Anything, employee.Id, mock.Anything).
+					Return(nil, errors.New("history error"))
+			},
+			expectedError: errors.New("history error"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockHistoryRepo := new(mockHistoryRepo)
+			historyService := NewHistoryService(mockEmployeeRepo, mockHistoryRepo)
+
+			tc.setup(mockEmployeeRepo, mockHistoryRepo)
+
+			page, err := historyService.Get(context.Background(), "test_user", tc.filter)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, page.Entries, tc.expectedEntries)
+				if tc.expectedNextCursor {
+					last := page.Entries[len(page.Entries)-1]
+					assert.Equal(t, &model.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}, page.NextCursor)
+				} else {
+					assert.Nil(t, page.NextCursor)
+				}
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockHistoryRepo.AssertExpectations(t)
+		})
+	}
+}
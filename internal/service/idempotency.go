@@ -0,0 +1,76 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type IdempotencyService struct {
+	trManager       TransactionManager
+	idempotencyRepo IdempotencyRepo
+}
+
+func NewIdempotencyService(trManager TransactionManager, idempotencyRepo IdempotencyRepo) *IdempotencyService {
+	return &IdempotencyService{
+		trManager:       trManager,
+		idempotencyRepo: idempotencyRepo,
+	}
+}
+
+// Do runs fn at most once per employee and key. The key, the request fingerprint and the response of fn
+// are stored in the same transaction as fn itself, so a failed fn leaves no trace and can be retried.
+// A duplicate request gets the stored response back with replayed set to true.
+func (s *IdempotencyService) Do(
+	ctx context.Context,
+	employeeId uuid.UUID,
+	key string,
+	fingerprint string,
+	fn func(ctx context.Context) (int, []byte, error),
+) (record *model.IdempotencyRecord, replayed bool, err error) {
+	const op = "service.IdempotencyService.Do"
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		record = &model.IdempotencyRecord{
+			EmployeeId:  employeeId,
+			Key:         key,
+			Fingerprint: fingerprint,
+		}
+
+		// a concurrent request with the same key blocks here until the first one commits or rolls back
+		reserved, err := s.idempotencyRepo.Reserve(ctx, record)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !reserved {
+			record, err = s.idempotencyRepo.FindByEmployeeAndKey(ctx, employeeId, key)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			if record.Fingerprint != fingerprint {
+				return ErrIdempotencyKeyReused
+			}
+			replayed = true
+			return nil
+		}
+
+		record.ResponseStatus, record.ResponseBody, err = fn(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err = s.idempotencyRepo.SaveResponse(ctx, record); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return record, replayed, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+)
+
+func TestIdempotencyService_Do(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	employeeId := uuid.New()
+	key := "key"
+	fingerprint := "fingerprint"
+
+	tests := []struct {
+		name             string
+		setup            func(*mockIdempotencyRepo)
+		fnErr            error
+		expectedError    error
+		expectedCalls    int
+		expectedReplayed bool
+		expectedStatus   int
+	}{
+		{
+			name: "first request runs and stores response",
+			setup: func(mir *mockIdempotencyRepo) {
+				mir.On("Reserve", mock.Anything, mock.Anything).
+					Return(true, nil)
+				mir.On("SaveResponse", mock.Anything, mock.MatchedBy(func(r *model.IdempotencyRecord) bool {
+					return r.Key == key && r.ResponseStatus == http.StatusOK && string(r.ResponseBody) == "{}"
+				})).Return(nil)
+			},
+			expectedCalls:  1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "duplicate request replays stored response",
+			setup: func(mir *mockIdempotencyRepo) {
+				mir.On("Reserve", mock.Anything, mock.Anything).
+					Return(false, nil)
+				mir.On("FindByEmployeeAndKey", mock.Anything, employeeId, key).
+					Return(&model.IdempotencyRecord{
+						EmployeeId:     employeeId,
+						Key:            key,
+						Fingerprint:    fingerprint,
+						ResponseStatus: http.StatusCreated,
+					}, nil)
+			},
+			expectedCalls:    0,
+			expectedReplayed: true,
+			expectedStatus:   http.StatusCreated,
+		},
+		{
+			name: "reused key with different payload",
+			setup: func(mir *mockIdempotencyRepo) {
+				mir.On("Reserve", mock.Anything, mock.Anything).
+					Return(false, nil)
+				mir.On("FindByEmployeeAndKey", mock.Anything, employeeId, key).
+					Return(&model.IdempotencyRecord{EmployeeId: employeeId, Key: key, Fingerprint: "other"}, nil)
+			},
+			expectedError: ErrIdempotencyKeyReused,
+			expectedCalls: 0,
+		},
+		{
+			name: "failed operation is not stored",
+			setup: func(mir *mockIdempotencyRepo) {
+				mir.On("Reserve", mock.Anything, mock.Anything).
+					Return(true, nil)
+			},
+			fnErr:         errors.New("operation error"),
+			expectedError: errors.New("operation error"),
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockIdempotencyRepo := new(mockIdempotencyRepo)
+			idempotencyService := NewIdempotencyService(mockTrManager, mockIdempotencyRepo)
+
+			tc.setup(mockIdempotencyRepo)
+
+			calls := 0
+			record, replayed, err := idempotencyService.Do(context.Background(), employeeId, key, fingerprint,
+				func(ctx context.Context) (int, []byte, error) {
+					calls++
+					return http.StatusOK, []byte("{}"), tc.fnErr
+				})
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedReplayed, replayed)
+				assert.Equal(t, tc.expectedStatus, record.ResponseStatus)
+			}
+			assert.Equal(t, tc.expectedCalls, calls)
+
+			mockIdempotencyRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
 type InfoService struct {
@@ -14,6 +15,8 @@ type InfoService struct {
 	inventoryRepo InventoryRepo
 	transferRepo  TransferRepo
 	itemRepo      ItemRepo
+	coinLotRepo   CoinLotRepo
+	expiry        model.CoinExpiryPolicy
 }
 
 func NewInfoService(
@@ -22,6 +25,8 @@ func NewInfoService(
 	inventoryRepo InventoryRepo,
 	transferRepo TransferRepo,
 	itemRepo ItemRepo,
+	coinLotRepo CoinLotRepo,
+	expiry model.CoinExpiryPolicy,
 ) *InfoService {
 	return &InfoService{
 		trManager:     trManager,
@@ -29,6 +34,8 @@ func NewInfoService(
 		inventoryRepo: inventoryRepo,
 		transferRepo:  transferRepo,
 		itemRepo:      itemRepo,
+		coinLotRepo:   coinLotRepo,
+		expiry:        expiry,
 	}
 }
 
@@ -60,8 +67,16 @@ func (s *InfoService) Get(ctx context.Context, username string) (*model.Employee
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
+		expiring, err := s.expiring(ctx, employee)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
 		employeeInfo = model.EmployeeInfo{
 			Coins:     employee.Balance,
+			Available: employee.Available(),
+			Held:      employee.Held,
+			Expiring:  expiring,
 			Inventory: inventoryItems,
 			CoinHistory: model.CoinHistory{
 				Sent:     transfersAsSender,
@@ -74,3 +89,17 @@ func (s *InfoService) Get(ctx context.Context, username string) (*model.Employee
 
 	return &employeeInfo, err
 }
+
+// expiring reports the coins that expire within the notice period.
+func (s *InfoService) expiring(ctx context.Context, employee *model.Employee) ([]model.ExpiringCoins, error) {
+	if !s.expiry.Enabled() {
+		return nil, nil
+	}
+
+	lots, err := s.coinLotRepo.FindActiveGrantedBefore(ctx, employee.Id, time.Now().Add(s.expiry.Notice-s.expiry.TTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return expiringCoins(lots, s.expiry.TTL), nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestInfoService_Get(t *testing.T) {
@@ -67,8 +68,8 @@ func TestInfoService_Get(t *testing.T) {
 			mockEmployeeRepo := new(mockEmployeeRepo)
 			mockInventoryRepo := new(mockInventoryRepo)
 			mockTransferRepo := new(mockTransferRepo)
-			infoService := NewInfoService(
-				mockTrManager, mockEmployeeRepo, mockInventoryRepo, mockTransferRepo, nil)
+			infoService := NewInfoService(mockTrManager, mockEmployeeRepo, mockInventoryRepo, mockTransferRepo, nil,
+				nil, model.CoinExpiryPolicy{})
 
 			tc.setup(mockEmployeeRepo, mockInventoryRepo, mockTransferRepo)
 
@@ -88,3 +89,63 @@ func TestInfoService_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestInfoService_Get_HeldCoins(t *testing.T) {
+	mockEmployeeRepo := new(mockEmployeeRepo)
+	mockInventoryRepo := new(mockInventoryRepo)
+	mockTransferRepo := new(mockTransferRepo)
+	infoService := NewInfoService(new(mockTransactionManager), mockEmployeeRepo, mockInventoryRepo, mockTransferRepo,
+		nil, nil, model.CoinExpiryPolicy{})
+
+	employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 1000, Held: 600}
+	mockEmployeeRepo.On("FindByUsername", mock.Anything, "test_user").Return(employee, nil)
+	mockInventoryRepo.On("FindAllInventoryItemsByEmployee", mock.Anything, employee.Id).
+		Return([]model.InventoryItem{}, nil)
+	mockTransferRepo.On("FindAllForSenderGroupedByReceivers", mock.Anything, employee.Id).
+		Return([]model.CoinTransaction{}, nil)
+	mockTransferRepo.On("FindAllForReceiverGroupedBySenders", mock.Anything, employee.Id).
+		Return([]model.CoinTransaction{}, nil)
+
+	info, err := infoService.Get(context.Background(), "test_user")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, info.Coins)
+	assert.Equal(t, 400, info.Available)
+	assert.Equal(t, 600, info.Held)
+}
+
+func TestInfoService_Get_ExpiringCoins(t *testing.T) {
+	mockEmployeeRepo := new(mockEmployeeRepo)
+	mockInventoryRepo := new(mockInventoryRepo)
+	mockTransferRepo := new(mockTransferRepo)
+	mockCoinLotRepo := new(mockCoinLotRepo)
+	policy := model.CoinExpiryPolicy{TTL: 365 * 24 * time.Hour, Notice: 30 * 24 * time.Hour}
+	infoService := NewInfoService(new(mockTransactionManager), mockEmployeeRepo, mockInventoryRepo, mockTransferRepo,
+		nil, mockCoinLotRepo, policy)
+
+	employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 1000}
+	grantedAt := time.Now().Add(-350 * 24 * time.Hour)
+	mockEmployeeRepo.On("FindByUsername", mock.Anything, "test_user").Return(employee, nil)
+	mockInventoryRepo.On("FindAllInventoryItemsByEmployee", mock.Anything, employee.Id).
+		Return([]model.InventoryItem{}, nil)
+	mockTransferRepo.On("FindAllForSenderGroupedByReceivers", mock.Anything, employee.Id).
+		Return([]model.CoinTransaction{}, nil)
+	mockTransferRepo.On("FindAllForReceiverGroupedBySenders", mock.Anything, employee.Id).
+		Return([]model.CoinTransaction{}, nil)
+	noticeStart := mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(-334*24*time.Hour)) && before.After(time.Now().Add(-336*24*time.Hour))
+	})
+	mockCoinLotRepo.On("FindActiveGrantedBefore", mock.Anything, employee.Id, noticeStart).Return([]model.CoinLot{
+		{Remaining: 10, GrantedAt: grantedAt},
+		{Remaining: 5, GrantedAt: grantedAt},
+		{Remaining: 20, GrantedAt: grantedAt.Add(time.Hour)},
+	}, nil)
+
+	info, err := infoService.Get(context.Background(), "test_user")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ExpiringCoins{
+		{Amount: 15, ExpiresAt: grantedAt.Add(policy.TTL)},
+		{Amount: 20, ExpiresAt: grantedAt.Add(time.Hour + policy.TTL)},
+	}, info.Expiring)
+}
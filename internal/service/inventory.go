@@ -0,0 +1,50 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+)
+
+// Inventory rows are not locked on their own: callers must hold the row lock of every employee involved.
+
+func addToInventory(
+	ctx context.Context, inventoryRepo InventoryRepo, employeeId uuid.UUID, itemId uuid.UUID, quantity int) error {
+	employeeInventory, err := inventoryRepo.FindByEmployeeAndItem(ctx, employeeId, itemId)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeInventoryNotFound) {
+			return inventoryRepo.Save(ctx, &model.EmployeeInventory{
+				Id:         uuid.New(),
+				EmployeeId: employeeId,
+				ItemId:     itemId,
+				Amount:     quantity,
+			})
+		}
+		return err
+	}
+
+	employeeInventory.Amount += quantity
+
+	return inventoryRepo.UpdateById(ctx, employeeInventory.Id, employeeInventory)
+}
+
+func removeFromInventory(
+	ctx context.Context, inventoryRepo InventoryRepo, employeeId uuid.UUID, itemId uuid.UUID, quantity int) error {
+	employeeInventory, err := inventoryRepo.FindByEmployeeAndItem(ctx, employeeId, itemId)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeInventoryNotFound) {
+			return ErrNotEnoughItems
+		}
+		return err
+	}
+
+	if employeeInventory.Amount < quantity {
+		return ErrNotEnoughItems
+	}
+
+	employeeInventory.Amount -= quantity
+
+	return inventoryRepo.UpdateById(ctx, employeeInventory.Id, employeeInventory)
+}
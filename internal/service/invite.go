@@ -0,0 +1,48 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+const inviteCodeBytes = 16
+
+type InviteService struct {
+	inviteRepo InviteRepo
+	ttl        time.Duration
+}
+
+func NewInviteService(inviteRepo InviteRepo, ttl time.Duration) *InviteService {
+	return &InviteService{
+		inviteRepo: inviteRepo,
+		ttl:        ttl,
+	}
+}
+
+// Create issues a single-use invite code on behalf of an admin.
+func (s *InviteService) Create(ctx context.Context, adminId uuid.UUID) (*model.Invite, error) {
+	const op = "service.InviteService.Create"
+
+	code := make([]byte, inviteCodeBytes)
+	if _, err := rand.Read(code); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invite := &model.Invite{
+		Id:        uuid.New(),
+		Code:      hex.EncodeToString(code),
+		CreatedBy: adminId,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+
+	if err := s.inviteRepo.Save(ctx, invite); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invite, nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestInviteService_Create(t *testing.T) {
+	mockInviteRepo := new(mockInviteRepo)
+	inviteService := NewInviteService(mockInviteRepo, 24*time.Hour)
+	adminId := uuid.New()
+
+	mockInviteRepo.On("Save", mock.Anything, mock.MatchedBy(func(i *model.Invite) bool {
+		return i.CreatedBy == adminId && len(i.Code) == 2*inviteCodeBytes
+	})).Return(nil)
+
+	invite, err := inviteService.Create(context.Background(), adminId)
+
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), invite.ExpiresAt, time.Minute)
+
+	other, err := inviteService.Create(context.Background(), adminId)
+	assert.NoError(t, err)
+	assert.NotEqual(t, invite.Code, other.Code)
+
+	mockInviteRepo.AssertExpectations(t)
+}
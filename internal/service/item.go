@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"slices"
+	"strings"
 )
 
 type ItemService struct {
@@ -14,6 +16,9 @@ type ItemService struct {
 	itemRepo      ItemRepo
 	employeeRepo  EmployeeRepo
 	inventoryRepo InventoryRepo
+	purchaseRepo  PurchaseRepo
+	orderRepo     OrderRepo
+	ledger        Ledger
 }
 
 func NewItemService(
@@ -21,20 +26,37 @@ func NewItemService(
 	itemRepo ItemRepo,
 	employeeRepo EmployeeRepo,
 	inventoryRepo InventoryRepo,
+	purchaseRepo PurchaseRepo,
+	orderRepo OrderRepo,
+	ledger Ledger,
 ) *ItemService {
 	return &ItemService{
 		trManager:     trManager,
 		itemRepo:      itemRepo,
 		employeeRepo:  employeeRepo,
 		inventoryRepo: inventoryRepo,
+		purchaseRepo:  purchaseRepo,
+		orderRepo:     orderRepo,
+		ledger:        ledger,
 	}
 }
 
 func (s *ItemService) Buy(ctx context.Context, itemName string, username string) error {
-	const op = "service.ItemService.BuyItem"
+	_, err := s.Checkout(ctx, username, []model.OrderLine{{Item: itemName, Quantity: 1}})
+	return err
+}
 
-	err := s.trManager.Do(ctx, func(ctx context.Context) error {
-		employee, err := s.employeeRepo.FindByUsername(ctx, username)
+func (s *ItemService) Checkout(ctx context.Context, username string, lines []model.OrderLine) (*model.Order, error) {
+	const op = "service.ItemService.Checkout"
+
+	lines, err := normalizeOrderLines(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	var order *model.Order
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		employee, err := s.employeeRepo.FindByUsernameForUpdate(ctx, username)
 		if err != nil {
 			if errors.Is(err, repo.ErrEmployeeNotFound) {
 				return ErrEmployeeNotFound
@@ -42,50 +64,280 @@ func (s *ItemService) Buy(ctx context.Context, itemName string, username string)
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		item, err := s.itemRepo.FindByName(ctx, itemName)
-		if err != nil {
-			if errors.Is(err, repo.ErrItemNotFound) {
-				return ErrItemNotFound
+		if err = checkActive(employee); err != nil {
+			return err
+		}
+
+		order = &model.Order{
+			Id:         uuid.New(),
+			EmployeeId: employee.Id,
+			Employee:   employee.Username,
+			Status:     model.OrderPlaced,
+			Items:      make([]model.OrderItem, 0, len(lines)),
+		}
+
+		for _, line := range lines {
+			item, err := s.itemRepo.FindByName(ctx, line.Item)
+			if err != nil {
+				if errors.Is(err, repo.ErrItemNotFound) {
+					return ErrItemNotFound
+				}
+				return fmt.Errorf("%s: %w", op, err)
 			}
-			return fmt.Errorf("%s: %w", op, err)
+
+			if item.Retired {
+				return ErrItemRetired
+			}
+
+			if err = s.reserveItem(ctx, employee, item, line.Quantity); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			orderItem := model.OrderItem{
+				PurchaseId: uuid.New(),
+				ItemId:     item.Id,
+				Item:       item.Name,
+				Quantity:   line.Quantity,
+				Price:      item.Price,
+			}
+			order.Items = append(order.Items, orderItem)
+			order.Total += orderItem.Amount()
 		}
 
-		if item.Price > employee.Balance {
+		if order.Total > employee.Available() {
 			return ErrNotEnoughCoins
 		}
 
-		employee.Balance -= item.Price
+		if err = s.ledger.Purchase(ctx, order.Id, employee, order.Total); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
 
-		if err = s.employeeRepo.UpdateByUsername(ctx, employee.Username, employee); err != nil {
+		if err = s.orderRepo.Save(ctx, order); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		employeeInventory, err := s.inventoryRepo.FindByEmployeeAndItem(ctx, employee.Id, item.Id)
+		for _, orderItem := range order.Items {
+			if err = s.purchaseRepo.Save(ctx, &model.Purchase{
+				Id:         orderItem.PurchaseId,
+				OrderId:    order.Id,
+				EmployeeId: employee.Id,
+				ItemId:     orderItem.ItemId,
+				Quantity:   orderItem.Quantity,
+				Price:      orderItem.Price,
+			}); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			err = addToInventory(ctx, s.inventoryRepo, employee.Id, orderItem.ItemId, orderItem.Quantity)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// normalizeOrderLines merges repeated items and sorts lines by name,
+// so concurrent checkouts decrement stock rows in the same order.
+func normalizeOrderLines(lines []model.OrderLine) ([]model.OrderLine, error) {
+	if len(lines) == 0 {
+		return nil, ErrEmptyOrder
+	}
+
+	quantities := make(map[string]int, len(lines))
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		quantities[line.Item] += line.Quantity
+	}
+
+	normalized := make([]model.OrderLine, 0, len(quantities))
+	for item, quantity := range quantities {
+		normalized = append(normalized, model.OrderLine{Item: item, Quantity: quantity})
+	}
+	slices.SortFunc(normalized, func(a, b model.OrderLine) int {
+		return strings.Compare(a.Item, b.Item)
+	})
+
+	return normalized, nil
+}
+
+// reserveItem relies on the caller holding the employee row lock, which serialises the purchase count check.
+func (s *ItemService) reserveItem(ctx context.Context, employee *model.Employee, item *model.Item, quantity int) error {
+	if item.MaxPerEmployee != nil {
+		purchased, err := s.purchaseRepo.CountByEmployeeAndItem(ctx, employee.Id, item.Id)
+		if err != nil {
+			return err
+		}
+		if purchased+quantity > *item.MaxPerEmployee {
+			return ErrPurchaseLimitReached
+		}
+	}
 
+	if item.Stock != nil {
+		reserved, err := s.itemRepo.DecrementStock(ctx, item.Id, quantity)
 		if err != nil {
-			if errors.Is(err, repo.ErrEmployeeInventoryNotFound) {
-				if err = s.inventoryRepo.Save(ctx, &model.EmployeeInventory{
-					Id:         uuid.New(),
-					EmployeeId: employee.Id,
-					ItemId:     item.Id,
-					Amount:     1,
-				}); err != nil {
-					return fmt.Errorf("%s: %w", op, err)
-				}
-				return nil
+			return err
+		}
+		if !reserved {
+			return ErrOutOfStock
+		}
+	}
+
+	return nil
+}
+
+func (s *ItemService) List(
+	ctx context.Context, username string, filter model.ItemFilter, affordableOnly bool) ([]model.CatalogItem, error) {
+	const op = "service.ItemService.List"
+
+	employee, err := s.employeeRepo.FindByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	available := employee.Available()
+	if affordableOnly {
+		filter.MaxPrice = &available
+	}
+
+	items, err := s.itemRepo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	catalog := make([]model.CatalogItem, len(items))
+	for i := range items {
+		inStock := items[i].Stock == nil || *items[i].Stock > 0
+		catalog[i] = model.CatalogItem{
+			Name:      items[i].Name,
+			Price:     items[i].Price,
+			Stock:     items[i].Stock,
+			Available: inStock && items[i].Price <= available,
+		}
+	}
+
+	return catalog, nil
+}
+
+func (s *ItemService) Create(ctx context.Context, item *model.Item) error {
+	const op = "service.ItemService.Create"
+
+	if item.Price <= 0 {
+		return ErrInvalidItemPrice
+	}
+	if !validItemLimits(item.Stock, item.MaxPerEmployee) {
+		return ErrInvalidItemLimit
+	}
+
+	item.Id = uuid.New()
+
+	if err := s.itemRepo.Save(ctx, item); err != nil {
+		if errors.Is(err, repo.ErrItemExists) {
+			return ErrItemExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *ItemService) Update(ctx context.Context, name string, update model.ItemUpdate) (*model.Item, error) {
+	const op = "service.ItemService.Update"
+
+	if update.Price != nil && *update.Price <= 0 {
+		return nil, ErrInvalidItemPrice
+	}
+	if !validItemLimits(update.Stock, update.MaxPerEmployee) {
+		return nil, ErrInvalidItemLimit
+	}
+
+	var item *model.Item
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		item, err = s.findItem(ctx, name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if update.Name != nil {
+			item.Name = *update.Name
+		}
+		if update.Price != nil {
+			item.Price = *update.Price
+		}
+		if update.Stock != nil {
+			item.Stock = update.Stock
+		}
+		if update.MaxPerEmployee != nil {
+			item.MaxPerEmployee = update.MaxPerEmployee
+		}
+		if update.NonReturnable != nil {
+			item.NonReturnable = *update.NonReturnable
+		}
+
+		if err = s.itemRepo.UpdateById(ctx, item.Id, item); err != nil {
+			if errors.Is(err, repo.ErrItemExists) {
+				return ErrItemExists
 			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
 
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *ItemService) Retire(ctx context.Context, name string) error {
+	const op = "service.ItemService.Retire"
+
+	return s.trManager.Do(ctx, func(ctx context.Context) error {
+		item, err := s.findItem(ctx, name)
+		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		employeeInventory.Amount++
+		if item.Retired {
+			return nil
+		}
+
+		item.Retired = true
 
-		if err = s.inventoryRepo.UpdateById(ctx, employeeInventory.Id, employeeInventory); err != nil {
+		if err = s.itemRepo.UpdateById(ctx, item.Id, item); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
 		return nil
 	})
+}
 
-	return err
+func (s *ItemService) findItem(ctx context.Context, name string) (*model.Item, error) {
+	item, err := s.itemRepo.FindByNameForUpdate(ctx, name)
+	if err != nil {
+		if errors.Is(err, repo.ErrItemNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+func validItemLimits(stock *int, maxPerEmployee *int) bool {
+	return (stock == nil || *stock >= 0) && (maxPerEmployee == nil || *maxPerEmployee > 0)
 }
@@ -16,26 +16,58 @@ func TestItemService_Buy(t *testing.T) {
 
 	tests := []struct {
 		name          string
-		setup         func(*mockItemRepo, *mockEmployeeRepo, *mockInventoryRepo)
+		setup         func(*mockItemRepo, *mockEmployeeRepo, *mockInventoryRepo, *mockPurchaseRepo, *mockLedger)
 		expectedError error
 	}{
+		{
+			name: "successful limited item purchase",
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				ml *mockLedger) {
+				stock := 3
+				limit := 2
+				employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 1000}
+				item := &model.Item{Id: uuid.New(), Name: "Item1", Price: 500, Stock: &stock, MaxPerEmployee: &limit}
+
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
+					Return(employee, nil)
+				mir.On("FindByName", mock.Anything, "Item1").
+					Return(item, nil)
+				mpr.On("CountByEmployeeAndItem", mock.Anything, employee.Id, item.Id).
+					Return(1, nil)
+				mir.On("DecrementStock", mock.Anything, item.Id, 1).
+					Return(true, nil)
+				ml.On("Purchase", mock.Anything, mock.Anything, employee, item.Price).
+					Return(nil)
+				mpr.On("Save", mock.Anything, mock.Anything).
+					Return(nil)
+				minr.On("FindByEmployeeAndItem", mock.Anything, employee.Id, item.Id).
+					Return(nil, repo.ErrEmployeeInventoryNotFound)
+				minr.On("Save", mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			expectedError: nil,
+		},
 		{
 			name: "successful item purchase",
-			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo) {
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				ml *mockLedger) {
 				employeeID := uuid.New()
 				employee := &model.Employee{Id: employeeID, Username: "test_user", Balance: 1000}
 				item := &model.Item{Id: uuid.New(), Name: "Item1", Price: 500}
 				employeeInventory := &model.EmployeeInventory{
 					Id: uuid.New(), EmployeeId: employeeID, ItemId: item.Id, Amount: 1}
 
-				mer.On("FindByUsername", mock.Anything, "test_user").
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
 					Return(employee, nil)
 				mir.On("FindByName", mock.Anything, "Item1").
 					Return(item, nil)
 				minr.On("FindByEmployeeAndItem", mock.Anything, employeeID, item.Id).
 					Return(employeeInventory, nil)
-				mer.On("UpdateByUsername", mock.Anything, "test_user", mock.Anything).
+				ml.On("Purchase", mock.Anything, mock.Anything, employee, item.Price).
 					Return(nil)
+				mpr.On("Save", mock.Anything, mock.MatchedBy(func(p *model.Purchase) bool {
+					return p.EmployeeId == employeeID && p.ItemId == item.Id && p.Quantity == 1 && p.Price == item.Price
+				})).Return(nil)
 				minr.On("UpdateById", mock.Anything, employeeInventory.Id, mock.Anything).
 					Return(nil)
 			},
@@ -43,31 +75,85 @@ func TestItemService_Buy(t *testing.T) {
 		},
 		{
 			name: "employee not found",
-			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo) {
-				mer.On("FindByUsername", mock.Anything, "test_user").
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				ml *mockLedger) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
 					Return(nil, repo.ErrEmployeeNotFound)
 			},
 			expectedError: ErrEmployeeNotFound,
 		},
 		{
 			name: "item not found",
-			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo) {
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				ml *mockLedger) {
 				employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 1000}
 
-				mer.On("FindByUsername", mock.Anything, "test_user").
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
 					Return(employee, nil)
 				mir.On("FindByName", mock.Anything, "Item1").
 					Return(nil, repo.ErrItemNotFound)
 			},
 			expectedError: ErrItemNotFound,
 		},
+		{
+			name: "retired item",
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				ml *mockLedger) {
+				employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 1000}
+				item := &model.Item{Id: uuid.New(), Name: "Item1", Price: 500, Retired: true}
+
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
+					Return(employee, nil)
+				mir.On("FindByName", mock.Anything, "Item1").
+					Return(item, nil)
+			},
+			expectedError: ErrItemRetired,
+		},
+		{
+			name: "purchase limit reached",
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				ml *mockLedger) {
+				limit := 1
+				employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 1000}
+				item := &model.Item{Id: uuid.New(), Name: "Item1", Price: 500, MaxPerEmployee: &limit}
+
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
+					Return(employee, nil)
+				mir.On("FindByName", mock.Anything, "Item1").
+					Return(item, nil)
+				mpr.On("CountByEmployeeAndItem", mock.Anything, employee.Id, item.Id).
+					Return(1, nil)
+			},
+			expectedError: ErrPurchaseLimitReached,
+		},
+		{
+			name: "out of stock",
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				ml *mockLedger) {
+				stock := 0
+				limit := 2
+				employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 1000}
+				item := &model.Item{Id: uuid.New(), Name: "Item1", Price: 500, Stock: &stock, MaxPerEmployee: &limit}
+
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
+					Return(employee, nil)
+				mir.On("FindByName", mock.Anything, "Item1").
+					Return(item, nil)
+				mpr.On("CountByEmployeeAndItem", mock.Anything, employee.Id, item.Id).
+					Return(1, nil)
+				mir.On("DecrementStock", mock.Anything, item.Id, 1).
+					Return(false, nil)
+			},
+			expectedError: ErrOutOfStock,
+		},
 		{
 			name: "not enough balance",
-			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo) {
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				ml *mockLedger) {
 				employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 100}
 				item := &model.Item{Id: uuid.New(), Name: "Item1", Price: 500}
 
-				mer.On("FindByUsername", mock.Anything, "test_user").
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
 					Return(employee, nil)
 				mir.On("FindByName", mock.Anything, "Item1").
 					Return(item, nil)
@@ -75,19 +161,20 @@ func TestItemService_Buy(t *testing.T) {
 			expectedError: ErrNotEnoughCoins,
 		},
 		{
-			name: "error updating balance",
-			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo) {
+			name: "error recording purchase",
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				ml *mockLedger) {
 				employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 1000}
 				item := &model.Item{Id: uuid.New(), Name: "Item1", Price: 500}
 
-				mer.On("FindByUsername", mock.Anything, "test_user").
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
 					Return(employee, nil)
 				mir.On("FindByName", mock.Anything, "Item1").
 					Return(item, nil)
-				mer.On("UpdateByUsername", mock.Anything, "test_user", mock.Anything).
-					Return(errors.New("update error"))
+				ml.On("Purchase", mock.Anything, mock.Anything, employee, item.Price).
+					Return(errors.New("ledger error"))
 			},
-			expectedError: errors.New("update error"),
+			expectedError: errors.New("ledger error"),
 		},
 	}
 
@@ -96,9 +183,15 @@ func TestItemService_Buy(t *testing.T) {
 			mockItemRepo := new(mockItemRepo)
 			mockEmployeeRepo := new(mockEmployeeRepo)
 			mockInventoryRepo := new(mockInventoryRepo)
-			itemService := NewItemService(mockTrManager, mockItemRepo, mockEmployeeRepo, mockInventoryRepo)
+			mockPurchaseRepo := new(mockPurchaseRepo)
+			mockLedger := new(mockLedger)
+			mockOrderRepo := new(mockOrderRepo)
+			mockOrderRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Maybe()
+			itemService := NewItemService(
+				mockTrManager, mockItemRepo, mockEmployeeRepo, mockInventoryRepo, mockPurchaseRepo, mockOrderRepo,
+				mockLedger)
 
-			tc.setup(mockItemRepo, mockEmployeeRepo, mockInventoryRepo)
+			tc.setup(mockItemRepo, mockEmployeeRepo, mockInventoryRepo, mockPurchaseRepo, mockLedger)
 
 			err := itemService.Buy(context.Background(), "Item1", "test_user")
 
@@ -112,6 +205,464 @@ func TestItemService_Buy(t *testing.T) {
 			mockItemRepo.AssertExpectations(t)
 			mockEmployeeRepo.AssertExpectations(t)
 			mockInventoryRepo.AssertExpectations(t)
+			mockPurchaseRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemService_Checkout(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 100}
+	cup := &model.Item{Id: uuid.New(), Name: "cup", Price: 20}
+	pen := &model.Item{Id: uuid.New(), Name: "pen", Price: 10}
+
+	tests := []struct {
+		name  string
+		lines []model.OrderLine
+		setup func(
+			*mockItemRepo, *mockEmployeeRepo, *mockInventoryRepo, *mockPurchaseRepo, *mockOrderRepo, *mockLedger)
+		expectedError error
+		expectedItems []model.OrderItem
+		expectedTotal int
+	}{
+		{
+			name: "successful checkout merges repeated items",
+			lines: []model.OrderLine{
+				{Item: "pen", Quantity: 1}, {Item: "cup", Quantity: 2}, {Item: "pen", Quantity: 2}},
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				mor *mockOrderRepo, ml *mockLedger) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
+					Return(employee, nil)
+				mir.On("FindByName", mock.Anything, "cup").
+					Return(cup, nil)
+				mir.On("FindByName", mock.Anything, "pen").
+					Return(pen, nil)
+				ml.On("Purchase", mock.Anything, mock.Anything, employee, 70).
+					Return(nil)
+				mor.On("Save", mock.Anything, mock.MatchedBy(func(o *model.Order) bool {
+					return o.EmployeeId == employee.Id && o.Total == 70
+				})).Return(nil)
+				mpr.On("Save", mock.Anything, mock.MatchedBy(func(p *model.Purchase) bool {
+					return p.ItemId == cup.Id && p.Quantity == 2 && p.Price == cup.Price
+				})).Return(nil)
+				mpr.On("Save", mock.Anything, mock.MatchedBy(func(p *model.Purchase) bool {
+					return p.ItemId == pen.Id && p.Quantity == 3 && p.Price == pen.Price
+				})).Return(nil)
+				minr.On("FindByEmployeeAndItem", mock.Anything, employee.Id, cup.Id).
+					Return(nil, repo.ErrEmployeeInventoryNotFound)
+				minr.On("Save", mock.Anything, mock.MatchedBy(func(i *model.EmployeeInventory) bool {
+					return i.ItemId == cup.Id && i.Amount == 2
+				})).Return(nil)
+				minr.On("FindByEmployeeAndItem", mock.Anything, employee.Id, pen.Id).
+					Return(&model.EmployeeInventory{
+						Id: uuid.New(), EmployeeId: employee.Id, ItemId: pen.Id, Amount: 1}, nil)
+				minr.On("UpdateById", mock.Anything, mock.Anything, mock.MatchedBy(
+					func(i *model.EmployeeInventory) bool { return i.Amount == 4 })).Return(nil)
+			},
+			expectedItems: []model.OrderItem{
+				{ItemId: cup.Id, Item: "cup", Quantity: 2, Price: 20},
+				{ItemId: pen.Id, Item: "pen", Quantity: 3, Price: 10},
+			},
+			expectedTotal: 70,
+		},
+		{
+			name:  "total exceeds balance",
+			lines: []model.OrderLine{{Item: "cup", Quantity: 6}},
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				mor *mockOrderRepo, ml *mockLedger) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
+					Return(employee, nil)
+				mir.On("FindByName", mock.Anything, "cup").
+					Return(cup, nil)
+			},
+			expectedError: ErrNotEnoughCoins,
+		},
+		{
+			name:  "unknown item fails whole order",
+			lines: []model.OrderLine{{Item: "cup", Quantity: 1}, {Item: "yacht", Quantity: 1}},
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				mor *mockOrderRepo, ml *mockLedger) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
+					Return(employee, nil)
+				mir.On("FindByName", mock.Anything, "cup").
+					Return(cup, nil)
+				mir.On("FindByName", mock.Anything, "yacht").
+					Return(nil, repo.ErrItemNotFound)
+			},
+			expectedError: ErrItemNotFound,
+		},
+		{
+			name:  "empty order",
+			lines: nil,
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				mor *mockOrderRepo, ml *mockLedger) {
+			},
+			expectedError: ErrEmptyOrder,
+		},
+		{
+			name:  "frozen buyer",
+			lines: []model.OrderLine{{Item: "cup", Quantity: 1}},
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				mor *mockOrderRepo, ml *mockLedger) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "test_user").
+					Return(&model.Employee{Id: employee.Id, Username: "test_user", Balance: 100,
+						Status: model.EmployeeFrozen}, nil)
+			},
+			expectedError: ErrAccountFrozen,
+		},
+		{
+			name:  "non-positive quantity",
+			lines: []model.OrderLine{{Item: "cup", Quantity: 0}},
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo, minr *mockInventoryRepo, mpr *mockPurchaseRepo,
+				mor *mockOrderRepo, ml *mockLedger) {
+			},
+			expectedError: ErrInvalidQuantity,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockItemRepo := new(mockItemRepo)
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockInventoryRepo := new(mockInventoryRepo)
+			mockPurchaseRepo := new(mockPurchaseRepo)
+			mockOrderRepo := new(mockOrderRepo)
+			mockLedger := new(mockLedger)
+			itemService := NewItemService(
+				mockTrManager, mockItemRepo, mockEmployeeRepo, mockInventoryRepo, mockPurchaseRepo, mockOrderRepo,
+				mockLedger)
+
+			tc.setup(mockItemRepo, mockEmployeeRepo, mockInventoryRepo, mockPurchaseRepo, mockOrderRepo, mockLedger)
+
+			order, err := itemService.Checkout(context.Background(), "test_user", tc.lines)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				for i := range order.Items {
+					assert.NotEqual(t, uuid.Nil, order.Items[i].PurchaseId)
+					order.Items[i].PurchaseId = uuid.Nil
+				}
+				assert.Equal(t, tc.expectedItems, order.Items)
+				assert.Equal(t, tc.expectedTotal, order.Total)
+			}
+
+			mockItemRepo.AssertExpectations(t)
+			mockEmployeeRepo.AssertExpectations(t)
+			mockInventoryRepo.AssertExpectations(t)
+			mockPurchaseRepo.AssertExpectations(t)
+			mockOrderRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemService_List(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 100}
+	items := []model.Item{
+		{Id: uuid.New(), Name: "pen", Price: 10},
+		{Id: uuid.New(), Name: "hoody", Price: 300},
+	}
+
+	tests := []struct {
+		name           string
+		filter         model.ItemFilter
+		affordableOnly bool
+		setup          func(*mockItemRepo, *mockEmployeeRepo)
+		expectedError  error
+		expectedItems  []model.CatalogItem
+	}{
+		{
+			name:   "all items with availability",
+			filter: model.ItemFilter{SortBy: model.ItemSortPrice},
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo) {
+				mer.On("FindByUsername", mock.Anything, "test_user").
+					Return(employee, nil)
+				mir.On("FindAll", mock.Anything, model.ItemFilter{SortBy: model.ItemSortPrice}).
+					Return(items, nil)
+			},
+			expectedItems: []model.CatalogItem{
+				{Name: "pen", Price: 10, Available: true},
+				{Name: "hoody", Price: 300, Available: false},
+			},
+		},
+		{
+			name:           "affordable items only",
+			affordableOnly: true,
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo) {
+				mer.On("FindByUsername", mock.Anything, "test_user").
+					Return(employee, nil)
+				mir.On("FindAll", mock.Anything, mock.MatchedBy(func(f model.ItemFilter) bool {
+					return f.MaxPrice != nil && *f.MaxPrice == employee.Balance
+				})).Return(items[:1], nil)
+			},
+			expectedItems: []model.CatalogItem{
+				{Name: "pen", Price: 10, Available: true},
+			},
+		},
+		{
+			name: "employee not found",
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo) {
+				mer.On("FindByUsername", mock.Anything, "test_user").
+					Return(nil, repo.ErrEmployeeNotFound)
+			},
+			expectedError: ErrEmployeeNotFound,
+		},
+		{
+			name: "items retrieval error",
+			setup: func(mir *mockItemRepo, mer *mockEmployeeRepo) {
+				mer.On("FindByUsername", mock.Anything, "test_user").
+					Return(employee, nil)
+				mir.On("FindAll", mock.Anything, mock.Anything).
+					Return(nil, errors.New("items error"))
+			},
+			expectedError: errors.New("items error"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockItemRepo := new(mockItemRepo)
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			itemService := NewItemService(
+				mockTrManager, mockItemRepo, mockEmployeeRepo, new(mockInventoryRepo), new(mockPurchaseRepo),
+				new(mockOrderRepo), new(mockLedger))
+
+			tc.setup(mockItemRepo, mockEmployeeRepo)
+
+			catalog, err := itemService.List(context.Background(), "test_user", tc.filter, tc.affordableOnly)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedItems, catalog)
+			}
+
+			mockItemRepo.AssertExpectations(t)
+			mockEmployeeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemService_Create(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	stock := 5
+	negative := -1
+	zero := 0
+
+	tests := []struct {
+		name          string
+		item          model.Item
+		setup         func(*mockItemRepo)
+		expectedError error
+	}{
+		{
+			name: "successful creation",
+			item: model.Item{Name: "sticker", Price: 15, Stock: &stock},
+			setup: func(mir *mockItemRepo) {
+				mir.On("Save", mock.Anything, mock.MatchedBy(func(i *model.Item) bool {
+					return i.Id != uuid.Nil && i.Name == "sticker" && i.Price == 15 && *i.Stock == stock
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "non-positive price",
+			item:          model.Item{Name: "sticker"},
+			setup:         func(mir *mockItemRepo) {},
+			expectedError: ErrInvalidItemPrice,
+		},
+		{
+			name:          "negative stock",
+			item:          model.Item{Name: "sticker", Price: 15, Stock: &negative},
+			setup:         func(mir *mockItemRepo) {},
+			expectedError: ErrInvalidItemLimit,
+		},
+		{
+			name:          "zero purchase limit",
+			item:          model.Item{Name: "sticker", Price: 15, MaxPerEmployee: &zero},
+			setup:         func(mir *mockItemRepo) {},
+			expectedError: ErrInvalidItemLimit,
+		},
+		{
+			name: "duplicate name",
+			item: model.Item{Name: "sticker", Price: 15},
+			setup: func(mir *mockItemRepo) {
+				mir.On("Save", mock.Anything, mock.Anything).
+					Return(repo.ErrItemExists)
+			},
+			expectedError: ErrItemExists,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockItemRepo := new(mockItemRepo)
+			itemService := NewItemService(
+				mockTrManager, mockItemRepo, new(mockEmployeeRepo), new(mockInventoryRepo), new(mockPurchaseRepo),
+				new(mockOrderRepo), new(mockLedger))
+
+			tc.setup(mockItemRepo)
+
+			err := itemService.Create(context.Background(), &tc.item)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemService_Update(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	newName := "big cup"
+	newPrice := 25
+	badPrice := -1
+
+	tests := []struct {
+		name          string
+		update        model.ItemUpdate
+		setup         func(*mockItemRepo, *model.Item)
+		expectedError error
+		expectedItem  model.Item
+	}{
+		{
+			name:   "rename and change price",
+			update: model.ItemUpdate{Name: &newName, Price: &newPrice},
+			setup: func(mir *mockItemRepo, item *model.Item) {
+				mir.On("FindByNameForUpdate", mock.Anything, "cup").
+					Return(item, nil)
+				mir.On("UpdateById", mock.Anything, item.Id, mock.Anything).
+					Return(nil)
+			},
+			expectedItem: model.Item{Name: newName, Price: newPrice},
+		},
+		{
+			name:   "change price only",
+			update: model.ItemUpdate{Price: &newPrice},
+			setup: func(mir *mockItemRepo, item *model.Item) {
+				mir.On("FindByNameForUpdate", mock.Anything, "cup").
+					Return(item, nil)
+				mir.On("UpdateById", mock.Anything, item.Id, mock.Anything).
+					Return(nil)
+			},
+			expectedItem: model.Item{Name: "cup", Price: newPrice},
+		},
+		{
+			name:          "non-positive price",
+			update:        model.ItemUpdate{Price: &badPrice},
+			setup:         func(mir *mockItemRepo, item *model.Item) {},
+			expectedError: ErrInvalidItemPrice,
+		},
+		{
+			name:   "item not found",
+			update: model.ItemUpdate{Name: &newName},
+			setup: func(mir *mockItemRepo, item *model.Item) {
+				mir.On("FindByNameForUpdate", mock.Anything, "cup").
+					Return(nil, repo.ErrItemNotFound)
+			},
+			expectedError: ErrItemNotFound,
+		},
+		{
+			name:   "name taken",
+			update: model.ItemUpdate{Name: &newName},
+			setup: func(mir *mockItemRepo, item *model.Item) {
+				mir.On("FindByNameForUpdate", mock.Anything, "cup").
+					Return(item, nil)
+				mir.On("UpdateById", mock.Anything, item.Id, mock.Anything).
+					Return(repo.ErrItemExists)
+			},
+			expectedError: ErrItemExists,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockItemRepo := new(mockItemRepo)
+			itemService := NewItemService(
+				mockTrManager, mockItemRepo, new(mockEmployeeRepo), new(mockInventoryRepo), new(mockPurchaseRepo),
+				new(mockOrderRepo), new(mockLedger))
+			item := &model.Item{Id: uuid.New(), Name: "cup", Price: 20}
+
+			tc.setup(mockItemRepo, item)
+
+			updated, err := itemService.Update(context.Background(), "cup", tc.update)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedItem.Name, updated.Name)
+				assert.Equal(t, tc.expectedItem.Price, updated.Price)
+			}
+
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemService_Retire(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+
+	tests := []struct {
+		name          string
+		setup         func(*mockItemRepo)
+		expectedError error
+	}{
+		{
+			name: "successful retirement",
+			setup: func(mir *mockItemRepo) {
+				item := &model.Item{Id: uuid.New(), Name: "cup", Price: 20}
+				mir.On("FindByNameForUpdate", mock.Anything, "cup").
+					Return(item, nil)
+				mir.On("UpdateById", mock.Anything, item.Id, mock.MatchedBy(func(i *model.Item) bool {
+					return i.Retired
+				})).Return(nil)
+			},
+		},
+		{
+			name: "already retired",
+			setup: func(mir *mockItemRepo) {
+				mir.On("FindByNameForUpdate", mock.Anything, "cup").
+					Return(&model.Item{Id: uuid.New(), Name: "cup", Price: 20, Retired: true}, nil)
+			},
+		},
+		{
+			name: "item not found",
+			setup: func(mir *mockItemRepo) {
+				mir.On("FindByNameForUpdate", mock.Anything, "cup").
+					Return(nil, repo.ErrItemNotFound)
+			},
+			expectedError: ErrItemNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockItemRepo := new(mockItemRepo)
+			itemService := NewItemService(
+				mockTrManager, mockItemRepo, new(mockEmployeeRepo), new(mockInventoryRepo), new(mockPurchaseRepo),
+				new(mockOrderRepo), new(mockLedger))
+
+			tc.setup(mockItemRepo)
+
+			err := itemService.Retire(context.Background(), "cup")
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockItemRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type LedgerService struct {
+	employeeRepo EmployeeRepo
+	ledgerRepo   LedgerRepo
+	coinLotRepo  CoinLotRepo
+}
+
+func NewLedgerService(employeeRepo EmployeeRepo, ledgerRepo LedgerRepo, coinLotRepo CoinLotRepo) *LedgerService {
+	return &LedgerService{
+		employeeRepo: employeeRepo,
+		ledgerRepo:   ledgerRepo,
+		coinLotRepo:  coinLotRepo,
+	}
+}
+
+// posting is one side of a ledger operation: either an employee wallet or a system account. A system account
+// debit may carry the lots it gives back, otherwise the credited coins form a new lot.
+type posting struct {
+	employee *model.Employee
+	account  model.LedgerAccount
+	lots     []model.CoinLot
+}
+
+func employeeAccount(employee *model.Employee) posting {
+	return posting{employee: employee, account: model.AccountEmployee}
+}
+
+func systemAccount(account model.LedgerAccount) posting {
+	return posting{account: account}
+}
+
+// The methods below must run inside a transaction that already holds the locks on the employees involved.
+
+func (s *LedgerService) Transfer(
+	ctx context.Context, operationId uuid.UUID, from *model.Employee, to *model.Employee, amount int) error {
+	return s.post(ctx, operationId, model.OperationTransfer, employeeAccount(from), employeeAccount(to), amount)
+}
+
+func (s *LedgerService) Sale(
+	ctx context.Context, operationId uuid.UUID, buyer *model.Employee, seller *model.Employee, amount int) error {
+	return s.post(ctx, operationId, model.OperationSale, employeeAccount(buyer), employeeAccount(seller), amount)
+}
+
+func (s *LedgerService) Purchase(
+	ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	return s.post(ctx, operationId, model.OperationPurchase,
+		employeeAccount(employee), systemAccount(model.AccountShop), amount)
+}
+
+// Refund and Return give back coins paid for the order, keeping the grant dates of the lots they were paid from.
+func (s *LedgerService) Refund(
+	ctx context.Context, operationId uuid.UUID, orderId uuid.UUID, employee *model.Employee, amount int) error {
+	return s.giveBack(ctx, operationId, model.OperationRefund, orderId, employee, amount)
+}
+
+func (s *LedgerService) Return(
+	ctx context.Context, operationId uuid.UUID, orderId uuid.UUID, employee *model.Employee, amount int) error {
+	return s.giveBack(ctx, operationId, model.OperationReturn, orderId, employee, amount)
+}
+
+func (s *LedgerService) Grant(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	return s.post(ctx, operationId, model.OperationInitialGrant,
+		systemAccount(model.AccountEmission), employeeAccount(employee), amount)
+}
+
+func (s *LedgerService) Allowance(
+	ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	return s.post(ctx, operationId, model.OperationAllowance,
+		systemAccount(model.AccountEmission), employeeAccount(employee), amount)
+}
+
+func (s *LedgerService) Expire(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	return s.post(ctx, operationId, model.OperationExpiry,
+		employeeAccount(employee), systemAccount(model.AccountExpiry), amount)
+}
+
+func (s *LedgerService) Sweep(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	return s.post(ctx, operationId, model.OperationSweep,
+		employeeAccount(employee), systemAccount(model.AccountPool), amount)
+}
+
+func (s *LedgerService) Adjust(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	if amount < 0 {
+		return s.post(ctx, operationId, model.OperationAdjustment,
+			employeeAccount(employee), systemAccount(model.AccountAdjustment), -amount)
+	}
+	return s.post(ctx, operationId, model.OperationAdjustment,
+		systemAccount(model.AccountAdjustment), employeeAccount(employee), amount)
+}
+
+// Verify checks the employee's stored balance against the sum of their ledger entries. It reads the employee's whole
+// history, so postings don't call it; Reconcile checks every employee periodically instead.
+func (s *LedgerService) Verify(ctx context.Context, employee *model.Employee) error {
+	const op = "service.LedgerService.Verify"
+
+	balance, err := s.ledgerRepo.BalanceByEmployee(ctx, employee.Id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if balance != employee.Balance {
+		return fmt.Errorf("%s: %w: %s has %d coins, ledger has %d",
+			op, ErrLedgerMismatch, employee.Username, employee.Balance, balance)
+	}
+
+	return nil
+}
+
+// Reconcile returns ErrLedgerMismatch listing every employee whose stored balance differs from their ledger.
+func (s *LedgerService) Reconcile(ctx context.Context) error {
+	const op = "service.LedgerService.Reconcile"
+
+	mismatches, err := s.ledgerRepo.FindMismatches(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	details := make([]string, len(mismatches))
+	for i, m := range mismatches {
+		details[i] = fmt.Sprintf("%s has %d coins, ledger has %d", m.Username, m.Balance, m.LedgerBalance)
+	}
+	return fmt.Errorf("%s: %w: %s", op, ErrLedgerMismatch, strings.Join(details, "; "))
+}
+
+func (s *LedgerService) giveBack(
+	ctx context.Context,
+	operationId uuid.UUID,
+	operationType model.OperationType,
+	orderId uuid.UUID,
+	employee *model.Employee,
+	amount int,
+) error {
+	const op = "service.LedgerService.giveBack"
+
+	if amount <= 0 {
+		return ErrNonPositiveLedgerAmount
+	}
+
+	lots, err := s.restoreLots(ctx, orderId, amount)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	shop := systemAccount(model.AccountShop)
+	shop.lots = lots
+	return s.post(ctx, operationId, operationType, shop, employeeAccount(employee), amount)
+}
+
+func (s *LedgerService) post(
+	ctx context.Context,
+	operationId uuid.UUID,
+	operationType model.OperationType,
+	debit posting,
+	credit posting,
+	amount int,
+) error {
+	const op = "service.LedgerService.post"
+
+	if amount <= 0 {
+		return ErrNonPositiveLedgerAmount
+	}
+
+	spent := debit.lots
+	if debit.employee != nil {
+		if debit.employee.Available() < amount {
+			return ErrNotEnoughCoins
+		}
+
+		debit.employee.Balance -= amount
+		if err := s.employeeRepo.UpdateByUsername(ctx, debit.employee.Username, debit.employee); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		var err error
+		if spent, err = s.spendLots(ctx, debit.employee, amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		// coins paid to the shop can come back through a refund or return
+		if credit.account == model.AccountShop {
+			if err = s.recordSpent(ctx, operationId, spent); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	if credit.employee != nil {
+		credit.employee.Balance += amount
+		if err := s.employeeRepo.UpdateByUsername(ctx, credit.employee.Username, credit.employee); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := s.receiveLots(ctx, credit.employee, spent, amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	entries := []model.LedgerEntry{
+		newLedgerEntry(operationId, operationType, debit, model.Debit, amount),
+		newLedgerEntry(operationId, operationType, credit, model.Credit, amount),
+	}
+	if !balanced(entries) {
+		return ErrUnbalancedPosting
+	}
+
+	if err := s.ledgerRepo.SaveAll(ctx, entries); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// balanced reports whether the entries of one operation debit exactly as much as they credit.
+func balanced(entries []model.LedgerEntry) bool {
+	sum := 0
+	for _, entry := range entries {
+		if entry.Direction == model.Debit {
+			sum += entry.Amount
+		} else {
+			sum -= entry.Amount
+		}
+	}
+	return sum == 0
+}
+
+// spendLots takes amount coins from the employee's oldest lots and returns what it took, one part per lot. Coins
+// that no lot accounts for are returned as a part granted now.
+func (s *LedgerService) spendLots(ctx context.Context, employee *model.Employee, amount int) ([]model.CoinLot, error) {
+	lots, err := s.coinLotRepo.FindActiveByEmployee(ctx, employee.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var spent []model.CoinLot
+	for _, lot := range lots {
+		if amount == 0 {
+			break
+		}
+
+		taken := min(lot.Remaining, amount)
+		if err = s.coinLotRepo.UpdateRemaining(ctx, lot.Id, lot.Remaining-taken); err != nil {
+			return nil, err
+		}
+
+		spent = append(spent, model.CoinLot{Amount: taken, GrantedAt: lot.GrantedAt})
+		amount -= taken
+	}
+
+	if amount > 0 {
+		spent = append(spent, model.CoinLot{Amount: amount, GrantedAt: time.Now()})
+	}
+
+	return spent, nil
+}
+
+func (s *LedgerService) recordSpent(ctx context.Context, operationId uuid.UUID, spent []model.CoinLot) error {
+	parts := make([]model.SpentCoins, len(spent))
+	for i, part := range spent {
+		parts[i] = model.SpentCoins{
+			Id:          uuid.New(),
+			OperationId: operationId,
+			Amount:      part.Amount,
+			Remaining:   part.Amount,
+			GrantedAt:   part.GrantedAt,
+		}
+	}
+
+	return s.coinLotRepo.SaveSpent(ctx, parts)
+}
+
+// restoreLots takes amount coins back from what the operation spent, newest first. Coins it has no record of, such
+// as those paid before the records were kept, are returned as a part granted now.
+func (s *LedgerService) restoreLots(ctx context.Context, operationId uuid.UUID, amount int) ([]model.CoinLot, error) {
+	spent, err := s.coinLotRepo.FindSpentByOperationForUpdate(ctx, operationId)
+	if err != nil {
+		return nil, err
+	}
+
+	var lots []model.CoinLot
+	for _, part := range spent {
+		if amount == 0 {
+			break
+		}
+
+		taken := min(part.Remaining, amount)
+		if err = s.coinLotRepo.UpdateSpentRemaining(ctx, part.Id, part.Remaining-taken); err != nil {
+			return nil, err
+		}
+
+		lots = append(lots, model.CoinLot{Amount: taken, GrantedAt: part.GrantedAt})
+		amount -= taken
+	}
+
+	if amount > 0 {
+		lots = append(lots, model.CoinLot{Amount: amount, GrantedAt: time.Now()})
+	}
+
+	return lots, nil
+}
+
+// receiveLots gives the employee the lots spent or given back by the other side, so the coins keep their grant
+// date. Coins coming from a system account without lots form a new lot.
+func (s *LedgerService) receiveLots(
+	ctx context.Context, employee *model.Employee, spent []model.CoinLot, amount int) error {
+	if spent == nil {
+		spent = []model.CoinLot{{Amount: amount, GrantedAt: time.Now()}}
+	}
+
+	lots := make([]model.CoinLot, len(spent))
+	for i, part := range spent {
+		lots[i] = model.CoinLot{
+			Id:         uuid.New(),
+			EmployeeId: employee.Id,
+			Amount:     part.Amount,
+			Remaining:  part.Amount,
+			GrantedAt:  part.GrantedAt,
+		}
+	}
+
+	return s.coinLotRepo.SaveAll(ctx, lots)
+}
+
+func newLedgerEntry(
+	operationId uuid.UUID,
+	operationType model.OperationType,
+	p posting,
+	direction model.EntryDirection,
+	amount int,
+) model.LedgerEntry {
+	entry := model.LedgerEntry{
+		Id:            uuid.New(),
+		OperationId:   operationId,
+		OperationType: operationType,
+		Account:       p.account,
+		Direction:     direction,
+		Amount:        amount,
+	}
+	if p.employee != nil {
+		employeeId := p.employee.Id
+		entry.EmployeeId = &employeeId
+	}
+	return entry
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestLedgerService_Transfer(t *testing.T) {
+	tests := []struct {
+		name             string
+		senderBalance    int
+		amount           int
+		setup            func(*mockEmployeeRepo, *mockLedgerRepo)
+		expectedError    error
+		expectedSender   int
+		expectedReceiver int
+	}{
+		{
+			name:          "successful transfer",
+			senderBalance: 1000,
+			amount:        200,
+			setup: func(mer *mockEmployeeRepo, mlr *mockLedgerRepo) {
+				mer.On("UpdateByUsername", mock.Anything, "sender", mock.Anything).
+					Return(nil)
+				mer.On("UpdateByUsername", mock.Anything, "receiver", mock.Anything).
+					Return(nil)
+				mlr.On("SaveAll", mock.Anything, mock.MatchedBy(func(entries []model.LedgerEntry) bool {
+					return len(entries) == 2 &&
+						entries[0].Direction == model.Debit && entries[0].Amount == 200 &&
+						entries[1].Direction == model.Credit && entries[1].Amount == 200
+				})).Return(nil)
+			},
+			expectedError:    nil,
+			expectedSender:   800,
+			expectedReceiver: 700,
+		},
+		{
+			name:             "not enough coins",
+			senderBalance:    100,
+			amount:           200,
+			setup:            func(mer *mockEmployeeRepo, mlr *mockLedgerRepo) {},
+			expectedError:    ErrNotEnoughCoins,
+			expectedSender:   100,
+			expectedReceiver: 500,
+		},
+		{
+			name:             "non-positive amount",
+			senderBalance:    1000,
+			amount:           0,
+			setup:            func(mer *mockEmployeeRepo, mlr *mockLedgerRepo) {},
+			expectedError:    ErrNonPositiveLedgerAmount,
+			expectedSender:   1000,
+			expectedReceiver: 500,
+		},
+		{
+			name:          "error saving entries",
+			senderBalance: 1000,
+			amount:        200,
+			setup: func(mer *mockEmployeeRepo, mlr *mockLedgerRepo) {
+				mer.On("UpdateByUsername", mock.Anything, "sender", mock.Anything).
+					Return(nil)
+				mer.On("UpdateByUsername", mock.Anything, "receiver", mock.Anything).
+					Return(nil)
+				mlr.On("SaveAll", mock.Anything, mock.Anything).
+					Return(errors.New("save error"))
+			},
+			expectedError:    errors.New("save error"),
+			expectedSender:   800,
+			expectedReceiver: 700,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockLedgerRepo := new(mockLedgerRepo)
+			mockCoinLotRepo := new(mockCoinLotRepo)
+			ledgerService := NewLedgerService(mockEmployeeRepo, mockLedgerRepo, mockCoinLotRepo)
+
+			tc.setup(mockEmployeeRepo, mockLedgerRepo)
+			mockCoinLotRepo.On("FindActiveByEmployee", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+			mockCoinLotRepo.On("SaveAll", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: tc.senderBalance}
+			receiver := &model.Employee{Id: uuid.New(), Username: "receiver", Balance: 500}
+
+			err := ledgerService.Transfer(context.Background(), uuid.New(), sender, receiver, tc.amount)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedSender, sender.Balance)
+			assert.Equal(t, tc.expectedReceiver, receiver.Balance)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockLedgerRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLedgerService_Adjust(t *testing.T) {
+	tests := []struct {
+		name              string
+		amount            int
+		expectedBalance   int
+		expectedDebitAcc  model.LedgerAccount
+		expectedCreditAcc model.LedgerAccount
+	}{
+		{
+			name:              "positive adjustment credits employee",
+			amount:            150,
+			expectedBalance:   650,
+			expectedDebitAcc:  model.AccountAdjustment,
+			expectedCreditAcc: model.AccountEmployee,
+		},
+		{
+			name:              "negative adjustment debits employee",
+			amount:            -150,
+			expectedBalance:   350,
+			expectedDebitAcc:  model.AccountEmployee,
+			expectedCreditAcc: model.AccountAdjustment,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockLedgerRepo := new(mockLedgerRepo)
+			mockCoinLotRepo := new(mockCoinLotRepo)
+			ledgerService := NewLedgerService(mockEmployeeRepo, mockLedgerRepo, mockCoinLotRepo)
+
+			mockEmployeeRepo.On("UpdateByUsername", mock.Anything, "employee", mock.Anything).
+				Return(nil)
+			mockCoinLotRepo.On("FindActiveByEmployee", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+			mockCoinLotRepo.On("SaveAll", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockLedgerRepo.On("SaveAll", mock.Anything, mock.MatchedBy(func(entries []model.LedgerEntry) bool {
+				return entries[0].Account == tc.expectedDebitAcc && entries[1].Account == tc.expectedCreditAcc
+			})).Return(nil)
+
+			employee := &model.Employee{Id: uuid.New(), Username: "employee", Balance: 500}
+
+			err := ledgerService.Adjust(context.Background(), uuid.New(), employee, tc.amount)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedBalance, employee.Balance)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockLedgerRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLedgerService_Transfer_SpendsOldestLots(t *testing.T) {
+	mockEmployeeRepo := new(mockEmployeeRepo)
+	mockLedgerRepo := new(mockLedgerRepo)
+	mockCoinLotRepo := new(mockCoinLotRepo)
+	ledgerService := NewLedgerService(mockEmployeeRepo, mockLedgerRepo, mockCoinLotRepo)
+
+	sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: 100}
+	receiver := &model.Employee{Id: uuid.New(), Username: "receiver"}
+	older := model.CoinLot{Id: uuid.New(), Remaining: 30, GrantedAt: time.Now().AddDate(-1, 0, 0)}
+	newer := model.CoinLot{Id: uuid.New(), Remaining: 70, GrantedAt: time.Now().AddDate(0, -1, 0)}
+
+	mockEmployeeRepo.On("UpdateByUsername", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockLedgerRepo.On("SaveAll", mock.Anything, mock.Anything).Return(nil)
+	mockCoinLotRepo.On("FindActiveByEmployee", mock.Anything, sender.Id).
+		Return([]model.CoinLot{older, newer}, nil)
+	mockCoinLotRepo.On("UpdateRemaining", mock.Anything, older.Id, 0).Return(nil)
+	mockCoinLotRepo.On("UpdateRemaining", mock.Anything, newer.Id, 60).Return(nil)
+	mockCoinLotRepo.On("SaveAll", mock.Anything, mock.MatchedBy(func(lots []model.CoinLot) bool {
+		return len(lots) == 2 &&
+			lots[0].EmployeeId == receiver.Id && lots[0].Remaining == 30 && lots[0].GrantedAt.Equal(older.GrantedAt) &&
+			lots[1].EmployeeId == receiver.Id && lots[1].Remaining == 10 && lots[1].GrantedAt.Equal(newer.GrantedAt)
+	})).Return(nil)
+
+	err := ledgerService.Transfer(context.Background(), uuid.New(), sender, receiver, 40)
+
+	assert.NoError(t, err)
+	mockCoinLotRepo.AssertExpectations(t)
+}
+
+func TestLedgerService_Purchase_RecordsSpentLots(t *testing.T) {
+	mockEmployeeRepo := new(mockEmployeeRepo)
+	mockLedgerRepo := new(mockLedgerRepo)
+	mockCoinLotRepo := new(mockCoinLotRepo)
+	ledgerService := NewLedgerService(mockEmployeeRepo, mockLedgerRepo, mockCoinLotRepo)
+
+	employee := &model.Employee{Id: uuid.New(), Username: "employee", Balance: 100}
+	orderId := uuid.New()
+	older := model.CoinLot{Id: uuid.New(), Remaining: 30, GrantedAt: time.Now().AddDate(-1, 0, 0)}
+	newer := model.CoinLot{Id: uuid.New(), Remaining: 70, GrantedAt: time.Now().AddDate(0, -1, 0)}
+
+	mockEmployeeRepo.On("UpdateByUsername", mock.Anything, "employee", employee).Return(nil)
+	mockLedgerRepo.On("SaveAll", mock.Anything, mock.Anything).Return(nil)
+	mockCoinLotRepo.On("FindActiveByEmployee", mock.Anything, employee.Id).
+		Return([]model.CoinLot{older, newer}, nil)
+	mockCoinLotRepo.On("UpdateRemaining", mock.Anything, older.Id, 0).Return(nil)
+	mockCoinLotRepo.On("UpdateRemaining", mock.Anything, newer.Id, 60).Return(nil)
+	mockCoinLotRepo.On("SaveSpent", mock.Anything, mock.MatchedBy(func(spent []model.SpentCoins) bool {
+		return len(spent) == 2 &&
+			spent[0].OperationId == orderId && spent[0].Remaining == 30 && spent[0].GrantedAt.Equal(older.GrantedAt) &&
+			spent[1].OperationId == orderId && spent[1].Remaining == 10 && spent[1].GrantedAt.Equal(newer.GrantedAt)
+	})).Return(nil)
+
+	err := ledgerService.Purchase(context.Background(), orderId, employee, 40)
+
+	assert.NoError(t, err)
+	mockCoinLotRepo.AssertExpectations(t)
+}
+
+func TestLedgerService_Return_RestoresGrantDates(t *testing.T) {
+	mockEmployeeRepo := new(mockEmployeeRepo)
+	mockLedgerRepo := new(mockLedgerRepo)
+	mockCoinLotRepo := new(mockCoinLotRepo)
+	ledgerService := NewLedgerService(mockEmployeeRepo, mockLedgerRepo, mockCoinLotRepo)
+
+	employee := &model.Employee{Id: uuid.New(), Username: "employee", Balance: 60}
+	orderId := uuid.New()
+	newer := model.SpentCoins{Id: uuid.New(), Remaining: 10, GrantedAt: time.Now().AddDate(0, -1, 0)}
+	older := model.SpentCoins{Id: uuid.New(), Remaining: 30, GrantedAt: time.Now().AddDate(-1, 0, 0)}
+
+	mockEmployeeRepo.On("UpdateByUsername", mock.Anything, "employee", employee).Return(nil)
+	mockLedgerRepo.On("SaveAll", mock.Anything, mock.Anything).Return(nil)
+	mockCoinLotRepo.On("FindSpentByOperationForUpdate", mock.Anything, orderId).
+		Return([]model.SpentCoins{newer, older}, nil)
+	mockCoinLotRepo.On("UpdateSpentRemaining", mock.Anything, newer.Id, 0).Return(nil)
+	mockCoinLotRepo.On("UpdateSpentRemaining", mock.Anything, older.Id, 15).Return(nil)
+	mockCoinLotRepo.On("SaveAll", mock.Anything, mock.MatchedBy(func(lots []model.CoinLot) bool {
+		return len(lots) == 2 &&
+			lots[0].EmployeeId == employee.Id && lots[0].Remaining == 10 && lots[0].GrantedAt.Equal(newer.GrantedAt) &&
+			lots[1].EmployeeId == employee.Id && lots[1].Remaining == 15 && lots[1].GrantedAt.Equal(older.GrantedAt)
+	})).Return(nil)
+
+	err := ledgerService.Return(context.Background(), uuid.New(), orderId, employee, 25)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 85, employee.Balance)
+	mockCoinLotRepo.AssertExpectations(t)
+}
+
+func TestLedgerService_Refund_WithoutSpentRecords(t *testing.T) {
+	mockEmployeeRepo := new(mockEmployeeRepo)
+	mockLedgerRepo := new(mockLedgerRepo)
+	mockCoinLotRepo := new(mockCoinLotRepo)
+	ledgerService := NewLedgerService(mockEmployeeRepo, mockLedgerRepo, mockCoinLotRepo)
+
+	employee := &model.Employee{Id: uuid.New(), Username: "employee"}
+	orderId := uuid.New()
+
+	mockEmployeeRepo.On("UpdateByUsername", mock.Anything, "employee", employee).Return(nil)
+	mockLedgerRepo.On("SaveAll", mock.Anything, mock.Anything).Return(nil)
+	mockCoinLotRepo.On("FindSpentByOperationForUpdate", mock.Anything, orderId).Return(nil, nil)
+	mockCoinLotRepo.On("SaveAll", mock.Anything, mock.MatchedBy(func(lots []model.CoinLot) bool {
+		return len(lots) == 1 && lots[0].Remaining == 40 && time.Since(lots[0].GrantedAt) < time.Minute
+	})).Return(nil)
+
+	err := ledgerService.Refund(context.Background(), uuid.New(), orderId, employee, 40)
+
+	assert.NoError(t, err)
+	mockCoinLotRepo.AssertExpectations(t)
+}
+
+func TestLedgerService_Transfer_DoesNotReadLedger(t *testing.T) {
+	mockEmployeeRepo := new(mockEmployeeRepo)
+	mockLedgerRepo := new(mockLedgerRepo)
+	mockCoinLotRepo := new(mockCoinLotRepo)
+	ledgerService := NewLedgerService(mockEmployeeRepo, mockLedgerRepo, mockCoinLotRepo)
+
+	sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: 1000}
+	receiver := &model.Employee{Id: uuid.New(), Username: "receiver", Balance: 500}
+
+	mockEmployeeRepo.On("UpdateByUsername", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockCoinLotRepo.On("FindActiveByEmployee", mock.Anything, mock.Anything).Return(nil, nil)
+	mockCoinLotRepo.On("SaveAll", mock.Anything, mock.Anything).Return(nil)
+	mockLedgerRepo.On("SaveAll", mock.Anything, mock.Anything).Return(nil)
+
+	err := ledgerService.Transfer(context.Background(), uuid.New(), sender, receiver, 200)
+
+	assert.NoError(t, err)
+	mockLedgerRepo.AssertNotCalled(t, "BalanceByEmployee", mock.Anything, mock.Anything)
+}
+
+func TestLedgerService_Verify(t *testing.T) {
+	employee := &model.Employee{Id: uuid.New(), Username: "employee", Balance: 500}
+
+	tests := []struct {
+		name          string
+		ledgerBalance int
+		expectedError error
+	}{
+		{
+			name:          "balance matches ledger",
+			ledgerBalance: 500,
+			expectedError: nil,
+		},
+		{
+			name:          "balance does not match ledger",
+			ledgerBalance: 400,
+			expectedError: ErrLedgerMismatch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockLedgerRepo := new(mockLedgerRepo)
+			ledgerService := NewLedgerService(nil, mockLedgerRepo, nil)
+
+			mockLedgerRepo.On("BalanceByEmployee", mock.Anything, employee.Id).
+				Return(tc.ledgerBalance, nil)
+
+			err := ledgerService.Verify(context.Background(), employee)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockLedgerRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLedgerService_Reconcile(t *testing.T) {
+	tests := []struct {
+		name          string
+		mismatches    []model.LedgerMismatch
+		repoErr       error
+		expectedError error
+	}{
+		{
+			name:          "every balance matches",
+			mismatches:    nil,
+			expectedError: nil,
+		},
+		{
+			name: "balance does not match ledger",
+			mismatches: []model.LedgerMismatch{
+				{EmployeeId: uuid.New(), Username: "employee", Balance: 500, LedgerBalance: 400},
+			},
+			expectedError: ErrLedgerMismatch,
+		},
+		{
+			name:          "repository error",
+			repoErr:       errors.New("db error"),
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockLedgerRepo := new(mockLedgerRepo)
+			ledgerService := NewLedgerService(nil, mockLedgerRepo, nil)
+
+			mockLedgerRepo.On("FindMismatches", mock.Anything).Return(tc.mismatches, tc.repoErr)
+
+			err := ledgerService.Reconcile(context.Background())
+
+			if tc.expectedError != nil {
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockLedgerRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBalanced(t *testing.T) {
+	assert.True(t, balanced([]model.LedgerEntry{
+		{Direction: model.Debit, Amount: 200},
+		{Direction: model.Credit, Amount: 150},
+		{Direction: model.Credit, Amount: 50},
+	}))
+	assert.False(t, balanced([]model.LedgerEntry{
+		{Direction: model.Debit, Amount: 200},
+		{Direction: model.Credit, Amount: 150},
+	}))
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type MarketplaceService struct {
+	trManager     TransactionManager
+	employeeRepo  EmployeeRepo
+	itemRepo      ItemRepo
+	inventoryRepo InventoryRepo
+	listingRepo   ListingRepo
+	ledger        Ledger
+	salePolicy    SalePolicy
+	listingTTL    time.Duration
+}
+
+func NewMarketplaceService(
+	trManager TransactionManager,
+	employeeRepo EmployeeRepo,
+	itemRepo ItemRepo,
+	inventoryRepo InventoryRepo,
+	listingRepo ListingRepo,
+	ledger Ledger,
+	salePolicy SalePolicy,
+	listingTTL time.Duration,
+) *MarketplaceService {
+	return &MarketplaceService{
+		trManager:     trManager,
+		employeeRepo:  employeeRepo,
+		itemRepo:      itemRepo,
+		inventoryRepo: inventoryRepo,
+		listingRepo:   listingRepo,
+		ledger:        ledger,
+		salePolicy:    salePolicy,
+		listingTTL:    listingTTL,
+	}
+}
+
+// Create puts the listed units into escrow by taking them out of the seller's inventory.
+func (s *MarketplaceService) Create(
+	ctx context.Context, username string, itemName string, quantity int, price int) (*model.Listing, error) {
+	const op = "service.MarketplaceService.Create"
+
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	if price <= 0 {
+		return nil, ErrInvalidListingPrice
+	}
+
+	var listing *model.Listing
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		seller, err := s.employeeRepo.FindByUsernameForUpdate(ctx, username)
+		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeNotFound) {
+				return ErrEmployeeNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = checkActive(seller); err != nil {
+			return err
+		}
+
+		item, err := s.itemRepo.FindByName(ctx, itemName)
+		if err != nil {
+			if errors.Is(err, repo.ErrItemNotFound) {
+				return ErrItemNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = removeFromInventory(ctx, s.inventoryRepo, seller.Id, item.Id, quantity); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		listing = &model.Listing{
+			Id:        uuid.New(),
+			SellerId:  seller.Id,
+			Seller:    seller.Username,
+			ItemId:    item.Id,
+			Item:      item.Name,
+			Quantity:  quantity,
+			Price:     price,
+			Status:    model.ListingActive,
+			ExpiresAt: time.Now().Add(s.listingTTL),
+		}
+
+		if err = s.listingRepo.Save(ctx, listing); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return listing, nil
+}
+
+func (s *MarketplaceService) List(ctx context.Context, filter model.ListingFilter) ([]model.Listing, error) {
+	const op = "service.MarketplaceService.List"
+
+	listings, err := s.listingRepo.FindActive(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listings, nil
+}
+
+// Buy moves the coins from buyer to seller and the escrowed units to the buyer in one transaction.
+func (s *MarketplaceService) Buy(ctx context.Context, username string, listingId uuid.UUID) (*model.Listing, error) {
+	const op = "service.MarketplaceService.Buy"
+
+	var listing *model.Listing
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		listing, err = s.findActiveListing(ctx, listingId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if listing.Seller == username {
+			return ErrOwnListing
+		}
+
+		buyer, seller, err := lockEmployees(ctx, s.employeeRepo, username, listing.Seller)
+		if err != nil {
+			if errors.Is(err, ErrSenderNotFound) {
+				return ErrEmployeeNotFound
+			}
+			return err
+		}
+
+		if err = checkActive(buyer); err != nil {
+			return err
+		}
+		// a frozen or deactivated seller can't receive coins, so their listings can't be bought
+		if checkActive(seller) != nil {
+			return ErrListingNotActive
+		}
+
+		if err = s.salePolicy.CheckSale(ctx, buyer, seller, listing.Price); err != nil {
+			return err
+		}
+
+		if err = s.ledger.Sale(ctx, listing.Id, buyer, seller, listing.Price); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = addToInventory(ctx, s.inventoryRepo, buyer.Id, listing.ItemId, listing.Quantity); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = s.listingRepo.Close(ctx, listing.Id, model.ListingSold, &buyer.Id); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		listing.Status = model.ListingSold
+		listing.BuyerId = &buyer.Id
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return listing, nil
+}
+
+func (s *MarketplaceService) Cancel(ctx context.Context, username string, listingId uuid.UUID) (*model.Listing, error) {
+	const op = "service.MarketplaceService.Cancel"
+
+	var listing *model.Listing
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		listing, err = s.findListing(ctx, listingId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if listing.Seller != username {
+			return ErrListingNotFound
+		}
+
+		if listing.Status != model.ListingActive {
+			return ErrListingNotActive
+		}
+
+		if err = s.release(ctx, listing, model.ListingCancelled); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return listing, nil
+}
+
+// ExpireListings releases up to limit expired listings back to their sellers and reports how many it released.
+// Each listing is released in its own transaction, so one seller's row lock is held at a time.
+func (s *MarketplaceService) ExpireListings(ctx context.Context, limit int) (int, error) {
+	const op = "service.MarketplaceService.ExpireListings"
+
+	released := 0
+	for released < limit {
+		found := false
+		err := s.trManager.Do(ctx, func(ctx context.Context) error {
+			listings, err := s.listingRepo.FindExpiredForUpdate(ctx, 1)
+			if err != nil || len(listings) == 0 {
+				return err
+			}
+
+			found = true
+			return s.release(ctx, &listings[0], model.ListingExpired)
+		})
+
+		if err != nil {
+			return released, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !found {
+			break
+		}
+		released++
+	}
+
+	return released, nil
+}
+
+// release returns the escrowed units to the seller; the listing row must already be locked.
+func (s *MarketplaceService) release(ctx context.Context, listing *model.Listing, status model.ListingStatus) error {
+	seller, err := s.employeeRepo.FindByIdForUpdate(ctx, listing.SellerId)
+	if err != nil {
+		return err
+	}
+
+	if err = addToInventory(ctx, s.inventoryRepo, seller.Id, listing.ItemId, listing.Quantity); err != nil {
+		return err
+	}
+
+	if err = s.listingRepo.Close(ctx, listing.Id, status, nil); err != nil {
+		return err
+	}
+
+	listing.Status = status
+	return nil
+}
+
+func (s *MarketplaceService) findActiveListing(ctx context.Context, listingId uuid.UUID) (*model.Listing, error) {
+	listing, err := s.findListing(ctx, listingId)
+	if err != nil {
+		return nil, err
+	}
+
+	if listing.Status != model.ListingActive || !listing.ExpiresAt.After(time.Now()) {
+		return nil, ErrListingNotActive
+	}
+
+	return listing, nil
+}
+
+func (s *MarketplaceService) findListing(ctx context.Context, listingId uuid.UUID) (*model.Listing, error) {
+	listing, err := s.listingRepo.FindByIdForUpdate(ctx, listingId)
+	if err != nil {
+		if errors.Is(err, repo.ErrListingNotFound) {
+			return nil, ErrListingNotFound
+		}
+		return nil, err
+	}
+	return listing, nil
+}
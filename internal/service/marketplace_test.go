@@ -0,0 +1,404 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestMarketplaceService_Create(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	seller := &model.Employee{Id: uuid.New(), Username: "seller"}
+	item := &model.Item{Id: uuid.New(), Name: "cup", Price: 20}
+
+	tests := []struct {
+		name          string
+		quantity      int
+		price         int
+		setup         func(*mockEmployeeRepo, *mockItemRepo, *mockInventoryRepo, *mockListingRepo)
+		expectedError error
+	}{
+		{
+			name:     "successful listing",
+			quantity: 2,
+			price:    50,
+			setup: func(mer *mockEmployeeRepo, mir *mockItemRepo, minv *mockInventoryRepo, mlr *mockListingRepo) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "seller").Return(seller, nil)
+				mir.On("FindByName", mock.Anything, "cup").Return(item, nil)
+				minv.On("FindByEmployeeAndItem", mock.Anything, seller.Id, item.Id).
+					Return(&model.EmployeeInventory{Id: uuid.New(), EmployeeId: seller.Id, Amount: 3}, nil)
+				minv.On("UpdateById", mock.Anything, mock.Anything, mock.MatchedBy(
+					func(i *model.EmployeeInventory) bool { return i.Amount == 1 })).
+					Return(nil)
+				mlr.On("Save", mock.Anything, mock.MatchedBy(func(l *model.Listing) bool {
+					return l.SellerId == seller.Id && l.ItemId == item.Id && l.Quantity == 2 && l.Price == 50 &&
+						l.Status == model.ListingActive
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "non-positive quantity",
+			quantity:      0,
+			price:         50,
+			setup:         func(*mockEmployeeRepo, *mockItemRepo, *mockInventoryRepo, *mockListingRepo) {},
+			expectedError: ErrInvalidQuantity,
+		},
+		{
+			name:          "non-positive price",
+			quantity:      1,
+			price:         0,
+			setup:         func(*mockEmployeeRepo, *mockItemRepo, *mockInventoryRepo, *mockListingRepo) {},
+			expectedError: ErrInvalidListingPrice,
+		},
+		{
+			name:     "item not found",
+			quantity: 1,
+			price:    50,
+			setup: func(mer *mockEmployeeRepo, mir *mockItemRepo, minv *mockInventoryRepo, mlr *mockListingRepo) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "seller").Return(seller, nil)
+				mir.On("FindByName", mock.Anything, "cup").Return(nil, repo.ErrItemNotFound)
+			},
+			expectedError: ErrItemNotFound,
+		},
+		{
+			name:     "frozen seller",
+			quantity: 1,
+			price:    50,
+			setup: func(mer *mockEmployeeRepo, mir *mockItemRepo, minv *mockInventoryRepo, mlr *mockListingRepo) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "seller").
+					Return(&model.Employee{Id: seller.Id, Username: "seller", Status: model.EmployeeFrozen}, nil)
+			},
+			expectedError: ErrAccountFrozen,
+		},
+		{
+			name:     "not enough units",
+			quantity: 5,
+			price:    50,
+			setup: func(mer *mockEmployeeRepo, mir *mockItemRepo, minv *mockInventoryRepo, mlr *mockListingRepo) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "seller").Return(seller, nil)
+				mir.On("FindByName", mock.Anything, "cup").Return(item, nil)
+				minv.On("FindByEmployeeAndItem", mock.Anything, seller.Id, item.Id).
+					Return(&model.EmployeeInventory{Id: uuid.New(), Amount: 3}, nil)
+			},
+			expectedError: ErrNotEnoughItems,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockItemRepo := new(mockItemRepo)
+			mockInventoryRepo := new(mockInventoryRepo)
+			mockListingRepo := new(mockListingRepo)
+			marketplaceService := NewMarketplaceService(mockTrManager, mockEmployeeRepo, mockItemRepo,
+				mockInventoryRepo, mockListingRepo, new(mockLedger), new(mockSalePolicy), time.Hour)
+
+			tc.setup(mockEmployeeRepo, mockItemRepo, mockInventoryRepo, mockListingRepo)
+
+			listing, err := marketplaceService.Create(context.Background(), "seller", "cup", tc.quantity, tc.price)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "seller", listing.Seller)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), listing.ExpiresAt, time.Minute)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockItemRepo.AssertExpectations(t)
+			mockInventoryRepo.AssertExpectations(t)
+			mockListingRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMarketplaceService_Buy(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	buyer := &model.Employee{Id: uuid.New(), Username: "buyer", Balance: 100}
+	seller := &model.Employee{Id: uuid.New(), Username: "seller"}
+	listingId := uuid.New()
+	itemId := uuid.New()
+
+	activeListing := func() *model.Listing {
+		return &model.Listing{
+			Id: listingId, SellerId: seller.Id, Seller: "seller", ItemId: itemId, Item: "cup",
+			Quantity: 2, Price: 60, Status: model.ListingActive, ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+	lockBoth := func(mer *mockEmployeeRepo) {
+		mer.On("FindByUsernameForUpdate", mock.Anything, "buyer").Return(buyer, nil)
+		mer.On("FindByUsernameForUpdate", mock.Anything, "seller").Return(seller, nil)
+	}
+
+	tests := []struct {
+		name          string
+		username      string
+		setup         func(*mockEmployeeRepo, *mockInventoryRepo, *mockListingRepo, *mockLedger)
+		saleErr       error
+		expectedError error
+	}{
+		{
+			name:     "successful purchase",
+			username: "buyer",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo, ml *mockLedger) {
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).Return(activeListing(), nil)
+				lockBoth(mer)
+				ml.On("Sale", mock.Anything, listingId, buyer, seller, 60).Return(nil)
+				minv.On("FindByEmployeeAndItem", mock.Anything, buyer.Id, itemId).
+					Return(nil, repo.ErrEmployeeInventoryNotFound)
+				minv.On("Save", mock.Anything, mock.MatchedBy(
+					func(i *model.EmployeeInventory) bool { return i.EmployeeId == buyer.Id && i.Amount == 2 })).
+					Return(nil)
+				mlr.On("Close", mock.Anything, listingId, model.ListingSold, &buyer.Id).Return(nil)
+			},
+		},
+		{
+			name:     "listing not found",
+			username: "buyer",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo, ml *mockLedger) {
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).Return(nil, repo.ErrListingNotFound)
+			},
+			expectedError: ErrListingNotFound,
+		},
+		{
+			name:     "listing already sold",
+			username: "buyer",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo, ml *mockLedger) {
+				listing := activeListing()
+				listing.Status = model.ListingSold
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).Return(listing, nil)
+			},
+			expectedError: ErrListingNotActive,
+		},
+		{
+			name:     "listing past expiry",
+			username: "buyer",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo, ml *mockLedger) {
+				listing := activeListing()
+				listing.ExpiresAt = time.Now().Add(-time.Minute)
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).Return(listing, nil)
+			},
+			expectedError: ErrListingNotActive,
+		},
+		{
+			name:     "own listing",
+			username: "seller",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo, ml *mockLedger) {
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).Return(activeListing(), nil)
+			},
+			expectedError: ErrOwnListing,
+		},
+		{
+			name:     "not enough coins",
+			username: "buyer",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo, ml *mockLedger) {
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).Return(activeListing(), nil)
+				lockBoth(mer)
+				ml.On("Sale", mock.Anything, listingId, buyer, seller, 60).Return(ErrNotEnoughCoins)
+			},
+			expectedError: ErrNotEnoughCoins,
+		},
+		{
+			name:     "sale over the transfer limit",
+			username: "buyer",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo, ml *mockLedger) {
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).Return(activeListing(), nil)
+				lockBoth(mer)
+			},
+			saleErr:       &TransferLimitError{Limit: LimitDailyAmount, Remaining: 10},
+			expectedError: ErrTransferLimitExceeded,
+		},
+		{
+			name:     "sale above the approval threshold",
+			username: "buyer",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo, ml *mockLedger) {
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).Return(activeListing(), nil)
+				lockBoth(mer)
+			},
+			saleErr:       ErrSaleRequiresApproval,
+			expectedError: ErrSaleRequiresApproval,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockInventoryRepo := new(mockInventoryRepo)
+			mockListingRepo := new(mockListingRepo)
+			mockLedger := new(mockLedger)
+			mockSalePolicy := new(mockSalePolicy)
+			mockSalePolicy.On("CheckSale", mock.Anything, buyer, seller, 60).Return(tc.saleErr).Maybe()
+			marketplaceService := NewMarketplaceService(mockTrManager, mockEmployeeRepo, new(mockItemRepo),
+				mockInventoryRepo, mockListingRepo, mockLedger, mockSalePolicy, time.Hour)
+
+			tc.setup(mockEmployeeRepo, mockInventoryRepo, mockListingRepo, mockLedger)
+
+			listing, err := marketplaceService.Buy(context.Background(), tc.username, listingId)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, model.ListingSold, listing.Status)
+				assert.Equal(t, buyer.Id, *listing.BuyerId)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockInventoryRepo.AssertExpectations(t)
+			mockListingRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMarketplaceService_Cancel(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	seller := &model.Employee{Id: uuid.New(), Username: "seller"}
+	listingId := uuid.New()
+	itemId := uuid.New()
+
+	listingWithStatus := func(status model.ListingStatus) *model.Listing {
+		return &model.Listing{
+			Id: listingId, SellerId: seller.Id, Seller: "seller", ItemId: itemId, Quantity: 2, Status: status,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		username      string
+		setup         func(*mockEmployeeRepo, *mockInventoryRepo, *mockListingRepo)
+		expectedError error
+	}{
+		{
+			name:     "successful cancellation",
+			username: "seller",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo) {
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).
+					Return(listingWithStatus(model.ListingActive), nil)
+				mer.On("FindByIdForUpdate", mock.Anything, seller.Id).Return(seller, nil)
+				minv.On("FindByEmployeeAndItem", mock.Anything, seller.Id, itemId).
+					Return(&model.EmployeeInventory{Id: uuid.New(), Amount: 1}, nil)
+				minv.On("UpdateById", mock.Anything, mock.Anything, mock.MatchedBy(
+					func(i *model.EmployeeInventory) bool { return i.Amount == 3 })).
+					Return(nil)
+				mlr.On("Close", mock.Anything, listingId, model.ListingCancelled, (*uuid.UUID)(nil)).Return(nil)
+			},
+		},
+		{
+			name:     "someone else's listing",
+			username: "stranger",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo) {
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).
+					Return(listingWithStatus(model.ListingActive), nil)
+			},
+			expectedError: ErrListingNotFound,
+		},
+		{
+			name:     "already sold",
+			username: "seller",
+			setup: func(mer *mockEmployeeRepo, minv *mockInventoryRepo, mlr *mockListingRepo) {
+				mlr.On("FindByIdForUpdate", mock.Anything, listingId).Return(listingWithStatus(model.ListingSold), nil)
+			},
+			expectedError: ErrListingNotActive,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockInventoryRepo := new(mockInventoryRepo)
+			mockListingRepo := new(mockListingRepo)
+			marketplaceService := NewMarketplaceService(mockTrManager, mockEmployeeRepo, new(mockItemRepo),
+				mockInventoryRepo, mockListingRepo, new(mockLedger), new(mockSalePolicy), time.Hour)
+
+			tc.setup(mockEmployeeRepo, mockInventoryRepo, mockListingRepo)
+
+			listing, err := marketplaceService.Cancel(context.Background(), tc.username, listingId)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, model.ListingCancelled, listing.Status)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockInventoryRepo.AssertExpectations(t)
+			mockListingRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMarketplaceService_ExpireListings(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	seller := &model.Employee{Id: uuid.New(), Username: "seller"}
+	itemId := uuid.New()
+	expired := model.Listing{
+		Id: uuid.New(), SellerId: seller.Id, ItemId: itemId, Quantity: 1, Status: model.ListingActive,
+	}
+
+	t.Run("releases until nothing is left", func(t *testing.T) {
+		mockEmployeeRepo := new(mockEmployeeRepo)
+		mockInventoryRepo := new(mockInventoryRepo)
+		mockListingRepo := new(mockListingRepo)
+		marketplaceService := NewMarketplaceService(mockTrManager, mockEmployeeRepo, new(mockItemRepo),
+			mockInventoryRepo, mockListingRepo, new(mockLedger), new(mockSalePolicy), time.Hour)
+
+		mockListingRepo.On("FindExpiredForUpdate", mock.Anything, 1).Return([]model.Listing{expired}, nil).Once()
+		mockListingRepo.On("FindExpiredForUpdate", mock.Anything, 1).Return([]model.Listing{}, nil).Once()
+		mockEmployeeRepo.On("FindByIdForUpdate", mock.Anything, seller.Id).Return(seller, nil)
+		mockInventoryRepo.On("FindByEmployeeAndItem", mock.Anything, seller.Id, itemId).
+			Return(nil, repo.ErrEmployeeInventoryNotFound)
+		mockInventoryRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+		mockListingRepo.On("Close", mock.Anything, expired.Id, model.ListingExpired, (*uuid.UUID)(nil)).Return(nil)
+
+		released, err := marketplaceService.ExpireListings(context.Background(), 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, released)
+		mockEmployeeRepo.AssertExpectations(t)
+		mockInventoryRepo.AssertExpectations(t)
+		mockListingRepo.AssertExpectations(t)
+	})
+
+	t.Run("stops at the limit", func(t *testing.T) {
+		mockEmployeeRepo := new(mockEmployeeRepo)
+		mockInventoryRepo := new(mockInventoryRepo)
+		mockListingRepo := new(mockListingRepo)
+		marketplaceService := NewMarketplaceService(mockTrManager, mockEmployeeRepo, new(mockItemRepo),
+			mockInventoryRepo, mockListingRepo, new(mockLedger), new(mockSalePolicy), time.Hour)
+
+		mockListingRepo.On("FindExpiredForUpdate", mock.Anything, 1).Return([]model.Listing{expired}, nil)
+		mockEmployeeRepo.On("FindByIdForUpdate", mock.Anything, seller.Id).Return(seller, nil)
+		mockInventoryRepo.On("FindByEmployeeAndItem", mock.Anything, seller.Id, itemId).
+			Return(&model.EmployeeInventory{Id: uuid.New(), Amount: 1}, nil)
+		mockInventoryRepo.On("UpdateById", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockListingRepo.On("Close", mock.Anything, expired.Id, model.ListingExpired, (*uuid.UUID)(nil)).Return(nil)
+
+		released, err := marketplaceService.ExpireListings(context.Background(), 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, released)
+		mockListingRepo.AssertNumberOfCalls(t, "FindExpiredForUpdate", 2)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockListingRepo := new(mockListingRepo)
+		marketplaceService := NewMarketplaceService(mockTrManager, new(mockEmployeeRepo), new(mockItemRepo),
+			new(mockInventoryRepo), mockListingRepo, new(mockLedger), new(mockSalePolicy), time.Hour)
+
+		mockListingRepo.On("FindExpiredForUpdate", mock.Anything, 1).Return(nil, errors.New("db error"))
+
+		released, err := marketplaceService.ExpireListings(context.Background(), 10)
+
+		assert.ErrorContains(t, err, "db error")
+		assert.Equal(t, 0, released)
+	})
+}
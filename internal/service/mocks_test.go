@@ -3,8 +3,10 @@ package service
 import (
 	"avito-shop/internal/model"
 	"context"
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type mockEmployeeRepo struct {
@@ -24,11 +26,50 @@ func (m *mockEmployeeRepo) FindByUsername(ctx context.Context, username string)
 	return nil, args.Error(1)
 }
 
+func (m *mockEmployeeRepo) FindByUsernameForUpdate(ctx context.Context, username string) (*model.Employee, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Employee), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockEmployeeRepo) FindById(ctx context.Context, employeeId uuid.UUID) (*model.Employee, error) {
+	args := m.Called(ctx, employeeId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Employee), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockEmployeeRepo) FindByIdForUpdate(ctx context.Context, employeeId uuid.UUID) (*model.Employee, error) {
+	args := m.Called(ctx, employeeId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Employee), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *mockEmployeeRepo) UpdateByUsername(ctx context.Context, username string, employee *model.Employee) error {
 	args := m.Called(ctx, username, employee)
 	return args.Error(0)
 }
 
+func (m *mockEmployeeRepo) UpdateManager(ctx context.Context, employeeId uuid.UUID, managerId *uuid.UUID) error {
+	args := m.Called(ctx, employeeId, managerId)
+	return args.Error(0)
+}
+
+func (m *mockEmployeeRepo) UpdateStatus(ctx context.Context, employeeId uuid.UUID, status model.EmployeeStatus) error {
+	args := m.Called(ctx, employeeId, status)
+	return args.Error(0)
+}
+
+func (m *mockEmployeeRepo) UpdateRole(ctx context.Context, employeeId uuid.UUID, role model.Role) error {
+	args := m.Called(ctx, employeeId, role)
+	return args.Error(0)
+}
+
 type mockTransferRepo struct {
 	mock.Mock
 }
@@ -56,10 +97,37 @@ func (m *mockTransferRepo) FindAllForSenderGroupedByReceivers(
 	return nil, args.Error(1)
 }
 
+func (m *mockTransferRepo) FindKudos(ctx context.Context, filter model.KudosFilter) ([]model.Kudos, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Kudos), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTransferRepo) FindVelocity(
+	ctx context.Context, query model.VelocityQuery) (*model.TransferVelocity, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.TransferVelocity), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 type mockItemRepo struct {
 	mock.Mock
 }
 
+func (m *mockItemRepo) Save(ctx context.Context, item *model.Item) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *mockItemRepo) UpdateById(ctx context.Context, itemId uuid.UUID, item *model.Item) error {
+	args := m.Called(ctx, itemId, item)
+	return args.Error(0)
+}
+
 func (m *mockItemRepo) FindByName(ctx context.Context, itemName string) (*model.Item, error) {
 	args := m.Called(ctx, itemName)
 	if args.Get(0) != nil {
@@ -68,6 +136,24 @@ func (m *mockItemRepo) FindByName(ctx context.Context, itemName string) (*model.
 	return nil, args.Error(1)
 }
 
+func (m *mockItemRepo) FindByNameForUpdate(ctx context.Context, itemName string) (*model.Item, error) {
+	args := m.Called(ctx, itemName)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Item), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockItemRepo) DecrementStock(ctx context.Context, itemId uuid.UUID, quantity int) (bool, error) {
+	args := m.Called(ctx, itemId, quantity)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockItemRepo) IncrementStock(ctx context.Context, itemId uuid.UUID, quantity int) error {
+	args := m.Called(ctx, itemId, quantity)
+	return args.Error(0)
+}
+
 func (m *mockItemRepo) FindById(ctx context.Context, itemId uuid.UUID) (*model.Item, error) {
 	args := m.Called(ctx, itemId)
 	if args.Get(0) != nil {
@@ -76,6 +162,14 @@ func (m *mockItemRepo) FindById(ctx context.Context, itemId uuid.UUID) (*model.I
 	return nil, args.Error(1)
 }
 
+func (m *mockItemRepo) FindAll(ctx context.Context, filter model.ItemFilter) ([]model.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Item), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 type mockInventoryRepo struct {
 	mock.Mock
 }
@@ -109,9 +203,558 @@ func (m *mockInventoryRepo) UpdateById(
 	return args.Error(0)
 }
 
+type mockPurchaseRepo struct {
+	mock.Mock
+}
+
+func (m *mockPurchaseRepo) Save(ctx context.Context, purchase *model.Purchase) error {
+	args := m.Called(ctx, purchase)
+	return args.Error(0)
+}
+
+func (m *mockPurchaseRepo) CountByEmployeeAndItem(
+	ctx context.Context, employeeId uuid.UUID, itemId uuid.UUID) (int, error) {
+	args := m.Called(ctx, employeeId, itemId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockPurchaseRepo) FindByIdForUpdate(ctx context.Context, purchaseId uuid.UUID) (*model.Purchase, error) {
+	args := m.Called(ctx, purchaseId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Purchase), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPurchaseRepo) UpdateReturnedQuantity(ctx context.Context, purchaseId uuid.UUID, returned int) error {
+	args := m.Called(ctx, purchaseId, returned)
+	return args.Error(0)
+}
+
+type mockPurchaseReturnRepo struct {
+	mock.Mock
+}
+
+func (m *mockPurchaseReturnRepo) Save(ctx context.Context, purchaseReturn *model.PurchaseReturn) error {
+	args := m.Called(ctx, purchaseReturn)
+	return args.Error(0)
+}
+
+type mockOrderRepo struct {
+	mock.Mock
+}
+
+func (m *mockOrderRepo) Save(ctx context.Context, order *model.Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *mockOrderRepo) FindByIdForUpdate(ctx context.Context, orderId uuid.UUID) (*model.Order, error) {
+	args := m.Called(ctx, orderId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockOrderRepo) FindAll(ctx context.Context, filter model.OrderFilter) ([]model.Order, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockOrderRepo) UpdateStatus(ctx context.Context, orderId uuid.UUID, status model.OrderStatus) error {
+	args := m.Called(ctx, orderId, status)
+	return args.Error(0)
+}
+
+type mockGiftRepo struct {
+	mock.Mock
+}
+
+func (m *mockGiftRepo) Save(ctx context.Context, gift *model.Gift) error {
+	args := m.Called(ctx, gift)
+	return args.Error(0)
+}
+
+type mockListingRepo struct {
+	mock.Mock
+}
+
+func (m *mockListingRepo) Save(ctx context.Context, listing *model.Listing) error {
+	args := m.Called(ctx, listing)
+	return args.Error(0)
+}
+
+func (m *mockListingRepo) FindByIdForUpdate(ctx context.Context, listingId uuid.UUID) (*model.Listing, error) {
+	args := m.Called(ctx, listingId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Listing), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockListingRepo) FindActive(ctx context.Context, filter model.ListingFilter) ([]model.Listing, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Listing), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockListingRepo) FindExpiredForUpdate(ctx context.Context, limit int) ([]model.Listing, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Listing), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockListingRepo) Close(
+	ctx context.Context, listingId uuid.UUID, status model.ListingStatus, buyerId *uuid.UUID) error {
+	args := m.Called(ctx, listingId, status, buyerId)
+	return args.Error(0)
+}
+
+type mockScheduleRepo struct {
+	mock.Mock
+}
+
+func (m *mockScheduleRepo) Save(ctx context.Context, schedule *model.ScheduledTransfer) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *mockScheduleRepo) FindById(ctx context.Context, scheduleId uuid.UUID) (*model.ScheduledTransfer, error) {
+	args := m.Called(ctx, scheduleId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.ScheduledTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduleRepo) FindByIdForUpdate(
+	ctx context.Context, scheduleId uuid.UUID) (*model.ScheduledTransfer, error) {
+	args := m.Called(ctx, scheduleId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.ScheduledTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduleRepo) FindByEmployee(
+	ctx context.Context, employeeId uuid.UUID) ([]model.ScheduledTransfer, error) {
+	args := m.Called(ctx, employeeId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.ScheduledTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduleRepo) FindDueForUpdate(ctx context.Context, limit int) ([]model.ScheduledTransfer, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.ScheduledTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduleRepo) Update(ctx context.Context, schedule *model.ScheduledTransfer) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *mockScheduleRepo) SaveRun(ctx context.Context, run *model.ScheduledTransferRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *mockScheduleRepo) FindRuns(ctx context.Context, scheduleId uuid.UUID) ([]model.ScheduledTransferRun, error) {
+	args := m.Called(ctx, scheduleId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.ScheduledTransferRun), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type mockPaymentRequestRepo struct {
+	mock.Mock
+}
+
+func (m *mockPaymentRequestRepo) Save(ctx context.Context, request *model.PaymentRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *mockPaymentRequestRepo) FindByIdForUpdate(
+	ctx context.Context, requestId uuid.UUID) (*model.PaymentRequest, error) {
+	args := m.Called(ctx, requestId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.PaymentRequest), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPaymentRequestRepo) FindByEmployee(
+	ctx context.Context, employeeId uuid.UUID, filter model.PaymentRequestFilter) ([]model.PaymentRequest, error) {
+	args := m.Called(ctx, employeeId, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.PaymentRequest), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPaymentRequestRepo) Resolve(
+	ctx context.Context, requestId uuid.UUID, status model.PaymentRequestStatus) error {
+	args := m.Called(ctx, requestId, status)
+	return args.Error(0)
+}
+
+type mockCoinSender struct {
+	mock.Mock
+}
+
+func (m *mockCoinSender) SendCoins(
+	ctx context.Context, fromUsername string, toUsername string, amount int, memo model.TransferMemo,
+) (*model.PendingTransfer, error) {
+	args := m.Called(ctx, fromUsername, toUsername, amount, memo)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type mockPendingTransferRepo struct {
+	mock.Mock
+}
+
+func (m *mockPendingTransferRepo) Save(ctx context.Context, transfer *model.PendingTransfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *mockPendingTransferRepo) FindByIdForUpdate(
+	ctx context.Context, transferId uuid.UUID) (*model.PendingTransfer, error) {
+	args := m.Called(ctx, transferId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPendingTransferRepo) FindPending(
+	ctx context.Context, managerId *uuid.UUID) ([]model.PendingTransfer, error) {
+	args := m.Called(ctx, managerId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPendingTransferRepo) Resolve(
+	ctx context.Context, transferId uuid.UUID, status model.PendingTransferStatus, approverId uuid.UUID) error {
+	args := m.Called(ctx, transferId, status, approverId)
+	return args.Error(0)
+}
+
+type mockAdjustmentRepo struct {
+	mock.Mock
+}
+
+func (m *mockAdjustmentRepo) Save(ctx context.Context, adjustment *model.BalanceAdjustment) error {
+	args := m.Called(ctx, adjustment)
+	return args.Error(0)
+}
+
+type mockInviteRepo struct {
+	mock.Mock
+}
+
+func (m *mockInviteRepo) Save(ctx context.Context, invite *model.Invite) error {
+	args := m.Called(ctx, invite)
+	return args.Error(0)
+}
+
+func (m *mockInviteRepo) FindByCodeForUpdate(ctx context.Context, code string) (*model.Invite, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Invite), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockInviteRepo) MarkUsed(ctx context.Context, inviteId uuid.UUID, employeeId uuid.UUID) error {
+	args := m.Called(ctx, inviteId, employeeId)
+	return args.Error(0)
+}
+
+type mockSessionRepo struct {
+	mock.Mock
+}
+
+func (m *mockSessionRepo) Save(ctx context.Context, session *model.Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *mockSessionRepo) FindByIdForUpdate(ctx context.Context, sessionId uuid.UUID) (*model.Session, error) {
+	args := m.Called(ctx, sessionId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Session), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockSessionRepo) Revoke(ctx context.Context, sessionId uuid.UUID) error {
+	args := m.Called(ctx, sessionId)
+	return args.Error(0)
+}
+
+func (m *mockSessionRepo) IsActive(ctx context.Context, sessionId uuid.UUID) (bool, error) {
+	args := m.Called(ctx, sessionId)
+	return args.Bool(0), args.Error(1)
+}
+
+type mockRefreshTokenRepo struct {
+	mock.Mock
+}
+
+func (m *mockRefreshTokenRepo) Save(ctx context.Context, token *model.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *mockRefreshTokenRepo) FindByHashForUpdate(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.RefreshToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockRefreshTokenRepo) MarkUsed(ctx context.Context, tokenId uuid.UUID) error {
+	args := m.Called(ctx, tokenId)
+	return args.Error(0)
+}
+
+type mockAllowanceRepo struct {
+	mock.Mock
+}
+
+func (m *mockAllowanceRepo) Save(ctx context.Context, allowance *model.Allowance) (bool, error) {
+	args := m.Called(ctx, allowance)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockAllowanceRepo) FindEmployeesDueForUpdate(
+	ctx context.Context, period string, limit int) ([]model.Employee, error) {
+	args := m.Called(ctx, period, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Employee), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type mockHistoryRepo struct {
+	mock.Mock
+}
+
+func (m *mockHistoryRepo) FindByEmployee(
+	ctx context.Context, employeeId uuid.UUID, filter model.HistoryFilter) ([]model.HistoryEntry, error) {
+	args := m.Called(ctx, employeeId, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.HistoryEntry), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type mockIdempotencyRepo struct {
+	mock.Mock
+}
+
+func (m *mockIdempotencyRepo) Reserve(ctx context.Context, record *model.IdempotencyRecord) (bool, error) {
+	args := m.Called(ctx, record)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockIdempotencyRepo) FindByEmployeeAndKey(
+	ctx context.Context, employeeId uuid.UUID, key string) (*model.IdempotencyRecord, error) {
+	args := m.Called(ctx, employeeId, key)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.IdempotencyRecord), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockIdempotencyRepo) SaveResponse(ctx context.Context, record *model.IdempotencyRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+type mockLedgerRepo struct {
+	mock.Mock
+}
+
+func (m *mockLedgerRepo) SaveAll(ctx context.Context, entries []model.LedgerEntry) error {
+	args := m.Called(ctx, entries)
+	return args.Error(0)
+}
+
+func (m *mockLedgerRepo) BalanceByEmployee(ctx context.Context, employeeId uuid.UUID) (int, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockLedgerRepo) FindMismatches(ctx context.Context) ([]model.LedgerMismatch, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.LedgerMismatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type mockCoinLotRepo struct {
+	mock.Mock
+}
+
+func (m *mockCoinLotRepo) SaveAll(ctx context.Context, lots []model.CoinLot) error {
+	args := m.Called(ctx, lots)
+	return args.Error(0)
+}
+
+func (m *mockCoinLotRepo) FindActiveByEmployee(ctx context.Context, employeeId uuid.UUID) ([]model.CoinLot, error) {
+	args := m.Called(ctx, employeeId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.CoinLot), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockCoinLotRepo) FindActiveGrantedBefore(
+	ctx context.Context, employeeId uuid.UUID, before time.Time) ([]model.CoinLot, error) {
+	args := m.Called(ctx, employeeId, before)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.CoinLot), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockCoinLotRepo) UpdateRemaining(ctx context.Context, lotId uuid.UUID, remaining int) error {
+	args := m.Called(ctx, lotId, remaining)
+	return args.Error(0)
+}
+
+func (m *mockCoinLotRepo) SaveSpent(ctx context.Context, spent []model.SpentCoins) error {
+	args := m.Called(ctx, spent)
+	return args.Error(0)
+}
+
+func (m *mockCoinLotRepo) FindSpentByOperationForUpdate(
+	ctx context.Context, operationId uuid.UUID) ([]model.SpentCoins, error) {
+	args := m.Called(ctx, operationId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.SpentCoins), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockCoinLotRepo) UpdateSpentRemaining(ctx context.Context, spentId uuid.UUID, remaining int) error {
+	args := m.Called(ctx, spentId, remaining)
+	return args.Error(0)
+}
+
+func (m *mockCoinLotRepo) FindEmployeesWithExpired(
+	ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]uuid.UUID), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type mockLedger struct {
+	mock.Mock
+}
+
+func (m *mockLedger) Transfer(
+	ctx context.Context, operationId uuid.UUID, from *model.Employee, to *model.Employee, amount int) error {
+	args := m.Called(ctx, operationId, from, to, amount)
+	return args.Error(0)
+}
+
+func (m *mockLedger) Sale(
+	ctx context.Context, operationId uuid.UUID, buyer *model.Employee, seller *model.Employee, amount int) error {
+	args := m.Called(ctx, operationId, buyer, seller, amount)
+	return args.Error(0)
+}
+
+func (m *mockLedger) Purchase(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	args := m.Called(ctx, operationId, employee, amount)
+	return args.Error(0)
+}
+
+func (m *mockLedger) Refund(
+	ctx context.Context, operationId uuid.UUID, orderId uuid.UUID, employee *model.Employee, amount int) error {
+	args := m.Called(ctx, operationId, orderId, employee, amount)
+	return args.Error(0)
+}
+
+func (m *mockLedger) Return(
+	ctx context.Context, operationId uuid.UUID, orderId uuid.UUID, employee *model.Employee, amount int) error {
+	args := m.Called(ctx, operationId, orderId, employee, amount)
+	return args.Error(0)
+}
+
+func (m *mockLedger) Grant(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	args := m.Called(ctx, operationId, employee, amount)
+	return args.Error(0)
+}
+
+func (m *mockLedger) Allowance(
+	ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	args := m.Called(ctx, operationId, employee, amount)
+	return args.Error(0)
+}
+
+func (m *mockLedger) Expire(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	args := m.Called(ctx, operationId, employee, amount)
+	return args.Error(0)
+}
+
+func (m *mockLedger) Sweep(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	args := m.Called(ctx, operationId, employee, amount)
+	return args.Error(0)
+}
+
+func (m *mockLedger) Adjust(ctx context.Context, operationId uuid.UUID, employee *model.Employee, amount int) error {
+	args := m.Called(ctx, operationId, employee, amount)
+	return args.Error(0)
+}
+
 type mockTransactionManager struct {
+	savepoints int
 }
 
 func (m *mockTransactionManager) Do(ctx context.Context, fn func(context.Context) error) error {
 	return fn(ctx)
 }
+
+func (m *mockTransactionManager) DoWithSettings(
+	ctx context.Context, s trm.Settings, fn func(context.Context) error) error {
+	if s.Propagation() == trm.PropagationNested {
+		m.savepoints++
+	}
+	return fn(ctx)
+}
+
+type mockSalePolicy struct {
+	mock.Mock
+}
+
+func (m *mockSalePolicy) CheckSale(
+	ctx context.Context, buyer *model.Employee, seller *model.Employee, amount int) error {
+	args := m.Called(ctx, buyer, seller, amount)
+	return args.Error(0)
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type OrderService struct {
+	trManager          TransactionManager
+	orderRepo          OrderRepo
+	purchaseRepo       PurchaseRepo
+	purchaseReturnRepo PurchaseReturnRepo
+	employeeRepo       EmployeeRepo
+	itemRepo           ItemRepo
+	inventoryRepo      InventoryRepo
+	ledger             Ledger
+	returnWindow       time.Duration
+}
+
+// NewOrderService creates the service; a zero returnWindow accepts returns at any time after delivery.
+func NewOrderService(
+	trManager TransactionManager,
+	orderRepo OrderRepo,
+	purchaseRepo PurchaseRepo,
+	purchaseReturnRepo PurchaseReturnRepo,
+	employeeRepo EmployeeRepo,
+	itemRepo ItemRepo,
+	inventoryRepo InventoryRepo,
+	ledger Ledger,
+	returnWindow time.Duration,
+) *OrderService {
+	return &OrderService{
+		trManager:          trManager,
+		orderRepo:          orderRepo,
+		purchaseRepo:       purchaseRepo,
+		purchaseReturnRepo: purchaseReturnRepo,
+		employeeRepo:       employeeRepo,
+		itemRepo:           itemRepo,
+		inventoryRepo:      inventoryRepo,
+		ledger:             ledger,
+		returnWindow:       returnWindow,
+	}
+}
+
+func (s *OrderService) ListForEmployee(
+	ctx context.Context, username string, status model.OrderStatus) ([]model.Order, error) {
+	const op = "service.OrderService.ListForEmployee"
+
+	employee, err := s.employeeRepo.FindByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.list(ctx, op, model.OrderFilter{EmployeeId: &employee.Id, Status: status})
+}
+
+func (s *OrderService) List(ctx context.Context, status model.OrderStatus) ([]model.Order, error) {
+	const op = "service.OrderService.List"
+	return s.list(ctx, op, model.OrderFilter{Status: status})
+}
+
+func (s *OrderService) list(ctx context.Context, op string, filter model.OrderFilter) ([]model.Order, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, ErrInvalidOrderStatus
+	}
+
+	orders, err := s.orderRepo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orders, nil
+}
+
+// Cancel lets an employee withdraw their own order before it is prepared for pickup.
+func (s *OrderService) Cancel(ctx context.Context, username string, orderId uuid.UUID) (*model.Order, error) {
+	const op = "service.OrderService.Cancel"
+
+	var order *model.Order
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.findOrder(ctx, orderId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if order.Employee != username {
+			return ErrOrderNotFound
+		}
+
+		if order.Status != model.OrderPlaced {
+			return ErrOrderTransition
+		}
+
+		if err = s.cancel(ctx, order); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *OrderService) Advance(ctx context.Context, orderId uuid.UUID, status model.OrderStatus) (*model.Order, error) {
+	const op = "service.OrderService.Advance"
+
+	if !status.Valid() {
+		return nil, ErrInvalidOrderStatus
+	}
+
+	var order *model.Order
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.findOrder(ctx, orderId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !order.Status.CanTransitionTo(status) {
+			return ErrOrderTransition
+		}
+
+		if status == model.OrderCancelled {
+			err = s.cancel(ctx, order)
+		} else {
+			err = s.orderRepo.UpdateStatus(ctx, order.Id, status)
+			order.Status = status
+			if status == model.OrderDelivered {
+				deliveredAt := time.Now()
+				order.DeliveredAt = &deliveredAt
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// Return takes part of a delivered purchase back and credits the price paid at purchase time.
+// Orders that have not been handed out yet are cancelled instead.
+func (s *OrderService) Return(
+	ctx context.Context, username string, purchaseId uuid.UUID, quantity int) (*model.PurchaseReturn, error) {
+	const op = "service.OrderService.Return"
+
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	var purchaseReturn *model.PurchaseReturn
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		purchase, err := s.purchaseRepo.FindByIdForUpdate(ctx, purchaseId)
+		if err != nil {
+			if errors.Is(err, repo.ErrPurchaseNotFound) {
+				return ErrPurchaseNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		order, err := s.findOrder(ctx, purchase.OrderId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if order.Employee != username {
+			return ErrPurchaseNotFound
+		}
+
+		if order.Status != model.OrderDelivered {
+			return ErrOrderNotDelivered
+		}
+
+		item, err := s.itemRepo.FindById(ctx, purchase.ItemId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if item.NonReturnable {
+			return ErrItemNotReturnable
+		}
+
+		if s.returnWindow > 0 && order.DeliveredAt != nil && time.Since(*order.DeliveredAt) > s.returnWindow {
+			return ErrReturnWindowExpired
+		}
+
+		if purchase.Quantity-purchase.ReturnedQuantity < quantity {
+			return ErrReturnQuantityExceeded
+		}
+
+		employee, err := s.employeeRepo.FindByIdForUpdate(ctx, order.EmployeeId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = s.removeFromInventory(ctx, employee.Id, item.Id, quantity); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = s.itemRepo.IncrementStock(ctx, item.Id, quantity); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		purchaseReturn = &model.PurchaseReturn{
+			Id:         uuid.New(),
+			PurchaseId: purchase.Id,
+			EmployeeId: employee.Id,
+			Item:       item.Name,
+			Quantity:   quantity,
+			Amount:     quantity * purchase.Price,
+		}
+
+		if err = s.ledger.Return(ctx, purchaseReturn.Id, order.Id, employee, purchaseReturn.Amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = s.purchaseRepo.UpdateReturnedQuantity(ctx, purchase.Id, purchase.ReturnedQuantity+quantity)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = s.purchaseReturnRepo.Save(ctx, purchaseReturn); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return purchaseReturn, nil
+}
+
+// cancel reverses a checkout: the order row is already locked, so the employee row is locked after it,
+// which keeps the lock order the same for employee and admin cancellations.
+func (s *OrderService) cancel(ctx context.Context, order *model.Order) error {
+	employee, err := s.employeeRepo.FindByIdForUpdate(ctx, order.EmployeeId)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range order.Items {
+		if err = s.removeFromInventory(ctx, employee.Id, item.ItemId, item.Quantity); err != nil {
+			return err
+		}
+
+		if err = s.itemRepo.IncrementStock(ctx, item.ItemId, item.Quantity); err != nil {
+			return err
+		}
+	}
+
+	if err = s.ledger.Refund(ctx, uuid.New(), order.Id, employee, order.Total); err != nil {
+		return err
+	}
+
+	if err = s.orderRepo.UpdateStatus(ctx, order.Id, model.OrderCancelled); err != nil {
+		return err
+	}
+
+	order.Status = model.OrderCancelled
+	return nil
+}
+
+// removeFromInventory reports missing units as ErrOrderItemsNotAvailable: the buyer has already given them away.
+func (s *OrderService) removeFromInventory(
+	ctx context.Context, employeeId uuid.UUID, itemId uuid.UUID, quantity int) error {
+	err := removeFromInventory(ctx, s.inventoryRepo, employeeId, itemId, quantity)
+	if errors.Is(err, ErrNotEnoughItems) {
+		return ErrOrderItemsNotAvailable
+	}
+	return err
+}
+
+func (s *OrderService) findOrder(ctx context.Context, orderId uuid.UUID) (*model.Order, error) {
+	order, err := s.orderRepo.FindByIdForUpdate(ctx, orderId)
+	if err != nil {
+		if errors.Is(err, repo.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return order, nil
+}
@@ -0,0 +1,449 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type orderMocks struct {
+	orders    *mockOrderRepo
+	purchases *mockPurchaseRepo
+	returns   *mockPurchaseReturnRepo
+	employees *mockEmployeeRepo
+	items     *mockItemRepo
+	inventory *mockInventoryRepo
+	ledger    *mockLedger
+}
+
+func newOrderMocks() orderMocks {
+	return orderMocks{
+		orders:    new(mockOrderRepo),
+		purchases: new(mockPurchaseRepo),
+		returns:   new(mockPurchaseReturnRepo),
+		employees: new(mockEmployeeRepo),
+		items:     new(mockItemRepo),
+		inventory: new(mockInventoryRepo),
+		ledger:    new(mockLedger),
+	}
+}
+
+func (m orderMocks) service(returnWindow time.Duration) *OrderService {
+	return NewOrderService(new(mockTransactionManager),
+		m.orders, m.purchases, m.returns, m.employees, m.items, m.inventory, m.ledger, returnWindow)
+}
+
+func (m orderMocks) assertExpectations(t *testing.T) {
+	m.orders.AssertExpectations(t)
+	m.purchases.AssertExpectations(t)
+	m.returns.AssertExpectations(t)
+	m.employees.AssertExpectations(t)
+	m.items.AssertExpectations(t)
+	m.inventory.AssertExpectations(t)
+	m.ledger.AssertExpectations(t)
+}
+
+func TestOrderService_Cancel(t *testing.T) {
+	employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 100}
+	itemId := uuid.New()
+	orderId := uuid.New()
+
+	newOrder := func(status model.OrderStatus) *model.Order {
+		return &model.Order{
+			Id:         orderId,
+			EmployeeId: employee.Id,
+			Employee:   employee.Username,
+			Status:     status,
+			Items:      []model.OrderItem{{ItemId: itemId, Item: "cup", Quantity: 2, Price: 20}},
+			Total:      40,
+		}
+	}
+	inventory := func(amount int) *model.EmployeeInventory {
+		return &model.EmployeeInventory{Id: uuid.New(), EmployeeId: employee.Id, ItemId: itemId, Amount: amount}
+	}
+
+	tests := []struct {
+		name          string
+		username      string
+		setup         func(orderMocks)
+		expectedError error
+	}{
+		{
+			name:     "cancel placed order",
+			username: "test_user",
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderPlaced), nil)
+				m.employees.On("FindByIdForUpdate", mock.Anything, employee.Id).Return(employee, nil)
+				m.inventory.On("FindByEmployeeAndItem", mock.Anything, employee.Id, itemId).Return(inventory(3), nil)
+				m.inventory.On("UpdateById", mock.Anything, mock.Anything, mock.MatchedBy(
+					func(i *model.EmployeeInventory) bool { return i.Amount == 1 })).Return(nil)
+				m.items.On("IncrementStock", mock.Anything, itemId, 2).Return(nil)
+				m.ledger.On("Refund", mock.Anything, mock.Anything, orderId, employee, 40).Return(nil)
+				m.orders.On("UpdateStatus", mock.Anything, orderId, model.OrderCancelled).Return(nil)
+			},
+		},
+		{
+			name:     "order of another employee",
+			username: "other_user",
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderPlaced), nil)
+			},
+			expectedError: ErrOrderNotFound,
+		},
+		{
+			name:     "order not found",
+			username: "test_user",
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(nil, repo.ErrOrderNotFound)
+			},
+			expectedError: ErrOrderNotFound,
+		},
+		{
+			name:     "order already ready for pickup",
+			username: "test_user",
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).
+					Return(newOrder(model.OrderReadyForPickup), nil)
+			},
+			expectedError: ErrOrderTransition,
+		},
+		{
+			name:     "items already given away",
+			username: "test_user",
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderPlaced), nil)
+				m.employees.On("FindByIdForUpdate", mock.Anything, employee.Id).Return(employee, nil)
+				m.inventory.On("FindByEmployeeAndItem", mock.Anything, employee.Id, itemId).Return(inventory(1), nil)
+			},
+			expectedError: ErrOrderItemsNotAvailable,
+		},
+		{
+			name:     "refund error",
+			username: "test_user",
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderPlaced), nil)
+				m.employees.On("FindByIdForUpdate", mock.Anything, employee.Id).Return(employee, nil)
+				m.inventory.On("FindByEmployeeAndItem", mock.Anything, employee.Id, itemId).Return(inventory(2), nil)
+				m.inventory.On("UpdateById", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				m.items.On("IncrementStock", mock.Anything, itemId, 2).Return(nil)
+				m.ledger.On("Refund", mock.Anything, mock.Anything, orderId, employee, 40).
+					Return(errors.New("ledger error"))
+			},
+			expectedError: errors.New("ledger error"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newOrderMocks()
+			tc.setup(m)
+
+			order, err := m.service(0).Cancel(context.Background(), tc.username, orderId)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, model.OrderCancelled, order.Status)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_Advance(t *testing.T) {
+	employee := &model.Employee{Id: uuid.New(), Username: "test_user"}
+	orderId := uuid.New()
+
+	newOrder := func(status model.OrderStatus) *model.Order {
+		return &model.Order{Id: orderId, EmployeeId: employee.Id, Status: status, Total: 0}
+	}
+
+	tests := []struct {
+		name          string
+		status        model.OrderStatus
+		setup         func(orderMocks)
+		expectedError error
+	}{
+		{
+			name:   "placed to ready for pickup",
+			status: model.OrderReadyForPickup,
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderPlaced), nil)
+				m.orders.On("UpdateStatus", mock.Anything, orderId, model.OrderReadyForPickup).Return(nil)
+			},
+		},
+		{
+			name:   "ready for pickup to delivered",
+			status: model.OrderDelivered,
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).
+					Return(newOrder(model.OrderReadyForPickup), nil)
+				m.orders.On("UpdateStatus", mock.Anything, orderId, model.OrderDelivered).Return(nil)
+			},
+		},
+		{
+			name:   "admin cancels ready for pickup order",
+			status: model.OrderCancelled,
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).
+					Return(newOrder(model.OrderReadyForPickup), nil)
+				m.employees.On("FindByIdForUpdate", mock.Anything, employee.Id).Return(employee, nil)
+				m.ledger.On("Refund", mock.Anything, mock.Anything, orderId, employee, 0).Return(nil)
+				m.orders.On("UpdateStatus", mock.Anything, orderId, model.OrderCancelled).Return(nil)
+			},
+		},
+		{
+			name:   "delivered order is final",
+			status: model.OrderCancelled,
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderDelivered), nil)
+			},
+			expectedError: ErrOrderTransition,
+		},
+		{
+			name:   "skipping pickup is not allowed",
+			status: model.OrderDelivered,
+			setup: func(m orderMocks) {
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderPlaced), nil)
+			},
+			expectedError: ErrOrderTransition,
+		},
+		{
+			name:          "unknown status",
+			status:        model.OrderStatus("lost"),
+			setup:         func(m orderMocks) {},
+			expectedError: ErrInvalidOrderStatus,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newOrderMocks()
+			tc.setup(m)
+
+			order, err := m.service(0).Advance(context.Background(), orderId, tc.status)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.status, order.Status)
+				assert.Equal(t, tc.status == model.OrderDelivered, order.DeliveredAt != nil)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_ListForEmployee(t *testing.T) {
+	employee := &model.Employee{Id: uuid.New(), Username: "test_user"}
+
+	t.Run("filters by employee and status", func(t *testing.T) {
+		m := newOrderMocks()
+		m.employees.On("FindByUsername", mock.Anything, "test_user").Return(employee, nil)
+		m.orders.On("FindAll", mock.Anything, model.OrderFilter{EmployeeId: &employee.Id, Status: model.OrderPlaced}).
+			Return([]model.Order{{Id: uuid.New()}}, nil)
+
+		orders, err := m.service(0).ListForEmployee(context.Background(), "test_user", model.OrderPlaced)
+
+		assert.NoError(t, err)
+		assert.Len(t, orders, 1)
+		m.assertExpectations(t)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		m := newOrderMocks()
+		m.employees.On("FindByUsername", mock.Anything, "test_user").Return(employee, nil)
+
+		_, err := m.service(0).ListForEmployee(context.Background(), "test_user", model.OrderStatus("lost"))
+
+		assert.ErrorIs(t, err, ErrInvalidOrderStatus)
+		m.assertExpectations(t)
+	})
+}
+
+func TestOrderService_Return(t *testing.T) {
+	employee := &model.Employee{Id: uuid.New(), Username: "test_user", Balance: 100}
+	orderId := uuid.New()
+	purchaseId := uuid.New()
+	item := &model.Item{Id: uuid.New(), Name: "cup", Price: 25}
+
+	newPurchase := func(returned int, createdAt time.Time) *model.Purchase {
+		return &model.Purchase{
+			Id:               purchaseId,
+			OrderId:          orderId,
+			EmployeeId:       employee.Id,
+			ItemId:           item.Id,
+			Quantity:         3,
+			ReturnedQuantity: returned,
+			Price:            20,
+			CreatedAt:        createdAt,
+		}
+	}
+	newOrder := func(status model.OrderStatus) *model.Order {
+		return &model.Order{Id: orderId, EmployeeId: employee.Id, Employee: employee.Username, Status: status}
+	}
+	deliveredOrder := func(deliveredAt time.Time) *model.Order {
+		order := newOrder(model.OrderDelivered)
+		order.DeliveredAt = &deliveredAt
+		return order
+	}
+	expectReturn := func(m orderMocks) {
+		m.items.On("FindById", mock.Anything, item.Id).Return(item, nil)
+		m.employees.On("FindByIdForUpdate", mock.Anything, employee.Id).Return(employee, nil)
+		m.inventory.On("FindByEmployeeAndItem", mock.Anything, employee.Id, item.Id).
+			Return(&model.EmployeeInventory{Id: uuid.New(), Amount: 2}, nil)
+		m.inventory.On("UpdateById", mock.Anything, mock.Anything, mock.MatchedBy(
+			func(i *model.EmployeeInventory) bool { return i.Amount == 0 })).Return(nil)
+		m.items.On("IncrementStock", mock.Anything, item.Id, 2).Return(nil)
+		m.ledger.On("Return", mock.Anything, mock.Anything, orderId, employee, 40).Return(nil)
+		m.purchases.On("UpdateReturnedQuantity", mock.Anything, purchaseId, 3).Return(nil)
+		m.returns.On("Save", mock.Anything, mock.MatchedBy(func(r *model.PurchaseReturn) bool {
+			return r.PurchaseId == purchaseId && r.Quantity == 2 && r.Amount == 40 && r.Item == "cup"
+		})).Return(nil)
+	}
+
+	tests := []struct {
+		name          string
+		username      string
+		quantity      int
+		returnWindow  time.Duration
+		setup         func(orderMocks)
+		expectedError error
+	}{
+		{
+			name:         "return at purchase price",
+			username:     "test_user",
+			quantity:     2,
+			returnWindow: 24 * time.Hour,
+			setup: func(m orderMocks) {
+				m.purchases.On("FindByIdForUpdate", mock.Anything, purchaseId).
+					Return(newPurchase(1, time.Now().Add(-2*time.Hour)), nil)
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).
+					Return(deliveredOrder(time.Now().Add(-time.Hour)), nil)
+				expectReturn(m)
+			},
+		},
+		{
+			name:         "return window starts at delivery",
+			username:     "test_user",
+			quantity:     2,
+			returnWindow: 24 * time.Hour,
+			setup: func(m orderMocks) {
+				m.purchases.On("FindByIdForUpdate", mock.Anything, purchaseId).
+					Return(newPurchase(1, time.Now().Add(-72*time.Hour)), nil)
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).
+					Return(deliveredOrder(time.Now().Add(-time.Hour)), nil)
+				expectReturn(m)
+			},
+		},
+		{
+			name:          "non-positive quantity",
+			username:      "test_user",
+			quantity:      0,
+			setup:         func(m orderMocks) {},
+			expectedError: ErrInvalidQuantity,
+		},
+		{
+			name:     "purchase not found",
+			username: "test_user",
+			quantity: 1,
+			setup: func(m orderMocks) {
+				m.purchases.On("FindByIdForUpdate", mock.Anything, purchaseId).Return(nil, repo.ErrPurchaseNotFound)
+			},
+			expectedError: ErrPurchaseNotFound,
+		},
+		{
+			name:     "purchase of another employee",
+			username: "other_user",
+			quantity: 1,
+			setup: func(m orderMocks) {
+				m.purchases.On("FindByIdForUpdate", mock.Anything, purchaseId).
+					Return(newPurchase(0, time.Now()), nil)
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderDelivered), nil)
+			},
+			expectedError: ErrPurchaseNotFound,
+		},
+		{
+			name:     "order not delivered",
+			username: "test_user",
+			quantity: 1,
+			setup: func(m orderMocks) {
+				m.purchases.On("FindByIdForUpdate", mock.Anything, purchaseId).
+					Return(newPurchase(0, time.Now()), nil)
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderPlaced), nil)
+			},
+			expectedError: ErrOrderNotDelivered,
+		},
+		{
+			name:     "non-returnable item",
+			username: "test_user",
+			quantity: 1,
+			setup: func(m orderMocks) {
+				m.purchases.On("FindByIdForUpdate", mock.Anything, purchaseId).
+					Return(newPurchase(0, time.Now()), nil)
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderDelivered), nil)
+				m.items.On("FindById", mock.Anything, item.Id).
+					Return(&model.Item{Id: item.Id, Name: "cup", NonReturnable: true}, nil)
+			},
+			expectedError: ErrItemNotReturnable,
+		},
+		{
+			name:         "return window expired",
+			username:     "test_user",
+			quantity:     1,
+			returnWindow: 24 * time.Hour,
+			setup: func(m orderMocks) {
+				m.purchases.On("FindByIdForUpdate", mock.Anything, purchaseId).
+					Return(newPurchase(0, time.Now().Add(-72*time.Hour)), nil)
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).
+					Return(deliveredOrder(time.Now().Add(-48*time.Hour)), nil)
+				m.items.On("FindById", mock.Anything, item.Id).Return(item, nil)
+			},
+			expectedError: ErrReturnWindowExpired,
+		},
+		{
+			name:     "more than left to return",
+			username: "test_user",
+			quantity: 2,
+			setup: func(m orderMocks) {
+				m.purchases.On("FindByIdForUpdate", mock.Anything, purchaseId).
+					Return(newPurchase(2, time.Now().Add(-48*time.Hour)), nil)
+				m.orders.On("FindByIdForUpdate", mock.Anything, orderId).Return(newOrder(model.OrderDelivered), nil)
+				m.items.On("FindById", mock.Anything, item.Id).Return(item, nil)
+			},
+			expectedError: ErrReturnQuantityExceeded,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newOrderMocks()
+			tc.setup(m)
+
+			purchaseReturn, err := m.service(tc.returnWindow).
+				Return(context.Background(), tc.username, purchaseId, tc.quantity)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 40, purchaseReturn.Amount)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
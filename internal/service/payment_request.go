@@ -0,0 +1,217 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PaymentRequestService struct {
+	trManager          TransactionManager
+	employeeRepo       EmployeeRepo
+	paymentRequestRepo PaymentRequestRepo
+	coinSender         CoinSender
+	requestTTL         time.Duration
+}
+
+func NewPaymentRequestService(
+	trManager TransactionManager,
+	employeeRepo EmployeeRepo,
+	paymentRequestRepo PaymentRequestRepo,
+	coinSender CoinSender,
+	requestTTL time.Duration,
+) *PaymentRequestService {
+	return &PaymentRequestService{
+		trManager:          trManager,
+		employeeRepo:       employeeRepo,
+		paymentRequestRepo: paymentRequestRepo,
+		coinSender:         coinSender,
+		requestTTL:         requestTTL,
+	}
+}
+
+// Create asks payerUsername to send amount coins to requesterUsername. No coins move until the payer accepts.
+func (s *PaymentRequestService) Create(
+	ctx context.Context, requesterUsername string, payerUsername string, amount int, message string,
+) (*model.PaymentRequest, error) {
+	const op = "service.PaymentRequestService.Create"
+
+	if requesterUsername == payerUsername {
+		return nil, ErrTransferToSameEmployee
+	}
+
+	if amount <= 0 {
+		return nil, ErrNonPositiveTransferAmount
+	}
+
+	memo, err := validateMemo(model.TransferMemo{Message: message})
+	if err != nil {
+		return nil, err
+	}
+
+	requester, err := s.employeeRepo.FindByUsername(ctx, requesterUsername)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	payer, err := s.employeeRepo.FindByUsername(ctx, payerUsername)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrPayerNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	request := &model.PaymentRequest{
+		Id:          uuid.New(),
+		RequesterId: requester.Id,
+		Requester:   requester.Username,
+		PayerId:     payer.Id,
+		Payer:       payer.Username,
+		Amount:      amount,
+		Message:     memo.Message,
+		Status:      model.PaymentRequestPending,
+		ExpiresAt:   time.Now().Add(s.requestTTL),
+	}
+
+	if err = s.paymentRequestRepo.Save(ctx, request); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return request, nil
+}
+
+func (s *PaymentRequestService) List(
+	ctx context.Context, username string, filter model.PaymentRequestFilter) ([]model.PaymentRequest, error) {
+	const op = "service.PaymentRequestService.List"
+
+	if filter.Direction == "" {
+		filter.Direction = model.PaymentRequestsIncoming
+	}
+
+	if !filter.Direction.Valid() || (filter.Status != "" && !filter.Status.Valid()) {
+		return nil, ErrInvalidRequestFilter
+	}
+
+	employee, err := s.employeeRepo.FindByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	requests, err := s.paymentRequestRepo.FindByEmployee(ctx, employee.Id, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return requests, nil
+}
+
+// Accept pays the request with a regular transfer from the payer to the requester in the same transaction.
+// A transfer held for approval still accepts the request. The transfer runs in a savepoint, so a failed one is rolled
+// back even when the caller's transaction goes on.
+func (s *PaymentRequestService) Accept(
+	ctx context.Context, username string, requestId uuid.UUID) (*model.PaymentRequest, error) {
+	const op = "service.PaymentRequestService.Accept"
+
+	var request *model.PaymentRequest
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = s.findPendingRequest(ctx, username, requestId)
+		if err != nil {
+			return err
+		}
+
+		memo := model.TransferMemo{Message: request.Message}
+		err = s.trManager.DoWithSettings(ctx, savepoint, func(ctx context.Context) error {
+			_, err := s.coinSender.SendCoins(ctx, request.Payer, request.Requester, request.Amount, memo)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = s.paymentRequestRepo.Resolve(ctx, request.Id, model.PaymentRequestAccepted); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		resolvedAt := time.Now()
+		request.Status = model.PaymentRequestAccepted
+		request.ResolvedAt = &resolvedAt
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (s *PaymentRequestService) Decline(
+	ctx context.Context, username string, requestId uuid.UUID) (*model.PaymentRequest, error) {
+	const op = "service.PaymentRequestService.Decline"
+
+	var request *model.PaymentRequest
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = s.findPendingRequest(ctx, username, requestId)
+		if err != nil {
+			return err
+		}
+
+		if err = s.paymentRequestRepo.Resolve(ctx, request.Id, model.PaymentRequestDeclined); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		resolvedAt := time.Now()
+		request.Status = model.PaymentRequestDeclined
+		request.ResolvedAt = &resolvedAt
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// findPendingRequest locks a request addressed to username. Requests addressed to someone else are reported as
+// not found so their existence isn't leaked.
+func (s *PaymentRequestService) findPendingRequest(
+	ctx context.Context, username string, requestId uuid.UUID) (*model.PaymentRequest, error) {
+	const op = "service.PaymentRequestService.findPendingRequest"
+
+	request, err := s.paymentRequestRepo.FindByIdForUpdate(ctx, requestId)
+	if err != nil {
+		if errors.Is(err, repo.ErrPaymentRequestNotFound) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if request.Payer != username {
+		return nil, ErrPaymentRequestNotFound
+	}
+
+	switch {
+	case request.Status == model.PaymentRequestExpired:
+		return nil, ErrPaymentRequestExpired
+	case request.Status != model.PaymentRequestPending:
+		return nil, ErrPaymentRequestNotPending
+	case !request.ExpiresAt.After(time.Now()):
+		return nil, ErrPaymentRequestExpired
+	}
+
+	return request, nil
+}
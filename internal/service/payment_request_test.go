@@ -0,0 +1,272 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestPaymentRequestService_Create(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	requester := &model.Employee{Id: uuid.New(), Username: "requester"}
+	payer := &model.Employee{Id: uuid.New(), Username: "payer"}
+
+	tests := []struct {
+		name          string
+		payer         string
+		amount        int
+		message       string
+		setup         func(*mockEmployeeRepo, *mockPaymentRequestRepo)
+		expectedError error
+	}{
+		{
+			name:    "successful request",
+			payer:   "payer",
+			amount:  15,
+			message: "  team hoodie  ",
+			setup: func(mer *mockEmployeeRepo, mpr *mockPaymentRequestRepo) {
+				mer.On("FindByUsername", mock.Anything, "requester").Return(requester, nil)
+				mer.On("FindByUsername", mock.Anything, "payer").Return(payer, nil)
+				mpr.On("Save", mock.Anything, mock.MatchedBy(func(r *model.PaymentRequest) bool {
+					return r.RequesterId == requester.Id && r.PayerId == payer.Id && r.Amount == 15 &&
+						r.Message == "team hoodie" && r.Status == model.PaymentRequestPending &&
+						r.ExpiresAt.After(time.Now().Add(71*time.Hour))
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "request from self",
+			payer:         "requester",
+			amount:        15,
+			setup:         func(*mockEmployeeRepo, *mockPaymentRequestRepo) {},
+			expectedError: ErrTransferToSameEmployee,
+		},
+		{
+			name:          "non-positive amount",
+			payer:         "payer",
+			amount:        0,
+			setup:         func(*mockEmployeeRepo, *mockPaymentRequestRepo) {},
+			expectedError: ErrNonPositiveTransferAmount,
+		},
+		{
+			name:   "payer not found",
+			payer:  "payer",
+			amount: 15,
+			setup: func(mer *mockEmployeeRepo, mpr *mockPaymentRequestRepo) {
+				mer.On("FindByUsername", mock.Anything, "requester").Return(requester, nil)
+				mer.On("FindByUsername", mock.Anything, "payer").Return(nil, repo.ErrEmployeeNotFound)
+			},
+			expectedError: ErrPayerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockPaymentRequestRepo := new(mockPaymentRequestRepo)
+			paymentRequestService := NewPaymentRequestService(mockTrManager, mockEmployeeRepo, mockPaymentRequestRepo,
+				new(mockCoinSender), 72*time.Hour)
+
+			tt.setup(mockEmployeeRepo, mockPaymentRequestRepo)
+
+			_, err := paymentRequestService.Create(context.Background(), "requester", tt.payer, tt.amount, tt.message)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockPaymentRequestRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPaymentRequestService_List(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	employee := &model.Employee{Id: uuid.New(), Username: "payer"}
+
+	t.Run("defaults to incoming requests", func(t *testing.T) {
+		mockEmployeeRepo := new(mockEmployeeRepo)
+		mockPaymentRequestRepo := new(mockPaymentRequestRepo)
+		paymentRequestService := NewPaymentRequestService(mockTrManager, mockEmployeeRepo, mockPaymentRequestRepo,
+			new(mockCoinSender), time.Hour)
+
+		filter := model.PaymentRequestFilter{Direction: model.PaymentRequestsIncoming}
+		mockEmployeeRepo.On("FindByUsername", mock.Anything, "payer").Return(employee, nil)
+		mockPaymentRequestRepo.On("FindByEmployee", mock.Anything, employee.Id, filter).
+			Return([]model.PaymentRequest{{Id: uuid.New()}}, nil)
+
+		requests, err := paymentRequestService.List(context.Background(), "payer", model.PaymentRequestFilter{})
+
+		assert.NoError(t, err)
+		assert.Len(t, requests, 1)
+		mockPaymentRequestRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		paymentRequestService := NewPaymentRequestService(mockTrManager, new(mockEmployeeRepo),
+			new(mockPaymentRequestRepo), new(mockCoinSender), time.Hour)
+
+		_, err := paymentRequestService.List(context.Background(), "payer",
+			model.PaymentRequestFilter{Status: "paid"})
+
+		assert.ErrorIs(t, err, ErrInvalidRequestFilter)
+	})
+}
+
+func TestPaymentRequestService_Accept(t *testing.T) {
+	requestId := uuid.New()
+
+	pending := func() *model.PaymentRequest {
+		return &model.PaymentRequest{
+			Id:        requestId,
+			Requester: "requester",
+			Payer:     "payer",
+			Amount:    15,
+			Message:   "hoodie",
+			Status:    model.PaymentRequestPending,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name          string
+		username      string
+		request       func() *model.PaymentRequest
+		findErr       error
+		sendErr       error
+		expectedError error
+	}{
+		{
+			name:     "successful accept",
+			username: "payer",
+			request:  pending,
+		},
+		{
+			name:          "request not found",
+			username:      "payer",
+			findErr:       repo.ErrPaymentRequestNotFound,
+			expectedError: ErrPaymentRequestNotFound,
+		},
+		{
+			name:          "request addressed to someone else",
+			username:      "requester",
+			request:       pending,
+			expectedError: ErrPaymentRequestNotFound,
+		},
+		{
+			name:     "already declined",
+			username: "payer",
+			request: func() *model.PaymentRequest {
+				r := pending()
+				r.Status = model.PaymentRequestDeclined
+				return r
+			},
+			expectedError: ErrPaymentRequestNotPending,
+		},
+		{
+			name:     "expired",
+			username: "payer",
+			request: func() *model.PaymentRequest {
+				r := pending()
+				r.ExpiresAt = time.Now().Add(-time.Minute)
+				return r
+			},
+			expectedError: ErrPaymentRequestExpired,
+		},
+		{
+			name:          "not enough coins",
+			username:      "payer",
+			request:       pending,
+			sendErr:       ErrNotEnoughCoins,
+			expectedError: ErrNotEnoughCoins,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPaymentRequestRepo := new(mockPaymentRequestRepo)
+			mockCoinSender := new(mockCoinSender)
+			trManager := new(mockTransactionManager)
+			paymentRequestService := NewPaymentRequestService(trManager, new(mockEmployeeRepo),
+				mockPaymentRequestRepo, mockCoinSender, time.Hour)
+
+			if tt.findErr != nil {
+				mockPaymentRequestRepo.On("FindByIdForUpdate", mock.Anything, requestId).Return(nil, tt.findErr)
+			} else {
+				mockPaymentRequestRepo.On("FindByIdForUpdate", mock.Anything, requestId).Return(tt.request(), nil)
+			}
+			sends := tt.expectedError == nil || tt.sendErr != nil
+			if sends {
+				mockCoinSender.On("SendCoins", mock.Anything, "payer", "requester", 15,
+					model.TransferMemo{Message: "hoodie"}).Return(nil, tt.sendErr)
+			}
+			if tt.expectedError == nil {
+				mockPaymentRequestRepo.On("Resolve", mock.Anything, requestId, model.PaymentRequestAccepted).
+					Return(nil)
+			}
+
+			request, err := paymentRequestService.Accept(context.Background(), tt.username, requestId)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, model.PaymentRequestAccepted, request.Status)
+				assert.NotNil(t, request.ResolvedAt)
+			}
+			if sends {
+				assert.Equal(t, 1, trManager.savepoints)
+			}
+
+			mockPaymentRequestRepo.AssertExpectations(t)
+			mockCoinSender.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPaymentRequestService_Decline(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	requestId := uuid.New()
+
+	t.Run("successful decline", func(t *testing.T) {
+		mockPaymentRequestRepo := new(mockPaymentRequestRepo)
+		mockCoinSender := new(mockCoinSender)
+		paymentRequestService := NewPaymentRequestService(mockTrManager, new(mockEmployeeRepo),
+			mockPaymentRequestRepo, mockCoinSender, time.Hour)
+
+		mockPaymentRequestRepo.On("FindByIdForUpdate", mock.Anything, requestId).Return(&model.PaymentRequest{
+			Id: requestId, Payer: "payer", Status: model.PaymentRequestPending, ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		mockPaymentRequestRepo.On("Resolve", mock.Anything, requestId, model.PaymentRequestDeclined).Return(nil)
+
+		request, err := paymentRequestService.Decline(context.Background(), "payer", requestId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.PaymentRequestDeclined, request.Status)
+		mockPaymentRequestRepo.AssertExpectations(t)
+		mockCoinSender.AssertNotCalled(t, "SendCoins")
+	})
+
+	t.Run("already expired", func(t *testing.T) {
+		mockPaymentRequestRepo := new(mockPaymentRequestRepo)
+		paymentRequestService := NewPaymentRequestService(mockTrManager, new(mockEmployeeRepo),
+			mockPaymentRequestRepo, new(mockCoinSender), time.Hour)
+
+		mockPaymentRequestRepo.On("FindByIdForUpdate", mock.Anything, requestId).Return(&model.PaymentRequest{
+			Id: requestId, Payer: "payer", Status: model.PaymentRequestExpired, ExpiresAt: time.Now(),
+		}, nil)
+
+		_, err := paymentRequestService.Decline(context.Background(), "payer", requestId)
+
+		assert.ErrorIs(t, err, ErrPaymentRequestExpired)
+		mockPaymentRequestRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestTransferService_SendCoins_AboveThreshold(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+
+	tests := []struct {
+		name          string
+		held          int
+		expectedError error
+	}{
+		{
+			name: "coins are held for approval",
+		},
+		{
+			name:          "held coins can't be spent twice",
+			held:          700,
+			expectedError: ErrNotEnoughCoins,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockPendingTransferRepo := new(mockPendingTransferRepo)
+			mockLedger := new(mockLedger)
+			transferService := NewTransferService(
+				mockTrManager, mockEmployeeRepo, new(mockTransferRepo), mockPendingTransferRepo, mockLedger, 500,
+				model.TransferLimits{})
+
+			sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: 1000, Held: tc.held}
+			receiver := &model.Employee{Id: uuid.New(), Username: "receiver"}
+			mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "sender").Return(sender, nil)
+			mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "receiver").Return(receiver, nil)
+			if tc.expectedError == nil {
+				held := mock.MatchedBy(func(e *model.Employee) bool { return e.Balance == 1000 && e.Held == 600 })
+				mockEmployeeRepo.On("UpdateByUsername", mock.Anything, "sender", held).Return(nil)
+				mockPendingTransferRepo.On("Save", mock.Anything, mock.MatchedBy(func(p *model.PendingTransfer) bool {
+					return p.FromEmployee == sender.Id && p.ToEmployee == receiver.Id && p.Amount == 600 &&
+						p.Status == model.PendingTransferPending
+				})).Return(nil)
+			}
+
+			pending, err := transferService.SendCoins(context.Background(), "sender", "receiver", 600,
+				model.TransferMemo{})
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, pending)
+				assert.Equal(t, "receiver", pending.To)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockPendingTransferRepo.AssertExpectations(t)
+			mockLedger.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+				mock.Anything)
+		})
+	}
+}
+
+func TestTransferService_SendCoinsBatch_AboveThreshold(t *testing.T) {
+	mockEmployeeRepo := new(mockEmployeeRepo)
+	mockTransferRepo := new(mockTransferRepo)
+	mockPendingTransferRepo := new(mockPendingTransferRepo)
+	mockLedger := new(mockLedger)
+	transferService := NewTransferService(
+		new(mockTransactionManager), mockEmployeeRepo, mockTransferRepo, mockPendingTransferRepo, mockLedger, 500,
+		model.TransferLimits{})
+
+	sender := &model.Employee{Id: uuid.New(), Username: "lead", Balance: 1000}
+	bob := &model.Employee{Id: uuid.New(), Username: "bob"}
+	carol := &model.Employee{Id: uuid.New(), Username: "carol"}
+	mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "bob").Return(bob, nil)
+	mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "carol").Return(carol, nil)
+	mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "lead").Return(sender, nil)
+	mockLedger.On("Transfer", mock.Anything, mock.Anything, sender, bob, 100).Return(nil)
+	mockTransferRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	mockEmployeeRepo.On("UpdateByUsername", mock.Anything, "lead", sender).Return(nil)
+	mockPendingTransferRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	lines, err := transferService.SendCoinsBatch(context.Background(), "lead",
+		[]model.TransferLine{{ToUser: "carol", Amount: 800}, {ToUser: "bob", Amount: 100}}, model.TransferMemo{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TransferLine{
+		{ToUser: "bob", Amount: 100},
+		{ToUser: "carol", Amount: 800, Pending: true},
+	}, lines)
+	assert.Equal(t, 800, sender.Held)
+	mockPendingTransferRepo.AssertExpectations(t)
+}
+
+func TestTransferService_ListPending(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+
+	t.Run("admin sees every pending transfer", func(t *testing.T) {
+		mockEmployeeRepo := new(mockEmployeeRepo)
+		mockPendingTransferRepo := new(mockPendingTransferRepo)
+		transferService := NewTransferService(
+			mockTrManager, mockEmployeeRepo, new(mockTransferRepo), mockPendingTransferRepo, new(mockLedger), 500,
+			model.TransferLimits{})
+
+		admin := &model.Employee{Id: uuid.New(), Username: "admin", Role: model.RoleAdmin}
+		mockEmployeeRepo.On("FindByUsername", mock.Anything, "admin").Return(admin, nil)
+		mockPendingTransferRepo.On("FindPending", mock.Anything, (*uuid.UUID)(nil)).
+			Return([]model.PendingTransfer{{Id: uuid.New()}}, nil)
+
+		transfers, err := transferService.ListPending(context.Background(), "admin")
+
+		assert.NoError(t, err)
+		assert.Len(t, transfers, 1)
+	})
+
+	t.Run("manager sees their reports", func(t *testing.T) {
+		mockEmployeeRepo := new(mockEmployeeRepo)
+		mockPendingTransferRepo := new(mockPendingTransferRepo)
+		transferService := NewTransferService(
+			mockTrManager, mockEmployeeRepo, new(mockTransferRepo), mockPendingTransferRepo, new(mockLedger), 500,
+			model.TransferLimits{})
+
+		manager := &model.Employee{Id: uuid.New(), Username: "manager", Role: model.RoleEmployee}
+		mockEmployeeRepo.On("FindByUsername", mock.Anything, "manager").Return(manager, nil)
+		mockPendingTransferRepo.On("FindPending", mock.Anything, &manager.Id).Return([]model.PendingTransfer{}, nil)
+
+		transfers, err := transferService.ListPending(context.Background(), "manager")
+
+		assert.NoError(t, err)
+		assert.Empty(t, transfers)
+	})
+}
+
+func TestTransferService_ResolvePending(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	transferId := uuid.New()
+	managerId := uuid.New()
+
+	tests := []struct {
+		name          string
+		approver      *model.Employee
+		status        model.PendingTransferStatus
+		reject        bool
+		findErr       error
+		expectedError error
+	}{
+		{
+			name:     "manager approves",
+			approver: &model.Employee{Id: managerId, Username: "manager", Role: model.RoleEmployee},
+		},
+		{
+			name:     "admin rejects",
+			approver: &model.Employee{Id: uuid.New(), Username: "admin", Role: model.RoleAdmin},
+			reject:   true,
+		},
+		{
+			name:          "someone else's manager",
+			approver:      &model.Employee{Id: uuid.New(), Username: "manager", Role: model.RoleEmployee},
+			expectedError: ErrNotApprover,
+		},
+		{
+			name:          "sender can't approve their own transfer",
+			approver:      &model.Employee{Id: uuid.New(), Username: "sender", Role: model.RoleAdmin},
+			expectedError: ErrNotApprover,
+		},
+		{
+			name:          "already resolved",
+			approver:      &model.Employee{Id: managerId, Username: "manager", Role: model.RoleEmployee},
+			status:        model.PendingTransferRejected,
+			expectedError: ErrPendingTransferNotPending,
+		},
+		{
+			name:          "unknown transfer",
+			findErr:       repo.ErrPendingTransferNotFound,
+			expectedError: ErrPendingTransferNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockTransferRepo := new(mockTransferRepo)
+			mockPendingTransferRepo := new(mockPendingTransferRepo)
+			mockLedger := new(mockLedger)
+			transferService := NewTransferService(
+				mockTrManager, mockEmployeeRepo, mockTransferRepo, mockPendingTransferRepo, mockLedger, 500,
+				model.TransferLimits{})
+
+			sender := &model.Employee{
+				Id: uuid.New(), Username: "sender", Balance: 1000, Held: 600, ManagerId: &managerId}
+			if tc.approver != nil && tc.approver.Username == "sender" {
+				tc.approver.Id = sender.Id
+			}
+			receiver := &model.Employee{Id: uuid.New(), Username: "receiver"}
+			status := tc.status
+			if status == "" {
+				status = model.PendingTransferPending
+			}
+			pending := &model.PendingTransfer{
+				Id: transferId, FromEmployee: sender.Id, From: "sender", ToEmployee: receiver.Id, To: "receiver",
+				Amount: 600, Status: status,
+			}
+
+			if tc.findErr != nil {
+				mockPendingTransferRepo.On("FindByIdForUpdate", mock.Anything, transferId).Return(nil, tc.findErr)
+			} else {
+				mockPendingTransferRepo.On("FindByIdForUpdate", mock.Anything, transferId).Return(pending, nil)
+			}
+			if tc.approver != nil && status == model.PendingTransferPending {
+				mockEmployeeRepo.On("FindByUsername", mock.Anything, tc.approver.Username).Return(tc.approver, nil)
+				mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "sender").Return(sender, nil)
+				mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "receiver").Return(receiver, nil)
+			}
+
+			expectedStatus := model.PendingTransferApproved
+			if tc.reject {
+				expectedStatus = model.PendingTransferRejected
+			}
+			if tc.expectedError == nil {
+				if tc.reject {
+					released := mock.MatchedBy(func(e *model.Employee) bool { return e.Balance == 1000 && e.Held == 0 })
+					mockEmployeeRepo.On("UpdateByUsername", mock.Anything, "sender", released).Return(nil)
+				} else {
+					mockLedger.On("Transfer", mock.Anything, transferId, sender, receiver, 600).Return(nil)
+					mockTransferRepo.On("Save", mock.Anything, mock.MatchedBy(func(t *model.Transfer) bool {
+						return t.Id == transferId && t.Amount == 600
+					})).Return(nil)
+				}
+				mockPendingTransferRepo.On("Resolve", mock.Anything, transferId, expectedStatus, tc.approver.Id).
+					Return(nil)
+			}
+
+			var resolved *model.PendingTransfer
+			var err error
+			if tc.reject {
+				resolved, err = transferService.RejectPending(context.Background(), tc.approver.Username, transferId)
+			} else {
+				username := "manager"
+				if tc.approver != nil {
+					username = tc.approver.Username
+				}
+				resolved, err = transferService.ApprovePending(context.Background(), username, transferId)
+			}
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, expectedStatus, resolved.Status)
+				assert.Equal(t, 0, sender.Held)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockTransferRepo.AssertExpectations(t)
+			mockPendingTransferRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferService_ApprovePending_RechecksLimits(t *testing.T) {
+	mockEmployeeRepo := new(mockEmployeeRepo)
+	mockTransferRepo := new(mockTransferRepo)
+	mockPendingTransferRepo := new(mockPendingTransferRepo)
+	mockLedger := new(mockLedger)
+	transferService := NewTransferService(
+		new(mockTransactionManager), mockEmployeeRepo, mockTransferRepo, mockPendingTransferRepo, mockLedger, 500,
+		model.TransferLimits{DailyAmount: 1000})
+
+	transferId := uuid.New()
+	admin := &model.Employee{Id: uuid.New(), Username: "admin", Role: model.RoleAdmin}
+	sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: 1000, Held: 600}
+	receiver := &model.Employee{Id: uuid.New(), Username: "receiver"}
+	pending := &model.PendingTransfer{
+		Id: transferId, FromEmployee: sender.Id, From: "sender", ToEmployee: receiver.Id, To: "receiver",
+		Amount: 600, Status: model.PendingTransferPending,
+	}
+
+	mockPendingTransferRepo.On("FindByIdForUpdate", mock.Anything, transferId).Return(pending, nil)
+	mockEmployeeRepo.On("FindByUsername", mock.Anything, "admin").Return(admin, nil)
+	mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "sender").Return(sender, nil)
+	mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "receiver").Return(receiver, nil)
+	// the sender has sent 500 since the transfer was held, so settling it now would break the daily limit
+	mockTransferRepo.On("FindVelocity", mock.Anything, mock.MatchedBy(func(q model.VelocityQuery) bool {
+		return q.ExcludePending == transferId
+	})).Return(&model.TransferVelocity{DailyAmount: 500}, nil)
+
+	_, err := transferService.ApprovePending(context.Background(), "admin", transferId)
+
+	assert.ErrorIs(t, err, ErrTransferLimitExceeded)
+	mockTransferRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockPendingTransferRepo.AssertNotCalled(t, "Resolve")
+}
+
+func TestTransferService_CheckSale(t *testing.T) {
+	buyer := &model.Employee{Id: uuid.New(), Username: "buyer"}
+	seller := &model.Employee{Id: uuid.New(), Username: "seller"}
+
+	tests := []struct {
+		name          string
+		amount        int
+		velocity      model.TransferVelocity
+		expectedError error
+	}{
+		{
+			name:   "within limits",
+			amount: 200,
+		},
+		{
+			name:          "above the approval threshold",
+			amount:        600,
+			expectedError: ErrSaleRequiresApproval,
+		},
+		{
+			name:          "daily amount exceeded",
+			amount:        200,
+			velocity:      model.TransferVelocity{DailyAmount: 900},
+			expectedError: ErrTransferLimitExceeded,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTransferRepo := new(mockTransferRepo)
+			transferService := NewTransferService(new(mockTransactionManager), new(mockEmployeeRepo), mockTransferRepo,
+				new(mockPendingTransferRepo), new(mockLedger), 500, model.TransferLimits{DailyAmount: 1000})
+
+			mockTransferRepo.On("FindVelocity", mock.Anything, mock.MatchedBy(func(q model.VelocityQuery) bool {
+				return q.FromEmployee == buyer.Id && q.ToEmployee == seller.Id
+			})).Return(&tc.velocity, nil).Maybe()
+
+			err := transferService.CheckSale(context.Background(), buyer, seller, tc.amount)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
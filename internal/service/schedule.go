@@ -0,0 +1,334 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// permanentRunErrors stop a schedule for good: retrying them later can't succeed.
+var permanentRunErrors = []error{
+	ErrSenderNotFound,
+	ErrReceiverNotFound,
+	ErrTransferToSameEmployee,
+	ErrNonPositiveTransferAmount,
+	ErrTransferMessageTooLong,
+	ErrInvalidTransferCategory,
+	ErrAccountDeactivated,
+}
+
+type ScheduleService struct {
+	trManager     TransactionManager
+	employeeRepo  EmployeeRepo
+	scheduleRepo  ScheduleRepo
+	coinSender    CoinSender
+	retryInterval time.Duration
+	maxAttempts   int
+}
+
+func NewScheduleService(
+	trManager TransactionManager,
+	employeeRepo EmployeeRepo,
+	scheduleRepo ScheduleRepo,
+	coinSender CoinSender,
+	retryInterval time.Duration,
+	maxAttempts int,
+) *ScheduleService {
+	return &ScheduleService{
+		trManager:     trManager,
+		employeeRepo:  employeeRepo,
+		scheduleRepo:  scheduleRepo,
+		coinSender:    coinSender,
+		retryInterval: retryInterval,
+		maxAttempts:   maxAttempts,
+	}
+}
+
+func (s *ScheduleService) Create(
+	ctx context.Context,
+	fromUsername string,
+	toUsername string,
+	amount int,
+	runAt time.Time,
+	recurrence model.Recurrence,
+	memo model.TransferMemo,
+) (*model.ScheduledTransfer, error) {
+	const op = "service.ScheduleService.Create"
+
+	if fromUsername == toUsername {
+		return nil, ErrTransferToSameEmployee
+	}
+
+	if amount <= 0 {
+		return nil, ErrNonPositiveTransferAmount
+	}
+
+	memo, err := validateMemo(memo)
+	if err != nil {
+		return nil, err
+	}
+
+	if recurrence == "" {
+		recurrence = model.RecurrenceOnce
+	}
+	if !recurrence.Valid() {
+		return nil, ErrInvalidRecurrence
+	}
+
+	if !runAt.After(time.Now()) {
+		return nil, ErrScheduleInPast
+	}
+
+	fromEmployee, err := s.employeeRepo.FindByUsername(ctx, fromUsername)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	toEmployee, err := s.employeeRepo.FindByUsername(ctx, toUsername)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrReceiverNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	schedule := &model.ScheduledTransfer{
+		Id:            uuid.New(),
+		FromEmployee:  fromEmployee.Id,
+		From:          fromEmployee.Username,
+		ToEmployee:    toEmployee.Id,
+		To:            toEmployee.Username,
+		Amount:        amount,
+		Message:       memo.Message,
+		Category:      memo.Category,
+		Recurrence:    recurrence,
+		Status:        model.ScheduleActive,
+		StartAt:       runAt,
+		NextRunAt:     runAt,
+		NextAttemptAt: runAt,
+	}
+
+	if err = s.scheduleRepo.Save(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return schedule, nil
+}
+
+func (s *ScheduleService) List(ctx context.Context, username string) ([]model.ScheduledTransfer, error) {
+	const op = "service.ScheduleService.List"
+
+	employee, err := s.employeeRepo.FindByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	schedules, err := s.scheduleRepo.FindByEmployee(ctx, employee.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return schedules, nil
+}
+
+func (s *ScheduleService) Cancel(
+	ctx context.Context, username string, scheduleId uuid.UUID) (*model.ScheduledTransfer, error) {
+	const op = "service.ScheduleService.Cancel"
+
+	var schedule *model.ScheduledTransfer
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		schedule, err = s.scheduleRepo.FindByIdForUpdate(ctx, scheduleId)
+		if err != nil {
+			if errors.Is(err, repo.ErrScheduleNotFound) {
+				return ErrScheduleNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if schedule.From != username {
+			return ErrScheduleNotFound
+		}
+
+		if schedule.Status != model.ScheduleActive {
+			return ErrScheduleNotActive
+		}
+
+		schedule.Status = model.ScheduleCancelled
+		if err = s.scheduleRepo.Update(ctx, schedule); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (s *ScheduleService) Runs(
+	ctx context.Context, username string, scheduleId uuid.UUID) ([]model.ScheduledTransferRun, error) {
+	const op = "service.ScheduleService.Runs"
+
+	schedule, err := s.scheduleRepo.FindById(ctx, scheduleId)
+	if err != nil {
+		if errors.Is(err, repo.ErrScheduleNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if schedule.From != username {
+		return nil, ErrScheduleNotFound
+	}
+
+	runs, err := s.scheduleRepo.FindRuns(ctx, scheduleId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return runs, nil
+}
+
+// RunDue executes up to limit due schedules and reports how many it processed. Each schedule runs in its own
+// transaction; rows locked by another replica are skipped, so several replicas can share the work.
+func (s *ScheduleService) RunDue(ctx context.Context, limit int) (int, error) {
+	const op = "service.ScheduleService.RunDue"
+
+	processed := 0
+	for processed < limit {
+		found := false
+		err := s.trManager.Do(ctx, func(ctx context.Context) error {
+			schedules, err := s.scheduleRepo.FindDueForUpdate(ctx, 1)
+			if err != nil || len(schedules) == 0 {
+				return err
+			}
+
+			found = true
+			return s.run(ctx, &schedules[0])
+		})
+
+		if err != nil {
+			return processed, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !found {
+			break
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// run makes one attempt of a locked schedule and records its outcome. Insufficient funds and exceeded transfer limits
+// are retried after retryInterval up to maxAttempts times; after that a one-off schedule fails and a recurring one
+// moves on to its next occurrence. Any other business error fails the schedule at once. A transfer held for approval
+// counts as a run. The transfer runs in a savepoint, so a failed attempt leaves only its run record behind.
+func (s *ScheduleService) run(ctx context.Context, schedule *model.ScheduledTransfer) error {
+	now := time.Now()
+	schedule.Attempts++
+
+	run := &model.ScheduledTransferRun{
+		Id:          uuid.New(),
+		ScheduleId:  schedule.Id,
+		ScheduledAt: schedule.NextRunAt,
+		Attempt:     schedule.Attempts,
+		Status:      model.RunSucceeded,
+	}
+
+	memo := model.TransferMemo{Message: schedule.Message, Category: schedule.Category}
+	err := s.trManager.DoWithSettings(ctx, savepoint, func(ctx context.Context) error {
+		_, err := s.coinSender.SendCoins(ctx, schedule.From, schedule.To, schedule.Amount, memo)
+		return err
+	})
+
+	switch {
+	case err == nil:
+		schedule.LastError = ""
+		s.advance(schedule, now)
+	case errors.Is(err, ErrNotEnoughCoins), errors.Is(err, ErrTransferLimitExceeded),
+		errors.Is(err, ErrAccountFrozen), errors.Is(err, ErrReceiverNotActive):
+		run.Status = model.RunFailed
+		run.Reason = retryableRunReason(err)
+		schedule.LastError = run.Reason
+
+		if schedule.Attempts < s.maxAttempts {
+			schedule.NextAttemptAt = now.Add(s.retryInterval)
+		} else if schedule.Recurrence == model.RecurrenceOnce {
+			schedule.Status = model.ScheduleFailed
+		} else {
+			s.advance(schedule, now)
+		}
+	default:
+		reason, ok := permanentRunError(err)
+		if !ok {
+			return err
+		}
+
+		run.Status = model.RunFailed
+		run.Reason = reason
+		schedule.LastError = reason
+		schedule.Status = model.ScheduleFailed
+	}
+
+	if err = s.scheduleRepo.SaveRun(ctx, run); err != nil {
+		return err
+	}
+
+	return s.scheduleRepo.Update(ctx, schedule)
+}
+
+// advance moves a schedule past its current occurrence. Occurrences missed while the service was down are skipped
+// rather than paid out in a burst.
+func (s *ScheduleService) advance(schedule *model.ScheduledTransfer, now time.Time) {
+	schedule.Attempts = 0
+
+	if schedule.Recurrence == model.RecurrenceOnce {
+		schedule.Status = model.ScheduleCompleted
+		return
+	}
+
+	next := schedule.NextRunAt
+	for !next.After(now) {
+		schedule.Occurrence++
+		next = schedule.Recurrence.At(schedule.StartAt, schedule.Occurrence)
+	}
+
+	schedule.NextRunAt = next
+	schedule.NextAttemptAt = next
+}
+
+func retryableRunReason(err error) string {
+	switch {
+	case errors.Is(err, ErrTransferLimitExceeded):
+		return err.Error()
+	case errors.Is(err, ErrAccountFrozen):
+		return ErrAccountFrozen.Error()
+	case errors.Is(err, ErrReceiverNotActive):
+		return ErrReceiverNotActive.Error()
+	}
+	return ErrNotEnoughCoins.Error()
+}
+
+func permanentRunError(err error) (string, bool) {
+	for _, target := range permanentRunErrors {
+		if errors.Is(err, target) {
+			return target.Error(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestScheduleService_Create(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	sender := &model.Employee{Id: uuid.New(), Username: "sender"}
+	receiver := &model.Employee{Id: uuid.New(), Username: "receiver"}
+	runAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		toUser        string
+		amount        int
+		runAt         time.Time
+		recurrence    model.Recurrence
+		setup         func(*mockEmployeeRepo, *mockScheduleRepo)
+		expectedError error
+	}{
+		{
+			name:       "successful weekly schedule",
+			toUser:     "receiver",
+			amount:     10,
+			runAt:      runAt,
+			recurrence: model.RecurrenceWeekly,
+			setup: func(mer *mockEmployeeRepo, msr *mockScheduleRepo) {
+				mer.On("FindByUsername", mock.Anything, "sender").Return(sender, nil)
+				mer.On("FindByUsername", mock.Anything, "receiver").Return(receiver, nil)
+				msr.On("Save", mock.Anything, mock.MatchedBy(func(s *model.ScheduledTransfer) bool {
+					return s.FromEmployee == sender.Id && s.ToEmployee == receiver.Id && s.Amount == 10 &&
+						s.Recurrence == model.RecurrenceWeekly && s.Status == model.ScheduleActive &&
+						s.NextRunAt.Equal(runAt) && s.NextAttemptAt.Equal(runAt)
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "recurrence defaults to once",
+			toUser: "receiver",
+			amount: 10,
+			runAt:  runAt,
+			setup: func(mer *mockEmployeeRepo, msr *mockScheduleRepo) {
+				mer.On("FindByUsername", mock.Anything, "sender").Return(sender, nil)
+				mer.On("FindByUsername", mock.Anything, "receiver").Return(receiver, nil)
+				msr.On("Save", mock.Anything, mock.MatchedBy(func(s *model.ScheduledTransfer) bool {
+					return s.Recurrence == model.RecurrenceOnce
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "transfer to self",
+			toUser:        "sender",
+			amount:        10,
+			runAt:         runAt,
+			setup:         func(*mockEmployeeRepo, *mockScheduleRepo) {},
+			expectedError: ErrTransferToSameEmployee,
+		},
+		{
+			name:          "non-positive amount",
+			toUser:        "receiver",
+			amount:        0,
+			runAt:         runAt,
+			setup:         func(*mockEmployeeRepo, *mockScheduleRepo) {},
+			expectedError: ErrNonPositiveTransferAmount,
+		},
+		{
+			name:          "invalid recurrence",
+			toUser:        "receiver",
+			amount:        10,
+			runAt:         runAt,
+			recurrence:    "daily",
+			setup:         func(*mockEmployeeRepo, *mockScheduleRepo) {},
+			expectedError: ErrInvalidRecurrence,
+		},
+		{
+			name:          "run time in the past",
+			toUser:        "receiver",
+			amount:        10,
+			runAt:         time.Now().Add(-time.Minute),
+			setup:         func(*mockEmployeeRepo, *mockScheduleRepo) {},
+			expectedError: ErrScheduleInPast,
+		},
+		{
+			name:   "receiver not found",
+			toUser: "receiver",
+			amount: 10,
+			runAt:  runAt,
+			setup: func(mer *mockEmployeeRepo, msr *mockScheduleRepo) {
+				mer.On("FindByUsername", mock.Anything, "sender").Return(sender, nil)
+				mer.On("FindByUsername", mock.Anything, "receiver").Return(nil, repo.ErrEmployeeNotFound)
+			},
+			expectedError: ErrReceiverNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockScheduleRepo := new(mockScheduleRepo)
+			scheduleService := NewScheduleService(mockTrManager, mockEmployeeRepo, mockScheduleRepo,
+				new(mockCoinSender), time.Hour, 3)
+
+			tt.setup(mockEmployeeRepo, mockScheduleRepo)
+
+			_, err := scheduleService.Create(context.Background(), "sender", tt.toUser, tt.amount, tt.runAt,
+				tt.recurrence, model.TransferMemo{})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockScheduleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestScheduleService_Cancel(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	scheduleId := uuid.New()
+
+	tests := []struct {
+		name          string
+		schedule      *model.ScheduledTransfer
+		findErr       error
+		expectedError error
+	}{
+		{
+			name:     "successful cancel",
+			schedule: &model.ScheduledTransfer{Id: scheduleId, From: "sender", Status: model.ScheduleActive},
+		},
+		{
+			name:          "schedule not found",
+			findErr:       repo.ErrScheduleNotFound,
+			expectedError: ErrScheduleNotFound,
+		},
+		{
+			name:          "someone else's schedule",
+			schedule:      &model.ScheduledTransfer{Id: scheduleId, From: "other", Status: model.ScheduleActive},
+			expectedError: ErrScheduleNotFound,
+		},
+		{
+			name:          "already completed",
+			schedule:      &model.ScheduledTransfer{Id: scheduleId, From: "sender", Status: model.ScheduleCompleted},
+			expectedError: ErrScheduleNotActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockScheduleRepo := new(mockScheduleRepo)
+			scheduleService := NewScheduleService(mockTrManager, new(mockEmployeeRepo), mockScheduleRepo,
+				new(mockCoinSender), time.Hour, 3)
+
+			if tt.findErr != nil {
+				mockScheduleRepo.On("FindByIdForUpdate", mock.Anything, scheduleId).Return(nil, tt.findErr)
+			} else {
+				mockScheduleRepo.On("FindByIdForUpdate", mock.Anything, scheduleId).Return(tt.schedule, nil)
+			}
+			if tt.expectedError == nil {
+				mockScheduleRepo.On("Update", mock.Anything, mock.MatchedBy(func(s *model.ScheduledTransfer) bool {
+					return s.Status == model.ScheduleCancelled
+				})).Return(nil)
+			}
+
+			_, err := scheduleService.Cancel(context.Background(), "sender", scheduleId)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockScheduleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestScheduleService_RunDue(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	memo := model.TransferMemo{Message: "rent", Category: model.CategoryHelp}
+
+	dueSchedule := func(recurrence model.Recurrence, startAt time.Time, attempts int) model.ScheduledTransfer {
+		return model.ScheduledTransfer{
+			Id:            uuid.New(),
+			From:          "sender",
+			To:            "receiver",
+			Amount:        10,
+			Message:       memo.Message,
+			Category:      memo.Category,
+			Recurrence:    recurrence,
+			Status:        model.ScheduleActive,
+			StartAt:       startAt,
+			NextRunAt:     startAt,
+			NextAttemptAt: startAt,
+			Attempts:      attempts,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		schedule  model.ScheduledTransfer
+		sendErr   error
+		runStatus model.RunStatus
+		check     func(t *testing.T, s *model.ScheduledTransfer)
+	}{
+		{
+			name:      "one-off schedule completes",
+			schedule:  dueSchedule(model.RecurrenceOnce, time.Now().Add(-time.Minute), 0),
+			runStatus: model.RunSucceeded,
+			check: func(t *testing.T, s *model.ScheduledTransfer) {
+				assert.Equal(t, model.ScheduleCompleted, s.Status)
+				assert.Equal(t, 0, s.Attempts)
+			},
+		},
+		{
+			name:      "weekly schedule moves to next week",
+			schedule:  dueSchedule(model.RecurrenceWeekly, time.Now().Add(-time.Minute), 0),
+			runStatus: model.RunSucceeded,
+			check: func(t *testing.T, s *model.ScheduledTransfer) {
+				assert.Equal(t, model.ScheduleActive, s.Status)
+				assert.Equal(t, 1, s.Occurrence)
+				assert.True(t, s.NextRunAt.Equal(s.StartAt.AddDate(0, 0, 7)))
+				assert.True(t, s.NextAttemptAt.Equal(s.NextRunAt))
+			},
+		},
+		{
+			name:      "missed occurrences are skipped",
+			schedule:  dueSchedule(model.RecurrenceWeekly, time.Now().Add(-20*24*time.Hour), 0),
+			runStatus: model.RunSucceeded,
+			check: func(t *testing.T, s *model.ScheduledTransfer) {
+				assert.Equal(t, 3, s.Occurrence)
+				assert.True(t, s.NextRunAt.After(time.Now()))
+			},
+		},
+		{
+			name:      "insufficient funds is retried",
+			schedule:  dueSchedule(model.RecurrenceOnce, time.Now().Add(-time.Minute), 0),
+			sendErr:   ErrNotEnoughCoins,
+			runStatus: model.RunFailed,
+			check: func(t *testing.T, s *model.ScheduledTransfer) {
+				assert.Equal(t, model.ScheduleActive, s.Status)
+				assert.Equal(t, 1, s.Attempts)
+				assert.Equal(t, ErrNotEnoughCoins.Error(), s.LastError)
+				assert.True(t, s.NextAttemptAt.After(time.Now().Add(59*time.Minute)))
+			},
+		},
+		{
+			name:      "exceeded transfer limit is retried",
+			schedule:  dueSchedule(model.RecurrenceOnce, time.Now().Add(-time.Minute), 0),
+			sendErr:   &TransferLimitError{Limit: LimitDailyAmount, Remaining: 5},
+			runStatus: model.RunFailed,
+			check: func(t *testing.T, s *model.ScheduledTransfer) {
+				assert.Equal(t, model.ScheduleActive, s.Status)
+				assert.Equal(t, 1, s.Attempts)
+				assert.Contains(t, s.LastError, ErrTransferLimitExceeded.Error())
+			},
+		},
+		{
+			name:      "one-off schedule fails after last attempt",
+			schedule:  dueSchedule(model.RecurrenceOnce, time.Now().Add(-time.Minute), 2),
+			sendErr:   ErrNotEnoughCoins,
+			runStatus: model.RunFailed,
+			check: func(t *testing.T, s *model.ScheduledTransfer) {
+				assert.Equal(t, model.ScheduleFailed, s.Status)
+				assert.Equal(t, 3, s.Attempts)
+			},
+		},
+		{
+			name:      "recurring schedule skips occurrence after last attempt",
+			schedule:  dueSchedule(model.RecurrenceMonthly, time.Now().Add(-time.Minute), 2),
+			sendErr:   ErrNotEnoughCoins,
+			runStatus: model.RunFailed,
+			check: func(t *testing.T, s *model.ScheduledTransfer) {
+				assert.Equal(t, model.ScheduleActive, s.Status)
+				assert.Equal(t, 1, s.Occurrence)
+				assert.Equal(t, 0, s.Attempts)
+				assert.Equal(t, ErrNotEnoughCoins.Error(), s.LastError)
+			},
+		},
+		{
+			name:      "missing receiver fails the schedule",
+			schedule:  dueSchedule(model.RecurrenceWeekly, time.Now().Add(-time.Minute), 0),
+			sendErr:   ErrReceiverNotFound,
+			runStatus: model.RunFailed,
+			check: func(t *testing.T, s *model.ScheduledTransfer) {
+				assert.Equal(t, model.ScheduleFailed, s.Status)
+				assert.Equal(t, ErrReceiverNotFound.Error(), s.LastError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trManager := new(mockTransactionManager)
+			mockScheduleRepo := new(mockScheduleRepo)
+			mockCoinSender := new(mockCoinSender)
+			scheduleService := NewScheduleService(trManager, new(mockEmployeeRepo), mockScheduleRepo,
+				mockCoinSender, time.Hour, 3)
+
+			var updated *model.ScheduledTransfer
+			mockScheduleRepo.On("FindDueForUpdate", mock.Anything, 1).
+				Return([]model.ScheduledTransfer{tt.schedule}, nil).Once()
+			mockScheduleRepo.On("FindDueForUpdate", mock.Anything, 1).Return([]model.ScheduledTransfer{}, nil).Once()
+			mockCoinSender.On("SendCoins", mock.Anything, "sender", "receiver", 10, memo).Return(nil, tt.sendErr)
+			mockScheduleRepo.On("SaveRun", mock.Anything, mock.MatchedBy(func(r *model.ScheduledTransferRun) bool {
+				return r.ScheduleId == tt.schedule.Id && r.Status == tt.runStatus &&
+					r.ScheduledAt.Equal(tt.schedule.NextRunAt) && r.Attempt == tt.schedule.Attempts+1
+			})).Return(nil)
+			mockScheduleRepo.On("Update", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { updated = args.Get(1).(*model.ScheduledTransfer) }).
+				Return(nil)
+
+			processed, err := scheduleService.RunDue(context.Background(), 10)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, processed)
+			assert.Equal(t, 1, trManager.savepoints)
+			tt.check(t, updated)
+			mockScheduleRepo.AssertExpectations(t)
+			mockCoinSender.AssertExpectations(t)
+		})
+	}
+
+	t.Run("infrastructure error is returned", func(t *testing.T) {
+		mockScheduleRepo := new(mockScheduleRepo)
+		mockCoinSender := new(mockCoinSender)
+		scheduleService := NewScheduleService(mockTrManager, new(mockEmployeeRepo), mockScheduleRepo,
+			mockCoinSender, time.Hour, 3)
+
+		schedule := dueSchedule(model.RecurrenceOnce, time.Now().Add(-time.Minute), 0)
+		mockScheduleRepo.On("FindDueForUpdate", mock.Anything, 1).Return([]model.ScheduledTransfer{schedule}, nil)
+		mockCoinSender.On("SendCoins", mock.Anything, "sender", "receiver", 10, memo).
+			Return(nil, errors.New("db error"))
+
+		processed, err := scheduleService.RunDue(context.Background(), 10)
+
+		assert.ErrorContains(t, err, "db error")
+		assert.Equal(t, 0, processed)
+		mockScheduleRepo.AssertNotCalled(t, "SaveRun", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRecurrence_At(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, start, model.RecurrenceOnce.At(start, 3))
+	assert.Equal(t, time.Date(2024, time.February, 14, 9, 0, 0, 0, time.UTC), model.RecurrenceWeekly.At(start, 2))
+	assert.Equal(t, time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC), model.RecurrenceMonthly.At(start, 1))
+	assert.Equal(t, time.Date(2024, time.March, 31, 9, 0, 0, 0, time.UTC), model.RecurrenceMonthly.At(start, 2))
+	assert.Equal(t, time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC), model.RecurrenceMonthly.At(start, 12))
+}
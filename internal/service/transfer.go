@@ -7,81 +7,574 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type TransferService struct {
-	trManager    TransactionManager
-	employeeRepo EmployeeRepo
-	transferRepo TransferRepo
+	trManager           TransactionManager
+	employeeRepo        EmployeeRepo
+	transferRepo        TransferRepo
+	pendingTransferRepo PendingTransferRepo
+	ledger              Ledger
+	approvalThreshold   int
+	limits              model.TransferLimits
 }
 
 func NewTransferService(
 	trManager TransactionManager,
 	employeeRepo EmployeeRepo,
 	transferRepo TransferRepo,
+	pendingTransferRepo PendingTransferRepo,
+	ledger Ledger,
+	approvalThreshold int,
+	limits model.TransferLimits,
 ) *TransferService {
 	return &TransferService{
-		trManager:    trManager,
-		employeeRepo: employeeRepo,
-		transferRepo: transferRepo,
+		trManager:           trManager,
+		employeeRepo:        employeeRepo,
+		transferRepo:        transferRepo,
+		pendingTransferRepo: pendingTransferRepo,
+		ledger:              ledger,
+		approvalThreshold:   approvalThreshold,
+		limits:              limits,
 	}
 }
-func (s *TransferService) SendCoins(ctx context.Context, fromUsername string, toUsername string, amount int) error {
+
+// SendCoins moves coins right away unless the amount is above the approval threshold. Then the coins are held on the
+// sender and the returned PendingTransfer waits for an admin or the sender's manager.
+func (s *TransferService) SendCoins(
+	ctx context.Context, fromUsername string, toUsername string, amount int, memo model.TransferMemo,
+) (*model.PendingTransfer, error) {
 	const op = "service.TransferService.SendCoins"
 
 	if fromUsername == toUsername {
-		return ErrTransferToSameEmployee
+		return nil, ErrTransferToSameEmployee
 	}
 
-	if amount < 0 {
-		return ErrNegativeTransferAmount
+	if amount <= 0 {
+		return nil, ErrNonPositiveTransferAmount
 	}
 
-	err := s.trManager.Do(ctx, func(ctx context.Context) error {
-		fromEmployee, err := s.employeeRepo.FindByUsername(ctx, fromUsername)
+	memo, err := validateMemo(memo)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending *model.PendingTransfer
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		fromEmployee, toEmployee, err := lockEmployees(ctx, s.employeeRepo, fromUsername, toUsername)
 		if err != nil {
-			if errors.Is(err, repo.ErrEmployeeNotFound) {
-				return ErrSenderNotFound
-			}
+			return err
+		}
+
+		if err = checkParties(fromEmployee, toEmployee); err != nil {
+			return err
+		}
+
+		if err = s.checkLimits(ctx, fromEmployee, toEmployee, amount, uuid.Nil); err != nil {
+			return err
+		}
+
+		if s.requiresApproval(amount) {
+			pending, err = s.hold(ctx, fromEmployee, toEmployee, amount, memo)
+			return err
+		}
+
+		transfer := &model.Transfer{
+			Id:           uuid.New(),
+			FromEmployee: fromEmployee.Id,
+			ToEmployee:   toEmployee.Id,
+			Amount:       amount,
+			Message:      memo.Message,
+			Category:     memo.Category,
+		}
+
+		if err = s.ledger.Transfer(ctx, transfer.Id, fromEmployee, toEmployee, amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = s.transferRepo.Save(ctx, transfer); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		if fromEmployee.Balance < amount {
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+// SendCoinsBatch applies every line of the batch in one transaction, so a failed line rolls back the whole batch.
+// Repeated recipients are merged into a single transfer. Lines above the approval threshold are held as pending.
+func (s *TransferService) SendCoinsBatch(
+	ctx context.Context, fromUsername string, lines []model.TransferLine, memo model.TransferMemo,
+) ([]model.TransferLine, error) {
+	const op = "service.TransferService.SendCoinsBatch"
+
+	lines, total, err := normalizeTransferLines(fromUsername, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	memo, err = validateMemo(memo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		employees, err := lockBatchEmployees(ctx, s.employeeRepo, fromUsername, lines)
+		if err != nil {
+			return err
+		}
+
+		fromEmployee := employees[fromUsername]
+		if err = checkActive(fromEmployee); err != nil {
+			return err
+		}
+		if fromEmployee.Available() < total {
 			return ErrNotEnoughCoins
 		}
 
-		toEmployee, err := s.employeeRepo.FindByUsername(ctx, toUsername)
+		for i, line := range lines {
+			toEmployee := employees[line.ToUser]
+			if err = checkParties(fromEmployee, toEmployee); err != nil {
+				return err
+			}
+			if err = s.checkLimits(ctx, fromEmployee, toEmployee, line.Amount, uuid.Nil); err != nil {
+				return err
+			}
+
+			if s.requiresApproval(line.Amount) {
+				if _, err = s.hold(ctx, fromEmployee, toEmployee, line.Amount, memo); err != nil {
+					return err
+				}
+				lines[i].Pending = true
+				continue
+			}
+
+			transfer := &model.Transfer{
+				Id:           uuid.New(),
+				FromEmployee: fromEmployee.Id,
+				ToEmployee:   toEmployee.Id,
+				Amount:       line.Amount,
+				Message:      memo.Message,
+				Category:     memo.Category,
+			}
+
+			if err = s.ledger.Transfer(ctx, transfer.Id, fromEmployee, toEmployee, line.Amount); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if err = s.transferRepo.Save(ctx, transfer); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// Kudos returns the public feed of transfers that were sent with a message or a category.
+func (s *TransferService) Kudos(ctx context.Context, filter model.KudosFilter) (*model.KudosPage, error) {
+	const op = "service.TransferService.Kudos"
+
+	if filter.Category != "" && !filter.Category.Valid() {
+		return nil, ErrInvalidTransferCategory
+	}
+
+	limit := pageLimit(filter.Limit)
+
+	// one extra row tells whether there is a next page
+	filter.Limit = limit + 1
+	kudos, err := s.transferRepo.FindKudos(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	page := &model.KudosPage{Entries: kudos}
+	if len(kudos) > limit {
+		page.Entries = kudos[:limit]
+		last := page.Entries[limit-1]
+		page.NextCursor = &model.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}
+	}
+
+	return page, nil
+}
+
+// ListPending returns the transfers the employee may approve: all of them for an admin, their reports' for a manager.
+func (s *TransferService) ListPending(ctx context.Context, approverUsername string) ([]model.PendingTransfer, error) {
+	const op = "service.TransferService.ListPending"
+
+	approver, err := s.employeeRepo.FindByUsername(ctx, approverUsername)
+	if err != nil {
+		if errors.Is(err, repo.ErrEmployeeNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var managerId *uuid.UUID
+	if approver.Role != model.RoleAdmin {
+		managerId = &approver.Id
+	}
+
+	transfers, err := s.pendingTransferRepo.FindPending(ctx, managerId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transfers, nil
+}
+
+// ApprovePending releases the hold and settles the transfer under the pending transfer's id. It fails while either
+// party is frozen or deactivated, or when settling now would break the sender's limits; such transfers can still
+// be rejected.
+func (s *TransferService) ApprovePending(
+	ctx context.Context, approverUsername string, transferId uuid.UUID) (*model.PendingTransfer, error) {
+	const op = "service.TransferService.ApprovePending"
+
+	return s.resolvePending(ctx, approverUsername, transferId, model.PendingTransferApproved,
+		func(ctx context.Context, pending *model.PendingTransfer, from *model.Employee, to *model.Employee) error {
+			if err := checkParties(from, to); err != nil {
+				return err
+			}
+
+			if err := s.checkLimits(ctx, from, to, pending.Amount, pending.Id); err != nil {
+				return err
+			}
+
+			transfer := &model.Transfer{
+				Id:           pending.Id,
+				FromEmployee: from.Id,
+				ToEmployee:   to.Id,
+				Amount:       pending.Amount,
+				Message:      pending.Message,
+				Category:     pending.Category,
+			}
+
+			if err := s.ledger.Transfer(ctx, transfer.Id, from, to, pending.Amount); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if err := s.transferRepo.Save(ctx, transfer); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		})
+}
+
+// RejectPending releases the hold; no coins move.
+func (s *TransferService) RejectPending(
+	ctx context.Context, approverUsername string, transferId uuid.UUID) (*model.PendingTransfer, error) {
+	const op = "service.TransferService.RejectPending"
+
+	return s.resolvePending(ctx, approverUsername, transferId, model.PendingTransferRejected,
+		func(ctx context.Context, _ *model.PendingTransfer, from *model.Employee, _ *model.Employee) error {
+			if err := s.employeeRepo.UpdateByUsername(ctx, from.Username, from); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			return nil
+		})
+}
+
+// resolvePending locks the pending transfer and both parties, checks the approver, releases the hold on the sender
+// and lets settle persist the outcome before the transfer is marked resolved.
+func (s *TransferService) resolvePending(
+	ctx context.Context,
+	approverUsername string,
+	transferId uuid.UUID,
+	status model.PendingTransferStatus,
+	settle func(ctx context.Context, pending *model.PendingTransfer, from *model.Employee, to *model.Employee) error,
+) (*model.PendingTransfer, error) {
+	const op = "service.TransferService.resolvePending"
+
+	var pending *model.PendingTransfer
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		pending, err = s.pendingTransferRepo.FindByIdForUpdate(ctx, transferId)
 		if err != nil {
-			if errors.Is(err, repo.ErrEmployeeNotFound) {
-				return ErrReceiverNotFound
+			if errors.Is(err, repo.ErrPendingTransferNotFound) {
+				return ErrPendingTransferNotFound
 			}
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		fromEmployee.Balance -= amount
-		toEmployee.Balance += amount
+		if pending.Status != model.PendingTransferPending {
+			return ErrPendingTransferNotPending
+		}
 
-		err = s.employeeRepo.UpdateByUsername(ctx, fromUsername, fromEmployee)
+		approver, err := s.employeeRepo.FindByUsername(ctx, approverUsername)
 		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeNotFound) {
+				return ErrEmployeeNotFound
+			}
 			return fmt.Errorf("%s: %w", op, err)
 		}
-		err = s.employeeRepo.UpdateByUsername(ctx, toUsername, toEmployee)
+
+		fromEmployee, toEmployee, err := lockEmployees(ctx, s.employeeRepo, pending.From, pending.To)
 		if err != nil {
+			return err
+		}
+
+		if !canApprove(approver, fromEmployee) {
+			return ErrNotApprover
+		}
+
+		fromEmployee.Held -= pending.Amount
+		if err = settle(ctx, pending, fromEmployee, toEmployee); err != nil {
+			return err
+		}
+
+		if err = s.pendingTransferRepo.Resolve(ctx, pending.Id, status, approver.Id); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		err = s.transferRepo.Save(ctx, &model.Transfer{
-			Id:           uuid.New(),
-			FromEmployee: fromEmployee.Id,
-			ToEmployee:   toEmployee.Id,
-			Amount:       amount,
-		})
+		resolvedAt := time.Now()
+		pending.Status = status
+		pending.ApproverId = &approver.Id
+		pending.ResolvedAt = &resolvedAt
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+// CheckSale applies the transfer limits to a marketplace sale, which moves coins between employees just like
+// a transfer. A sale can't wait for approval, so one above the approval threshold is refused.
+func (s *TransferService) CheckSale(
+	ctx context.Context, buyer *model.Employee, seller *model.Employee, amount int) error {
+	if s.requiresApproval(amount) {
+		return ErrSaleRequiresApproval
+	}
+
+	return s.checkLimits(ctx, buyer, seller, amount, uuid.Nil)
+}
+
+// checkLimits must run after the sender is locked, so concurrent transfers from the same sender can't both pass.
+// Batch lines are checked one by one and see the lines saved before them. excludePending is the pending transfer
+// being approved, so its own hold isn't counted twice.
+func (s *TransferService) checkLimits(
+	ctx context.Context, from *model.Employee, to *model.Employee, amount int, excludePending uuid.UUID) error {
+	const op = "service.TransferService.checkLimits"
+
+	if !s.limits.Enabled() {
+		return nil
+	}
+
+	now := time.Now()
+	velocity, err := s.transferRepo.FindVelocity(ctx, model.VelocityQuery{
+		FromEmployee:   from.Id,
+		ToEmployee:     to.Id,
+		DaySince:       now.Add(-24 * time.Hour),
+		HourSince:      now.Add(-time.Hour),
+		MonthSince:     now.AddDate(0, 0, -30),
+		ExcludePending: excludePending,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if s.limits.HourlyCount > 0 && velocity.HourlyCount >= s.limits.HourlyCount {
+		return &TransferLimitError{Limit: LimitHourlyCount, Remaining: 0}
+	}
+	if s.limits.DailyAmount > 0 && velocity.DailyAmount+amount > s.limits.DailyAmount {
+		return &TransferLimitError{
+			Limit:     LimitDailyAmount,
+			Remaining: max(s.limits.DailyAmount-velocity.DailyAmount, 0),
+		}
+	}
+	if s.limits.MonthlyRecipientAmount > 0 && velocity.MonthlyRecipientAmount+amount > s.limits.MonthlyRecipientAmount {
+		return &TransferLimitError{
+			Limit:     LimitMonthlyRecipientAmount,
+			Remaining: max(s.limits.MonthlyRecipientAmount-velocity.MonthlyRecipientAmount, 0),
+		}
+	}
+
+	return nil
+}
+
+func (s *TransferService) requiresApproval(amount int) bool {
+	return s.approvalThreshold > 0 && amount > s.approvalThreshold
+}
+
+// hold reserves amount on the locked sender and records the transfer as pending approval.
+func (s *TransferService) hold(
+	ctx context.Context, from *model.Employee, to *model.Employee, amount int, memo model.TransferMemo,
+) (*model.PendingTransfer, error) {
+	const op = "service.TransferService.hold"
+
+	if from.Available() < amount {
+		return nil, ErrNotEnoughCoins
+	}
+
+	from.Held += amount
+	if err := s.employeeRepo.UpdateByUsername(ctx, from.Username, from); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pending := &model.PendingTransfer{
+		Id:           uuid.New(),
+		FromEmployee: from.Id,
+		From:         from.Username,
+		ToEmployee:   to.Id,
+		To:           to.Username,
+		Amount:       amount,
+		Message:      memo.Message,
+		Category:     memo.Category,
+		Status:       model.PendingTransferPending,
+	}
+
+	if err := s.pendingTransferRepo.Save(ctx, pending); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pending, nil
+}
+
+// canApprove allows admins and the sender's manager, but never the sender.
+func canApprove(approver *model.Employee, sender *model.Employee) bool {
+	if approver.Id == sender.Id {
+		return false
+	}
+	return approver.Role == model.RoleAdmin || (sender.ManagerId != nil && *sender.ManagerId == approver.Id)
+}
+
+func validateMemo(memo model.TransferMemo) (model.TransferMemo, error) {
+	memo.Message = strings.TrimSpace(memo.Message)
+	if utf8.RuneCountInString(memo.Message) > model.MaxTransferMessageLength {
+		return memo, ErrTransferMessageTooLong
+	}
+
+	if memo.Category != "" && !memo.Category.Valid() {
+		return memo, ErrInvalidTransferCategory
+	}
+
+	return memo, nil
+}
+
+// normalizeTransferLines merges repeated recipients, sorts lines by username and returns the batch total.
+func normalizeTransferLines(
+	fromUsername string, lines []model.TransferLine) ([]model.TransferLine, int, error) {
+	if len(lines) == 0 {
+		return nil, 0, ErrEmptyTransferBatch
+	}
+
+	amounts := make(map[string]int, len(lines))
+	total := 0
+	for _, line := range lines {
+		if line.ToUser == fromUsername {
+			return nil, 0, ErrTransferToSameEmployee
+		}
+		if line.Amount <= 0 {
+			return nil, 0, ErrNonPositiveTransferAmount
+		}
+		amounts[line.ToUser] += line.Amount
+		total += line.Amount
+	}
+
+	normalized := make([]model.TransferLine, 0, len(amounts))
+	for toUser, amount := range amounts {
+		normalized = append(normalized, model.TransferLine{ToUser: toUser, Amount: amount})
+	}
+	slices.SortFunc(normalized, func(a, b model.TransferLine) int {
+		return strings.Compare(a.ToUser, b.ToUser)
+	})
+
+	return normalized, total, nil
+}
+
+// lockBatchEmployees locks the sender and every recipient in username order, the same order lockEmployees uses.
+func lockBatchEmployees(
+	ctx context.Context, employeeRepo EmployeeRepo, fromUsername string, lines []model.TransferLine,
+) (map[string]*model.Employee, error) {
+	const op = "service.lockBatchEmployees"
+
+	usernames := make([]string, 0, len(lines)+1)
+	usernames = append(usernames, fromUsername)
+	for _, line := range lines {
+		usernames = append(usernames, line.ToUser)
+	}
+	slices.Sort(usernames)
+
+	employees := make(map[string]*model.Employee, len(usernames))
+	for _, username := range usernames {
+		employee, err := employeeRepo.FindByUsernameForUpdate(ctx, username)
 		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeNotFound) {
+				if username == fromUsername {
+					return nil, ErrSenderNotFound
+				}
+				return nil, fmt.Errorf("%w: %s", ErrReceiverNotFound, username)
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		employees[username] = employee
+	}
+
+	return employees, nil
+}
+
+// lockEmployees locks both parties of a transfer in username order,
+// so concurrent mirrored transfers always acquire row locks in the same order and cannot deadlock.
+func lockEmployees(
+	ctx context.Context, employeeRepo EmployeeRepo, fromUsername string, toUsername string,
+) (*model.Employee, *model.Employee, error) {
+	const op = "service.lockEmployees"
+
+	var fromEmployee, toEmployee *model.Employee
+
+	lockSender := func() error {
+		employee, err := employeeRepo.FindByUsernameForUpdate(ctx, fromUsername)
+		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeNotFound) {
+				return ErrSenderNotFound
+			}
 			return fmt.Errorf("%s: %w", op, err)
 		}
+		fromEmployee = employee
+		return nil
+	}
 
+	lockReceiver := func() error {
+		employee, err := employeeRepo.FindByUsernameForUpdate(ctx, toUsername)
+		if err != nil {
+			if errors.Is(err, repo.ErrEmployeeNotFound) {
+				return ErrReceiverNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		toEmployee = employee
 		return nil
-	})
+	}
+
+	first, second := lockSender, lockReceiver
+	if toUsername < fromUsername {
+		first, second = lockReceiver, lockSender
+	}
+
+	if err := first(); err != nil {
+		return nil, nil, err
+	}
+	if err := second(); err != nil {
+		return nil, nil, err
+	}
 
-	return err
+	return fromEmployee, toEmployee, nil
 }
@@ -8,7 +8,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestTransferService_SendCoins(t *testing.T) {
@@ -16,71 +18,131 @@ func TestTransferService_SendCoins(t *testing.T) {
 
 	tests := []struct {
 		name          string
-		setup         func(*mockEmployeeRepo, *mockTransferRepo)
+		memo          model.TransferMemo
+		setup         func(*mockEmployeeRepo, *mockTransferRepo, *mockLedger)
 		expectedError error
 	}{
 		{
 			name: "successful transfer",
-			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo) {
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
 				sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: 1000}
 				receiver := &model.Employee{Id: uuid.New(), Username: "receiver", Balance: 500}
 
-				mer.On("FindByUsername", mock.Anything, "sender").
+				mer.On("FindByUsernameForUpdate", mock.Anything, "sender").
 					Return(sender, nil)
-				mer.On("FindByUsername", mock.Anything, "receiver").
+				mer.On("FindByUsernameForUpdate", mock.Anything, "receiver").
 					Return(receiver, nil)
-				mer.On("UpdateByUsername", mock.Anything, "sender", mock.Anything).
-					Return(nil)
-				mer.On("UpdateByUsername", mock.Anything, "receiver", mock.Anything).
+				ml.On("Transfer", mock.Anything, mock.Anything, sender, receiver, 200).
 					Return(nil)
 				mtr.On("Save", mock.Anything, mock.Anything).
 					Return(nil)
 			},
 			expectedError: nil,
 		},
+		{
+			name: "transfer with memo",
+			memo: model.TransferMemo{Message: "  thanks for the review ", Category: model.CategoryThanks},
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
+				sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: 1000}
+				receiver := &model.Employee{Id: uuid.New(), Username: "receiver", Balance: 500}
+
+				mer.On("FindByUsernameForUpdate", mock.Anything, "sender").
+					Return(sender, nil)
+				mer.On("FindByUsernameForUpdate", mock.Anything, "receiver").
+					Return(receiver, nil)
+				ml.On("Transfer", mock.Anything, mock.Anything, sender, receiver, 200).
+					Return(nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(t *model.Transfer) bool {
+					return t.Message == "thanks for the review" && t.Category == model.CategoryThanks
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "message too long",
+			memo:          model.TransferMemo{Message: strings.Repeat("я", model.MaxTransferMessageLength+1)},
+			setup:         func(*mockEmployeeRepo, *mockTransferRepo, *mockLedger) {},
+			expectedError: ErrTransferMessageTooLong,
+		},
+		{
+			name:          "unknown category",
+			memo:          model.TransferMemo{Category: "bribe"},
+			setup:         func(*mockEmployeeRepo, *mockTransferRepo, *mockLedger) {},
+			expectedError: ErrInvalidTransferCategory,
+		},
 		{
 			name: "sender not found",
-			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo) {
-				mer.On("FindByUsername", mock.Anything, "sender").
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
+				receiver := &model.Employee{Id: uuid.New(), Username: "receiver", Balance: 500}
+
+				mer.On("FindByUsernameForUpdate", mock.Anything, "receiver").
+					Return(receiver, nil)
+				mer.On("FindByUsernameForUpdate", mock.Anything, "sender").
 					Return(nil, repo.ErrEmployeeNotFound)
 			},
 			expectedError: ErrSenderNotFound,
 		},
 		{
 			name: "receiver not found",
-			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo) {
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "receiver").
+					Return(nil, repo.ErrEmployeeNotFound)
+			},
+			expectedError: ErrReceiverNotFound,
+		},
+		{
+			name: "frozen sender",
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
+				sender := &model.Employee{
+					Id: uuid.New(), Username: "sender", Balance: 1000, Status: model.EmployeeFrozen}
+				receiver := &model.Employee{Id: uuid.New(), Username: "receiver", Balance: 500}
+
+				mer.On("FindByUsernameForUpdate", mock.Anything, "sender").
+					Return(sender, nil)
+				mer.On("FindByUsernameForUpdate", mock.Anything, "receiver").
+					Return(receiver, nil)
+			},
+			expectedError: ErrAccountFrozen,
+		},
+		{
+			name: "deactivated receiver",
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
 				sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: 1000}
+				receiver := &model.Employee{
+					Id: uuid.New(), Username: "receiver", Balance: 500, Status: model.EmployeeDeactivated}
 
-				mer.On("FindByUsername", mock.Anything, "sender").
+				mer.On("FindByUsernameForUpdate", mock.Anything, "sender").
 					Return(sender, nil)
-				mer.On("FindByUsername", mock.Anything, "receiver").
-					Return(nil, repo.ErrEmployeeNotFound)
+				mer.On("FindByUsernameForUpdate", mock.Anything, "receiver").
+					Return(receiver, nil)
 			},
-			expectedError: ErrReceiverNotFound,
+			expectedError: ErrReceiverNotActive,
 		},
 		{
 			name: "not enough balance",
-			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo) {
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
 				sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: 100}
+				receiver := &model.Employee{Id: uuid.New(), Username: "receiver", Balance: 500}
 
-				mer.On("FindByUsername", mock.Anything, "sender").
+				mer.On("FindByUsernameForUpdate", mock.Anything, "sender").
 					Return(sender, nil)
+				mer.On("FindByUsernameForUpdate", mock.Anything, "receiver").
+					Return(receiver, nil)
+				ml.On("Transfer", mock.Anything, mock.Anything, sender, receiver, 200).
+					Return(ErrNotEnoughCoins)
 			},
 			expectedError: ErrNotEnoughCoins,
 		},
 		{
 			name: "error saving transfer",
-			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo) {
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
 				sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: 1000}
 				receiver := &model.Employee{Id: uuid.New(), Username: "receiver", Balance: 500}
 
-				mer.On("FindByUsername", mock.Anything, "sender").
+				mer.On("FindByUsernameForUpdate", mock.Anything, "sender").
 					Return(sender, nil)
-				mer.On("FindByUsername", mock.Anything, "receiver").
+				mer.On("FindByUsernameForUpdate", mock.Anything, "receiver").
 					Return(receiver, nil)
-				mer.On("UpdateByUsername", mock.Anything, "sender", mock.Anything).
-					Return(nil)
-				mer.On("UpdateByUsername", mock.Anything, "receiver", mock.Anything).
+				ml.On("Transfer", mock.Anything, mock.Anything, sender, receiver, 200).
 					Return(nil)
 				mtr.On("Save", mock.Anything, mock.Anything).
 					Return(errors.New("save error"))
@@ -93,11 +155,14 @@ func TestTransferService_SendCoins(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			mockEmployeeRepo := new(mockEmployeeRepo)
 			mockTransferRepo := new(mockTransferRepo)
-			transferService := NewTransferService(mockTrManager, mockEmployeeRepo, mockTransferRepo)
+			mockLedger := new(mockLedger)
+			transferService := NewTransferService(
+				mockTrManager, mockEmployeeRepo, mockTransferRepo, new(mockPendingTransferRepo), mockLedger, 0,
+				model.TransferLimits{})
 
-			tc.setup(mockEmployeeRepo, mockTransferRepo)
+			tc.setup(mockEmployeeRepo, mockTransferRepo, mockLedger)
 
-			err := transferService.SendCoins(context.Background(), "sender", "receiver", 200)
+			_, err := transferService.SendCoins(context.Background(), "sender", "receiver", 200, tc.memo)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -108,6 +173,281 @@ func TestTransferService_SendCoins(t *testing.T) {
 
 			mockEmployeeRepo.AssertExpectations(t)
 			mockTransferRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferService_SendCoins_NonPositiveAmount(t *testing.T) {
+	transferService := NewTransferService(new(mockTransactionManager), new(mockEmployeeRepo), new(mockTransferRepo),
+		new(mockPendingTransferRepo), new(mockLedger), 0, model.TransferLimits{})
+
+	for _, amount := range []int{0, -10} {
+		_, err := transferService.SendCoins(context.Background(), "sender", "receiver", amount, model.TransferMemo{})
+		assert.ErrorIs(t, err, ErrNonPositiveTransferAmount)
+	}
+}
+
+func TestTransferService_Kudos(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	kudos := []model.Kudos{
+		{Id: uuid.New(), From: "alice", To: "bob", Amount: 10, Category: model.CategoryThanks, CreatedAt: createdAt},
+		{Id: uuid.New(), From: "bob", To: "carol", Amount: 5, Message: "great demo", CreatedAt: createdAt},
+		{Id: uuid.New(), From: "carol", To: "alice", Amount: 1, Category: model.CategoryHelp, CreatedAt: createdAt},
+	}
+
+	tests := []struct {
+		name           string
+		filter         model.KudosFilter
+		setup          func(*mockTransferRepo)
+		expectedLen    int
+		expectedCursor *model.Cursor
+		expectedError  error
+	}{
+		{
+			name:   "last page",
+			filter: model.KudosFilter{Category: model.CategoryThanks},
+			setup: func(mtr *mockTransferRepo) {
+				mtr.On("FindKudos", mock.Anything, model.KudosFilter{Category: model.CategoryThanks, Limit: 21}).
+					Return(kudos[:1], nil)
+			},
+			expectedLen: 1,
+		},
+		{
+			name:   "page with next cursor",
+			filter: model.KudosFilter{Limit: 2},
+			setup: func(mtr *mockTransferRepo) {
+				mtr.On("FindKudos", mock.Anything, model.KudosFilter{Limit: 3}).Return(kudos, nil)
+			},
+			expectedLen:    2,
+			expectedCursor: &model.Cursor{CreatedAt: createdAt, Id: kudos[1].Id},
+		},
+		{
+			name:          "unknown category",
+			filter:        model.KudosFilter{Category: "bribe"},
+			setup:         func(*mockTransferRepo) {},
+			expectedError: ErrInvalidTransferCategory,
+		},
+		{
+			name:   "repository error",
+			filter: model.KudosFilter{},
+			setup: func(mtr *mockTransferRepo) {
+				mtr.On("FindKudos", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTransferRepo := new(mockTransferRepo)
+			transferService := NewTransferService(
+				mockTrManager, new(mockEmployeeRepo), mockTransferRepo, new(mockPendingTransferRepo), new(mockLedger),
+				0, model.TransferLimits{})
+
+			tc.setup(mockTransferRepo)
+
+			page, err := transferService.Kudos(context.Background(), tc.filter)
+
+			if tc.expectedError != nil {
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, page.Entries, tc.expectedLen)
+				assert.Equal(t, tc.expectedCursor, page.NextCursor)
+			}
+
+			mockTransferRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferService_SendCoinsBatch(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+
+	tests := []struct {
+		name          string
+		lines         []model.TransferLine
+		setup         func(*mockEmployeeRepo, *mockTransferRepo, *mockLedger)
+		expectedLines []model.TransferLine
+		expectedError error
+	}{
+		{
+			name: "merges repeated recipients and locks in username order",
+			lines: []model.TransferLine{
+				{ToUser: "carol", Amount: 10}, {ToUser: "bob", Amount: 20}, {ToUser: "carol", Amount: 5},
+			},
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
+				sender := &model.Employee{Id: uuid.New(), Username: "lead", Balance: 100}
+				bob := &model.Employee{Id: uuid.New(), Username: "bob"}
+				carol := &model.Employee{Id: uuid.New(), Username: "carol"}
+
+				var locked []string
+				for _, employee := range []*model.Employee{bob, carol, sender} {
+					username := employee.Username
+					mer.On("FindByUsernameForUpdate", mock.Anything, username).
+						Run(func(mock.Arguments) { locked = append(locked, username) }).
+						Return(employee, nil).Once()
+				}
+				ml.On("Transfer", mock.Anything, mock.Anything, sender, bob, 20).
+					Run(func(mock.Arguments) { assert.Equal(t, []string{"bob", "carol", "lead"}, locked) }).
+					Return(nil)
+				ml.On("Transfer", mock.Anything, mock.Anything, sender, carol, 15).Return(nil)
+				mtr.On("Save", mock.Anything, mock.Anything).Return(nil).Twice()
+			},
+			expectedLines: []model.TransferLine{{ToUser: "bob", Amount: 20}, {ToUser: "carol", Amount: 15}},
+		},
+		{
+			name:          "empty batch",
+			setup:         func(*mockEmployeeRepo, *mockTransferRepo, *mockLedger) {},
+			expectedError: ErrEmptyTransferBatch,
+		},
+		{
+			name:          "sender among recipients",
+			lines:         []model.TransferLine{{ToUser: "bob", Amount: 1}, {ToUser: "lead", Amount: 1}},
+			setup:         func(*mockEmployeeRepo, *mockTransferRepo, *mockLedger) {},
+			expectedError: ErrTransferToSameEmployee,
+		},
+		{
+			name:          "non-positive amount",
+			lines:         []model.TransferLine{{ToUser: "bob", Amount: 0}},
+			setup:         func(*mockEmployeeRepo, *mockTransferRepo, *mockLedger) {},
+			expectedError: ErrNonPositiveTransferAmount,
+		},
+		{
+			name:  "unknown recipient",
+			lines: []model.TransferLine{{ToUser: "bob", Amount: 1}, {ToUser: "ghost", Amount: 1}},
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "bob").
+					Return(&model.Employee{Id: uuid.New(), Username: "bob"}, nil)
+				mer.On("FindByUsernameForUpdate", mock.Anything, "ghost").
+					Return(nil, repo.ErrEmployeeNotFound)
+			},
+			expectedError: ErrReceiverNotFound,
+		},
+		{
+			name:  "total exceeds balance",
+			lines: []model.TransferLine{{ToUser: "bob", Amount: 60}, {ToUser: "carol", Amount: 60}},
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
+				mer.On("FindByUsernameForUpdate", mock.Anything, "bob").
+					Return(&model.Employee{Id: uuid.New(), Username: "bob"}, nil)
+				mer.On("FindByUsernameForUpdate", mock.Anything, "carol").
+					Return(&model.Employee{Id: uuid.New(), Username: "carol"}, nil)
+				mer.On("FindByUsernameForUpdate", mock.Anything, "lead").
+					Return(&model.Employee{Id: uuid.New(), Username: "lead", Balance: 100}, nil)
+			},
+			expectedError: ErrNotEnoughCoins,
+		},
+		{
+			name:  "failed line aborts the batch",
+			lines: []model.TransferLine{{ToUser: "bob", Amount: 10}, {ToUser: "carol", Amount: 10}},
+			setup: func(mer *mockEmployeeRepo, mtr *mockTransferRepo, ml *mockLedger) {
+				sender := &model.Employee{Id: uuid.New(), Username: "lead", Balance: 100}
+				bob := &model.Employee{Id: uuid.New(), Username: "bob"}
+				carol := &model.Employee{Id: uuid.New(), Username: "carol"}
+				mer.On("FindByUsernameForUpdate", mock.Anything, "bob").Return(bob, nil)
+				mer.On("FindByUsernameForUpdate", mock.Anything, "carol").Return(carol, nil)
+				mer.On("FindByUsernameForUpdate", mock.Anything, "lead").Return(sender, nil)
+				ml.On("Transfer", mock.Anything, mock.Anything, sender, bob, 10).Return(nil)
+				mtr.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+				ml.On("Transfer", mock.Anything, mock.Anything, sender, carol, 10).Return(errors.New("ledger error"))
+			},
+			expectedError: errors.New("ledger error"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockTransferRepo := new(mockTransferRepo)
+			mockLedger := new(mockLedger)
+			transferService := NewTransferService(
+				mockTrManager, mockEmployeeRepo, mockTransferRepo, new(mockPendingTransferRepo), mockLedger, 0,
+				model.TransferLimits{})
+
+			tc.setup(mockEmployeeRepo, mockTransferRepo, mockLedger)
+
+			lines, err := transferService.SendCoinsBatch(context.Background(), "lead", tc.lines, model.TransferMemo{})
+
+			if tc.expectedError != nil {
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedLines, lines)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockTransferRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferService_SendCoins_Limits(t *testing.T) {
+	mockTrManager := new(mockTransactionManager)
+	limits := model.TransferLimits{DailyAmount: 300, HourlyCount: 5, MonthlyRecipientAmount: 500}
+
+	tests := []struct {
+		name          string
+		velocity      model.TransferVelocity
+		expectedError *TransferLimitError
+	}{
+		{
+			name:     "within limits",
+			velocity: model.TransferVelocity{DailyAmount: 100, HourlyCount: 4, MonthlyRecipientAmount: 300},
+		},
+		{
+			name:          "daily amount exceeded",
+			velocity:      model.TransferVelocity{DailyAmount: 150},
+			expectedError: &TransferLimitError{Limit: LimitDailyAmount, Remaining: 150},
+		},
+		{
+			name:          "too many transfers this hour",
+			velocity:      model.TransferVelocity{HourlyCount: 5},
+			expectedError: &TransferLimitError{Limit: LimitHourlyCount, Remaining: 0},
+		},
+		{
+			name:          "recipient got too much this month",
+			velocity:      model.TransferVelocity{MonthlyRecipientAmount: 400},
+			expectedError: &TransferLimitError{Limit: LimitMonthlyRecipientAmount, Remaining: 100},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mockEmployeeRepo)
+			mockTransferRepo := new(mockTransferRepo)
+			mockLedger := new(mockLedger)
+			transferService := NewTransferService(
+				mockTrManager, mockEmployeeRepo, mockTransferRepo, new(mockPendingTransferRepo), mockLedger, 0, limits)
+
+			sender := &model.Employee{Id: uuid.New(), Username: "sender", Balance: 1000}
+			receiver := &model.Employee{Id: uuid.New(), Username: "receiver"}
+			mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "sender").Return(sender, nil)
+			mockEmployeeRepo.On("FindByUsernameForUpdate", mock.Anything, "receiver").Return(receiver, nil)
+			mockTransferRepo.On("FindVelocity", mock.Anything, mock.MatchedBy(func(q model.VelocityQuery) bool {
+				return q.FromEmployee == sender.Id && q.ToEmployee == receiver.Id &&
+					q.HourSince.After(q.DaySince) && q.DaySince.After(q.MonthSince)
+			})).Return(&tc.velocity, nil)
+			if tc.expectedError == nil {
+				mockLedger.On("Transfer", mock.Anything, mock.Anything, sender, receiver, 200).Return(nil)
+				mockTransferRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			_, err := transferService.SendCoins(context.Background(), "sender", "receiver", 200, model.TransferMemo{})
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, ErrTransferLimitExceeded)
+				var limitErr *TransferLimitError
+				assert.ErrorAs(t, err, &limitErr)
+				assert.Equal(t, tc.expectedError, limitErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockTransferRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
 		})
 	}
 }
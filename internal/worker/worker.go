@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"avito-shop/internal/lib/logger/sl"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job is a piece of background work that runs every Interval until the runner is stopped.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Runner struct {
+	log  *slog.Logger
+	jobs []Job
+	wg   sync.WaitGroup
+}
+
+// NewRunner panics on a job without a positive interval, which time.NewTicker would only reject once the job starts.
+func NewRunner(log *slog.Logger, jobs ...Job) *Runner {
+	for _, job := range jobs {
+		if job.Interval <= 0 {
+			panic(fmt.Errorf("background job %s: interval must be positive, got %s", job.Name, job.Interval))
+		}
+	}
+
+	return &Runner{
+		log:  log,
+		jobs: jobs,
+	}
+}
+
+// Start launches every job in its own goroutine; the jobs stop when ctx is cancelled.
+func (r *Runner) Start(ctx context.Context) {
+	for _, job := range r.jobs {
+		r.wg.Add(1)
+		go func(job Job) {
+			defer r.wg.Done()
+			r.loop(ctx, job)
+		}(job)
+	}
+}
+
+// Wait blocks until every job has returned after the context passed to Start was cancelled.
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Runner) loop(ctx context.Context, job Job) {
+	log := r.log.With(slog.String("job", job.Name))
+	log.Info("starting background job", slog.Duration("interval", job.Interval))
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("background job stopped")
+			return
+		case <-ticker.C:
+			if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+				log.Error("background job failed", sl.Err(err))
+			}
+		}
+	}
+}
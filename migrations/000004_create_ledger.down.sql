@@ -0,0 +1,4 @@
+drop index if exists ledger_entries_employee_idx;
+drop index if exists ledger_entries_operation_idx;
+
+drop table ledger_entries;
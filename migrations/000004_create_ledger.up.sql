@@ -0,0 +1,31 @@
+create table if not exists ledger_entries
+(
+    id             uuid primary key,
+    operation_id   uuid        not null,
+    operation_type text        not null,
+    account        text        not null,
+    employee_id    uuid,
+    direction      text        not null,
+    amount         int         not null,
+    created_at     timestamptz not null default now(),
+
+    foreign key (employee_id) references employees (id),
+    check (direction in ('debit', 'credit')),
+    check (amount > 0),
+    check ((account = 'employee') = (employee_id is not null))
+);
+
+create index if not exists ledger_entries_operation_idx on ledger_entries (operation_id);
+create index if not exists ledger_entries_employee_idx on ledger_entries (employee_id);
+
+-- opening balances of employees registered before the ledger existed
+with opening as (select id as employee_id, balance, gen_random_uuid() as operation_id
+                 from employees
+                 where balance > 0)
+insert
+into ledger_entries (id, operation_id, operation_type, account, employee_id, direction, amount)
+select gen_random_uuid(), operation_id, 'initial_grant', 'emission', null, 'debit', balance
+from opening
+union all
+select gen_random_uuid(), operation_id, 'initial_grant', 'employee', employee_id, 'credit', balance
+from opening;
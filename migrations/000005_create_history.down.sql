@@ -0,0 +1,10 @@
+drop view if exists employee_history;
+
+drop index if exists purchases_employee_created_at_idx;
+drop index if exists transfers_to_employee_created_at_idx;
+drop index if exists transfers_from_employee_created_at_idx;
+
+drop table purchases;
+
+alter table transfers
+    drop column if exists created_at;
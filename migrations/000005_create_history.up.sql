@@ -0,0 +1,56 @@
+alter table transfers
+    add column if not exists created_at timestamptz not null default now();
+
+create table if not exists purchases
+(
+    id          uuid primary key,
+    employee_id uuid        not null,
+    item_id     uuid        not null,
+    quantity    int         not null,
+    price       int         not null,
+    created_at  timestamptz not null default now(),
+
+    foreign key (employee_id) references employees (id),
+    foreign key (item_id) references items (id)
+);
+
+create index if not exists transfers_from_employee_created_at_idx on transfers (from_employee, created_at);
+create index if not exists transfers_to_employee_created_at_idx on transfers (to_employee, created_at);
+create index if not exists purchases_employee_created_at_idx on purchases (employee_id, created_at);
+
+create or replace view employee_history as
+select t.id,
+       t.from_employee as employee_id,
+       'transfer'      as type,
+       'out'           as direction,
+       e.username      as counterparty,
+       null::text      as item,
+       0               as quantity,
+       t.amount,
+       t.created_at
+from transfers t
+         join employees e on e.id = t.to_employee
+union all
+select t.id,
+       t.to_employee,
+       'transfer',
+       'in',
+       e.username,
+       null::text,
+       0,
+       t.amount,
+       t.created_at
+from transfers t
+         join employees e on e.id = t.from_employee
+union all
+select p.id,
+       p.employee_id,
+       'purchase',
+       'out',
+       null::text,
+       i.name,
+       p.quantity,
+       p.price * p.quantity,
+       p.created_at
+from purchases p
+         join items i on i.id = p.item_id;
@@ -0,0 +1,12 @@
+create table if not exists idempotency_keys
+(
+    employee_id     uuid        not null,
+    key             text        not null,
+    fingerprint     text        not null,
+    response_status int,
+    response_body   bytea,
+    created_at      timestamptz not null default now(),
+
+    primary key (employee_id, key),
+    foreign key (employee_id) references employees (id)
+);
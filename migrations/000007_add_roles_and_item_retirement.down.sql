@@ -0,0 +1,5 @@
+alter table items
+    drop column if exists retired;
+
+alter table employees
+    drop column if exists role;
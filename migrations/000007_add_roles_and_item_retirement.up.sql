@@ -0,0 +1,5 @@
+alter table employees
+    add column role text not null default 'employee' check (role in ('employee', 'admin'));
+
+alter table items
+    add column retired boolean not null default false;
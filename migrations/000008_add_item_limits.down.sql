@@ -0,0 +1,5 @@
+drop index if exists purchases_employee_item_idx;
+
+alter table items
+    drop column if exists max_per_employee,
+    drop column if exists stock;
@@ -0,0 +1,5 @@
+alter table items
+    add column stock            int null check (stock >= 0),
+    add column max_per_employee int null check (max_per_employee > 0);
+
+create index if not exists purchases_employee_item_idx on purchases (employee_id, item_id);
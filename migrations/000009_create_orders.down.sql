@@ -0,0 +1,6 @@
+drop index if exists purchases_order_idx;
+
+alter table purchases
+    drop column if exists order_id;
+
+drop table if exists orders;
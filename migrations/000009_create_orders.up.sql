@@ -0,0 +1,28 @@
+create table if not exists orders
+(
+    id          uuid primary key,
+    employee_id uuid        not null,
+    total       int         not null,
+    created_at  timestamptz not null default now(),
+
+    foreign key (employee_id) references employees (id)
+);
+
+create index if not exists orders_employee_created_at_idx on orders (employee_id, created_at);
+
+alter table purchases
+    add column if not exists order_id uuid null references orders (id);
+
+insert into orders (id, employee_id, total, created_at)
+select id, employee_id, price * quantity, created_at
+from purchases
+where order_id is null;
+
+update purchases
+set order_id = id
+where order_id is null;
+
+alter table purchases
+    alter column order_id set not null;
+
+create index if not exists purchases_order_idx on purchases (order_id);
@@ -0,0 +1,45 @@
+drop view if exists employee_history;
+
+create or replace view employee_history as
+select t.id,
+       t.from_employee as employee_id,
+       'transfer'      as type,
+       'out'           as direction,
+       e.username      as counterparty,
+       null::text      as item,
+       0               as quantity,
+       t.amount,
+       t.created_at
+from transfers t
+         join employees e on e.id = t.to_employee
+union all
+select t.id,
+       t.to_employee,
+       'transfer',
+       'in',
+       e.username,
+       null::text,
+       0,
+       t.amount,
+       t.created_at
+from transfers t
+         join employees e on e.id = t.from_employee
+union all
+select p.id,
+       p.employee_id,
+       'purchase',
+       'out',
+       null::text,
+       i.name,
+       p.quantity,
+       p.price * p.quantity,
+       p.created_at
+from purchases p
+         join items i on i.id = p.item_id;
+
+drop index if exists orders_status_created_at_idx;
+
+alter table orders
+    drop column if exists cancelled_at,
+    drop column if exists updated_at,
+    drop column if exists status;
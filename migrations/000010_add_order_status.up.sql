@@ -0,0 +1,60 @@
+-- orders placed before fulfilment tracking existed have already been handed out
+alter table orders
+    add column status       text        not null default 'delivered'
+        check (status in ('placed', 'ready_for_pickup', 'delivered', 'cancelled')),
+    add column updated_at   timestamptz not null default now(),
+    add column cancelled_at timestamptz null;
+
+alter table orders
+    alter column status set default 'placed';
+
+create index if not exists orders_status_created_at_idx on orders (status, created_at);
+
+create or replace view employee_history as
+select t.id,
+       t.from_employee as employee_id,
+       'transfer'      as type,
+       'out'           as direction,
+       e.username      as counterparty,
+       null::text      as item,
+       0               as quantity,
+       t.amount,
+       t.created_at
+from transfers t
+         join employees e on e.id = t.to_employee
+union all
+select t.id,
+       t.to_employee,
+       'transfer',
+       'in',
+       e.username,
+       null::text,
+       0,
+       t.amount,
+       t.created_at
+from transfers t
+         join employees e on e.id = t.from_employee
+union all
+select p.id,
+       p.employee_id,
+       'purchase',
+       'out',
+       null::text,
+       i.name,
+       p.quantity,
+       p.price * p.quantity,
+       p.created_at
+from purchases p
+         join items i on i.id = p.item_id
+union all
+select o.id,
+       o.employee_id,
+       'refund',
+       'in',
+       null::text,
+       null::text,
+       0,
+       o.total,
+       o.cancelled_at
+from orders o
+where o.status = 'cancelled';
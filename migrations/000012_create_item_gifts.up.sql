@@ -0,0 +1,106 @@
+create table if not exists item_gifts
+(
+    id            uuid primary key,
+    from_employee uuid        not null,
+    to_employee   uuid        not null,
+    item_id       uuid        not null,
+    quantity      int         not null,
+    created_at    timestamptz not null default now(),
+
+    foreign key (from_employee) references employees (id),
+    foreign key (to_employee) references employees (id),
+    foreign key (item_id) references items (id),
+    check (quantity > 0),
+    check (from_employee <> to_employee)
+);
+
+create index if not exists item_gifts_from_employee_created_at_idx on item_gifts (from_employee, created_at);
+create index if not exists item_gifts_to_employee_created_at_idx on item_gifts (to_employee, created_at);
+
+create or replace view employee_history as
+select t.id,
+       t.from_employee as employee_id,
+       'transfer'      as type,
+       'out'           as direction,
+       e.username      as counterparty,
+       null::text      as item,
+       0               as quantity,
+       t.amount,
+       t.created_at
+from transfers t
+         join employees e on e.id = t.to_employee
+union all
+select t.id,
+       t.to_employee,
+       'transfer',
+       'in',
+       e.username,
+       null::text,
+       0,
+       t.amount,
+       t.created_at
+from transfers t
+         join employees e on e.id = t.from_employee
+union all
+select p.id,
+       p.employee_id,
+       'purchase',
+       'out',
+       null::text,
+       i.name,
+       p.quantity,
+       p.price * p.quantity,
+       p.created_at
+from purchases p
+         join items i on i.id = p.item_id
+union all
+select o.id,
+       o.employee_id,
+       'refund',
+       'in',
+       null::text,
+       null::text,
+       0,
+       o.total,
+       o.cancelled_at
+from orders o
+where o.status = 'cancelled'
+union all
+select r.id,
+       r.employee_id,
+       'return',
+       'in',
+       null::text,
+       i.name,
+       r.quantity,
+       r.amount,
+       r.created_at
+from purchase_returns r
+         join purchases p on p.id = r.purchase_id
+         join items i on i.id = p.item_id
+union all
+select g.id,
+       g.from_employee,
+       'gift',
+       'out',
+       e.username,
+       i.name,
+       g.quantity,
+       0,
+       g.created_at
+from item_gifts g
+         join employees e on e.id = g.to_employee
+         join items i on i.id = g.item_id
+union all
+select g.id,
+       g.to_employee,
+       'gift',
+       'in',
+       e.username,
+       i.name,
+       g.quantity,
+       0,
+       g.created_at
+from item_gifts g
+         join employees e on e.id = g.from_employee
+         join items i on i.id = g.item_id;
@@ -0,0 +1,140 @@
+create table if not exists listings
+(
+    id         uuid primary key,
+    seller_id  uuid        not null,
+    item_id    uuid        not null,
+    quantity   int         not null,
+    price      int         not null,
+    status     text        not null default 'active',
+    buyer_id   uuid        null,
+    expires_at timestamptz not null,
+    created_at timestamptz not null default now(),
+    closed_at  timestamptz null,
+
+    foreign key (seller_id) references employees (id),
+    foreign key (buyer_id) references employees (id),
+    foreign key (item_id) references items (id),
+    check (quantity > 0),
+    check (price > 0),
+    check (status in ('active', 'sold', 'cancelled', 'expired')),
+    check ((status = 'sold') = (buyer_id is not null))
+);
+
+create index if not exists listings_active_expires_at_idx on listings (expires_at) where status = 'active';
+create index if not exists listings_active_created_at_idx on listings (created_at) where status = 'active';
+
+create or replace view employee_history as
+select t.id,
+       t.from_employee as employee_id,
+       'transfer'      as type,
+       'out'           as direction,
+       e.username      as counterparty,
+       null::text      as item,
+       0               as quantity,
+       t.amount,
+       t.created_at
+from transfers t
+         join employees e on e.id = t.to_employee
+union all
+select t.id,
+       t.to_employee,
+       'transfer',
+       'in',
+       e.username,
+       null::text,
+       0,
+       t.amount,
+       t.created_at
+from transfers t
+         join employees e on e.id = t.from_employee
+union all
+select p.id,
+       p.employee_id,
+       'purchase',
+       'out',
+       null::text,
+       i.name,
+       p.quantity,
+       p.price * p.quantity,
+       p.created_at
+from purchases p
+         join items i on i.id = p.item_id
+union all
+select o.id,
+       o.employee_id,
+       'refund',
+       'in',
+       null::text,
+       null::text,
+       0,
+       o.total,
+       o.cancelled_at
+from orders o
+where o.status = 'cancelled'
+union all
+select r.id,
+       r.employee_id,
+       'return',
+       'in',
+       null::text,
+       i.name,
+       r.quantity,
+       r.amount,
+       r.created_at
+from purchase_returns r
+         join purchases p on p.id = r.purchase_id
+         join items i on i.id = p.item_id
+union all
+select g.id,
+       g.from_employee,
+       'gift',
+       'out',
+       e.username,
+       i.name,
+       g.quantity,
+       0,
+       g.created_at
+from item_gifts g
+         join employees e on e.id = g.to_employee
+         join items i on i.id = g.item_id
+union all
+select g.id,
+       g.to_employee,
+       'gift',
+       'in',
+       e.username,
+       i.name,
+       g.quantity,
+       0,
+       g.created_at
+from item_gifts g
+         join employees e on e.id = g.from_employee
+         join items i on i.id = g.item_id
+union all
+select l.id,
+       l.seller_id,
+       'sale',
+       'in',
+       e.username,
+       i.name,
+       l.quantity,
+       l.price,
+       l.closed_at
+from listings l
+         join employees e on e.id = l.buyer_id
+         join items i on i.id = l.item_id
+where l.status = 'sold'
+union all
+select l.id,
+       l.buyer_id,
+       'sale',
+       'out',
+       e.username,
+       i.name,
+       l.quantity,
+       l.price,
+       l.closed_at
+from listings l
+         join employees e on e.id = l.seller_id
+         join items i on i.id = l.item_id
+where l.status = 'sold';
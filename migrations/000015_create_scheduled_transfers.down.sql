@@ -0,0 +1,2 @@
+drop table if exists scheduled_transfer_runs;
+drop table if exists scheduled_transfers;
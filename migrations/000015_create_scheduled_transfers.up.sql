@@ -0,0 +1,50 @@
+create table if not exists scheduled_transfers
+(
+    id              uuid primary key,
+    from_employee   uuid        not null,
+    to_employee     uuid        not null,
+    amount          int         not null,
+    message         text        null,
+    category        text        null,
+    recurrence      text        not null default 'once',
+    status          text        not null default 'active',
+    start_at        timestamptz not null,
+    occurrence      int         not null default 0,
+    next_run_at     timestamptz not null,
+    next_attempt_at timestamptz not null,
+    attempts        int         not null default 0,
+    last_error      text        null,
+    created_at      timestamptz not null default now(),
+
+    foreign key (from_employee) references employees (id),
+    foreign key (to_employee) references employees (id),
+    check (amount > 0),
+    check (from_employee <> to_employee),
+    check (char_length(message) <= 200),
+    check (category in ('thanks', 'help', 'teamwork')),
+    check (recurrence in ('once', 'weekly', 'monthly')),
+    check (status in ('active', 'completed', 'cancelled', 'failed')),
+    check (occurrence >= 0),
+    check (attempts >= 0)
+);
+
+create index if not exists scheduled_transfers_from_employee_idx on scheduled_transfers (from_employee, created_at);
+create index if not exists scheduled_transfers_due_idx on scheduled_transfers (next_attempt_at) where status = 'active';
+
+create table if not exists scheduled_transfer_runs
+(
+    id           uuid primary key,
+    schedule_id  uuid        not null,
+    scheduled_at timestamptz not null,
+    attempt      int         not null,
+    status       text        not null,
+    reason       text        null,
+    created_at   timestamptz not null default now(),
+
+    foreign key (schedule_id) references scheduled_transfers (id),
+    check (attempt > 0),
+    check (status in ('succeeded', 'failed')),
+    check ((status = 'failed') = (reason is not null))
+);
+
+create index if not exists scheduled_transfer_runs_schedule_id_idx on scheduled_transfer_runs (schedule_id, created_at);
@@ -0,0 +1 @@
+drop table if exists payment_requests;
\ No newline at end of file
@@ -0,0 +1,23 @@
+create table if not exists payment_requests
+(
+    id           uuid primary key,
+    requester_id uuid        not null,
+    payer_id     uuid        not null,
+    amount       int         not null,
+    message      text        null,
+    status       text        not null default 'pending',
+    expires_at   timestamptz not null,
+    created_at   timestamptz not null default now(),
+    resolved_at  timestamptz null,
+
+    foreign key (requester_id) references employees (id),
+    foreign key (payer_id) references employees (id),
+    check (amount > 0),
+    check (requester_id <> payer_id),
+    check (char_length(message) <= 200),
+    check (status in ('pending', 'accepted', 'declined')),
+    check ((status = 'pending') = (resolved_at is null))
+);
+
+create index if not exists payment_requests_payer_idx on payment_requests (payer_id, created_at);
+create index if not exists payment_requests_requester_idx on payment_requests (requester_id, created_at);
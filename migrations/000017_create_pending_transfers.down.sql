@@ -0,0 +1,5 @@
+drop table if exists pending_transfers;
+
+alter table employees
+    drop column if exists manager_id,
+    drop column if exists held;
@@ -0,0 +1,34 @@
+alter table employees
+    add column held       int  not null default 0,
+    add column manager_id uuid null references employees (id),
+    add constraint employees_held_check check (held >= 0 and held <= balance),
+    add constraint employees_manager_check check (manager_id <> id);
+
+create index if not exists employees_manager_id_idx on employees (manager_id);
+
+create table if not exists pending_transfers
+(
+    id            uuid primary key,
+    from_employee uuid        not null,
+    to_employee   uuid        not null,
+    amount        int         not null,
+    message       text        null,
+    category      text        null,
+    status        text        not null default 'pending',
+    approver_id   uuid        null,
+    created_at    timestamptz not null default now(),
+    resolved_at   timestamptz null,
+
+    foreign key (from_employee) references employees (id),
+    foreign key (to_employee) references employees (id),
+    foreign key (approver_id) references employees (id),
+    check (amount > 0),
+    check (from_employee <> to_employee),
+    check (char_length(message) <= 200),
+    check (category in ('thanks', 'help', 'teamwork')),
+    check (status in ('pending', 'approved', 'rejected')),
+    check ((status = 'pending') = (resolved_at is null))
+);
+
+create index if not exists pending_transfers_pending_idx on pending_transfers (created_at) where status = 'pending';
+create index if not exists pending_transfers_from_employee_idx on pending_transfers (from_employee, created_at);
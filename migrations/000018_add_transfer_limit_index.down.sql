@@ -0,0 +1 @@
+drop index if exists transfers_from_employee_to_employee_created_at_idx;
@@ -0,0 +1,2 @@
+create index if not exists transfers_from_employee_to_employee_created_at_idx
+    on transfers (from_employee, to_employee, created_at);
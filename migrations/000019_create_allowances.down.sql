@@ -0,0 +1,137 @@
+drop view if exists employee_history;
+
+create or replace view employee_history as
+select t.id,
+       t.from_employee as employee_id,
+       'transfer'      as type,
+       'out'           as direction,
+       e.username      as counterparty,
+       null::text      as item,
+       0               as quantity,
+       t.amount,
+       t.created_at,
+       t.message,
+       t.category
+from transfers t
+         join employees e on e.id = t.to_employee
+union all
+select t.id,
+       t.to_employee,
+       'transfer',
+       'in',
+       e.username,
+       null::text,
+       0,
+       t.amount,
+       t.created_at,
+       t.message,
+       t.category
+from transfers t
+         join employees e on e.id = t.from_employee
+union all
+select p.id,
+       p.employee_id,
+       'purchase',
+       'out',
+       null::text,
+       i.name,
+       p.quantity,
+       p.price * p.quantity,
+       p.created_at,
+       null::text,
+       null::text
+from purchases p
+         join items i on i.id = p.item_id
+union all
+select o.id,
+       o.employee_id,
+       'refund',
+       'in',
+       null::text,
+       null::text,
+       0,
+       o.total,
+       o.cancelled_at,
+       null::text,
+       null::text
+from orders o
+where o.status = 'cancelled'
+union all
+select r.id,
+       r.employee_id,
+       'return',
+       'in',
+       null::text,
+       i.name,
+       r.quantity,
+       r.amount,
+       r.created_at,
+       null::text,
+       null::text
+from purchase_returns r
+         join purchases p on p.id = r.purchase_id
+         join items i on i.id = p.item_id
+union all
+select g.id,
+       g.from_employee,
+       'gift',
+       'out',
+       e.username,
+       i.name,
+       g.quantity,
+       0,
+       g.created_at,
+       null::text,
+       null::text
+from item_gifts g
+         join employees e on e.id = g.to_employee
+         join items i on i.id = g.item_id
+union all
+select g.id,
+       g.to_employee,
+       'gift',
+       'in',
+       e.username,
+       i.name,
+       g.quantity,
+       0,
+       g.created_at,
+       null::text,
+       null::text
+from item_gifts g
+         join employees e on e.id = g.from_employee
+         join items i on i.id = g.item_id
+union all
+select l.id,
+       l.seller_id,
+       'sale',
+       'in',
+       e.username,
+       i.name,
+       l.quantity,
+       l.price,
+       l.closed_at,
+       null::text,
+       null::text
+from listings l
+         join employees e on e.id = l.buyer_id
+         join items i on i.id = l.item_id
+where l.status = 'sold'
+union all
+select l.id,
+       l.buyer_id,
+       'sale',
+       'out',
+       e.username,
+       i.name,
+       l.quantity,
+       l.price,
+       l.closed_at,
+       null::text,
+       null::text
+from listings l
+         join employees e on e.id = l.seller_id
+         join items i on i.id = l.item_id
+where l.status = 'sold';
+
+drop table if exists allowances;
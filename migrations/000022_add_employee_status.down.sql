@@ -0,0 +1,3 @@
+alter table employees
+    drop constraint if exists employees_status_check,
+    drop column if exists status;
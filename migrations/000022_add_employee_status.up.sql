@@ -0,0 +1,3 @@
+alter table employees
+    add column if not exists status text not null default 'active',
+    add constraint employees_status_check check (status in ('active', 'frozen', 'deactivated'));
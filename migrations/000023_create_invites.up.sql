@@ -0,0 +1,14 @@
+create table if not exists invites
+(
+    id         uuid primary key,
+    code       text        not null unique,
+    created_by uuid        not null,
+    used_by    uuid,
+    expires_at timestamptz not null,
+    used_at    timestamptz,
+    created_at timestamptz not null default now(),
+
+    foreign key (created_by) references employees (id),
+    foreign key (used_by) references employees (id),
+    check ((used_by is null) = (used_at is null))
+);
@@ -0,0 +1,25 @@
+create table if not exists sessions
+(
+    id          uuid primary key,
+    employee_id uuid        not null,
+    revoked_at  timestamptz,
+    created_at  timestamptz not null default now(),
+
+    foreign key (employee_id) references employees (id)
+);
+
+create index if not exists sessions_employee_idx on sessions (employee_id);
+
+create table if not exists refresh_tokens
+(
+    id         uuid primary key,
+    session_id uuid        not null,
+    token_hash text        not null unique,
+    expires_at timestamptz not null,
+    used_at    timestamptz,
+    created_at timestamptz not null default now(),
+
+    foreign key (session_id) references sessions (id) on delete cascade
+);
+
+create index if not exists refresh_tokens_session_idx on refresh_tokens (session_id);
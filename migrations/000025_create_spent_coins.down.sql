@@ -0,0 +1 @@
+drop table if exists spent_coins;
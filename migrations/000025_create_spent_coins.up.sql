@@ -0,0 +1,14 @@
+create table if not exists spent_coins
+(
+    id           uuid primary key,
+    operation_id uuid        not null,
+    amount       int         not null,
+    remaining    int         not null,
+    granted_at   timestamptz not null,
+    created_at   timestamptz not null default now(),
+
+    check (amount > 0),
+    check (remaining between 0 and amount)
+);
+
+create index if not exists spent_coins_operation_idx on spent_coins (operation_id) where remaining > 0;
@@ -0,0 +1,10 @@
+alter table orders
+    add column delivered_at timestamptz null;
+
+-- delivered is a final status, so the last update of a delivered order is its delivery
+update orders
+set delivered_at = updated_at
+where status = 'delivered';
+
+alter table orders
+    add constraint orders_delivered_at_check check ((status = 'delivered') = (delivered_at is not null));
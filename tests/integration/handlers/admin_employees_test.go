@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockEmployeeAdminService struct {
+	mock.Mock
+}
+
+func (m *mockEmployeeAdminService) SetManager(ctx context.Context, username string, manager string) error {
+	args := m.Called(ctx, username, manager)
+	return args.Error(0)
+}
+
+func (m *mockEmployeeAdminService) Adjust(
+	ctx context.Context, adminId uuid.UUID, username string, amount int, reason string,
+) (*model.BalanceAdjustment, error) {
+	args := m.Called(ctx, adminId, username, amount, reason)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.BalanceAdjustment), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockEmployeeAdminService) SetStatus(ctx context.Context, username string, status model.EmployeeStatus) error {
+	args := m.Called(ctx, username, status)
+	return args.Error(0)
+}
+
+func (m *mockEmployeeAdminService) Sweep(ctx context.Context, username string) (*model.Employee, int, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Employee), args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+func TestNewSetManagerHandlerFunc(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(*mockEmployeeAdminService)
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "assign manager",
+			body: `{"manager":"lead"}`,
+			setup: func(mockEmployees *mockEmployeeAdminService) {
+				mockEmployees.On("SetManager", mock.Anything, "dev", "lead").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "unknown manager",
+			body: `{"manager":"ghost"}`,
+			setup: func(mockEmployees *mockEmployeeAdminService) {
+				mockEmployees.On("SetManager", mock.Anything, "dev", "ghost").Return(service.ErrManagerNotFound)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "manager not found", Code: "manager_not_found"},
+		},
+		{
+			name:           "malformed body",
+			body:           `{`,
+			setup:          func(*mockEmployeeAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "failed to parse request"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockEmployees := new(mockEmployeeAdminService)
+			tc.setup(mockEmployees)
+
+			r := chi.NewRouter()
+			r.Post("/api/admin/employees/{username}/manager", handlers.NewSetManagerHandlerFunc(logger, mockEmployees))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/employees/dev/manager", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockEmployees.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewAdjustBalanceHandlerFunc(t *testing.T) {
+	adjustment := &model.BalanceAdjustment{
+		Id:        uuid.New(),
+		Employee:  "dev",
+		Amount:    -50,
+		Reason:    "duplicate bonus",
+		Balance:   950,
+		CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(*mockEmployeeAdminService)
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "debit with reason",
+			body: `{"amount":-50,"reason":"duplicate bonus"}`,
+			setup: func(mockEmployees *mockEmployeeAdminService) {
+				mockEmployees.On("Adjust", mock.Anything, mock.Anything, "dev", -50, "duplicate bonus").
+					Return(adjustment, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.AdjustmentResponse{
+				AdjustmentId: adjustment.Id.String(),
+				User:         "dev",
+				Amount:       -50,
+				Reason:       "duplicate bonus",
+				Balance:      950,
+				CreatedAt:    adjustment.CreatedAt,
+			},
+		},
+		{
+			name: "not enough coins",
+			body: `{"amount":-5000,"reason":"duplicate bonus"}`,
+			setup: func(mockEmployees *mockEmployeeAdminService) {
+				mockEmployees.On("Adjust", mock.Anything, mock.Anything, "dev", -5000, "duplicate bonus").
+					Return(nil, service.ErrNotEnoughCoins)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "not enough coins", Code: "not_enough_coins"},
+		},
+		{
+			name: "unknown employee",
+			body: `{"amount":10,"reason":"bonus"}`,
+			setup: func(mockEmployees *mockEmployeeAdminService) {
+				mockEmployees.On("Adjust", mock.Anything, mock.Anything, "dev", 10, "bonus").
+					Return(nil, service.ErrEmployeeNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   rep.ErrorResponse{Errors: "employee not found", Code: "employee_not_found"},
+		},
+		{
+			name:           "missing reason",
+			body:           `{"amount":10}`,
+			setup:          func(*mockEmployeeAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name:           "zero amount",
+			body:           `{"amount":0,"reason":"bonus"}`,
+			setup:          func(*mockEmployeeAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockEmployees := new(mockEmployeeAdminService)
+			tc.setup(mockEmployees)
+
+			r := chi.NewRouter()
+			r.Post("/api/admin/employees/{username}/adjust",
+				handlers.NewAdjustBalanceHandlerFunc(logger, mockEmployees, validator.New()))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/employees/dev/adjust", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, withClaims(req, "admin"))
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			expectedResp, err := json.Marshal(tc.expectedBody)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expectedResp), w.Body.String())
+
+			mockEmployees.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewSetStatusHandlerFunc(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(*mockEmployeeAdminService)
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "freeze",
+			body: `{"status":"frozen"}`,
+			setup: func(mockEmployees *mockEmployeeAdminService) {
+				mockEmployees.On("SetStatus", mock.Anything, "dev", model.EmployeeFrozen).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "unknown employee",
+			body: `{"status":"deactivated"}`,
+			setup: func(mockEmployees *mockEmployeeAdminService) {
+				mockEmployees.On("SetStatus", mock.Anything, "dev", model.EmployeeDeactivated).
+					Return(service.ErrEmployeeNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   rep.ErrorResponse{Errors: "employee not found", Code: "employee_not_found"},
+		},
+		{
+			name:           "unknown status",
+			body:           `{"status":"banned"}`,
+			setup:          func(*mockEmployeeAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockEmployees := new(mockEmployeeAdminService)
+			tc.setup(mockEmployees)
+
+			r := chi.NewRouter()
+			r.Post("/api/admin/employees/{username}/status",
+				handlers.NewSetStatusHandlerFunc(logger, mockEmployees, validator.New()))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/employees/dev/status", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockEmployees.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewSweepBalanceHandlerFunc(t *testing.T) {
+	tests := []struct {
+		name           string
+		setup          func(*mockEmployeeAdminService)
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "sweep available coins",
+			setup: func(mockEmployees *mockEmployeeAdminService) {
+				mockEmployees.On("Sweep", mock.Anything, "dev").
+					Return(&model.Employee{Username: "dev", Balance: 200, Held: 200}, 700, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   rep.SweepResponse{User: "dev", Amount: 700, Balance: 200},
+		},
+		{
+			name: "active employee",
+			setup: func(mockEmployees *mockEmployeeAdminService) {
+				mockEmployees.On("Sweep", mock.Anything, "dev").Return(nil, 0, service.ErrEmployeeNotDeactivated)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: rep.ErrorResponse{
+				Errors: "only deactivated accounts can be swept",
+				Code:   "not_deactivated",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockEmployees := new(mockEmployeeAdminService)
+			tc.setup(mockEmployees)
+
+			r := chi.NewRouter()
+			r.Post("/api/admin/employees/{username}/sweep", handlers.NewSweepBalanceHandlerFunc(logger, mockEmployees))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/employees/dev/sweep", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			expectedResp, err := json.Marshal(tc.expectedBody)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expectedResp), w.Body.String())
+
+			mockEmployees.AssertExpectations(t)
+		})
+	}
+}
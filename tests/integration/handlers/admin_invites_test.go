@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+type mockInviteService struct {
+	mock.Mock
+}
+
+func (m *mockInviteService) Create(ctx context.Context, adminId uuid.UUID) (*model.Invite, error) {
+	args := m.Called(ctx, adminId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Invite), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestNewCreateInviteHandlerFunc(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+	mockInvites := new(mockInviteService)
+
+	invite := &model.Invite{Code: "0123abcd", ExpiresAt: time.Date(2026, 10, 25, 12, 0, 0, 0, time.UTC)}
+	mockInvites.On("Create", mock.Anything, mock.Anything).Return(invite, nil)
+
+	r := chi.NewRouter()
+	r.Post("/api/admin/invites", handlers.NewCreateInviteHandlerFunc(logger, mockInvites))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/invites", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, withClaims(req, "admin"))
+
+	assert.Equal(t, http.StatusCreated, w.Result().StatusCode)
+
+	expectedResp, err := json.Marshal(rep.InviteResponse{Code: invite.Code, ExpiresAt: invite.ExpiresAt})
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(expectedResp), w.Body.String())
+
+	mockInvites.AssertExpectations(t)
+}
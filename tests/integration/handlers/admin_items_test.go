@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	mw "avito-shop/internal/http-server/middleware"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+type mockItemAdminService struct {
+	mock.Mock
+}
+
+func (m *mockItemAdminService) Create(ctx context.Context, item *model.Item) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *mockItemAdminService) Update(ctx context.Context, name string, update model.ItemUpdate) (*model.Item, error) {
+	args := m.Called(ctx, name, update)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Item), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockItemAdminService) Retire(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func withRole(req *http.Request, role model.Role) *http.Request {
+	req = withClaims(req, "admin")
+	req.Context().Value(mw.UserContextKey).(*service.TokenClaims).Role = role
+	return req
+}
+
+func setupAdminItemsRouter(log *slog.Logger, itemService *mockItemAdminService) http.Handler {
+	vld := validator.New()
+	r := chi.NewRouter()
+	r.Route("/api/admin", func(r chi.Router) {
+		r.Use(mw.NewRequireRole(log, model.RoleAdmin))
+		r.Post("/items", handlers.NewCreateItemHandlerFunc(log, itemService, vld))
+		r.Patch("/items/{item}", handlers.NewUpdateItemHandlerFunc(log, itemService, vld))
+		r.Post("/items/{item}/retire", handlers.NewRetireItemHandlerFunc(log, itemService))
+	})
+	return r
+}
+
+func TestAdminItemHandlers(t *testing.T) {
+	itemId := uuid.New()
+	newPrice := 25
+	stock := 3
+
+	tests := []struct {
+		name           string
+		setup          func(*mockItemAdminService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "create item",
+			setup: func(mockItems *mockItemAdminService) *http.Request {
+				mockItems.On("Create", mock.Anything, mock.MatchedBy(func(i *model.Item) bool {
+					return i.Name == "sticker" && i.Price == 5 && *i.Stock == 3 && i.MaxPerEmployee == nil
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*model.Item).Id = itemId
+				}).Return(nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/admin/items",
+					strings.NewReader(`{"name":"sticker","price":5,"stock":3}`))
+				return withRole(req, model.RoleAdmin)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   rep.ItemResponse{Id: itemId.String(), Name: "sticker", Price: 5, Stock: &stock},
+		},
+		{
+			name: "create duplicate item",
+			setup: func(mockItems *mockItemAdminService) *http.Request {
+				mockItems.On("Create", mock.Anything, mock.Anything).
+					Return(service.ErrItemExists)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/admin/items",
+					strings.NewReader(`{"name":"cup","price":5}`))
+				return withRole(req, model.RoleAdmin)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   rep.ErrorResponse{Errors: "item already exists"},
+		},
+		{
+			name: "create with invalid body",
+			setup: func(mockItems *mockItemAdminService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/admin/items", strings.NewReader(`{"price":5}`))
+				return withRole(req, model.RoleAdmin)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "update price",
+			setup: func(mockItems *mockItemAdminService) *http.Request {
+				mockItems.On("Update", mock.Anything, "cup", model.ItemUpdate{Price: &newPrice}).
+					Return(&model.Item{Id: itemId, Name: "cup", Price: newPrice}, nil)
+
+				req := httptest.NewRequest(http.MethodPatch, "/api/admin/items/cup", strings.NewReader(`{"price":25}`))
+				return withRole(req, model.RoleAdmin)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   rep.ItemResponse{Id: itemId.String(), Name: "cup", Price: newPrice},
+		},
+		{
+			name: "update missing item",
+			setup: func(mockItems *mockItemAdminService) *http.Request {
+				mockItems.On("Update", mock.Anything, "missing", mock.Anything).
+					Return(nil, service.ErrItemNotFound)
+
+				req := httptest.NewRequest(http.MethodPatch, "/api/admin/items/missing",
+					strings.NewReader(`{"name":"other"}`))
+				return withRole(req, model.RoleAdmin)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   rep.ErrorResponse{Errors: "item not found"},
+		},
+		{
+			name: "retire item",
+			setup: func(mockItems *mockItemAdminService) *http.Request {
+				mockItems.On("Retire", mock.Anything, "cup").Return(nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/admin/items/cup/retire", nil)
+				return withRole(req, model.RoleAdmin)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "forbidden for employee",
+			setup: func(mockItems *mockItemAdminService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/admin/items/cup/retire", nil)
+				return withRole(req, model.RoleEmployee)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   rep.ErrorResponse{Errors: "forbidden"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockItems := new(mockItemAdminService)
+
+			req := tc.setup(mockItems)
+
+			w := httptest.NewRecorder()
+			setupAdminItemsRouter(logger, mockItems).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockItems.AssertExpectations(t)
+		})
+	}
+}
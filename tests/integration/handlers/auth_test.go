@@ -4,6 +4,7 @@ import (
 	"avito-shop/internal/http-server/dto/request"
 	"avito-shop/internal/http-server/dto/response"
 	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
 	"avito-shop/internal/service"
 	"bytes"
 	"context"
@@ -11,6 +12,7 @@ import (
 	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"log/slog"
@@ -24,14 +26,42 @@ type mockAuthService struct {
 	mock.Mock
 }
 
-func (m *mockAuthService) Authorize(ctx context.Context, username, password string) (string, error) {
+func (m *mockAuthService) Authorize(ctx context.Context, username, password string) (*model.AuthTokens, error) {
 	args := m.Called(ctx, username, password)
-	return args.String(0), args.Error(1)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.AuthTokens), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockAuthService) Register(
+	ctx context.Context, username, password, inviteCode string) (*model.AuthTokens, error) {
+	args := m.Called(ctx, username, password, inviteCode)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.AuthTokens), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockAuthService) Refresh(ctx context.Context, refreshToken string) (*model.AuthTokens, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.AuthTokens), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockAuthService) Logout(ctx context.Context, sessionId uuid.UUID) error {
+	args := m.Called(ctx, sessionId)
+	return args.Error(0)
 }
 
 func setupAuthRouter(log *slog.Logger, authService *mockAuthService) http.Handler {
 	r := chi.NewRouter()
 	r.Post("/api/auth", handlers.NewAuthHandlerFunc(log, authService, validator.New()))
+	r.Post("/api/register", handlers.NewRegisterHandlerFunc(log, authService, validator.New()))
+	r.Post("/api/auth/refresh", handlers.NewRefreshHandlerFunc(log, authService, validator.New()))
+	r.Post("/api/auth/logout", handlers.NewLogoutHandlerFunc(log, authService))
 	return r
 }
 
@@ -40,6 +70,7 @@ func TestAuthHandler(t *testing.T) {
 		validUser     = "valid-user"
 		validPassword = "valid-password"
 		validToken    = "valid-token"
+		validRefresh  = "valid-refresh"
 		wrongPassword = "wrong-password"
 	)
 	tests := []struct {
@@ -58,11 +89,12 @@ func TestAuthHandler(t *testing.T) {
 				if err != nil {
 					return nil, err
 				}
-				mockAuth.On("Authorize", mock.Anything, validUser, validPassword).Return(validToken, nil)
+				mockAuth.On("Authorize", mock.Anything, validUser, validPassword).
+					Return(&model.AuthTokens{AccessToken: validToken, RefreshToken: validRefresh}, nil)
 				return reqBody, nil
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   response.AuthResponse{Token: validToken},
+			expectedBody:   response.AuthResponse{Token: validToken, RefreshToken: validRefresh},
 		},
 		{
 			name: "invalid password",
@@ -72,12 +104,26 @@ func TestAuthHandler(t *testing.T) {
 					return nil, err
 				}
 				mockAuth.On("Authorize", mock.Anything, validUser, wrongPassword).
-					Return("", service.ErrInvalidCredentials)
+					Return(nil, service.ErrInvalidCredentials)
 				return reqBody, nil
 			},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   response.ErrorResponse{Errors: "invalid credentials"},
 		},
+		{
+			name: "deactivated account",
+			setup: func(mockAuth *mockAuthService) ([]byte, error) {
+				reqBody, err := json.Marshal(request.AuthRequest{Username: validUser, Password: validPassword})
+				if err != nil {
+					return nil, err
+				}
+				mockAuth.On("Authorize", mock.Anything, validUser, validPassword).
+					Return(nil, service.ErrAccountDeactivated)
+				return reqBody, nil
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   response.ErrorResponse{Errors: "account is deactivated"},
+		},
 		{
 			name: "service error",
 			setup: func(mockAuth *mockAuthService) ([]byte, error) {
@@ -86,7 +132,7 @@ func TestAuthHandler(t *testing.T) {
 					return nil, err
 				}
 				mockAuth.On("Authorize", mock.Anything, validUser, validPassword).
-					Return("", errors.New("internal error"))
+					Return(nil, errors.New("internal error"))
 				return reqBody, nil
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -162,3 +208,190 @@ func TestAuthHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(*mockAuthService)
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "successful registration",
+			body: `{"username":"new-user","password":"secret","inviteCode":"code"}`,
+			setup: func(mockAuth *mockAuthService) {
+				mockAuth.On("Register", mock.Anything, "new-user", "secret", "code").
+					Return(&model.AuthTokens{AccessToken: "token", RefreshToken: "refresh"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   response.AuthResponse{Token: "token", RefreshToken: "refresh"},
+		},
+		{
+			name: "username taken",
+			body: `{"username":"new-user","password":"secret"}`,
+			setup: func(mockAuth *mockAuthService) {
+				mockAuth.On("Register", mock.Anything, "new-user", "secret", "").Return(nil, service.ErrUsernameTaken)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   response.ErrorResponse{Errors: "username is already taken", Code: "username_taken"},
+		},
+		{
+			name: "invalid invite",
+			body: `{"username":"new-user","password":"secret","inviteCode":"stale"}`,
+			setup: func(mockAuth *mockAuthService) {
+				mockAuth.On("Register", mock.Anything, "new-user", "secret", "stale").
+					Return(nil, service.ErrInvalidInvite)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: response.ErrorResponse{
+				Errors: "invite code is invalid, used or expired",
+				Code:   "invalid_invite",
+			},
+		},
+		{
+			name:           "missing password",
+			body:           `{"username":"new-user"}`,
+			setup:          func(*mockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   response.ErrorResponse{Errors: "invalid request body"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockAuthService := new(mockAuthService)
+			tc.setup(mockAuthService)
+
+			r := setupAuthRouter(logger, mockAuthService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/register", bytes.NewReader([]byte(tc.body)))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			expectedResp, err := json.Marshal(tc.expectedBody)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			assert.JSONEq(t, string(expectedResp), w.Body.String())
+
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRefreshHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(*mockAuthService)
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "successful refresh",
+			body: `{"refreshToken":"old-refresh"}`,
+			setup: func(mockAuth *mockAuthService) {
+				mockAuth.On("Refresh", mock.Anything, "old-refresh").
+					Return(&model.AuthTokens{AccessToken: "token", RefreshToken: "new-refresh"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   response.AuthResponse{Token: "token", RefreshToken: "new-refresh"},
+		},
+		{
+			name: "invalid refresh token",
+			body: `{"refreshToken":"unknown"}`,
+			setup: func(mockAuth *mockAuthService) {
+				mockAuth.On("Refresh", mock.Anything, "unknown").Return(nil, service.ErrInvalidRefreshToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody: response.ErrorResponse{
+				Errors: "refresh token is invalid or expired",
+				Code:   "invalid_refresh_token",
+			},
+		},
+		{
+			name: "reused refresh token",
+			body: `{"refreshToken":"old-refresh"}`,
+			setup: func(mockAuth *mockAuthService) {
+				mockAuth.On("Refresh", mock.Anything, "old-refresh").Return(nil, service.ErrRefreshTokenReused)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody: response.ErrorResponse{
+				Errors: "refresh token was already used",
+				Code:   "refresh_token_reused",
+			},
+		},
+		{
+			name:           "missing refresh token",
+			body:           `{}`,
+			setup:          func(*mockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   response.ErrorResponse{Errors: "invalid request body"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockAuthService := new(mockAuthService)
+			tc.setup(mockAuthService)
+
+			r := setupAuthRouter(logger, mockAuthService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewReader([]byte(tc.body)))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			expectedResp, err := json.Marshal(tc.expectedBody)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			assert.JSONEq(t, string(expectedResp), w.Body.String())
+
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogoutHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		setup          func(*mockAuthService)
+		expectedStatus int
+	}{
+		{
+			name: "successful logout",
+			setup: func(mockAuth *mockAuthService) {
+				mockAuth.On("Logout", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "service error",
+			setup: func(mockAuth *mockAuthService) {
+				mockAuth.On("Logout", mock.Anything, mock.Anything).Return(errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockAuthService := new(mockAuthService)
+			tc.setup(mockAuthService)
+
+			r := setupAuthRouter(logger, mockAuthService)
+
+			req := withClaims(httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil), "user")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
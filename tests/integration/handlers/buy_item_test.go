@@ -74,7 +74,7 @@ func TestNewBuyItemHandlerFunc(t *testing.T) {
 				return r.WithContext(ctx)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   response.ErrorResponse{Errors: "employee not found"},
+			expectedBody:   response.ErrorResponse{Errors: "employee not found", Code: "employee_not_found"},
 		},
 		{
 			name: "not enough coins",
@@ -91,7 +91,7 @@ func TestNewBuyItemHandlerFunc(t *testing.T) {
 				return r.WithContext(ctx)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   response.ErrorResponse{Errors: "not enough coins"},
+			expectedBody:   response.ErrorResponse{Errors: "not enough coins", Code: "not_enough_coins"},
 		},
 		{
 			name: "item not found",
@@ -108,7 +108,44 @@ func TestNewBuyItemHandlerFunc(t *testing.T) {
 				return r.WithContext(ctx)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   response.ErrorResponse{Errors: "item not found"},
+			expectedBody:   response.ErrorResponse{Errors: "item not found", Code: "item_not_found"},
+		},
+		{
+			name: "item out of stock",
+			setup: func(mockBuy *mockBuyItemService) *http.Request {
+				mockBuy.On("Buy", mock.Anything, validItemName, validUsername).Return(service.ErrOutOfStock)
+
+				r := httptest.NewRequest(http.MethodGet, "/api/buy/"+validItemName, nil)
+				rCtx := chi.NewRouteContext()
+				rCtx.URLParams.Add("item", validItemName)
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rCtx))
+				ctx := context.WithValue(r.Context(), mw.UserContextKey, &service.TokenClaims{
+					Username: validUsername,
+				})
+				return r.WithContext(ctx)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   response.ErrorResponse{Errors: "item is out of stock", Code: "out_of_stock"},
+		},
+		{
+			name: "purchase limit reached",
+			setup: func(mockBuy *mockBuyItemService) *http.Request {
+				mockBuy.On("Buy", mock.Anything, validItemName, validUsername).Return(service.ErrPurchaseLimitReached)
+
+				r := httptest.NewRequest(http.MethodGet, "/api/buy/"+validItemName, nil)
+				rCtx := chi.NewRouteContext()
+				rCtx.URLParams.Add("item", validItemName)
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rCtx))
+				ctx := context.WithValue(r.Context(), mw.UserContextKey, &service.TokenClaims{
+					Username: validUsername,
+				})
+				return r.WithContext(ctx)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: response.ErrorResponse{
+				Errors: "purchase limit for item reached",
+				Code:   "purchase_limit_reached",
+			},
 		},
 		{
 			name: "internal server error",
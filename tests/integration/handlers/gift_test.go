@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockGiftService struct {
+	mock.Mock
+}
+
+func (m *mockGiftService) GiftItem(
+	ctx context.Context, from string, to string, item string, quantity int) (*model.Gift, error) {
+	args := m.Called(ctx, from, to, item, quantity)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Gift), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestNewGiftHandlerFunc(t *testing.T) {
+	validUsername := "valid-user"
+	giftId := uuid.New()
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	body := `{"toUser":"colleague","item":"cup","quantity":2}`
+
+	tests := []struct {
+		name           string
+		setup          func(*mockGiftService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "successful gift",
+			setup: func(mockGifts *mockGiftService) *http.Request {
+				mockGifts.On("GiftItem", mock.Anything, validUsername, "colleague", "cup", 2).
+					Return(&model.Gift{
+						Id:        giftId,
+						Receiver:  "colleague",
+						Item:      "cup",
+						Quantity:  2,
+						CreatedAt: createdAt,
+					}, nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/inventory/gift", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: rep.GiftResponse{
+				GiftId:    giftId.String(),
+				ToUser:    "colleague",
+				Item:      "cup",
+				Quantity:  2,
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "missing quantity",
+			setup: func(mockGifts *mockGiftService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/inventory/gift",
+					strings.NewReader(`{"toUser":"colleague","item":"cup"}`))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "gift to yourself",
+			setup: func(mockGifts *mockGiftService) *http.Request {
+				mockGifts.On("GiftItem", mock.Anything, validUsername, "colleague", "cup", 2).
+					Return(nil, service.ErrTransferToSameEmployee)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/inventory/gift", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "can't gift items to yourself", Code: "same_employee"},
+		},
+		{
+			name: "receiver not found",
+			setup: func(mockGifts *mockGiftService) *http.Request {
+				mockGifts.On("GiftItem", mock.Anything, validUsername, "colleague", "cup", 2).
+					Return(nil, service.ErrReceiverNotFound)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/inventory/gift", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "receiver not found", Code: "receiver_not_found"},
+		},
+		{
+			name: "not enough items",
+			setup: func(mockGifts *mockGiftService) *http.Request {
+				mockGifts.On("GiftItem", mock.Anything, validUsername, "colleague", "cup", 2).
+					Return(nil, service.ErrNotEnoughItems)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/inventory/gift", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "not enough items in inventory", Code: "not_enough_items"},
+		},
+		{
+			name: "missing JWT token in context",
+			setup: func(mockGifts *mockGiftService) *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/api/inventory/gift", strings.NewReader(body))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   rep.ErrorResponse{Errors: "internal server error"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockGifts := new(mockGiftService)
+
+			req := tc.setup(mockGifts)
+
+			handler := handlers.NewGiftHandlerFunc(logger, mockGifts, validator.New())
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockGifts.AssertExpectations(t)
+		})
+	}
+}
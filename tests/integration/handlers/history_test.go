@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	mw "avito-shop/internal/http-server/middleware"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+type mockHistoryService struct {
+	mock.Mock
+}
+
+func (m *mockHistoryService) Get(
+	ctx context.Context, username string, filter model.HistoryFilter) (*model.HistoryPage, error) {
+	args := m.Called(ctx, username, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.HistoryPage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func withClaims(req *http.Request, username string) *http.Request {
+	claims := &service.TokenClaims{
+		Username:   username,
+		EmployeeId: uuid.New(),
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+	ctx := context.WithValue(req.Context(), mw.UserContextKey, claims)
+	return req.WithContext(ctx)
+}
+
+func TestNewHistoryHandlerFunc(t *testing.T) {
+	validUsername := "valid-user"
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	entryId := uuid.New()
+	cursor := &model.Cursor{CreatedAt: createdAt, Id: entryId}
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		setup          func(*mockHistoryService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "successful history retrieval",
+			setup: func(mockHistory *mockHistoryService) *http.Request {
+				mockHistory.On("Get", mock.Anything, validUsername, model.HistoryFilter{
+					Direction:    model.DirectionIn,
+					Counterparty: "sender",
+					From:         &from,
+					Limit:        1,
+				}).Return(&model.HistoryPage{
+					Entries: []model.HistoryEntry{{
+						Id:           entryId,
+						Type:         model.HistoryTransfer,
+						Direction:    model.DirectionIn,
+						Counterparty: "sender",
+						Amount:       100,
+						CreatedAt:    createdAt,
+					}},
+					NextCursor: cursor,
+				}, nil)
+
+				req := httptest.NewRequest(http.MethodGet,
+					"/api/history?direction=in&counterparty=sender&from=2025-01-01T00:00:00Z&limit=1", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.HistoryResponse{
+				Entries: []rep.HistoryEntry{{
+					Id:           entryId.String(),
+					Type:         "transfer",
+					Direction:    "in",
+					Counterparty: "sender",
+					Amount:       100,
+					CreatedAt:    createdAt,
+				}},
+				NextCursor: dto.EncodeCursor(cursor),
+			},
+		},
+		{
+			name: "next page by cursor",
+			setup: func(mockHistory *mockHistoryService) *http.Request {
+				mockHistory.On("Get", mock.Anything, validUsername, model.HistoryFilter{Cursor: cursor}).
+					Return(&model.HistoryPage{}, nil)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/history?cursor="+dto.EncodeCursor(cursor), nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   rep.HistoryResponse{Entries: []rep.HistoryEntry{}},
+		},
+		{
+			name: "invalid direction",
+			setup: func(mockHistory *mockHistoryService) *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/api/history?direction=sideways", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid cursor",
+			setup: func(mockHistory *mockHistoryService) *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/api/history?cursor=garbage", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid cursor"},
+		},
+		{
+			name: "invalid date range",
+			setup: func(mockHistory *mockHistoryService) *http.Request {
+				mockHistory.On("Get", mock.Anything, validUsername, mock.Anything).
+					Return(nil, service.ErrInvalidDateRange)
+
+				req := httptest.NewRequest(http.MethodGet,
+					"/api/history?from=2025-02-01T00:00:00Z&to=2025-01-01T00:00:00Z", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid date range"},
+		},
+		{
+			name: "missing JWT token in context",
+			setup: func(mockHistory *mockHistoryService) *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/api/history", nil)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   rep.ErrorResponse{Errors: "internal server error"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockHistoryService := new(mockHistoryService)
+
+			req := tc.setup(mockHistoryService)
+
+			handler := handlers.NewHistoryHandlerFunc(logger, mockHistoryService)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockHistoryService.AssertExpectations(t)
+		})
+	}
+}
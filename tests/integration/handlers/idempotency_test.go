@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	mw "avito-shop/internal/http-server/middleware"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+type mockIdempotencyService struct {
+	mock.Mock
+}
+
+func (m *mockIdempotencyService) Do(
+	ctx context.Context,
+	employeeId uuid.UUID,
+	key string,
+	fingerprint string,
+	fn func(ctx context.Context) (int, []byte, error),
+) (*model.IdempotencyRecord, bool, error) {
+	args := m.Called(ctx, employeeId, key, fingerprint)
+	if args.Bool(1) || args.Error(2) != nil {
+		if args.Get(0) != nil {
+			return args.Get(0).(*model.IdempotencyRecord), args.Bool(1), args.Error(2)
+		}
+		return nil, args.Bool(1), args.Error(2)
+	}
+
+	status, body, err := fn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	return &model.IdempotencyRecord{Key: key, ResponseStatus: status, ResponseBody: body}, false, nil
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	const key = "request-key"
+
+	tests := []struct {
+		name             string
+		setup            func(*mockIdempotencyService) *http.Request
+		handlerStatus    int
+		expectedStatus   int
+		expectedBody     any
+		expectedCalls    int
+		expectedReplayed bool
+	}{
+		{
+			name: "request without key passes through",
+			setup: func(mockIdempotency *mockIdempotencyService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(`{}`))
+				return withClaims(req, "user")
+			},
+			handlerStatus:  http.StatusOK,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  1,
+		},
+		{
+			name: "first request runs handler",
+			setup: func(mockIdempotency *mockIdempotencyService) *http.Request {
+				mockIdempotency.On("Do", mock.Anything, mock.Anything, key, mock.Anything).
+					Return(nil, false, nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(`{}`))
+				req.Header.Set(mw.IdempotencyKeyHeader, key)
+				return withClaims(req, "user")
+			},
+			handlerStatus:  http.StatusOK,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  1,
+		},
+		{
+			name: "failed request is returned as is",
+			setup: func(mockIdempotency *mockIdempotencyService) *http.Request {
+				mockIdempotency.On("Do", mock.Anything, mock.Anything, key, mock.Anything).
+					Return(nil, false, nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(`{}`))
+				req.Header.Set(mw.IdempotencyKeyHeader, key)
+				return withClaims(req, "user")
+			},
+			handlerStatus:  http.StatusBadRequest,
+			expectedStatus: http.StatusBadRequest,
+			expectedCalls:  1,
+		},
+		{
+			name: "duplicate request replays stored response",
+			setup: func(mockIdempotency *mockIdempotencyService) *http.Request {
+				mockIdempotency.On("Do", mock.Anything, mock.Anything, key, mock.Anything).
+					Return(&model.IdempotencyRecord{Key: key, ResponseStatus: http.StatusOK}, true, nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(`{}`))
+				req.Header.Set(mw.IdempotencyKeyHeader, key)
+				return withClaims(req, "user")
+			},
+			expectedStatus:   http.StatusOK,
+			expectedCalls:    0,
+			expectedReplayed: true,
+		},
+		{
+			name: "key reused with different request",
+			setup: func(mockIdempotency *mockIdempotencyService) *http.Request {
+				mockIdempotency.On("Do", mock.Anything, mock.Anything, key, mock.Anything).
+					Return(nil, false, service.ErrIdempotencyKeyReused)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(`{}`))
+				req.Header.Set(mw.IdempotencyKeyHeader, key)
+				return withClaims(req, "user")
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   rep.ErrorResponse{Errors: "idempotency key reused with different request"},
+			expectedCalls:  0,
+		},
+		{
+			name: "key is too long",
+			setup: func(mockIdempotency *mockIdempotencyService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(`{}`))
+				req.Header.Set(mw.IdempotencyKeyHeader, strings.Repeat("k", 256))
+				return withClaims(req, "user")
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "idempotency key is too long"},
+			expectedCalls:  0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockIdempotency := new(mockIdempotencyService)
+
+			req := tc.setup(mockIdempotency)
+
+			calls := 0
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(tc.handlerStatus)
+			})
+
+			handler := mw.NewIdempotency(logger, mockIdempotency)(next)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			assert.Equal(t, tc.expectedCalls, calls)
+			if tc.expectedReplayed {
+				assert.Equal(t, "true", w.Header().Get(mw.IdempotentReplayedHeader))
+			} else {
+				assert.Empty(t, w.Header().Get(mw.IdempotentReplayedHeader))
+			}
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockIdempotency.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+type mockCatalogService struct {
+	mock.Mock
+}
+
+func (m *mockCatalogService) List(
+	ctx context.Context, username string, filter model.ItemFilter, affordableOnly bool) ([]model.CatalogItem, error) {
+	args := m.Called(ctx, username, filter, affordableOnly)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.CatalogItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestNewItemsHandlerFunc(t *testing.T) {
+	validUsername := "valid-user"
+
+	tests := []struct {
+		name           string
+		setup          func(*mockCatalogService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "successful catalog retrieval",
+			setup: func(mockCatalog *mockCatalogService) *http.Request {
+				mockCatalog.On("List", mock.Anything, validUsername,
+					model.ItemFilter{SortBy: model.ItemSortPrice, Order: model.SortDesc}, true).
+					Return([]model.CatalogItem{{Name: "pen", Price: 10, Available: true}}, nil)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/items?sort=price&order=desc&affordable=true", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.ItemsResponse{
+				Items: []rep.CatalogItem{{Name: "pen", Price: 10, Available: true}},
+			},
+		},
+		{
+			name: "empty catalog",
+			setup: func(mockCatalog *mockCatalogService) *http.Request {
+				mockCatalog.On("List", mock.Anything, validUsername, model.ItemFilter{}, false).
+					Return([]model.CatalogItem{}, nil)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/items", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   rep.ItemsResponse{Items: []rep.CatalogItem{}},
+		},
+		{
+			name: "invalid sort",
+			setup: func(mockCatalog *mockCatalogService) *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/api/items?sort=weight", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: `invalid sort "weight"`},
+		},
+		{
+			name: "invalid affordable flag",
+			setup: func(mockCatalog *mockCatalogService) *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/api/items?affordable=maybe", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "employee not found",
+			setup: func(mockCatalog *mockCatalogService) *http.Request {
+				mockCatalog.On("List", mock.Anything, validUsername, model.ItemFilter{}, false).
+					Return(nil, service.ErrEmployeeNotFound)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/items", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   rep.ErrorResponse{Errors: "employee not found"},
+		},
+		{
+			name: "missing JWT token in context",
+			setup: func(mockCatalog *mockCatalogService) *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/api/items", nil)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   rep.ErrorResponse{Errors: "internal server error"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockCatalogService := new(mockCatalogService)
+
+			req := tc.setup(mockCatalogService)
+
+			handler := handlers.NewItemsHandlerFunc(logger, mockCatalogService)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockCatalogService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/lib/jwtkeys"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	edKey := newTestKey(t, "ed-key")
+
+	keys, err := jwtkeys.NewKeySet("ed-key", edKey,
+		jwtkeys.Key{Id: "rsa-key", Method: jwt.SigningMethodRS256, Public: &rsaKey.PublicKey})
+	assert.NoError(t, err)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+	handler := handlers.NewJWKSHandlerFunc(logger, keys)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	assert.NotEmpty(t, w.Result().Header.Get("Cache-Control"))
+
+	var jwks rep.JWKSResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &jwks))
+	assert.Len(t, jwks.Keys, 2)
+
+	assert.Equal(t, "ed-key", jwks.Keys[0].Kid)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Alg)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+	assert.NotEmpty(t, jwks.Keys[0].X)
+
+	assert.Equal(t, "rsa-key", jwks.Keys[1].Kid)
+	assert.Equal(t, "RSA", jwks.Keys[1].Kty)
+	assert.Equal(t, "RS256", jwks.Keys[1].Alg)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()), jwks.Keys[1].N)
+	assert.NotContains(t, w.Body.String(), `"d"`)
+}
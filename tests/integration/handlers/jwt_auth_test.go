@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	mw "avito-shop/internal/http-server/middleware"
+	"avito-shop/internal/lib/jwtkeys"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+type mockSessionChecker struct {
+	mock.Mock
+}
+
+func (m *mockSessionChecker) SessionActive(ctx context.Context, sessionId uuid.UUID) (bool, error) {
+	args := m.Called(ctx, sessionId)
+	return args.Bool(0), args.Error(1)
+}
+
+type mockAccountChecker struct {
+	mock.Mock
+}
+
+func (m *mockAccountChecker) AccountStatus(ctx context.Context, employeeId uuid.UUID) (model.EmployeeStatus, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).(model.EmployeeStatus), args.Error(1)
+}
+
+func newTestKey(t *testing.T, kid string) jwtkeys.Key {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	return jwtkeys.Key{Id: kid, Method: jwt.SigningMethodEdDSA, Private: private, Public: public}
+}
+
+func signTestToken(t *testing.T, keys *jwtkeys.KeySet, sessionId uuid.UUID) string {
+	token, err := keys.Sign(&service.TokenClaims{
+		Username:   "user",
+		EmployeeId: uuid.New(),
+		SessionId:  sessionId,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	})
+	assert.NoError(t, err)
+	return token
+}
+
+func TestJwtAuthMiddleware(t *testing.T) {
+	sessionId := uuid.New()
+	keys, err := jwtkeys.NewKeySet("current", newTestKey(t, "current"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		setup          func(*mockSessionChecker, *mockAccountChecker)
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "active session",
+			setup: func(mockSessions *mockSessionChecker, mockAccounts *mockAccountChecker) {
+				mockSessions.On("SessionActive", mock.Anything, sessionId).Return(true, nil)
+				mockAccounts.On("AccountStatus", mock.Anything, mock.Anything).Return(model.EmployeeActive, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "revoked session",
+			setup: func(mockSessions *mockSessionChecker, mockAccounts *mockAccountChecker) {
+				mockSessions.On("SessionActive", mock.Anything, sessionId).Return(false, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   rep.ErrorResponse{Errors: "session revoked"},
+		},
+		{
+			name: "session check failure",
+			setup: func(mockSessions *mockSessionChecker, mockAccounts *mockAccountChecker) {
+				mockSessions.On("SessionActive", mock.Anything, sessionId).Return(false, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   rep.ErrorResponse{Errors: "internal error"},
+		},
+		{
+			name: "frozen account keeps access",
+			setup: func(mockSessions *mockSessionChecker, mockAccounts *mockAccountChecker) {
+				mockSessions.On("SessionActive", mock.Anything, sessionId).Return(true, nil)
+				mockAccounts.On("AccountStatus", mock.Anything, mock.Anything).Return(model.EmployeeFrozen, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "deactivated account",
+			setup: func(mockSessions *mockSessionChecker, mockAccounts *mockAccountChecker) {
+				mockSessions.On("SessionActive", mock.Anything, sessionId).Return(true, nil)
+				mockAccounts.On("AccountStatus", mock.Anything, mock.Anything).Return(model.EmployeeDeactivated, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   rep.ErrorResponse{Errors: "account is deactivated"},
+		},
+		{
+			name: "unknown employee",
+			setup: func(mockSessions *mockSessionChecker, mockAccounts *mockAccountChecker) {
+				mockSessions.On("SessionActive", mock.Anything, sessionId).Return(true, nil)
+				mockAccounts.On("AccountStatus", mock.Anything, mock.Anything).
+					Return(model.EmployeeStatus(""), service.ErrEmployeeNotFound)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid token"},
+		},
+		{
+			name: "account check failure",
+			setup: func(mockSessions *mockSessionChecker, mockAccounts *mockAccountChecker) {
+				mockSessions.On("SessionActive", mock.Anything, sessionId).Return(true, nil)
+				mockAccounts.On("AccountStatus", mock.Anything, mock.Anything).
+					Return(model.EmployeeStatus(""), errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   rep.ErrorResponse{Errors: "internal error"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockSessions := new(mockSessionChecker)
+			mockAccounts := new(mockAccountChecker)
+			tc.setup(mockSessions, mockAccounts)
+
+			token := signTestToken(t, keys, sessionId)
+
+			handler := mw.NewJwtAuth(logger, keys, mockSessions, mockAccounts)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockSessions.AssertExpectations(t)
+			mockAccounts.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJwtAuthMiddleware_KeyRotation(t *testing.T) {
+	sessionId := uuid.New()
+	retiring := newTestKey(t, "retiring")
+	current := newTestKey(t, "current")
+
+	oldKeys, err := jwtkeys.NewKeySet("retiring", retiring)
+	assert.NoError(t, err)
+	strangerKeys, err := jwtkeys.NewKeySet("stranger", newTestKey(t, "stranger"))
+	assert.NoError(t, err)
+
+	// after rotation only the public part of the retiring key is kept
+	keys, err := jwtkeys.NewKeySet("current", current,
+		jwtkeys.Key{Id: retiring.Id, Method: retiring.Method, Public: retiring.Public})
+	assert.NoError(t, err)
+
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, &service.TokenClaims{SessionId: sessionId,
+		StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()}})
+	hmacToken.Header["kid"] = "current"
+	forged, err := hmacToken.SignedString([]byte("secret"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{
+			name:           "token of the signing key",
+			token:          signTestToken(t, keys, sessionId),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token of the retiring key",
+			token:          signTestToken(t, oldKeys, sessionId),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown kid",
+			token:          signTestToken(t, strangerKeys, sessionId),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "algorithm mismatch",
+			token:          forged,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockSessions := new(mockSessionChecker)
+			mockSessions.On("SessionActive", mock.Anything, sessionId).Return(true, nil).Maybe()
+			mockAccounts := new(mockAccountChecker)
+			mockAccounts.On("AccountStatus", mock.Anything, mock.Anything).Return(model.EmployeeActive, nil).Maybe()
+
+			handler := mw.NewJwtAuth(logger, keys, mockSessions, mockAccounts)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"avito-shop/internal/http-server/dto"
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+type mockKudosService struct {
+	mock.Mock
+}
+
+func (m *mockKudosService) Kudos(ctx context.Context, filter model.KudosFilter) (*model.KudosPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.KudosPage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestNewKudosHandlerFunc(t *testing.T) {
+	kudosId := uuid.New()
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	cursor := &model.Cursor{CreatedAt: createdAt, Id: kudosId}
+
+	tests := []struct {
+		name           string
+		url            string
+		setup          func(*mockKudosService)
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "first page with next cursor",
+			url:  "/api/kudos?category=thanks&limit=1",
+			setup: func(mockKudos *mockKudosService) {
+				mockKudos.On("Kudos", mock.Anything, model.KudosFilter{Category: model.CategoryThanks, Limit: 1}).
+					Return(&model.KudosPage{
+						Entries: []model.Kudos{{
+							Id:        kudosId,
+							From:      "alice",
+							To:        "bob",
+							Amount:    10,
+							Message:   "thanks for the review",
+							Category:  model.CategoryThanks,
+							CreatedAt: createdAt,
+						}},
+						NextCursor: cursor,
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.KudosResponse{
+				Entries: []rep.KudosEntry{{
+					Id:        kudosId.String(),
+					FromUser:  "alice",
+					ToUser:    "bob",
+					Amount:    10,
+					Message:   "thanks for the review",
+					Category:  "thanks",
+					CreatedAt: createdAt,
+				}},
+				NextCursor: dto.EncodeCursor(cursor),
+			},
+		},
+		{
+			name: "next page by cursor",
+			url:  "/api/kudos?cursor=" + dto.EncodeCursor(cursor),
+			setup: func(mockKudos *mockKudosService) {
+				mockKudos.On("Kudos", mock.Anything, model.KudosFilter{Cursor: cursor}).
+					Return(&model.KudosPage{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   rep.KudosResponse{Entries: []rep.KudosEntry{}},
+		},
+		{
+			name:           "invalid limit",
+			url:            "/api/kudos?limit=zero",
+			setup:          func(*mockKudosService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: `invalid limit "zero"`},
+		},
+		{
+			name: "unknown category",
+			url:  "/api/kudos?category=bribe",
+			setup: func(mockKudos *mockKudosService) {
+				mockKudos.On("Kudos", mock.Anything, model.KudosFilter{Category: "bribe"}).
+					Return(nil, service.ErrInvalidTransferCategory)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid category"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockKudos := new(mockKudosService)
+			tc.setup(mockKudos)
+
+			req := withClaims(httptest.NewRequest(http.MethodGet, tc.url, nil), "valid-user")
+
+			handler := handlers.NewKudosHandlerFunc(logger, mockKudos)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockKudos.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockMarketplaceService struct {
+	mock.Mock
+}
+
+func (m *mockMarketplaceService) Create(
+	ctx context.Context, username string, item string, quantity int, price int) (*model.Listing, error) {
+	args := m.Called(ctx, username, item, quantity, price)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Listing), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockMarketplaceService) List(ctx context.Context, filter model.ListingFilter) ([]model.Listing, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Listing), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockMarketplaceService) Buy(
+	ctx context.Context, username string, listingId uuid.UUID) (*model.Listing, error) {
+	args := m.Called(ctx, username, listingId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Listing), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockMarketplaceService) Cancel(
+	ctx context.Context, username string, listingId uuid.UUID) (*model.Listing, error) {
+	args := m.Called(ctx, username, listingId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Listing), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupMarketplaceRouter(log *slog.Logger, marketplaceService *mockMarketplaceService) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/api/marketplace/listings",
+		handlers.NewCreateListingHandlerFunc(log, marketplaceService, validator.New()))
+	r.Get("/api/marketplace/listings", handlers.NewListListingsHandlerFunc(log, marketplaceService))
+	r.Post("/api/marketplace/listings/{listingId}/buy", handlers.NewBuyListingHandlerFunc(log, marketplaceService))
+	r.Post("/api/marketplace/listings/{listingId}/cancel",
+		handlers.NewCancelListingHandlerFunc(log, marketplaceService))
+	return r
+}
+
+func TestMarketplaceHandlers(t *testing.T) {
+	validUsername := "valid-user"
+	listingId := uuid.New()
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(7 * 24 * time.Hour)
+	body := `{"item":"cup","quantity":2,"price":50}`
+
+	listing := func(status model.ListingStatus) *model.Listing {
+		return &model.Listing{
+			Id:        listingId,
+			Seller:    "seller",
+			Item:      "cup",
+			Quantity:  2,
+			Price:     50,
+			Status:    status,
+			ExpiresAt: expiresAt,
+			CreatedAt: createdAt,
+		}
+	}
+	listingResponse := func(status model.ListingStatus) rep.ListingResponse {
+		return rep.ListingResponse{
+			ListingId: listingId.String(),
+			Seller:    "seller",
+			Item:      "cup",
+			Quantity:  2,
+			Price:     50,
+			Status:    string(status),
+			ExpiresAt: expiresAt,
+			CreatedAt: createdAt,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		setup          func(*mockMarketplaceService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "create listing",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				mockMarketplace.On("Create", mock.Anything, validUsername, "cup", 2, 50).
+					Return(listing(model.ListingActive), nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/marketplace/listings", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   listingResponse(model.ListingActive),
+		},
+		{
+			name: "create listing without price",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/marketplace/listings",
+					strings.NewReader(`{"item":"cup","quantity":2}`))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "create listing without enough items",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				mockMarketplace.On("Create", mock.Anything, validUsername, "cup", 2, 50).
+					Return(nil, service.ErrNotEnoughItems)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/marketplace/listings", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "not enough items in inventory", Code: "not_enough_items"},
+		},
+		{
+			name: "list listings by item",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				mockMarketplace.On("List", mock.Anything, model.ListingFilter{Item: "cup"}).
+					Return([]model.Listing{*listing(model.ListingActive)}, nil)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/marketplace/listings?item=cup", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.ListingsResponse{
+				Listings: []rep.ListingResponse{listingResponse(model.ListingActive)},
+			},
+		},
+		{
+			name: "buy listing",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				mockMarketplace.On("Buy", mock.Anything, validUsername, listingId).
+					Return(listing(model.ListingSold), nil)
+
+				req := httptest.NewRequest(http.MethodPost,
+					"/api/marketplace/listings/"+listingId.String()+"/buy", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   listingResponse(model.ListingSold),
+		},
+		{
+			name: "buy sold listing",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				mockMarketplace.On("Buy", mock.Anything, validUsername, listingId).
+					Return(nil, service.ErrListingNotActive)
+
+				req := httptest.NewRequest(http.MethodPost,
+					"/api/marketplace/listings/"+listingId.String()+"/buy", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   rep.ErrorResponse{Errors: "listing is no longer active", Code: "listing_not_active"},
+		},
+		{
+			name: "buy own listing",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				mockMarketplace.On("Buy", mock.Anything, validUsername, listingId).
+					Return(nil, service.ErrOwnListing)
+
+				req := httptest.NewRequest(http.MethodPost,
+					"/api/marketplace/listings/"+listingId.String()+"/buy", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "can't buy your own listing", Code: "own_listing"},
+		},
+		{
+			name: "buy without enough coins",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				mockMarketplace.On("Buy", mock.Anything, validUsername, listingId).
+					Return(nil, service.ErrNotEnoughCoins)
+
+				req := httptest.NewRequest(http.MethodPost,
+					"/api/marketplace/listings/"+listingId.String()+"/buy", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "not enough coins", Code: "not_enough_coins"},
+		},
+		{
+			name: "buy with malformed id",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/marketplace/listings/not-a-uuid/buy", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid listing id"},
+		},
+		{
+			name: "cancel listing",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				mockMarketplace.On("Cancel", mock.Anything, validUsername, listingId).
+					Return(listing(model.ListingCancelled), nil)
+
+				req := httptest.NewRequest(http.MethodPost,
+					"/api/marketplace/listings/"+listingId.String()+"/cancel", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   listingResponse(model.ListingCancelled),
+		},
+		{
+			name: "cancel unknown listing",
+			setup: func(mockMarketplace *mockMarketplaceService) *http.Request {
+				mockMarketplace.On("Cancel", mock.Anything, validUsername, listingId).
+					Return(nil, service.ErrListingNotFound)
+
+				req := httptest.NewRequest(http.MethodPost,
+					"/api/marketplace/listings/"+listingId.String()+"/cancel", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   rep.ErrorResponse{Errors: "listing not found", Code: "listing_not_found"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockMarketplace := new(mockMarketplaceService)
+
+			req := tc.setup(mockMarketplace)
+
+			w := httptest.NewRecorder()
+			setupMarketplaceRouter(logger, mockMarketplace).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockMarketplace.AssertExpectations(t)
+		})
+	}
+}
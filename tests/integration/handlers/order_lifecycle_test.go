@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	mw "avito-shop/internal/http-server/middleware"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockOrderService struct {
+	mock.Mock
+}
+
+func (m *mockOrderService) ListForEmployee(
+	ctx context.Context, username string, status model.OrderStatus) ([]model.Order, error) {
+	args := m.Called(ctx, username, status)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockOrderService) Cancel(ctx context.Context, username string, orderId uuid.UUID) (*model.Order, error) {
+	args := m.Called(ctx, username, orderId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockOrderService) List(ctx context.Context, status model.OrderStatus) ([]model.Order, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockOrderService) Advance(
+	ctx context.Context, orderId uuid.UUID, status model.OrderStatus) (*model.Order, error) {
+	args := m.Called(ctx, orderId, status)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupOrdersRouter(log *slog.Logger, orderService *mockOrderService) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/api/orders", handlers.NewListOrdersHandlerFunc(log, orderService))
+	r.Post("/api/orders/{orderId}/cancel", handlers.NewCancelOrderHandlerFunc(log, orderService))
+	r.Route("/api/admin", func(r chi.Router) {
+		r.Use(mw.NewRequireRole(log, model.RoleAdmin))
+		r.Get("/orders", handlers.NewAdminListOrdersHandlerFunc(log, orderService))
+		r.Post("/orders/{orderId}/status", handlers.NewAdvanceOrderHandlerFunc(log, orderService, validator.New()))
+	})
+	return r
+}
+
+func TestOrderLifecycleHandlers(t *testing.T) {
+	validUsername := "valid-user"
+	orderId := uuid.New()
+	purchaseId := uuid.New()
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	order := func(status model.OrderStatus) *model.Order {
+		return &model.Order{
+			Id:        orderId,
+			Employee:  validUsername,
+			Status:    status,
+			Items:     []model.OrderItem{{PurchaseId: purchaseId, Item: "cup", Quantity: 2, Price: 20}},
+			Total:     40,
+			CreatedAt: createdAt,
+		}
+	}
+	orderResponse := func(status model.OrderStatus) rep.OrderResponse {
+		return rep.OrderResponse{
+			OrderId:  orderId.String(),
+			Employee: validUsername,
+			Status:   string(status),
+			Items: []rep.OrderItem{
+				{PurchaseId: purchaseId.String(), Item: "cup", Quantity: 2, Price: 20, Amount: 40},
+			},
+			Total:     40,
+			CreatedAt: createdAt,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		setup          func(*mockOrderService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "list own orders by status",
+			setup: func(mockOrders *mockOrderService) *http.Request {
+				mockOrders.On("ListForEmployee", mock.Anything, validUsername, model.OrderPlaced).
+					Return([]model.Order{*order(model.OrderPlaced)}, nil)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/orders?status=placed", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   rep.OrdersResponse{Orders: []rep.OrderResponse{orderResponse(model.OrderPlaced)}},
+		},
+		{
+			name: "list with unknown status",
+			setup: func(mockOrders *mockOrderService) *http.Request {
+				mockOrders.On("ListForEmployee", mock.Anything, validUsername, model.OrderStatus("lost")).
+					Return(nil, service.ErrInvalidOrderStatus)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/orders?status=lost", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid order status", Code: "invalid_status"},
+		},
+		{
+			name: "cancel placed order",
+			setup: func(mockOrders *mockOrderService) *http.Request {
+				mockOrders.On("Cancel", mock.Anything, validUsername, orderId).
+					Return(order(model.OrderCancelled), nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/orders/"+orderId.String()+"/cancel", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   orderResponse(model.OrderCancelled),
+		},
+		{
+			name: "cancel order ready for pickup",
+			setup: func(mockOrders *mockOrderService) *http.Request {
+				mockOrders.On("Cancel", mock.Anything, validUsername, orderId).
+					Return(nil, service.ErrOrderTransition)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/orders/"+orderId.String()+"/cancel", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: rep.ErrorResponse{
+				Errors: "order status transition not allowed", Code: "invalid_transition"},
+		},
+		{
+			name: "cancel unknown order",
+			setup: func(mockOrders *mockOrderService) *http.Request {
+				mockOrders.On("Cancel", mock.Anything, validUsername, orderId).
+					Return(nil, service.ErrOrderNotFound)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/orders/"+orderId.String()+"/cancel", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   rep.ErrorResponse{Errors: "order not found", Code: "order_not_found"},
+		},
+		{
+			name: "cancel with malformed order id",
+			setup: func(mockOrders *mockOrderService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/orders/not-a-uuid/cancel", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid order id"},
+		},
+		{
+			name: "admin lists all orders",
+			setup: func(mockOrders *mockOrderService) *http.Request {
+				mockOrders.On("List", mock.Anything, model.OrderStatus("")).
+					Return([]model.Order{}, nil)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/admin/orders", nil)
+				return withRole(req, model.RoleAdmin)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   rep.OrdersResponse{Orders: []rep.OrderResponse{}},
+		},
+		{
+			name: "admin marks order ready for pickup",
+			setup: func(mockOrders *mockOrderService) *http.Request {
+				mockOrders.On("Advance", mock.Anything, orderId, model.OrderReadyForPickup).
+					Return(order(model.OrderReadyForPickup), nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/"+orderId.String()+"/status",
+					strings.NewReader(`{"status":"ready_for_pickup"}`))
+				return withRole(req, model.RoleAdmin)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   orderResponse(model.OrderReadyForPickup),
+		},
+		{
+			name: "admin status without body",
+			setup: func(mockOrders *mockOrderService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/"+orderId.String()+"/status",
+					strings.NewReader(`{}`))
+				return withRole(req, model.RoleAdmin)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "employee cannot advance orders",
+			setup: func(mockOrders *mockOrderService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/"+orderId.String()+"/status",
+					strings.NewReader(`{"status":"delivered"}`))
+				return withRole(req, model.RoleEmployee)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   rep.ErrorResponse{Errors: "forbidden"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockOrders := new(mockOrderService)
+
+			req := tc.setup(mockOrders)
+
+			w := httptest.NewRecorder()
+			setupOrdersRouter(logger, mockOrders).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockOrders.AssertExpectations(t)
+		})
+	}
+}
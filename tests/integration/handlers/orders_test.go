@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockCheckoutService struct {
+	mock.Mock
+}
+
+func (m *mockCheckoutService) Checkout(
+	ctx context.Context, username string, lines []model.OrderLine) (*model.Order, error) {
+	args := m.Called(ctx, username, lines)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestNewCheckoutHandlerFunc(t *testing.T) {
+	validUsername := "valid-user"
+	orderId := uuid.New()
+	cupPurchaseId, penPurchaseId := uuid.New(), uuid.New()
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		setup          func(*mockCheckoutService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "successful checkout",
+			setup: func(mockCheckout *mockCheckoutService) *http.Request {
+				mockCheckout.On("Checkout", mock.Anything, validUsername, []model.OrderLine{
+					{Item: "cup", Quantity: 2},
+					{Item: "pen", Quantity: 1},
+				}).Return(&model.Order{
+					Id: orderId,
+					Items: []model.OrderItem{
+						{PurchaseId: cupPurchaseId, Item: "cup", Quantity: 2, Price: 20},
+						{PurchaseId: penPurchaseId, Item: "pen", Quantity: 1, Price: 10},
+					},
+					Total:     50,
+					CreatedAt: createdAt,
+				}, nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/orders",
+					strings.NewReader(`{"items":[{"item":"cup","quantity":2},{"item":"pen","quantity":1}]}`))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: rep.OrderResponse{
+				OrderId: orderId.String(),
+				Items: []rep.OrderItem{
+					{PurchaseId: cupPurchaseId.String(), Item: "cup", Quantity: 2, Price: 20, Amount: 40},
+					{PurchaseId: penPurchaseId.String(), Item: "pen", Quantity: 1, Price: 10, Amount: 10},
+				},
+				Total:     50,
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "empty cart",
+			setup: func(mockCheckout *mockCheckoutService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(`{"items":[]}`))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "zero quantity",
+			setup: func(mockCheckout *mockCheckoutService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/orders",
+					strings.NewReader(`{"items":[{"item":"cup","quantity":0}]}`))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "not enough coins",
+			setup: func(mockCheckout *mockCheckoutService) *http.Request {
+				mockCheckout.On("Checkout", mock.Anything, validUsername, mock.Anything).
+					Return(nil, service.ErrNotEnoughCoins)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/orders",
+					strings.NewReader(`{"items":[{"item":"hoody","quantity":5}]}`))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "not enough coins", Code: "not_enough_coins"},
+		},
+		{
+			name: "out of stock",
+			setup: func(mockCheckout *mockCheckoutService) *http.Request {
+				mockCheckout.On("Checkout", mock.Anything, validUsername, mock.Anything).
+					Return(nil, service.ErrOutOfStock)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/orders",
+					strings.NewReader(`{"items":[{"item":"pink-hoody","quantity":1}]}`))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   rep.ErrorResponse{Errors: "item is out of stock", Code: "out_of_stock"},
+		},
+		{
+			name: "missing JWT token in context",
+			setup: func(mockCheckout *mockCheckoutService) *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/api/orders",
+					strings.NewReader(`{"items":[{"item":"cup","quantity":1}]}`))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   rep.ErrorResponse{Errors: "internal server error"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockCheckout := new(mockCheckoutService)
+
+			req := tc.setup(mockCheckout)
+
+			handler := handlers.NewCheckoutHandlerFunc(logger, mockCheckout, validator.New())
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockCheckout.AssertExpectations(t)
+		})
+	}
+}
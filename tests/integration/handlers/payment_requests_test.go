@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockPaymentRequestService struct {
+	mock.Mock
+}
+
+func (m *mockPaymentRequestService) Create(
+	ctx context.Context, requester string, payer string, amount int, message string) (*model.PaymentRequest, error) {
+	args := m.Called(ctx, requester, payer, amount, message)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.PaymentRequest), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPaymentRequestService) List(
+	ctx context.Context, username string, filter model.PaymentRequestFilter) ([]model.PaymentRequest, error) {
+	args := m.Called(ctx, username, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.PaymentRequest), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPaymentRequestService) Accept(
+	ctx context.Context, username string, requestId uuid.UUID) (*model.PaymentRequest, error) {
+	args := m.Called(ctx, username, requestId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.PaymentRequest), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPaymentRequestService) Decline(
+	ctx context.Context, username string, requestId uuid.UUID) (*model.PaymentRequest, error) {
+	args := m.Called(ctx, username, requestId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.PaymentRequest), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupPaymentRequestsRouter(log *slog.Logger, paymentRequestService *mockPaymentRequestService) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/api/paymentRequests",
+		handlers.NewCreatePaymentRequestHandlerFunc(log, paymentRequestService, validator.New()))
+	r.Get("/api/paymentRequests", handlers.NewListPaymentRequestsHandlerFunc(log, paymentRequestService))
+	r.Post("/api/paymentRequests/{requestId}/accept",
+		handlers.NewAcceptPaymentRequestHandlerFunc(log, paymentRequestService))
+	r.Post("/api/paymentRequests/{requestId}/decline",
+		handlers.NewDeclinePaymentRequestHandlerFunc(log, paymentRequestService))
+	return r
+}
+
+func TestPaymentRequestHandlers(t *testing.T) {
+	validUsername := "valid-user"
+	requestId := uuid.New()
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(72 * time.Hour)
+	resolvedAt := createdAt.Add(time.Hour)
+	body := `{"fromUser":"payer","amount":15,"message":"hoodie"}`
+
+	paymentRequest := func(status model.PaymentRequestStatus, resolved *time.Time) *model.PaymentRequest {
+		return &model.PaymentRequest{
+			Id:         requestId,
+			Requester:  validUsername,
+			Payer:      "payer",
+			Amount:     15,
+			Message:    "hoodie",
+			Status:     status,
+			ExpiresAt:  expiresAt,
+			ResolvedAt: resolved,
+			CreatedAt:  createdAt,
+		}
+	}
+	paymentRequestResponse := func(status model.PaymentRequestStatus, resolved *time.Time) rep.PaymentRequestResponse {
+		return rep.PaymentRequestResponse{
+			RequestId:  requestId.String(),
+			Requester:  validUsername,
+			Payer:      "payer",
+			Amount:     15,
+			Message:    "hoodie",
+			Status:     string(status),
+			ExpiresAt:  expiresAt,
+			ResolvedAt: resolved,
+			CreatedAt:  createdAt,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		setup          func(*mockPaymentRequestService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "create request",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				mockRequests.On("Create", mock.Anything, validUsername, "payer", 15, "hoodie").
+					Return(paymentRequest(model.PaymentRequestPending, nil), nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   paymentRequestResponse(model.PaymentRequestPending, nil),
+		},
+		{
+			name: "create request without payer",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests", strings.NewReader(`{"amount":15}`))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "create request for unknown payer",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				mockRequests.On("Create", mock.Anything, validUsername, "payer", 15, "hoodie").
+					Return(nil, service.ErrPayerNotFound)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "payer not found", Code: "payer_not_found"},
+		},
+		{
+			name: "list pending outgoing requests",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				filter := model.PaymentRequestFilter{
+					Direction: model.PaymentRequestsOutgoing,
+					Status:    model.PaymentRequestPending,
+				}
+				mockRequests.On("List", mock.Anything, validUsername, filter).
+					Return([]model.PaymentRequest{*paymentRequest(model.PaymentRequestPending, nil)}, nil)
+
+				req := httptest.NewRequest(http.MethodGet,
+					"/api/paymentRequests?direction=outgoing&status=pending", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.PaymentRequestsResponse{
+				Requests: []rep.PaymentRequestResponse{paymentRequestResponse(model.PaymentRequestPending, nil)},
+			},
+		},
+		{
+			name: "list with unknown direction",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				filter := model.PaymentRequestFilter{Direction: "sideways"}
+				mockRequests.On("List", mock.Anything, validUsername, filter).
+					Return(nil, service.ErrInvalidRequestFilter)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/paymentRequests?direction=sideways", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid direction or status", Code: "invalid_filter"},
+		},
+		{
+			name: "accept request",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				mockRequests.On("Accept", mock.Anything, validUsername, requestId).
+					Return(paymentRequest(model.PaymentRequestAccepted, &resolvedAt), nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests/"+requestId.String()+"/accept", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   paymentRequestResponse(model.PaymentRequestAccepted, &resolvedAt),
+		},
+		{
+			name: "accept without enough coins",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				mockRequests.On("Accept", mock.Anything, validUsername, requestId).
+					Return(nil, service.ErrNotEnoughCoins)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests/"+requestId.String()+"/accept", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "not enough coins", Code: "not_enough_coins"},
+		},
+		{
+			name: "accept expired request",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				mockRequests.On("Accept", mock.Anything, validUsername, requestId).
+					Return(nil, service.ErrPaymentRequestExpired)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests/"+requestId.String()+"/accept", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   rep.ErrorResponse{Errors: "payment request expired", Code: "request_expired"},
+		},
+		{
+			name: "decline request",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				mockRequests.On("Decline", mock.Anything, validUsername, requestId).
+					Return(paymentRequest(model.PaymentRequestDeclined, &resolvedAt), nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests/"+requestId.String()+"/decline", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   paymentRequestResponse(model.PaymentRequestDeclined, &resolvedAt),
+		},
+		{
+			name: "decline unknown request",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				mockRequests.On("Decline", mock.Anything, validUsername, requestId).
+					Return(nil, service.ErrPaymentRequestNotFound)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests/"+requestId.String()+"/decline", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   rep.ErrorResponse{Errors: "payment request not found", Code: "request_not_found"},
+		},
+		{
+			name: "malformed request id",
+			setup: func(mockRequests *mockPaymentRequestService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests/not-a-uuid/accept", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request id"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockRequests := new(mockPaymentRequestService)
+
+			req := tc.setup(mockRequests)
+
+			w := httptest.NewRecorder()
+			setupPaymentRequestsRouter(logger, mockRequests).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockRequests.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+type mockPendingTransferService struct {
+	mock.Mock
+}
+
+func (m *mockPendingTransferService) ListPending(
+	ctx context.Context, approver string) ([]model.PendingTransfer, error) {
+	args := m.Called(ctx, approver)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPendingTransferService) ApprovePending(
+	ctx context.Context, approver string, transferId uuid.UUID) (*model.PendingTransfer, error) {
+	args := m.Called(ctx, approver, transferId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPendingTransferService) RejectPending(
+	ctx context.Context, approver string, transferId uuid.UUID) (*model.PendingTransfer, error) {
+	args := m.Called(ctx, approver, transferId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupPendingTransfersRouter(log *slog.Logger, transferService *mockPendingTransferService) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/api/pendingTransfers", handlers.NewListPendingTransfersHandlerFunc(log, transferService))
+	r.Post("/api/pendingTransfers/{transferId}/approve",
+		handlers.NewApprovePendingTransferHandlerFunc(log, transferService))
+	r.Post("/api/pendingTransfers/{transferId}/reject",
+		handlers.NewRejectPendingTransferHandlerFunc(log, transferService))
+	return r
+}
+
+func TestPendingTransferHandlers(t *testing.T) {
+	approver := "manager"
+	transferId := uuid.New()
+	pendingTransferURL := "/api/pendingTransfers/" + transferId.String()
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	resolvedAt := createdAt.Add(time.Hour)
+
+	pendingTransfer := func(status model.PendingTransferStatus, resolved *time.Time) *model.PendingTransfer {
+		return &model.PendingTransfer{
+			Id:         transferId,
+			From:       "sender",
+			To:         "receiver",
+			Amount:     600,
+			Message:    "bonus",
+			Status:     status,
+			CreatedAt:  createdAt,
+			ResolvedAt: resolved,
+		}
+	}
+	pendingTransferResponse := func(
+		status model.PendingTransferStatus, resolved *time.Time) rep.PendingTransferResponse {
+		return rep.PendingTransferResponse{
+			TransferId: transferId.String(),
+			FromUser:   "sender",
+			ToUser:     "receiver",
+			Amount:     600,
+			Message:    "bonus",
+			Status:     string(status),
+			CreatedAt:  createdAt,
+			ResolvedAt: resolved,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		setup          func(*mockPendingTransferService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "list pending transfers",
+			setup: func(mockTransfers *mockPendingTransferService) *http.Request {
+				mockTransfers.On("ListPending", mock.Anything, approver).
+					Return([]model.PendingTransfer{*pendingTransfer(model.PendingTransferPending, nil)}, nil)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/pendingTransfers", nil)
+				return withClaims(req, approver)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.PendingTransfersResponse{
+				Transfers: []rep.PendingTransferResponse{pendingTransferResponse(model.PendingTransferPending, nil)},
+			},
+		},
+		{
+			name: "approve transfer",
+			setup: func(mockTransfers *mockPendingTransferService) *http.Request {
+				mockTransfers.On("ApprovePending", mock.Anything, approver, transferId).
+					Return(pendingTransfer(model.PendingTransferApproved, &resolvedAt), nil)
+
+				req := httptest.NewRequest(http.MethodPost, pendingTransferURL+"/approve", nil)
+				return withClaims(req, approver)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   pendingTransferResponse(model.PendingTransferApproved, &resolvedAt),
+		},
+		{
+			name: "approve as someone else",
+			setup: func(mockTransfers *mockPendingTransferService) *http.Request {
+				mockTransfers.On("ApprovePending", mock.Anything, approver, transferId).
+					Return(nil, service.ErrNotApprover)
+
+				req := httptest.NewRequest(http.MethodPost, pendingTransferURL+"/approve", nil)
+				return withClaims(req, approver)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: rep.ErrorResponse{
+				Errors: "only an admin or the sender's manager can decide", Code: "not_approver"},
+		},
+		{
+			name: "reject transfer",
+			setup: func(mockTransfers *mockPendingTransferService) *http.Request {
+				mockTransfers.On("RejectPending", mock.Anything, approver, transferId).
+					Return(pendingTransfer(model.PendingTransferRejected, &resolvedAt), nil)
+
+				req := httptest.NewRequest(http.MethodPost, pendingTransferURL+"/reject", nil)
+				return withClaims(req, approver)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   pendingTransferResponse(model.PendingTransferRejected, &resolvedAt),
+		},
+		{
+			name: "reject resolved transfer",
+			setup: func(mockTransfers *mockPendingTransferService) *http.Request {
+				mockTransfers.On("RejectPending", mock.Anything, approver, transferId).
+					Return(nil, service.ErrPendingTransferNotPending)
+
+				req := httptest.NewRequest(http.MethodPost, pendingTransferURL+"/reject", nil)
+				return withClaims(req, approver)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   rep.ErrorResponse{Errors: "transfer is already resolved", Code: "transfer_not_pending"},
+		},
+		{
+			name: "malformed transfer id",
+			setup: func(mockTransfers *mockPendingTransferService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/pendingTransfers/not-a-uuid/approve", nil)
+				return withClaims(req, approver)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid transfer id"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockTransfers := new(mockPendingTransferService)
+
+			req := tc.setup(mockTransfers)
+
+			w := httptest.NewRecorder()
+			setupPendingTransfersRouter(logger, mockTransfers).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockTransfers.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockReturnService struct {
+	mock.Mock
+}
+
+func (m *mockReturnService) Return(
+	ctx context.Context, username string, purchaseId uuid.UUID, quantity int) (*model.PurchaseReturn, error) {
+	args := m.Called(ctx, username, purchaseId, quantity)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.PurchaseReturn), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestNewReturnHandlerFunc(t *testing.T) {
+	validUsername := "valid-user"
+	purchaseId := uuid.New()
+	returnId := uuid.New()
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	body := `{"purchaseId":"` + purchaseId.String() + `","quantity":1}`
+
+	tests := []struct {
+		name           string
+		setup          func(*mockReturnService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "successful return",
+			setup: func(mockReturns *mockReturnService) *http.Request {
+				mockReturns.On("Return", mock.Anything, validUsername, purchaseId, 1).
+					Return(&model.PurchaseReturn{
+						Id:         returnId,
+						PurchaseId: purchaseId,
+						Item:       "cup",
+						Quantity:   1,
+						Amount:     20,
+						CreatedAt:  createdAt,
+					}, nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/returns", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: rep.ReturnResponse{
+				ReturnId:   returnId.String(),
+				PurchaseId: purchaseId.String(),
+				Item:       "cup",
+				Quantity:   1,
+				Amount:     20,
+				CreatedAt:  createdAt,
+			},
+		},
+		{
+			name: "malformed purchase id",
+			setup: func(mockReturns *mockReturnService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/returns",
+					strings.NewReader(`{"purchaseId":"cup","quantity":1}`))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "purchase not found",
+			setup: func(mockReturns *mockReturnService) *http.Request {
+				mockReturns.On("Return", mock.Anything, validUsername, purchaseId, 1).
+					Return(nil, service.ErrPurchaseNotFound)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/returns", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   rep.ErrorResponse{Errors: "purchase not found", Code: "purchase_not_found"},
+		},
+		{
+			name: "non-returnable item",
+			setup: func(mockReturns *mockReturnService) *http.Request {
+				mockReturns.On("Return", mock.Anything, validUsername, purchaseId, 1).
+					Return(nil, service.ErrItemNotReturnable)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/returns", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   rep.ErrorResponse{Errors: "item cannot be returned", Code: "item_not_returnable"},
+		},
+		{
+			name: "return window expired",
+			setup: func(mockReturns *mockReturnService) *http.Request {
+				mockReturns.On("Return", mock.Anything, validUsername, purchaseId, 1).
+					Return(nil, service.ErrReturnWindowExpired)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/returns", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   rep.ErrorResponse{Errors: "return window expired", Code: "return_window_expired"},
+		},
+		{
+			name: "order not delivered",
+			setup: func(mockReturns *mockReturnService) *http.Request {
+				mockReturns.On("Return", mock.Anything, validUsername, purchaseId, 1).
+					Return(nil, service.ErrOrderNotDelivered)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/returns", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: rep.ErrorResponse{
+				Errors: "order is not delivered yet, cancel it instead", Code: "order_not_delivered"},
+		},
+		{
+			name: "missing JWT token in context",
+			setup: func(mockReturns *mockReturnService) *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/api/returns", strings.NewReader(body))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   rep.ErrorResponse{Errors: "internal server error"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockReturns := new(mockReturnService)
+
+			req := tc.setup(mockReturns)
+
+			handler := handlers.NewReturnHandlerFunc(logger, mockReturns, validator.New())
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockReturns.AssertExpectations(t)
+		})
+	}
+}
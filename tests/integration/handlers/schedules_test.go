@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	rep "avito-shop/internal/http-server/dto/response"
+	"avito-shop/internal/http-server/handlers"
+	"avito-shop/internal/model"
+	"avito-shop/internal/service"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockScheduleService struct {
+	mock.Mock
+}
+
+func (m *mockScheduleService) Create(ctx context.Context, fromUser string, toUser string, amount int,
+	runAt time.Time, recurrence model.Recurrence, memo model.TransferMemo) (*model.ScheduledTransfer, error) {
+	args := m.Called(ctx, fromUser, toUser, amount, runAt, recurrence, memo)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.ScheduledTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduleService) List(ctx context.Context, username string) ([]model.ScheduledTransfer, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.ScheduledTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduleService) Cancel(
+	ctx context.Context, username string, scheduleId uuid.UUID) (*model.ScheduledTransfer, error) {
+	args := m.Called(ctx, username, scheduleId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.ScheduledTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduleService) Runs(
+	ctx context.Context, username string, scheduleId uuid.UUID) ([]model.ScheduledTransferRun, error) {
+	args := m.Called(ctx, username, scheduleId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.ScheduledTransferRun), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupSchedulesRouter(log *slog.Logger, scheduleService *mockScheduleService) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/api/schedules", handlers.NewCreateScheduleHandlerFunc(log, scheduleService, validator.New()))
+	r.Get("/api/schedules", handlers.NewListSchedulesHandlerFunc(log, scheduleService))
+	r.Post("/api/schedules/{scheduleId}/cancel", handlers.NewCancelScheduleHandlerFunc(log, scheduleService))
+	r.Get("/api/schedules/{scheduleId}/runs", handlers.NewScheduleRunsHandlerFunc(log, scheduleService))
+	return r
+}
+
+func TestScheduleHandlers(t *testing.T) {
+	validUsername := "valid-user"
+	scheduleId := uuid.New()
+	runId := uuid.New()
+	runAt := time.Date(2030, 3, 1, 9, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2030, 2, 1, 12, 0, 0, 0, time.UTC)
+	body := `{"toUser":"receiver","amount":10,"runAt":"2030-03-01T09:00:00Z","recurrence":"monthly","message":"rent"}`
+	memo := model.TransferMemo{Message: "rent"}
+
+	schedule := func(status model.ScheduleStatus) *model.ScheduledTransfer {
+		return &model.ScheduledTransfer{
+			Id:            scheduleId,
+			From:          validUsername,
+			To:            "receiver",
+			Amount:        10,
+			Message:       "rent",
+			Recurrence:    model.RecurrenceMonthly,
+			Status:        status,
+			StartAt:       runAt,
+			NextRunAt:     runAt,
+			NextAttemptAt: runAt,
+			CreatedAt:     createdAt,
+		}
+	}
+	scheduleResponse := func(status model.ScheduleStatus) rep.ScheduleResponse {
+		return rep.ScheduleResponse{
+			ScheduleId:    scheduleId.String(),
+			ToUser:        "receiver",
+			Amount:        10,
+			Message:       "rent",
+			Recurrence:    string(model.RecurrenceMonthly),
+			Status:        string(status),
+			NextRunAt:     runAt,
+			NextAttemptAt: runAt,
+			CreatedAt:     createdAt,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		setup          func(*mockScheduleService) *http.Request
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "create schedule",
+			setup: func(mockSchedules *mockScheduleService) *http.Request {
+				mockSchedules.On("Create", mock.Anything, validUsername, "receiver", 10, runAt,
+					model.RecurrenceMonthly, memo).Return(schedule(model.ScheduleActive), nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/schedules", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   scheduleResponse(model.ScheduleActive),
+		},
+		{
+			name: "create schedule with unknown recurrence",
+			setup: func(mockSchedules *mockScheduleService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/schedules", strings.NewReader(
+					`{"toUser":"receiver","amount":10,"runAt":"2030-03-01T09:00:00Z","recurrence":"daily"}`))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "create schedule in the past",
+			setup: func(mockSchedules *mockScheduleService) *http.Request {
+				mockSchedules.On("Create", mock.Anything, validUsername, "receiver", 10, runAt,
+					model.RecurrenceMonthly, memo).Return(nil, service.ErrScheduleInPast)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/schedules", strings.NewReader(body))
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "runAt must be in the future", Code: "schedule_in_past"},
+		},
+		{
+			name: "list schedules",
+			setup: func(mockSchedules *mockScheduleService) *http.Request {
+				mockSchedules.On("List", mock.Anything, validUsername).
+					Return([]model.ScheduledTransfer{*schedule(model.ScheduleActive)}, nil)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/schedules", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.SchedulesResponse{
+				Schedules: []rep.ScheduleResponse{scheduleResponse(model.ScheduleActive)},
+			},
+		},
+		{
+			name: "cancel schedule",
+			setup: func(mockSchedules *mockScheduleService) *http.Request {
+				mockSchedules.On("Cancel", mock.Anything, validUsername, scheduleId).
+					Return(schedule(model.ScheduleCancelled), nil)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/schedules/"+scheduleId.String()+"/cancel", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   scheduleResponse(model.ScheduleCancelled),
+		},
+		{
+			name: "cancel finished schedule",
+			setup: func(mockSchedules *mockScheduleService) *http.Request {
+				mockSchedules.On("Cancel", mock.Anything, validUsername, scheduleId).
+					Return(nil, service.ErrScheduleNotActive)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/schedules/"+scheduleId.String()+"/cancel", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   rep.ErrorResponse{Errors: "schedule is no longer active", Code: "schedule_not_active"},
+		},
+		{
+			name: "list runs",
+			setup: func(mockSchedules *mockScheduleService) *http.Request {
+				mockSchedules.On("Runs", mock.Anything, validUsername, scheduleId).
+					Return([]model.ScheduledTransferRun{{
+						Id:          runId,
+						ScheduleId:  scheduleId,
+						ScheduledAt: runAt,
+						Attempt:     1,
+						Status:      model.RunFailed,
+						Reason:      "not enough coins",
+						CreatedAt:   runAt,
+					}}, nil)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/schedules/"+scheduleId.String()+"/runs", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.ScheduleRunsResponse{Runs: []rep.ScheduleRunResponse{{
+				RunId:       runId.String(),
+				ScheduledAt: runAt,
+				Attempt:     1,
+				Status:      string(model.RunFailed),
+				Reason:      "not enough coins",
+				CreatedAt:   runAt,
+			}}},
+		},
+		{
+			name: "list runs of unknown schedule",
+			setup: func(mockSchedules *mockScheduleService) *http.Request {
+				mockSchedules.On("Runs", mock.Anything, validUsername, scheduleId).
+					Return(nil, service.ErrScheduleNotFound)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/schedules/"+scheduleId.String()+"/runs", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   rep.ErrorResponse{Errors: "schedule not found", Code: "schedule_not_found"},
+		},
+		{
+			name: "malformed schedule id",
+			setup: func(mockSchedules *mockScheduleService) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/schedules/not-a-uuid/cancel", nil)
+				return withClaims(req, validUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid schedule id"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockSchedules := new(mockScheduleService)
+
+			req := tc.setup(mockSchedules)
+
+			w := httptest.NewRecorder()
+			setupSchedulesRouter(logger, mockSchedules).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockSchedules.AssertExpectations(t)
+		})
+	}
+}
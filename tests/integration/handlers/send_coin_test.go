@@ -5,9 +5,11 @@ import (
 	rep "avito-shop/internal/http-server/dto/response"
 	"avito-shop/internal/http-server/handlers"
 	mw "avito-shop/internal/http-server/middleware"
+	"avito-shop/internal/model"
 	"avito-shop/internal/service"
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
@@ -26,9 +28,14 @@ type mockTransferService struct {
 	mock.Mock
 }
 
-func (m *mockTransferService) SendCoins(ctx context.Context, from string, to string, amount int) error {
-	args := m.Called(ctx, from, to, amount)
-	return args.Error(0)
+func (m *mockTransferService) SendCoins(
+	ctx context.Context, from string, to string, amount int, memo model.TransferMemo,
+) (*model.PendingTransfer, error) {
+	args := m.Called(ctx, from, to, amount, memo)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
 func TestNewSendCoinsHandlerFunc(t *testing.T) {
@@ -36,6 +43,8 @@ func TestNewSendCoinsHandlerFunc(t *testing.T) {
 	validReceiver := "receiver-user"
 	validAmount := 100
 	validEmployeeId := uuid.New()
+	pendingId := uuid.New()
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
 	vld := validator.New()
 
 	tests := []struct {
@@ -47,8 +56,9 @@ func TestNewSendCoinsHandlerFunc(t *testing.T) {
 		{
 			name: "successful transfer",
 			setup: func(mockService *mockTransferService) *http.Request {
-				mockService.On("SendCoins", mock.Anything, validSender, validReceiver, validAmount).
-					Return(nil)
+				mockService.On(
+					"SendCoins", mock.Anything, validSender, validReceiver, validAmount, model.TransferMemo{}).
+					Return(nil, nil)
 
 				requestBody := request.SendCoinRequest{ToUser: validReceiver, Amount: validAmount}
 				jsonBody, _ := json.Marshal(requestBody)
@@ -71,8 +81,9 @@ func TestNewSendCoinsHandlerFunc(t *testing.T) {
 		{
 			name: "not enough coins",
 			setup: func(mockService *mockTransferService) *http.Request {
-				mockService.On("SendCoins", mock.Anything, validSender, validReceiver, validAmount).
-					Return(service.ErrNotEnoughCoins)
+				mockService.On(
+					"SendCoins", mock.Anything, validSender, validReceiver, validAmount, model.TransferMemo{}).
+					Return(nil, service.ErrNotEnoughCoins)
 
 				requestBody := request.SendCoinRequest{ToUser: validReceiver, Amount: validAmount}
 				jsonBody, _ := json.Marshal(requestBody)
@@ -93,6 +104,129 @@ func TestNewSendCoinsHandlerFunc(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   rep.ErrorResponse{Errors: "not enough coins to send"},
 		},
+		{
+			name: "transfer limit exceeded",
+			setup: func(mockService *mockTransferService) *http.Request {
+				mockService.On(
+					"SendCoins", mock.Anything, validSender, validReceiver, validAmount, model.TransferMemo{}).
+					Return(nil, &service.TransferLimitError{Limit: service.LimitDailyAmount, Remaining: 40})
+
+				requestBody := request.SendCoinRequest{ToUser: validReceiver, Amount: validAmount}
+				jsonBody, _ := json.Marshal(requestBody)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/send-coins", strings.NewReader(string(jsonBody)))
+				return withClaims(req, validSender)
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody: rep.TransferLimitErrorResponse{
+				Errors:    "transfer limit exceeded",
+				Code:      "transfer_limit_exceeded",
+				Limit:     "daily_amount",
+				Remaining: 40,
+			},
+		},
+		{
+			name: "frozen sender",
+			setup: func(mockService *mockTransferService) *http.Request {
+				mockService.On(
+					"SendCoins", mock.Anything, validSender, validReceiver, validAmount, model.TransferMemo{}).
+					Return(nil, service.ErrAccountFrozen)
+
+				requestBody := request.SendCoinRequest{ToUser: validReceiver, Amount: validAmount}
+				jsonBody, _ := json.Marshal(requestBody)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/send-coins", strings.NewReader(string(jsonBody)))
+				return withClaims(req, validSender)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   rep.ErrorResponse{Errors: "account is frozen", Code: "account_frozen"},
+		},
+		{
+			name: "deactivated receiver",
+			setup: func(mockService *mockTransferService) *http.Request {
+				mockService.On(
+					"SendCoins", mock.Anything, validSender, validReceiver, validAmount, model.TransferMemo{}).
+					Return(nil, fmt.Errorf("%w: %s", service.ErrReceiverNotActive, validReceiver))
+
+				requestBody := request.SendCoinRequest{ToUser: validReceiver, Amount: validAmount}
+				jsonBody, _ := json.Marshal(requestBody)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/send-coins", strings.NewReader(string(jsonBody)))
+				return withClaims(req, validSender)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   rep.ErrorResponse{Errors: "receiver account is not active", Code: "receiver_not_active"},
+		},
+		{
+			name: "transfer held for approval",
+			setup: func(mockService *mockTransferService) *http.Request {
+				mockService.On("SendCoins", mock.Anything, validSender, validReceiver, 600, model.TransferMemo{}).
+					Return(&model.PendingTransfer{
+						Id:        pendingId,
+						From:      validSender,
+						To:        validReceiver,
+						Amount:    600,
+						Status:    model.PendingTransferPending,
+						CreatedAt: createdAt,
+					}, nil)
+
+				requestBody := request.SendCoinRequest{ToUser: validReceiver, Amount: 600}
+				jsonBody, _ := json.Marshal(requestBody)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/send-coins", strings.NewReader(string(jsonBody)))
+				return withClaims(req, validSender)
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedBody: rep.PendingTransferResponse{
+				TransferId: pendingId.String(),
+				FromUser:   validSender,
+				ToUser:     validReceiver,
+				Amount:     600,
+				Status:     "pending",
+				CreatedAt:  createdAt,
+			},
+		},
+		{
+			name: "transfer with memo",
+			setup: func(mockService *mockTransferService) *http.Request {
+				memo := model.TransferMemo{Message: "thanks for the review", Category: model.CategoryThanks}
+				mockService.On("SendCoins", mock.Anything, validSender, validReceiver, validAmount, memo).
+					Return(nil, nil)
+
+				requestBody := request.SendCoinRequest{
+					ToUser: validReceiver, Amount: validAmount, Message: memo.Message, Category: "thanks"}
+				jsonBody, _ := json.Marshal(requestBody)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/send-coins", strings.NewReader(string(jsonBody)))
+				return withClaims(req, validSender)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "unknown category",
+			setup: func(mockService *mockTransferService) *http.Request {
+				requestBody := request.SendCoinRequest{ToUser: validReceiver, Amount: validAmount, Category: "bribe"}
+				jsonBody, _ := json.Marshal(requestBody)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/send-coins", strings.NewReader(string(jsonBody)))
+				return withClaims(req, validSender)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "message too long",
+			setup: func(mockService *mockTransferService) *http.Request {
+				requestBody := request.SendCoinRequest{
+					ToUser: validReceiver, Amount: validAmount, Message: strings.Repeat("a", 201)}
+				jsonBody, _ := json.Marshal(requestBody)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/send-coins", strings.NewReader(string(jsonBody)))
+				return withClaims(req, validSender)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
 		{
 			name: "missing JWT token",
 			setup: func(mockService *mockTransferService) *http.Request {
@@ -128,3 +262,136 @@ func TestNewSendCoinsHandlerFunc(t *testing.T) {
 		})
 	}
 }
+
+type mockBatchTransferService struct {
+	mock.Mock
+}
+
+func (m *mockBatchTransferService) SendCoinsBatch(
+	ctx context.Context, from string, lines []model.TransferLine, memo model.TransferMemo,
+) ([]model.TransferLine, error) {
+	args := m.Called(ctx, from, lines, memo)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.TransferLine), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestNewSendCoinsBatchHandlerFunc(t *testing.T) {
+	validSender := "team-lead"
+
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(*mockBatchTransferService)
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "explicit amounts",
+			body: `{"recipients":[{"toUser":"bob","amount":20},{"toUser":"carol","amount":15}],"category":"teamwork"}`,
+			setup: func(mockService *mockBatchTransferService) {
+				lines := []model.TransferLine{{ToUser: "bob", Amount: 20}, {ToUser: "carol", Amount: 15}}
+				mockService.On("SendCoinsBatch", mock.Anything, validSender, lines,
+					model.TransferMemo{Category: model.CategoryTeamwork}).
+					Return(lines, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.SendCoinBatchResponse{
+				Total:     35,
+				Transfers: []rep.BatchTransfer{{ToUser: "bob", Amount: 20}, {ToUser: "carol", Amount: 15}},
+			},
+		},
+		{
+			name: "even split with remainder",
+			body: `{"toUsers":["bob","carol","dave"],"totalAmount":100}`,
+			setup: func(mockService *mockBatchTransferService) {
+				lines := []model.TransferLine{
+					{ToUser: "bob", Amount: 34}, {ToUser: "carol", Amount: 33}, {ToUser: "dave", Amount: 33},
+				}
+				mockService.On("SendCoinsBatch", mock.Anything, validSender, lines, model.TransferMemo{}).
+					Return(lines, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: rep.SendCoinBatchResponse{
+				Total: 100,
+				Transfers: []rep.BatchTransfer{
+					{ToUser: "bob", Amount: 34}, {ToUser: "carol", Amount: 33}, {ToUser: "dave", Amount: 33},
+				},
+			},
+		},
+		{
+			name:           "both recipients and split",
+			body:           `{"recipients":[{"toUser":"bob","amount":20}],"toUsers":["carol"],"totalAmount":10}`,
+			setup:          func(*mockBatchTransferService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name:           "total smaller than recipient count",
+			body:           `{"toUsers":["bob","carol","dave"],"totalAmount":2}`,
+			setup:          func(*mockBatchTransferService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "total amount is too small to split between recipients"},
+		},
+		{
+			name:           "split without total",
+			body:           `{"toUsers":["bob","carol"]}`,
+			setup:          func(*mockBatchTransferService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name:           "no recipients",
+			body:           `{}`,
+			setup:          func(*mockBatchTransferService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "invalid request body"},
+		},
+		{
+			name: "unknown recipient",
+			body: `{"recipients":[{"toUser":"ghost","amount":20}]}`,
+			setup: func(mockService *mockBatchTransferService) {
+				mockService.On("SendCoinsBatch", mock.Anything, validSender, mock.Anything, model.TransferMemo{}).
+					Return(nil, service.ErrReceiverNotFound)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "receiver not found"},
+		},
+		{
+			name: "not enough coins for the batch",
+			body: `{"toUsers":["bob","carol"],"totalAmount":5000}`,
+			setup: func(mockService *mockBatchTransferService) {
+				mockService.On("SendCoinsBatch", mock.Anything, validSender, mock.Anything, model.TransferMemo{}).
+					Return(nil, service.ErrNotEnoughCoins)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   rep.ErrorResponse{Errors: "not enough coins to send"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{}))
+			mockService := new(mockBatchTransferService)
+			tc.setup(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/sendCoin/batch", strings.NewReader(tc.body))
+			req = withClaims(req, validSender)
+
+			handler := handlers.NewSendCoinsBatchHandlerFunc(logger, mockService, validator.New())
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+
+			if tc.expectedBody != nil {
+				expectedResp, err := json.Marshal(tc.expectedBody)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expectedResp), w.Body.String())
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PGAdjustmentRepoTestSuite struct {
+	PGDBTestSuite
+	ctx            context.Context
+	adjustmentRepo *pgdb.PGAdjustmentRepo
+}
+
+func (s *PGAdjustmentRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.adjustmentRepo = pgdb.NewPGAdjustmentRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table balance_adjustments restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGAdjustmentRepo(t *testing.T) {
+	suite.Run(t, new(PGAdjustmentRepoTestSuite))
+}
+
+func (s *PGAdjustmentRepoTestSuite) TestSave() {
+	employeeId := uuid.New()
+	adminId := uuid.New()
+	s.insertEmployee(employeeId, "employee")
+	s.insertEmployee(adminId, "admin")
+
+	adjustment := &model.BalanceAdjustment{
+		Id:         uuid.New(),
+		EmployeeId: employeeId,
+		AdminId:    adminId,
+		Amount:     -40,
+		Reason:     "duplicate bonus",
+	}
+
+	s.Run("should save adjustment", func() {
+		s.Require().NoError(s.adjustmentRepo.Save(s.ctx, adjustment))
+		s.Require().False(adjustment.CreatedAt.IsZero())
+
+		var savedAdmin uuid.UUID
+		var amount int
+		err := s.pool.QueryRow(s.ctx, "select admin_id, amount from balance_adjustments where id = $1", adjustment.Id).
+			Scan(&savedAdmin, &amount)
+		s.Require().NoError(err)
+		s.Require().Equal(adminId, savedAdmin)
+		s.Require().Equal(-40, amount)
+	})
+
+	s.Run("should show adjustment in history as a system entry", func() {
+		var direction, counterparty, message string
+		var amount int
+		err := s.pool.QueryRow(s.ctx,
+			`select direction, coalesce(counterparty, ''), amount, message from employee_history
+			where id = $1 and type = $2`,
+			adjustment.Id, model.HistoryAdjustment).Scan(&direction, &counterparty, &amount, &message)
+		s.Require().NoError(err)
+		s.Require().Equal(string(model.DirectionOut), direction)
+		s.Require().Empty(counterparty)
+		s.Require().Equal(40, amount)
+		s.Require().Equal("duplicate bonus", message)
+	})
+}
+
+func (s *PGAdjustmentRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 1000)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
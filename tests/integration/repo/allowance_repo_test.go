@@ -0,0 +1,115 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PGAllowanceRepoTestSuite struct {
+	PGDBTestSuite
+	ctx           context.Context
+	allowanceRepo *pgdb.PGAllowanceRepo
+}
+
+func (s *PGAllowanceRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.allowanceRepo = pgdb.NewPGAllowanceRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table allowances restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGAllowanceRepo(t *testing.T) {
+	suite.Run(t, new(PGAllowanceRepoTestSuite))
+}
+
+func (s *PGAllowanceRepoTestSuite) TestSave() {
+	employeeId := uuid.New()
+	s.insertEmployee(employeeId, "employee")
+
+	s.Run("should save allowance once per period", func() {
+		saved, err := s.allowanceRepo.Save(s.ctx, s.newAllowance(employeeId, "2026-10", 100))
+		s.Require().NoError(err)
+		s.Require().True(saved)
+
+		saved, err = s.allowanceRepo.Save(s.ctx, s.newAllowance(employeeId, "2026-10", 100))
+		s.Require().NoError(err)
+		s.Require().False(saved)
+	})
+
+	s.Run("should show credited allowances in history", func() {
+		saved, err := s.allowanceRepo.Save(s.ctx, s.newAllowance(employeeId, "2026-11", 0))
+		s.Require().NoError(err)
+		s.Require().True(saved)
+
+		var count, amount int
+		err = s.pool.QueryRow(s.ctx,
+			"select count(*), coalesce(sum(amount), 0) from employee_history where employee_id = $1 and type = $2",
+			employeeId, model.HistoryAllowance).Scan(&count, &amount)
+		s.Require().NoError(err)
+		s.Require().Equal(1, count)
+		s.Require().Equal(100, amount)
+	})
+}
+
+func (s *PGAllowanceRepoTestSuite) TestFindEmployeesDueForUpdate() {
+	granted := uuid.New()
+	due := uuid.New()
+	s.insertEmployee(granted, "granted")
+	s.insertEmployee(due, "due")
+
+	saved, err := s.allowanceRepo.Save(s.ctx, s.newAllowance(granted, "2026-10", 100))
+	s.Require().NoError(err)
+	s.Require().True(saved)
+
+	s.Run("should skip employees granted in the period", func() {
+		employees, err := s.allowanceRepo.FindEmployeesDueForUpdate(s.ctx, "2026-10", 10)
+		s.Require().NoError(err)
+		s.Require().Len(employees, 1)
+		s.Require().Equal(due, employees[0].Id)
+		s.Require().Equal(1000, employees[0].Balance)
+	})
+
+	s.Run("should return everyone for a new period", func() {
+		employees, err := s.allowanceRepo.FindEmployeesDueForUpdate(s.ctx, "2026-11", 10)
+		s.Require().NoError(err)
+		s.Require().Len(employees, 2)
+	})
+	s.Run("should skip frozen employees", func() {
+		_, err := s.pool.Exec(s.ctx, "update employees set status = 'frozen' where id = $1", due)
+		s.Require().NoError(err)
+
+		employees, err := s.allowanceRepo.FindEmployeesDueForUpdate(s.ctx, "2026-11", 10)
+		s.Require().NoError(err)
+		s.Require().Len(employees, 1)
+		s.Require().Equal(granted, employees[0].Id)
+	})
+}
+
+func (s *PGAllowanceRepoTestSuite) newAllowance(employeeId uuid.UUID, period string, amount int) *model.Allowance {
+	return &model.Allowance{
+		Id:         uuid.New(),
+		EmployeeId: employeeId,
+		Period:     period,
+		Amount:     amount,
+	}
+}
+
+func (s *PGAllowanceRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 1000)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PGCoinLotRepoTestSuite struct {
+	PGDBTestSuite
+	ctx         context.Context
+	coinLotRepo *pgdb.PGCoinLotRepo
+}
+
+func (s *PGCoinLotRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.coinLotRepo = pgdb.NewPGCoinLotRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table coin_lots restart identity cascade;
+		      truncate table spent_coins restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGCoinLotRepo(t *testing.T) {
+	suite.Run(t, new(PGCoinLotRepoTestSuite))
+}
+
+func (s *PGCoinLotRepoTestSuite) TestSaveAndFind() {
+	employeeId := uuid.New()
+	s.insertEmployee(employeeId, "employee", 60, 0)
+
+	now := time.Now().Truncate(time.Microsecond)
+	old := s.newLot(employeeId, 30, now.AddDate(-1, 0, -1))
+	recent := s.newLot(employeeId, 20, now.AddDate(0, -1, 0))
+	spent := s.newLot(employeeId, 10, now.AddDate(-2, 0, 0))
+	s.Require().NoError(s.coinLotRepo.SaveAll(s.ctx, []model.CoinLot{recent, old, spent}))
+	s.Require().NoError(s.coinLotRepo.UpdateRemaining(s.ctx, spent.Id, 0))
+
+	s.Run("should find active lots oldest first", func() {
+		lots, err := s.coinLotRepo.FindActiveByEmployee(s.ctx, employeeId)
+		s.Require().NoError(err)
+		s.Require().Len(lots, 2)
+		s.Require().Equal(old.Id, lots[0].Id)
+		s.Require().True(old.GrantedAt.Equal(lots[0].GrantedAt))
+		s.Require().Equal(recent.Id, lots[1].Id)
+	})
+
+	s.Run("should find lots granted before a time", func() {
+		lots, err := s.coinLotRepo.FindActiveGrantedBefore(s.ctx, employeeId, now.AddDate(-1, 0, 0))
+		s.Require().NoError(err)
+		s.Require().Len(lots, 1)
+		s.Require().Equal(old.Id, lots[0].Id)
+		s.Require().Equal(30, lots[0].Remaining)
+	})
+}
+
+func (s *PGCoinLotRepoTestSuite) TestFindEmployeesWithExpired() {
+	stale := uuid.New()
+	held := uuid.New()
+	fresh := uuid.New()
+	s.insertEmployee(stale, "stale", 10, 0)
+	s.insertEmployee(held, "held", 10, 10)
+	s.insertEmployee(fresh, "fresh", 10, 0)
+
+	old := time.Now().AddDate(-2, 0, 0)
+	s.Require().NoError(s.coinLotRepo.SaveAll(s.ctx, []model.CoinLot{
+		s.newLot(stale, 10, old),
+		s.newLot(held, 10, old),
+		s.newLot(fresh, 10, time.Now()),
+	}))
+
+	employeeIds, err := s.coinLotRepo.FindEmployeesWithExpired(s.ctx, time.Now().AddDate(-1, 0, 0), 10)
+	s.Require().NoError(err)
+	s.Require().Equal([]uuid.UUID{stale}, employeeIds)
+}
+
+func (s *PGCoinLotRepoTestSuite) TestSpentCoins() {
+	operationId := uuid.New()
+	now := time.Now().Truncate(time.Microsecond)
+	older := model.SpentCoins{Id: uuid.New(), OperationId: operationId, Amount: 30, Remaining: 30,
+		GrantedAt: now.AddDate(-1, 0, 0)}
+	newer := model.SpentCoins{Id: uuid.New(), OperationId: operationId, Amount: 10, Remaining: 10,
+		GrantedAt: now.AddDate(0, -1, 0)}
+	other := model.SpentCoins{Id: uuid.New(), OperationId: uuid.New(), Amount: 5, Remaining: 5, GrantedAt: now}
+	s.Require().NoError(s.coinLotRepo.SaveSpent(s.ctx, []model.SpentCoins{older, newer, other}))
+
+	s.Run("should find spent coins of the operation newest first", func() {
+		spent, err := s.coinLotRepo.FindSpentByOperationForUpdate(s.ctx, operationId)
+		s.Require().NoError(err)
+		s.Require().Len(spent, 2)
+		s.Require().Equal(newer.Id, spent[0].Id)
+		s.Require().True(newer.GrantedAt.Equal(spent[0].GrantedAt))
+		s.Require().Equal(older.Id, spent[1].Id)
+	})
+
+	s.Run("should skip coins already given back", func() {
+		s.Require().NoError(s.coinLotRepo.UpdateSpentRemaining(s.ctx, newer.Id, 0))
+
+		spent, err := s.coinLotRepo.FindSpentByOperationForUpdate(s.ctx, operationId)
+		s.Require().NoError(err)
+		s.Require().Len(spent, 1)
+		s.Require().Equal(older.Id, spent[0].Id)
+	})
+}
+
+func (s *PGCoinLotRepoTestSuite) newLot(employeeId uuid.UUID, amount int, grantedAt time.Time) model.CoinLot {
+	return model.CoinLot{
+		Id:         uuid.New(),
+		EmployeeId: employeeId,
+		Amount:     amount,
+		Remaining:  amount,
+		GrantedAt:  grantedAt,
+	}
+}
+
+func (s *PGCoinLotRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string, balance, held int) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance, held) VALUES ($1, $2, 'hash', $3, $4)",
+		employeeId, username, balance, held)
+	s.Require().NoError(err)
+}
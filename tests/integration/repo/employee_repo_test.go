@@ -42,6 +42,7 @@ func (s *PGEmployeeRepoTestSuite) TestSave() {
 		Username:     "test username",
 		PasswordHash: "test passwordHash",
 		Balance:      1,
+		Role:         model.RoleAdmin,
 	}
 
 	s.Run("should save employee", func() {
@@ -54,6 +55,7 @@ func (s *PGEmployeeRepoTestSuite) TestSave() {
 		s.Require().Equal(testEmployee.Username, savedEmployee.Username)
 		s.Require().Equal(testEmployee.PasswordHash, savedEmployee.PasswordHash)
 		s.Require().Equal(testEmployee.Balance, savedEmployee.Balance)
+		s.Require().Equal(testEmployee.Role, savedEmployee.Role)
 	})
 
 	s.Run("should fail on duplicate username", func() {
@@ -62,6 +64,7 @@ func (s *PGEmployeeRepoTestSuite) TestSave() {
 			Username:     testEmployee.Username,
 			PasswordHash: "another_password_hash",
 			Balance:      50,
+			Role:         model.RoleEmployee,
 		}
 
 		err := s.employeeRepo.Save(s.ctx, &duplicateEmployee)
@@ -87,6 +90,8 @@ func (s *PGEmployeeRepoTestSuite) TestFindByUsername() {
 		s.Require().Equal(testEmployee.Username, employee.Username)
 		s.Require().Equal(testEmployee.PasswordHash, employee.PasswordHash)
 		s.Require().Equal(testEmployee.Balance, employee.Balance)
+		s.Require().Equal(model.RoleEmployee, employee.Role)
+		s.Require().Equal(model.EmployeeActive, employee.Status)
 	})
 
 	s.Run("should not find employee by username", func() {
@@ -96,6 +101,30 @@ func (s *PGEmployeeRepoTestSuite) TestFindByUsername() {
 	})
 }
 
+func (s *PGEmployeeRepoTestSuite) TestFindByUsernameForUpdate() {
+	testEmployee := model.Employee{
+		Id:           uuid.New(),
+		Username:     "test username",
+		PasswordHash: "test passwordHash",
+		Balance:      1,
+	}
+
+	s.insertEmployee(&testEmployee)
+
+	s.Run("should find employee by username", func() {
+		employee, err := s.employeeRepo.FindByUsernameForUpdate(s.ctx, testEmployee.Username)
+		s.Require().NoError(err)
+		s.Require().Equal(testEmployee.Id, employee.Id)
+		s.Require().Equal(testEmployee.Balance, employee.Balance)
+	})
+
+	s.Run("should not find employee by username", func() {
+		employee, err := s.employeeRepo.FindByUsernameForUpdate(s.ctx, "non existing username")
+		s.Require().ErrorIs(err, repo.ErrEmployeeNotFound)
+		s.Require().Nil(employee)
+	})
+}
+
 func (s *PGEmployeeRepoTestSuite) TestUpdateByUsername() {
 	testEmployee := model.Employee{
 		Id:           uuid.New(),
@@ -142,6 +171,73 @@ func (s *PGEmployeeRepoTestSuite) TestUpdateByUsername() {
 	})
 }
 
+func (s *PGEmployeeRepoTestSuite) TestUpdateManager() {
+	employee := model.Employee{Id: uuid.New(), Username: "employee", PasswordHash: "hash"}
+	manager := model.Employee{Id: uuid.New(), Username: "manager", PasswordHash: "hash"}
+	s.insertEmployee(&employee)
+	s.insertEmployee(&manager)
+
+	s.Run("should assign and clear manager", func() {
+		s.Require().NoError(s.employeeRepo.UpdateManager(s.ctx, employee.Id, &manager.Id))
+
+		found, err := s.employeeRepo.FindByUsername(s.ctx, employee.Username)
+		s.Require().NoError(err)
+		s.Require().Equal(&manager.Id, found.ManagerId)
+
+		s.Require().NoError(s.employeeRepo.UpdateManager(s.ctx, employee.Id, nil))
+
+		found, err = s.employeeRepo.FindByUsername(s.ctx, employee.Username)
+		s.Require().NoError(err)
+		s.Require().Nil(found.ManagerId)
+	})
+
+	s.Run("should return error for unknown employee", func() {
+		err := s.employeeRepo.UpdateManager(s.ctx, uuid.New(), &manager.Id)
+		s.Require().ErrorIs(err, repo.ErrEmployeeNotFound)
+	})
+}
+
+func (s *PGEmployeeRepoTestSuite) TestUpdateStatus() {
+	employee := model.Employee{Id: uuid.New(), Username: "employee", PasswordHash: "hash"}
+	s.insertEmployee(&employee)
+
+	s.Run("should freeze employee", func() {
+		s.Require().NoError(s.employeeRepo.UpdateStatus(s.ctx, employee.Id, model.EmployeeFrozen))
+
+		found, err := s.employeeRepo.FindByIdForUpdate(s.ctx, employee.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(model.EmployeeFrozen, found.Status)
+	})
+
+	s.Run("should reject unknown status", func() {
+		err := s.employeeRepo.UpdateStatus(s.ctx, employee.Id, "banned")
+		s.Require().Error(err)
+	})
+
+	s.Run("should return error for unknown employee", func() {
+		err := s.employeeRepo.UpdateStatus(s.ctx, uuid.New(), model.EmployeeDeactivated)
+		s.Require().ErrorIs(err, repo.ErrEmployeeNotFound)
+	})
+}
+
+func (s *PGEmployeeRepoTestSuite) TestUpdateRole() {
+	employee := model.Employee{Id: uuid.New(), Username: "employee", PasswordHash: "hash"}
+	s.insertEmployee(&employee)
+
+	s.Run("should promote employee to admin", func() {
+		s.Require().NoError(s.employeeRepo.UpdateRole(s.ctx, employee.Id, model.RoleAdmin))
+
+		found, err := s.employeeRepo.FindByIdForUpdate(s.ctx, employee.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(model.RoleAdmin, found.Role)
+	})
+
+	s.Run("should return error for unknown employee", func() {
+		err := s.employeeRepo.UpdateRole(s.ctx, uuid.New(), model.RoleAdmin)
+		s.Require().ErrorIs(err, repo.ErrEmployeeNotFound)
+	})
+}
+
 func (s *PGEmployeeRepoTestSuite) insertEmployee(e *model.Employee) {
 	_, err := s.pool.Exec(s.ctx,
 		"insert into employees(id, username, password_hash, balance) values ($1, $2, $3, $4)",
@@ -151,8 +247,15 @@ func (s *PGEmployeeRepoTestSuite) insertEmployee(e *model.Employee) {
 
 func (s *PGEmployeeRepoTestSuite) selectEmployeeById(id uuid.UUID) *model.Employee {
 	var savedEmployee model.Employee
-	err := s.pool.QueryRow(s.ctx, "select id, username, password_hash, balance from employees where id = $1", id).
-		Scan(&savedEmployee.Id, &savedEmployee.Username, &savedEmployee.PasswordHash, &savedEmployee.Balance)
+	err := s.pool.QueryRow(s.ctx,
+		"select id, username, password_hash, balance, role from employees where id = $1", id).
+		Scan(
+			&savedEmployee.Id,
+			&savedEmployee.Username,
+			&savedEmployee.PasswordHash,
+			&savedEmployee.Balance,
+			&savedEmployee.Role,
+		)
 	s.Require().NoError(err)
 	return &savedEmployee
 }
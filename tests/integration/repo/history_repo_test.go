@@ -0,0 +1,248 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PGHistoryRepoTestSuite struct {
+	PGDBTestSuite
+	ctx          context.Context
+	historyRepo  *pgdb.PGHistoryRepo
+	purchaseRepo *pgdb.PGPurchaseRepo
+	giftRepo     *pgdb.PGGiftRepo
+}
+
+func (s *PGHistoryRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.historyRepo = pgdb.NewPGHistoryRepo(pg, trmpgx.DefaultCtxGetter)
+	s.purchaseRepo = pgdb.NewPGPurchaseRepo(pg, trmpgx.DefaultCtxGetter)
+	s.giftRepo = pgdb.NewPGGiftRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table transfers restart identity cascade;
+		      truncate table purchases restart identity cascade;
+		      truncate table employees restart identity cascade;
+		      truncate table items restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGHistoryRepo(t *testing.T) {
+	suite.Run(t, new(PGHistoryRepoTestSuite))
+}
+
+func (s *PGHistoryRepoTestSuite) TestFindByEmployee() {
+	employee := uuid.New()
+	colleague := uuid.New()
+	item := uuid.New()
+	s.insertEmployee(employee, "employee")
+	s.insertEmployee(colleague, "colleague")
+	s.insertItem(item, "cup", 20)
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	received := s.insertTransfer(colleague, employee, 100, base)
+	sent := s.insertTransfer(employee, colleague, 30, base.Add(time.Hour))
+
+	orderId := s.insertOrder(employee, 40)
+	purchase := &model.Purchase{
+		Id: uuid.New(), OrderId: orderId, EmployeeId: employee, ItemId: item, Quantity: 2, Price: 20}
+	s.Require().NoError(s.purchaseRepo.Save(s.ctx, purchase))
+	_, err := s.pool.Exec(s.ctx,
+		"update purchases set created_at = $1 where id = $2", base.Add(2*time.Hour), purchase.Id)
+	s.Require().NoError(err)
+
+	s.Run("should return entries in reverse chronological order", func() {
+		entries, err := s.historyRepo.FindByEmployee(s.ctx, employee, model.HistoryFilter{Limit: 10})
+		s.Require().NoError(err)
+		s.Require().Len(entries, 3)
+
+		s.Require().Equal(purchase.Id, entries[0].Id)
+		s.Require().Equal(model.HistoryPurchase, entries[0].Type)
+		s.Require().Equal("cup", entries[0].Item)
+		s.Require().Equal(2, entries[0].Quantity)
+		s.Require().Equal(40, entries[0].Amount)
+
+		s.Require().Equal(sent, entries[1].Id)
+		s.Require().Equal(model.DirectionOut, entries[1].Direction)
+		s.Require().Equal("colleague", entries[1].Counterparty)
+
+		s.Require().Equal(received, entries[2].Id)
+		s.Require().Equal(model.DirectionIn, entries[2].Direction)
+	})
+
+	s.Run("should filter by direction and counterparty", func() {
+		entries, err := s.historyRepo.FindByEmployee(s.ctx, employee, model.HistoryFilter{
+			Direction:    model.DirectionOut,
+			Counterparty: "colleague",
+			Limit:        10,
+		})
+		s.Require().NoError(err)
+		s.Require().Len(entries, 1)
+		s.Require().Equal(sent, entries[0].Id)
+	})
+
+	s.Run("should filter by date range", func() {
+		from := base.Add(30 * time.Minute)
+		to := base.Add(90 * time.Minute)
+		entries, err := s.historyRepo.FindByEmployee(
+			s.ctx, employee, model.HistoryFilter{From: &from, To: &to, Limit: 10})
+		s.Require().NoError(err)
+		s.Require().Len(entries, 1)
+		s.Require().Equal(sent, entries[0].Id)
+	})
+
+	s.Run("should continue after cursor", func() {
+		firstPage, err := s.historyRepo.FindByEmployee(s.ctx, employee, model.HistoryFilter{Limit: 2})
+		s.Require().NoError(err)
+		s.Require().Len(firstPage, 2)
+
+		last := firstPage[1]
+		secondPage, err := s.historyRepo.FindByEmployee(s.ctx, employee, model.HistoryFilter{
+			Cursor: &model.Cursor{CreatedAt: last.CreatedAt, Id: last.Id},
+			Limit:  2,
+		})
+		s.Require().NoError(err)
+		s.Require().Len(secondPage, 1)
+		s.Require().Equal(received, secondPage[0].Id)
+	})
+}
+
+func (s *PGHistoryRepoTestSuite) TestFindByEmployeeWithReturns() {
+	employee := uuid.New()
+	item := uuid.New()
+	s.insertEmployee(employee, "employee")
+	s.insertItem(item, "cup", 20)
+
+	purchase := &model.Purchase{
+		Id: uuid.New(), OrderId: s.insertOrder(employee, 60), EmployeeId: employee, ItemId: item,
+		Quantity: 3, Price: 20}
+	s.Require().NoError(s.purchaseRepo.Save(s.ctx, purchase))
+
+	returnId := uuid.New()
+	_, err := s.pool.Exec(s.ctx,
+		`insert into purchase_returns (id, purchase_id, employee_id, quantity, amount, created_at)
+		 VALUES ($1, $2, $3, 1, 20, now() + interval '1 minute')`,
+		returnId, purchase.Id, employee)
+	s.Require().NoError(err)
+
+	entries, err := s.historyRepo.FindByEmployee(s.ctx, employee, model.HistoryFilter{Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(entries, 2)
+
+	s.Require().Equal(returnId, entries[0].Id)
+	s.Require().Equal(model.HistoryReturn, entries[0].Type)
+	s.Require().Equal(model.DirectionIn, entries[0].Direction)
+	s.Require().Equal("cup", entries[0].Item)
+	s.Require().Equal(1, entries[0].Quantity)
+	s.Require().Equal(20, entries[0].Amount)
+}
+
+func (s *PGHistoryRepoTestSuite) TestFindByEmployeeWithGifts() {
+	employee := uuid.New()
+	colleague := uuid.New()
+	item := uuid.New()
+	s.insertEmployee(employee, "employee")
+	s.insertEmployee(colleague, "colleague")
+	s.insertItem(item, "cup", 20)
+
+	gift := &model.Gift{Id: uuid.New(), FromEmployee: employee, ToEmployee: colleague, ItemId: item, Quantity: 2}
+	s.Require().NoError(s.giftRepo.Save(s.ctx, gift))
+	s.Require().False(gift.CreatedAt.IsZero())
+
+	sent, err := s.historyRepo.FindByEmployee(s.ctx, employee, model.HistoryFilter{Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(sent, 1)
+	s.Require().Equal(model.HistoryGift, sent[0].Type)
+	s.Require().Equal(model.DirectionOut, sent[0].Direction)
+	s.Require().Equal("colleague", sent[0].Counterparty)
+	s.Require().Equal("cup", sent[0].Item)
+	s.Require().Equal(2, sent[0].Quantity)
+
+	received, err := s.historyRepo.FindByEmployee(s.ctx, colleague, model.HistoryFilter{Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(received, 1)
+	s.Require().Equal(model.DirectionIn, received[0].Direction)
+	s.Require().Equal("employee", received[0].Counterparty)
+}
+
+func (s *PGHistoryRepoTestSuite) TestFindByEmployeeWithTransferMemo() {
+	employee := uuid.New()
+	colleague := uuid.New()
+	s.insertEmployee(employee, "employee")
+	s.insertEmployee(colleague, "colleague")
+
+	_, err := s.pool.Exec(s.ctx,
+		`insert into transfers (id, from_employee, to_employee, amount, message, category)
+		 VALUES ($1, $2, $3, 10, 'thanks for the review', 'thanks')`,
+		uuid.New(), employee, colleague)
+	s.Require().NoError(err)
+
+	for _, employeeId := range []uuid.UUID{employee, colleague} {
+		entries, err := s.historyRepo.FindByEmployee(s.ctx, employeeId, model.HistoryFilter{Limit: 10})
+		s.Require().NoError(err)
+		s.Require().Len(entries, 1)
+		s.Require().Equal("thanks for the review", entries[0].Message)
+		s.Require().Equal(model.CategoryThanks, entries[0].Category)
+	}
+}
+
+func (s *PGHistoryRepoTestSuite) TestFindByEmployeeWithSweep() {
+	employee := uuid.New()
+	s.insertEmployee(employee, "employee")
+
+	operationId := uuid.New()
+	_, err := s.pool.Exec(s.ctx,
+		`insert into ledger_entries (id, operation_id, operation_type, account, employee_id, direction, amount)
+		 VALUES ($1, $2, 'sweep', 'employee', $3, 'debit', 250),
+		        ($4, $2, 'sweep', 'company_pool', null, 'credit', 250)`,
+		uuid.New(), operationId, employee, uuid.New())
+	s.Require().NoError(err)
+
+	entries, err := s.historyRepo.FindByEmployee(s.ctx, employee, model.HistoryFilter{Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(entries, 1)
+	s.Require().Equal(operationId, entries[0].Id)
+	s.Require().Equal(model.HistorySweep, entries[0].Type)
+	s.Require().Equal(model.DirectionOut, entries[0].Direction)
+	s.Require().Equal(250, entries[0].Amount)
+}
+
+func (s *PGHistoryRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 1000)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
+
+func (s *PGHistoryRepoTestSuite) insertItem(itemId uuid.UUID, name string, price int) {
+	_, err := s.pool.Exec(s.ctx, "insert into items (id, name, price) VALUES ($1, $2, $3)", itemId, name, price)
+	s.Require().NoError(err)
+}
+
+func (s *PGHistoryRepoTestSuite) insertTransfer(from, to uuid.UUID, amount int, createdAt time.Time) uuid.UUID {
+	id := uuid.New()
+	_, err := s.pool.Exec(s.ctx,
+		"insert into transfers (id, from_employee, to_employee, amount, created_at) VALUES ($1, $2, $3, $4, $5)",
+		id, from, to, amount, createdAt)
+	s.Require().NoError(err)
+	return id
+}
+
+func (s *PGHistoryRepoTestSuite) insertOrder(employeeId uuid.UUID, total int) uuid.UUID {
+	id := uuid.New()
+	_, err := s.pool.Exec(s.ctx,
+		"insert into orders (id, employee_id, total) VALUES ($1, $2, $3)", id, employeeId, total)
+	s.Require().NoError(err)
+	return id
+}
@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type PGIdempotencyRepoTestSuite struct {
+	PGDBTestSuite
+	ctx             context.Context
+	idempotencyRepo *pgdb.PGIdempotencyRepo
+}
+
+func (s *PGIdempotencyRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.idempotencyRepo = pgdb.NewPGIdempotencyRepo(
+		&pgdb.Postgres{
+			Pool:    s.pool,
+			Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		},
+		trmpgx.DefaultCtxGetter,
+	)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table idempotency_keys restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGIdempotencyRepo(t *testing.T) {
+	suite.Run(t, new(PGIdempotencyRepoTestSuite))
+}
+
+func (s *PGIdempotencyRepoTestSuite) TestReserve() {
+	employeeId := uuid.New()
+	s.insertEmployee(employeeId, "employee")
+
+	record := &model.IdempotencyRecord{EmployeeId: employeeId, Key: "key", Fingerprint: "fingerprint"}
+
+	s.Run("should reserve new key", func() {
+		reserved, err := s.idempotencyRepo.Reserve(s.ctx, record)
+		s.Require().NoError(err)
+		s.Require().True(reserved)
+	})
+
+	s.Run("should not reserve existing key", func() {
+		reserved, err := s.idempotencyRepo.Reserve(s.ctx, record)
+		s.Require().NoError(err)
+		s.Require().False(reserved)
+	})
+}
+
+func (s *PGIdempotencyRepoTestSuite) TestSaveResponse() {
+	employeeId := uuid.New()
+	s.insertEmployee(employeeId, "employee")
+
+	record := &model.IdempotencyRecord{EmployeeId: employeeId, Key: "key", Fingerprint: "fingerprint"}
+	_, err := s.idempotencyRepo.Reserve(s.ctx, record)
+	s.Require().NoError(err)
+
+	record.ResponseStatus = http.StatusOK
+	record.ResponseBody = []byte(`{"ok":true}`)
+	s.Require().NoError(s.idempotencyRepo.SaveResponse(s.ctx, record))
+
+	found, err := s.idempotencyRepo.FindByEmployeeAndKey(s.ctx, employeeId, "key")
+	s.Require().NoError(err)
+	s.Require().Equal(record, found)
+
+	_, err = s.idempotencyRepo.FindByEmployeeAndKey(s.ctx, employeeId, "missing")
+	s.Require().ErrorIs(err, repo.ErrIdempotencyKeyNotFound)
+}
+
+func (s *PGIdempotencyRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 1000)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
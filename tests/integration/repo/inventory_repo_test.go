@@ -100,6 +100,15 @@ func (s *PGInventoryRepoTestSuite) TestFindAllInventoryItemsByEmployee() {
 			s.Require().Equal(expected[inventoryItem.Type], inventoryItem.Quantity)
 		}
 	})
+
+	s.Run("should keep retired items", func() {
+		_, err := s.pool.Exec(s.ctx, "update items set retired = true where id = $1", itemId2)
+		s.Require().NoError(err)
+
+		inventoryItems, err := s.inventoryRepo.FindAllInventoryItemsByEmployee(s.ctx, employeeId)
+		s.Require().NoError(err)
+		s.Require().Len(inventoryItems, 2)
+	})
 }
 
 func (s *PGInventoryRepoTestSuite) TestFindByEmployeeAndItem() {
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PGInviteRepoTestSuite struct {
+	PGDBTestSuite
+	ctx        context.Context
+	inviteRepo *pgdb.PGInviteRepo
+}
+
+func (s *PGInviteRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.inviteRepo = pgdb.NewPGInviteRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table invites restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGInviteRepo(t *testing.T) {
+	suite.Run(t, new(PGInviteRepoTestSuite))
+}
+
+func (s *PGInviteRepoTestSuite) TestSaveAndMarkUsed() {
+	adminId := uuid.New()
+	employeeId := uuid.New()
+	s.insertEmployee(adminId, "admin")
+	s.insertEmployee(employeeId, "employee")
+
+	invite := &model.Invite{
+		Id:        uuid.New(),
+		Code:      "0123abcd",
+		CreatedBy: adminId,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	s.Run("should save invite", func() {
+		s.Require().NoError(s.inviteRepo.Save(s.ctx, invite))
+		s.Require().False(invite.CreatedAt.IsZero())
+
+		found, err := s.inviteRepo.FindByCodeForUpdate(s.ctx, invite.Code)
+		s.Require().NoError(err)
+		s.Require().Equal(invite.Id, found.Id)
+		s.Require().Equal(adminId, found.CreatedBy)
+		s.Require().Nil(found.UsedBy)
+	})
+
+	s.Run("should mark invite used", func() {
+		s.Require().NoError(s.inviteRepo.MarkUsed(s.ctx, invite.Id, employeeId))
+
+		found, err := s.inviteRepo.FindByCodeForUpdate(s.ctx, invite.Code)
+		s.Require().NoError(err)
+		s.Require().Equal(&employeeId, found.UsedBy)
+	})
+
+	s.Run("should not find unknown code", func() {
+		_, err := s.inviteRepo.FindByCodeForUpdate(s.ctx, "unknown")
+		s.Require().ErrorIs(err, repo.ErrInviteNotFound)
+	})
+}
+
+func (s *PGInviteRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 1000)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
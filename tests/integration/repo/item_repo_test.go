@@ -87,9 +87,136 @@ func (s *PGItemRepoTestSuite) TestPGItemRepo_FindById() {
 	})
 }
 
+func (s *PGItemRepoTestSuite) TestSave() {
+	testItem := model.Item{Id: uuid.New(), Name: "sticker", Price: 5}
+
+	s.Run("should save item", func() {
+		s.Require().NoError(s.itemRepo.Save(s.ctx, &testItem))
+
+		item, err := s.itemRepo.FindById(s.ctx, testItem.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(testItem, *item)
+	})
+
+	s.Run("should fail on duplicate name", func() {
+		err := s.itemRepo.Save(s.ctx, &model.Item{Id: uuid.New(), Name: testItem.Name, Price: 10})
+		s.Require().ErrorIs(err, repo.ErrItemExists)
+	})
+}
+
+func (s *PGItemRepoTestSuite) TestUpdateById() {
+	testItem := model.Item{Id: uuid.New(), Name: "sticker", Price: 5}
+	otherItem := model.Item{Id: uuid.New(), Name: "badge", Price: 5}
+	s.insertItem(&testItem)
+	s.insertItem(&otherItem)
+
+	s.Run("should update item", func() {
+		updated := model.Item{Id: testItem.Id, Name: "big sticker", Price: 15, Retired: true}
+		s.Require().NoError(s.itemRepo.UpdateById(s.ctx, testItem.Id, &updated))
+
+		item, err := s.itemRepo.FindById(s.ctx, testItem.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(updated, *item)
+	})
+
+	s.Run("should fail on duplicate name", func() {
+		err := s.itemRepo.UpdateById(s.ctx, testItem.Id, &model.Item{Name: otherItem.Name, Price: 5})
+		s.Require().ErrorIs(err, repo.ErrItemExists)
+	})
+
+	s.Run("should fail on missing item", func() {
+		err := s.itemRepo.UpdateById(s.ctx, uuid.New(), &model.Item{Name: "missing", Price: 5})
+		s.Require().ErrorIs(err, repo.ErrItemNotFound)
+	})
+}
+
+func (s *PGItemRepoTestSuite) TestDecrementStock() {
+	stock := 2
+	limited := model.Item{Id: uuid.New(), Name: "pink-hoody", Price: 500, Stock: &stock}
+	unlimited := model.Item{Id: uuid.New(), Name: "pen", Price: 10}
+	s.insertItem(&limited)
+	s.insertItem(&unlimited)
+
+	s.Run("should decrement available stock", func() {
+		reserved, err := s.itemRepo.DecrementStock(s.ctx, limited.Id, 2)
+		s.Require().NoError(err)
+		s.Require().True(reserved)
+
+		item, err := s.itemRepo.FindById(s.ctx, limited.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(0, *item.Stock)
+	})
+
+	s.Run("should not decrement below zero", func() {
+		reserved, err := s.itemRepo.DecrementStock(s.ctx, limited.Id, 1)
+		s.Require().NoError(err)
+		s.Require().False(reserved)
+	})
+
+	s.Run("should not touch items without stock", func() {
+		reserved, err := s.itemRepo.DecrementStock(s.ctx, unlimited.Id, 1)
+		s.Require().NoError(err)
+		s.Require().False(reserved)
+	})
+
+	s.Run("should restore stock", func() {
+		s.Require().NoError(s.itemRepo.IncrementStock(s.ctx, limited.Id, 2))
+		s.Require().NoError(s.itemRepo.IncrementStock(s.ctx, unlimited.Id, 1))
+
+		item, err := s.itemRepo.FindById(s.ctx, limited.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(2, *item.Stock)
+
+		item, err = s.itemRepo.FindById(s.ctx, unlimited.Id)
+		s.Require().NoError(err)
+		s.Require().Nil(item.Stock)
+	})
+}
+
+func (s *PGItemRepoTestSuite) TestFindAll() {
+	pen := model.Item{Id: uuid.New(), Name: "pen", Price: 10}
+	cup := model.Item{Id: uuid.New(), Name: "cup", Price: 20}
+	hoody := model.Item{Id: uuid.New(), Name: "hoody", Price: 300}
+	s.insertItem(&pen)
+	s.insertItem(&cup)
+	s.insertItem(&hoody)
+
+	s.Run("should sort by name by default", func() {
+		items, err := s.itemRepo.FindAll(s.ctx, model.ItemFilter{})
+		s.Require().NoError(err)
+		s.Require().Equal([]model.Item{cup, hoody, pen}, items)
+	})
+
+	s.Run("should sort by price descending", func() {
+		items, err := s.itemRepo.FindAll(s.ctx, model.ItemFilter{SortBy: model.ItemSortPrice, Order: model.SortDesc})
+		s.Require().NoError(err)
+		s.Require().Equal([]model.Item{hoody, cup, pen}, items)
+	})
+
+	s.Run("should skip retired items", func() {
+		_, err := s.pool.Exec(s.ctx, "update items set retired = true where id = $1", hoody.Id)
+		s.Require().NoError(err)
+		defer func() {
+			_, err = s.pool.Exec(s.ctx, "update items set retired = false where id = $1", hoody.Id)
+			s.Require().NoError(err)
+		}()
+
+		items, err := s.itemRepo.FindAll(s.ctx, model.ItemFilter{})
+		s.Require().NoError(err)
+		s.Require().Equal([]model.Item{cup, pen}, items)
+	})
+
+	s.Run("should filter by max price", func() {
+		maxPrice := 20
+		items, err := s.itemRepo.FindAll(s.ctx, model.ItemFilter{SortBy: model.ItemSortPrice, MaxPrice: &maxPrice})
+		s.Require().NoError(err)
+		s.Require().Equal([]model.Item{pen, cup}, items)
+	})
+}
+
 func (s *PGItemRepoTestSuite) insertItem(item *model.Item) {
 	_, err := s.pool.Exec(s.ctx,
-		"insert into items(id, name, price) values ($1, $2, $3)",
-		item.Id, item.Name, item.Price)
+		"insert into items(id, name, price, stock, max_per_employee) values ($1, $2, $3, $4, $5)",
+		item.Id, item.Name, item.Price, item.Stock, item.MaxPerEmployee)
 	s.Require().NoError(err)
 }
@@ -0,0 +1,151 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PGLedgerRepoTestSuite struct {
+	PGDBTestSuite
+	ctx        context.Context
+	ledgerRepo *pgdb.PGLedgerRepo
+}
+
+func (s *PGLedgerRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.ledgerRepo = pgdb.NewPGLedgerRepo(
+		&pgdb.Postgres{
+			Pool:    s.pool,
+			Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		},
+		trmpgx.DefaultCtxGetter,
+	)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table ledger_entries restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGLedgerRepo(t *testing.T) {
+	suite.Run(t, new(PGLedgerRepoTestSuite))
+}
+
+func (s *PGLedgerRepoTestSuite) TestSaveAll() {
+	employeeId := uuid.New()
+	operationId := uuid.New()
+	s.insertEmployee(employeeId, "employee")
+
+	entries := []model.LedgerEntry{
+		{
+			Id:            uuid.New(),
+			OperationId:   operationId,
+			OperationType: model.OperationInitialGrant,
+			Account:       model.AccountEmission,
+			Direction:     model.Debit,
+			Amount:        1000,
+		},
+		{
+			Id:            uuid.New(),
+			OperationId:   operationId,
+			OperationType: model.OperationInitialGrant,
+			Account:       model.AccountEmployee,
+			EmployeeId:    &employeeId,
+			Direction:     model.Credit,
+			Amount:        1000,
+		},
+	}
+
+	s.Run("should save both sides of operation", func() {
+		err := s.ledgerRepo.SaveAll(s.ctx, entries)
+		s.Require().NoError(err)
+
+		saved, err := s.ledgerRepo.FindAllByOperation(s.ctx, operationId)
+		s.Require().NoError(err)
+		s.Require().Len(saved, 2)
+		s.Require().Nil(saved[0].EmployeeId)
+		s.Require().Equal(model.Debit, saved[0].Direction)
+		s.Require().Equal(employeeId, *saved[1].EmployeeId)
+		s.Require().Equal(model.Credit, saved[1].Direction)
+	})
+
+	s.Run("should reject employee entry without employee", func() {
+		err := s.ledgerRepo.SaveAll(s.ctx, []model.LedgerEntry{{
+			Id:            uuid.New(),
+			OperationId:   uuid.New(),
+			OperationType: model.OperationAdjustment,
+			Account:       model.AccountEmployee,
+			Direction:     model.Credit,
+			Amount:        1,
+		}})
+		s.Require().Error(err)
+	})
+}
+
+func (s *PGLedgerRepoTestSuite) TestBalanceByEmployee() {
+	employeeId := uuid.New()
+	s.insertEmployee(employeeId, "employee")
+
+	s.insertEntry(employeeId, model.Credit, 1000)
+	s.insertEntry(employeeId, model.Debit, 300)
+	s.insertEntry(employeeId, model.Credit, 50)
+
+	s.Run("should sum credits minus debits", func() {
+		balance, err := s.ledgerRepo.BalanceByEmployee(s.ctx, employeeId)
+		s.Require().NoError(err)
+		s.Require().Equal(750, balance)
+	})
+
+	s.Run("should return zero for employee without entries", func() {
+		balance, err := s.ledgerRepo.BalanceByEmployee(s.ctx, uuid.New())
+		s.Require().NoError(err)
+		s.Require().Zero(balance)
+	})
+}
+
+func (s *PGLedgerRepoTestSuite) TestFindMismatches() {
+	matching := uuid.New()
+	drifted := uuid.New()
+	empty := uuid.New()
+	s.insertEmployee(matching, "matching")
+	s.insertEmployee(drifted, "drifted")
+	s.insertEmployee(empty, "empty")
+
+	s.insertEntry(matching, model.Credit, 1000)
+	s.insertEntry(matching, model.Debit, 300)
+	s.insertEntry(drifted, model.Credit, 1000)
+
+	_, err := s.pool.Exec(s.ctx,
+		"update employees set balance = case when id = $1 then 700 else 900 end where id in ($1, $2)",
+		matching, drifted)
+	s.Require().NoError(err)
+
+	s.Run("should return only employees whose balance differs from the ledger", func() {
+		mismatches, err := s.ledgerRepo.FindMismatches(s.ctx)
+		s.Require().NoError(err)
+		s.Require().Equal([]model.LedgerMismatch{
+			{EmployeeId: drifted, Username: "drifted", Balance: 900, LedgerBalance: 1000},
+		}, mismatches)
+	})
+}
+
+func (s *PGLedgerRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 0)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
+
+func (s *PGLedgerRepoTestSuite) insertEntry(employeeId uuid.UUID, direction model.EntryDirection, amount int) {
+	_, err := s.pool.Exec(s.ctx,
+		`insert into ledger_entries (id, operation_id, operation_type, account, employee_id, direction, amount)
+		 VALUES ($1, $2, 'adjustment', 'employee', $3, $4, $5)`,
+		uuid.New(), uuid.New(), employeeId, direction, amount)
+	s.Require().NoError(err)
+}
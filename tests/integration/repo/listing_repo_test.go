@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PGListingRepoTestSuite struct {
+	PGDBTestSuite
+	ctx         context.Context
+	listingRepo *pgdb.PGListingRepo
+}
+
+func (s *PGListingRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.listingRepo = pgdb.NewPGListingRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table listings restart identity cascade;
+		      truncate table employees restart identity cascade;
+		      truncate table items restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGListingRepo(t *testing.T) {
+	suite.Run(t, new(PGListingRepoTestSuite))
+}
+
+func (s *PGListingRepoTestSuite) TestSaveAndFind() {
+	seller := uuid.New()
+	cup := uuid.New()
+	pen := uuid.New()
+	s.insertEmployee(seller, "seller")
+	s.insertItem(cup, "cup")
+	s.insertItem(pen, "pen")
+
+	cupListing := s.newListing(seller, cup, time.Now().Add(time.Hour))
+	penListing := s.newListing(seller, pen, time.Now().Add(time.Hour))
+	expiredListing := s.newListing(seller, cup, time.Now().Add(-time.Hour))
+	for _, listing := range []*model.Listing{cupListing, penListing, expiredListing} {
+		s.Require().NoError(s.listingRepo.Save(s.ctx, listing))
+		s.Require().False(listing.CreatedAt.IsZero())
+	}
+
+	s.Run("should find listing by id with seller and item names", func() {
+		found, err := s.listingRepo.FindByIdForUpdate(s.ctx, cupListing.Id)
+		s.Require().NoError(err)
+		s.Require().Equal("seller", found.Seller)
+		s.Require().Equal("cup", found.Item)
+		s.Require().Equal(model.ListingActive, found.Status)
+		s.Require().Nil(found.BuyerId)
+	})
+
+	s.Run("should return error for unknown listing", func() {
+		_, err := s.listingRepo.FindByIdForUpdate(s.ctx, uuid.New())
+		s.Require().ErrorIs(err, repo.ErrListingNotFound)
+	})
+
+	s.Run("should list only unexpired active listings", func() {
+		listings, err := s.listingRepo.FindActive(s.ctx, model.ListingFilter{})
+		s.Require().NoError(err)
+		s.Require().Len(listings, 2)
+
+		listings, err = s.listingRepo.FindActive(s.ctx, model.ListingFilter{Item: "cup"})
+		s.Require().NoError(err)
+		s.Require().Len(listings, 1)
+		s.Require().Equal(cupListing.Id, listings[0].Id)
+	})
+
+	s.Run("should find expired active listings", func() {
+		listings, err := s.listingRepo.FindExpiredForUpdate(s.ctx, 10)
+		s.Require().NoError(err)
+		s.Require().Len(listings, 1)
+		s.Require().Equal(expiredListing.Id, listings[0].Id)
+	})
+}
+
+func (s *PGListingRepoTestSuite) TestClose() {
+	seller := uuid.New()
+	buyer := uuid.New()
+	item := uuid.New()
+	s.insertEmployee(seller, "seller")
+	s.insertEmployee(buyer, "buyer")
+	s.insertItem(item, "cup")
+
+	sold := s.newListing(seller, item, time.Now().Add(time.Hour))
+	cancelled := s.newListing(seller, item, time.Now().Add(time.Hour))
+	s.Require().NoError(s.listingRepo.Save(s.ctx, sold))
+	s.Require().NoError(s.listingRepo.Save(s.ctx, cancelled))
+
+	s.Run("should record buyer of sold listing", func() {
+		s.Require().NoError(s.listingRepo.Close(s.ctx, sold.Id, model.ListingSold, &buyer))
+
+		found, err := s.listingRepo.FindByIdForUpdate(s.ctx, sold.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(model.ListingSold, found.Status)
+		s.Require().Equal(buyer, *found.BuyerId)
+	})
+
+	s.Run("should reject sold listing without buyer", func() {
+		s.Require().Error(s.listingRepo.Close(s.ctx, cancelled.Id, model.ListingSold, nil))
+	})
+
+	s.Run("should cancel listing", func() {
+		s.Require().NoError(s.listingRepo.Close(s.ctx, cancelled.Id, model.ListingCancelled, nil))
+
+		listings, err := s.listingRepo.FindActive(s.ctx, model.ListingFilter{})
+		s.Require().NoError(err)
+		s.Require().Empty(listings)
+	})
+
+	s.Run("should return error for unknown listing", func() {
+		err := s.listingRepo.Close(s.ctx, uuid.New(), model.ListingCancelled, nil)
+		s.Require().ErrorIs(err, repo.ErrListingNotFound)
+	})
+}
+
+func (s *PGListingRepoTestSuite) newListing(sellerId uuid.UUID, itemId uuid.UUID, expiresAt time.Time) *model.Listing {
+	return &model.Listing{
+		Id:        uuid.New(),
+		SellerId:  sellerId,
+		ItemId:    itemId,
+		Quantity:  1,
+		Price:     50,
+		Status:    model.ListingActive,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (s *PGListingRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 1000)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
+
+func (s *PGListingRepoTestSuite) insertItem(itemId uuid.UUID, name string) {
+	_, err := s.pool.Exec(s.ctx, "insert into items (id, name, price) VALUES ($1, $2, 20)", itemId, name)
+	s.Require().NoError(err)
+}
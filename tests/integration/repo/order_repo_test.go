@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PGOrderRepoTestSuite struct {
+	PGDBTestSuite
+	ctx       context.Context
+	orderRepo *pgdb.PGOrderRepo
+}
+
+func (s *PGOrderRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.orderRepo = pgdb.NewPGOrderRepo(
+		&pgdb.Postgres{
+			Pool:    s.pool,
+			Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		},
+		trmpgx.DefaultCtxGetter,
+	)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table orders restart identity cascade;
+		      truncate table items restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGOrderRepo(t *testing.T) {
+	suite.Run(t, new(PGOrderRepoTestSuite))
+}
+
+func (s *PGOrderRepoTestSuite) TestSave() {
+	employeeId := uuid.New()
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, 'employee', 'hash', 1000)",
+		employeeId)
+	s.Require().NoError(err)
+
+	order := &model.Order{Id: uuid.New(), EmployeeId: employeeId, Status: model.OrderPlaced, Total: 70}
+
+	s.Require().NoError(s.orderRepo.Save(s.ctx, order))
+	s.Require().False(order.CreatedAt.IsZero())
+
+	var total int
+	err = s.pool.QueryRow(s.ctx, "select total from orders where id = $1", order.Id).Scan(&total)
+	s.Require().NoError(err)
+	s.Require().Equal(70, total)
+}
+
+func (s *PGOrderRepoTestSuite) insertOrderWithItem(employeeId uuid.UUID, status model.OrderStatus) uuid.UUID {
+	itemId := uuid.New()
+	_, err := s.pool.Exec(s.ctx, "insert into items (id, name, price) VALUES ($1, $2, 20)", itemId, itemId.String())
+	s.Require().NoError(err)
+
+	order := &model.Order{Id: uuid.New(), EmployeeId: employeeId, Status: status, Total: 40}
+	if status == model.OrderDelivered {
+		deliveredAt := time.Now()
+		order.DeliveredAt = &deliveredAt
+	}
+	s.Require().NoError(s.orderRepo.Save(s.ctx, order))
+
+	_, err = s.pool.Exec(s.ctx,
+		`insert into purchases (id, employee_id, item_id, quantity, price, order_id)
+		 VALUES ($1, $2, $3, 2, 20, $4)`,
+		uuid.New(), employeeId, itemId, order.Id)
+	s.Require().NoError(err)
+
+	return order.Id
+}
+
+func (s *PGOrderRepoTestSuite) TestFindAllAndUpdateStatus() {
+	employeeId := uuid.New()
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, 'employee', 'hash', 1000)",
+		employeeId)
+	s.Require().NoError(err)
+
+	placedId := s.insertOrderWithItem(employeeId, model.OrderPlaced)
+	s.insertOrderWithItem(employeeId, model.OrderDelivered)
+
+	placed, err := s.orderRepo.FindAll(s.ctx, model.OrderFilter{EmployeeId: &employeeId, Status: model.OrderPlaced})
+	s.Require().NoError(err)
+	s.Require().Len(placed, 1)
+	s.Require().Equal(placedId, placed[0].Id)
+	s.Require().Equal("employee", placed[0].Employee)
+	s.Require().Len(placed[0].Items, 1)
+	s.Require().Equal(2, placed[0].Items[0].Quantity)
+
+	all, err := s.orderRepo.FindAll(s.ctx, model.OrderFilter{})
+	s.Require().NoError(err)
+	s.Require().Len(all, 2)
+
+	s.Require().NoError(s.orderRepo.UpdateStatus(s.ctx, placedId, model.OrderCancelled))
+
+	order, err := s.orderRepo.FindByIdForUpdate(s.ctx, placedId)
+	s.Require().NoError(err)
+	s.Require().Equal(model.OrderCancelled, order.Status)
+
+	var cancelled bool
+	err = s.pool.QueryRow(s.ctx, "select cancelled_at is not null from orders where id = $1", placedId).
+		Scan(&cancelled)
+	s.Require().NoError(err)
+	s.Require().True(cancelled)
+	s.Require().Nil(order.DeliveredAt)
+
+	readyId := s.insertOrderWithItem(employeeId, model.OrderReadyForPickup)
+	s.Require().NoError(s.orderRepo.UpdateStatus(s.ctx, readyId, model.OrderDelivered))
+
+	delivered, err := s.orderRepo.FindByIdForUpdate(s.ctx, readyId)
+	s.Require().NoError(err)
+	s.Require().NotNil(delivered.DeliveredAt)
+	s.Require().WithinDuration(time.Now(), *delivered.DeliveredAt, time.Minute)
+}
+
+func (s *PGOrderRepoTestSuite) TestOrderNotFound() {
+	_, err := s.orderRepo.FindByIdForUpdate(s.ctx, uuid.New())
+	s.Require().ErrorIs(err, repo.ErrOrderNotFound)
+
+	err = s.orderRepo.UpdateStatus(s.ctx, uuid.New(), model.OrderDelivered)
+	s.Require().ErrorIs(err, repo.ErrOrderNotFound)
+}
@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PGPaymentRequestRepoTestSuite struct {
+	PGDBTestSuite
+	ctx                context.Context
+	paymentRequestRepo *pgdb.PGPaymentRequestRepo
+}
+
+func (s *PGPaymentRequestRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.paymentRequestRepo = pgdb.NewPGPaymentRequestRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table payment_requests restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGPaymentRequestRepo(t *testing.T) {
+	suite.Run(t, new(PGPaymentRequestRepoTestSuite))
+}
+
+func (s *PGPaymentRequestRepoTestSuite) TestSaveAndFind() {
+	requester := uuid.New()
+	payer := uuid.New()
+	s.insertEmployee(requester, "requester")
+	s.insertEmployee(payer, "payer")
+
+	pending := s.newRequest(requester, payer, time.Now().Add(time.Hour))
+	pending.Message = "hoodie"
+	expired := s.newRequest(requester, payer, time.Now().Add(-time.Hour))
+	for _, request := range []*model.PaymentRequest{pending, expired} {
+		s.Require().NoError(s.paymentRequestRepo.Save(s.ctx, request))
+		s.Require().False(request.CreatedAt.IsZero())
+	}
+
+	s.Run("should find request by id with usernames", func() {
+		found, err := s.paymentRequestRepo.FindByIdForUpdate(s.ctx, pending.Id)
+		s.Require().NoError(err)
+		s.Require().Equal("requester", found.Requester)
+		s.Require().Equal("payer", found.Payer)
+		s.Require().Equal("hoodie", found.Message)
+		s.Require().Equal(model.PaymentRequestPending, found.Status)
+		s.Require().Nil(found.ResolvedAt)
+	})
+
+	s.Run("should report pending request past its ttl as expired", func() {
+		found, err := s.paymentRequestRepo.FindByIdForUpdate(s.ctx, expired.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(model.PaymentRequestExpired, found.Status)
+	})
+
+	s.Run("should return error for unknown request", func() {
+		_, err := s.paymentRequestRepo.FindByIdForUpdate(s.ctx, uuid.New())
+		s.Require().ErrorIs(err, repo.ErrPaymentRequestNotFound)
+	})
+
+	s.Run("should list requests by direction and status", func() {
+		requests, err := s.paymentRequestRepo.FindByEmployee(s.ctx, payer,
+			model.PaymentRequestFilter{Direction: model.PaymentRequestsIncoming})
+		s.Require().NoError(err)
+		s.Require().Len(requests, 2)
+
+		requests, err = s.paymentRequestRepo.FindByEmployee(s.ctx, payer, model.PaymentRequestFilter{
+			Direction: model.PaymentRequestsIncoming, Status: model.PaymentRequestPending,
+		})
+		s.Require().NoError(err)
+		s.Require().Len(requests, 1)
+		s.Require().Equal(pending.Id, requests[0].Id)
+
+		requests, err = s.paymentRequestRepo.FindByEmployee(s.ctx, requester, model.PaymentRequestFilter{
+			Direction: model.PaymentRequestsOutgoing, Status: model.PaymentRequestExpired,
+		})
+		s.Require().NoError(err)
+		s.Require().Len(requests, 1)
+		s.Require().Equal(expired.Id, requests[0].Id)
+
+		requests, err = s.paymentRequestRepo.FindByEmployee(s.ctx, requester,
+			model.PaymentRequestFilter{Direction: model.PaymentRequestsIncoming})
+		s.Require().NoError(err)
+		s.Require().Empty(requests)
+	})
+}
+
+func (s *PGPaymentRequestRepoTestSuite) TestResolve() {
+	requester := uuid.New()
+	payer := uuid.New()
+	s.insertEmployee(requester, "requester")
+	s.insertEmployee(payer, "payer")
+
+	request := s.newRequest(requester, payer, time.Now().Add(time.Hour))
+	s.Require().NoError(s.paymentRequestRepo.Save(s.ctx, request))
+
+	s.Run("should record resolution time", func() {
+		s.Require().NoError(s.paymentRequestRepo.Resolve(s.ctx, request.Id, model.PaymentRequestAccepted))
+
+		found, err := s.paymentRequestRepo.FindByIdForUpdate(s.ctx, request.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(model.PaymentRequestAccepted, found.Status)
+		s.Require().NotNil(found.ResolvedAt)
+	})
+
+	s.Run("should return error for unknown request", func() {
+		err := s.paymentRequestRepo.Resolve(s.ctx, uuid.New(), model.PaymentRequestDeclined)
+		s.Require().ErrorIs(err, repo.ErrPaymentRequestNotFound)
+	})
+}
+
+func (s *PGPaymentRequestRepoTestSuite) newRequest(
+	requesterId uuid.UUID, payerId uuid.UUID, expiresAt time.Time) *model.PaymentRequest {
+	return &model.PaymentRequest{
+		Id:          uuid.New(),
+		RequesterId: requesterId,
+		PayerId:     payerId,
+		Amount:      15,
+		Status:      model.PaymentRequestPending,
+		ExpiresAt:   expiresAt,
+	}
+}
+
+func (s *PGPaymentRequestRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 1000)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
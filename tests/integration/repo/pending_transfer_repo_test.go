@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PGPendingTransferRepoTestSuite struct {
+	PGDBTestSuite
+	ctx                 context.Context
+	pendingTransferRepo *pgdb.PGPendingTransferRepo
+}
+
+func (s *PGPendingTransferRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.pendingTransferRepo = pgdb.NewPGPendingTransferRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table pending_transfers restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGPendingTransferRepo(t *testing.T) {
+	suite.Run(t, new(PGPendingTransferRepoTestSuite))
+}
+
+func (s *PGPendingTransferRepoTestSuite) TestSaveAndFind() {
+	manager := uuid.New()
+	report := uuid.New()
+	other := uuid.New()
+	s.insertEmployee(manager, "manager", nil)
+	s.insertEmployee(report, "report", &manager)
+	s.insertEmployee(other, "other", nil)
+
+	fromReport := s.newTransfer(report, other)
+	fromReport.Message = "bonus"
+	fromReport.Category = model.CategoryThanks
+	fromOther := s.newTransfer(other, report)
+	for _, transfer := range []*model.PendingTransfer{fromReport, fromOther} {
+		s.Require().NoError(s.pendingTransferRepo.Save(s.ctx, transfer))
+		s.Require().False(transfer.CreatedAt.IsZero())
+	}
+
+	s.Run("should find transfer by id with usernames", func() {
+		found, err := s.pendingTransferRepo.FindByIdForUpdate(s.ctx, fromReport.Id)
+		s.Require().NoError(err)
+		s.Require().Equal("report", found.From)
+		s.Require().Equal("other", found.To)
+		s.Require().Equal("bonus", found.Message)
+		s.Require().Equal(model.CategoryThanks, found.Category)
+		s.Require().Equal(model.PendingTransferPending, found.Status)
+		s.Require().Nil(found.ApproverId)
+	})
+
+	s.Run("should return error for unknown transfer", func() {
+		_, err := s.pendingTransferRepo.FindByIdForUpdate(s.ctx, uuid.New())
+		s.Require().ErrorIs(err, repo.ErrPendingTransferNotFound)
+	})
+
+	s.Run("should list every pending transfer for admins", func() {
+		transfers, err := s.pendingTransferRepo.FindPending(s.ctx, nil)
+		s.Require().NoError(err)
+		s.Require().Len(transfers, 2)
+	})
+
+	s.Run("should list only reports' transfers for a manager", func() {
+		transfers, err := s.pendingTransferRepo.FindPending(s.ctx, &manager)
+		s.Require().NoError(err)
+		s.Require().Len(transfers, 1)
+		s.Require().Equal(fromReport.Id, transfers[0].Id)
+	})
+}
+
+func (s *PGPendingTransferRepoTestSuite) TestResolve() {
+	sender := uuid.New()
+	receiver := uuid.New()
+	approver := uuid.New()
+	s.insertEmployee(sender, "sender", nil)
+	s.insertEmployee(receiver, "receiver", nil)
+	s.insertEmployee(approver, "approver", nil)
+
+	transfer := s.newTransfer(sender, receiver)
+	s.Require().NoError(s.pendingTransferRepo.Save(s.ctx, transfer))
+
+	s.Run("should record approver and resolution time", func() {
+		s.Require().NoError(s.pendingTransferRepo.Resolve(s.ctx, transfer.Id, model.PendingTransferApproved, approver))
+
+		found, err := s.pendingTransferRepo.FindByIdForUpdate(s.ctx, transfer.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(model.PendingTransferApproved, found.Status)
+		s.Require().Equal(&approver, found.ApproverId)
+		s.Require().NotNil(found.ResolvedAt)
+
+		transfers, err := s.pendingTransferRepo.FindPending(s.ctx, nil)
+		s.Require().NoError(err)
+		s.Require().Empty(transfers)
+	})
+
+	s.Run("should return error for unknown transfer", func() {
+		err := s.pendingTransferRepo.Resolve(s.ctx, uuid.New(), model.PendingTransferRejected, approver)
+		s.Require().ErrorIs(err, repo.ErrPendingTransferNotFound)
+	})
+}
+
+func (s *PGPendingTransferRepoTestSuite) newTransfer(fromId uuid.UUID, toId uuid.UUID) *model.PendingTransfer {
+	return &model.PendingTransfer{
+		Id:           uuid.New(),
+		FromEmployee: fromId,
+		ToEmployee:   toId,
+		Amount:       600,
+		Status:       model.PendingTransferPending,
+	}
+}
+
+func (s *PGPendingTransferRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string, managerId *uuid.UUID) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance, manager_id) VALUES ($1, $2, 'hash', 1000, $3)",
+		employeeId, username, managerId)
+	s.Require().NoError(err)
+}
@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PGPurchaseRepoTestSuite struct {
+	PGDBTestSuite
+	ctx                context.Context
+	purchaseRepo       *pgdb.PGPurchaseRepo
+	purchaseReturnRepo *pgdb.PGPurchaseReturnRepo
+}
+
+func (s *PGPurchaseRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.purchaseRepo = pgdb.NewPGPurchaseRepo(pg, trmpgx.DefaultCtxGetter)
+	s.purchaseReturnRepo = pgdb.NewPGPurchaseReturnRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table purchases restart identity cascade;
+		      truncate table orders restart identity cascade;
+		      truncate table employees restart identity cascade;
+		      truncate table items restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGPurchaseRepo(t *testing.T) {
+	suite.Run(t, new(PGPurchaseRepoTestSuite))
+}
+
+func (s *PGPurchaseRepoTestSuite) TestCountByEmployeeAndItem() {
+	employee := uuid.New()
+	colleague := uuid.New()
+	item := uuid.New()
+	s.insertEmployee(employee, "employee")
+	s.insertEmployee(colleague, "colleague")
+	s.insertItem(item, "cup")
+
+	s.Run("should return zero without purchases", func() {
+		count, err := s.purchaseRepo.CountByEmployeeAndItem(s.ctx, employee, item)
+		s.Require().NoError(err)
+		s.Require().Equal(0, count)
+	})
+
+	s.Run("should sum purchased quantity of employee", func() {
+		order := s.insertOrder(employee)
+		colleagueOrder := s.insertOrder(colleague)
+		for _, purchase := range []model.Purchase{
+			{Id: uuid.New(), OrderId: order, EmployeeId: employee, ItemId: item, Quantity: 1, Price: 20},
+			{Id: uuid.New(), OrderId: order, EmployeeId: employee, ItemId: item, Quantity: 2, Price: 20},
+			{Id: uuid.New(), OrderId: colleagueOrder, EmployeeId: colleague, ItemId: item, Quantity: 5, Price: 20},
+		} {
+			s.Require().NoError(s.purchaseRepo.Save(s.ctx, &purchase))
+		}
+
+		count, err := s.purchaseRepo.CountByEmployeeAndItem(s.ctx, employee, item)
+		s.Require().NoError(err)
+		s.Require().Equal(3, count)
+	})
+}
+
+func (s *PGPurchaseRepoTestSuite) TestReturns() {
+	employee := uuid.New()
+	item := uuid.New()
+	s.insertEmployee(employee, "employee")
+	s.insertItem(item, "cup")
+
+	purchase := model.Purchase{
+		Id: uuid.New(), OrderId: s.insertOrder(employee), EmployeeId: employee, ItemId: item, Quantity: 3, Price: 20,
+	}
+	s.Require().NoError(s.purchaseRepo.Save(s.ctx, &purchase))
+
+	s.Run("should record returned quantity", func() {
+		s.Require().NoError(s.purchaseRepo.UpdateReturnedQuantity(s.ctx, purchase.Id, 2))
+
+		found, err := s.purchaseRepo.FindByIdForUpdate(s.ctx, purchase.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(2, found.ReturnedQuantity)
+		s.Require().Equal(20, found.Price)
+
+		count, err := s.purchaseRepo.CountByEmployeeAndItem(s.ctx, employee, item)
+		s.Require().NoError(err)
+		s.Require().Equal(1, count)
+	})
+
+	s.Run("should not return more than purchased", func() {
+		err := s.purchaseRepo.UpdateReturnedQuantity(s.ctx, purchase.Id, 4)
+		s.Require().Error(err)
+	})
+
+	s.Run("should save return", func() {
+		purchaseReturn := &model.PurchaseReturn{
+			Id: uuid.New(), PurchaseId: purchase.Id, EmployeeId: employee, Quantity: 2, Amount: 40,
+		}
+		s.Require().NoError(s.purchaseReturnRepo.Save(s.ctx, purchaseReturn))
+		s.Require().False(purchaseReturn.CreatedAt.IsZero())
+	})
+
+	s.Run("should fail on missing purchase", func() {
+		_, err := s.purchaseRepo.FindByIdForUpdate(s.ctx, uuid.New())
+		s.Require().ErrorIs(err, repo.ErrPurchaseNotFound)
+	})
+}
+
+func (s *PGPurchaseRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 1000)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
+
+func (s *PGPurchaseRepoTestSuite) insertItem(itemId uuid.UUID, name string) {
+	_, err := s.pool.Exec(s.ctx, "insert into items (id, name, price) VALUES ($1, $2, 20)", itemId, name)
+	s.Require().NoError(err)
+}
+
+func (s *PGPurchaseRepoTestSuite) insertOrder(employeeId uuid.UUID) uuid.UUID {
+	id := uuid.New()
+	_, err := s.pool.Exec(s.ctx, "insert into orders (id, employee_id, total) VALUES ($1, $2, 0)", id, employeeId)
+	s.Require().NoError(err)
+	return id
+}
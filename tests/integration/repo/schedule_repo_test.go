@@ -0,0 +1,187 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PGScheduleRepoTestSuite struct {
+	PGDBTestSuite
+	ctx          context.Context
+	scheduleRepo *pgdb.PGScheduleRepo
+}
+
+func (s *PGScheduleRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.scheduleRepo = pgdb.NewPGScheduleRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table scheduled_transfers restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGScheduleRepo(t *testing.T) {
+	suite.Run(t, new(PGScheduleRepoTestSuite))
+}
+
+func (s *PGScheduleRepoTestSuite) TestSaveAndFind() {
+	sender := uuid.New()
+	receiver := uuid.New()
+	s.insertEmployee(sender, "sender")
+	s.insertEmployee(receiver, "receiver")
+
+	due := s.newSchedule(sender, receiver, time.Now().Add(-time.Minute))
+	due.Message = "rent"
+	due.Category = model.CategoryHelp
+	later := s.newSchedule(sender, receiver, time.Now().Add(time.Hour))
+	for _, schedule := range []*model.ScheduledTransfer{due, later} {
+		s.Require().NoError(s.scheduleRepo.Save(s.ctx, schedule))
+		s.Require().False(schedule.CreatedAt.IsZero())
+	}
+
+	s.Run("should find schedule by id with usernames and memo", func() {
+		found, err := s.scheduleRepo.FindById(s.ctx, due.Id)
+		s.Require().NoError(err)
+		s.Require().Equal("sender", found.From)
+		s.Require().Equal("receiver", found.To)
+		s.Require().Equal("rent", found.Message)
+		s.Require().Equal(model.CategoryHelp, found.Category)
+		s.Require().Equal(model.ScheduleActive, found.Status)
+	})
+
+	s.Run("should return error for unknown schedule", func() {
+		_, err := s.scheduleRepo.FindByIdForUpdate(s.ctx, uuid.New())
+		s.Require().ErrorIs(err, repo.ErrScheduleNotFound)
+	})
+
+	s.Run("should list schedules of sender", func() {
+		schedules, err := s.scheduleRepo.FindByEmployee(s.ctx, sender)
+		s.Require().NoError(err)
+		s.Require().Len(schedules, 2)
+
+		schedules, err = s.scheduleRepo.FindByEmployee(s.ctx, receiver)
+		s.Require().NoError(err)
+		s.Require().Empty(schedules)
+	})
+
+	s.Run("should find only due schedules", func() {
+		schedules, err := s.scheduleRepo.FindDueForUpdate(s.ctx, 10)
+		s.Require().NoError(err)
+		s.Require().Len(schedules, 1)
+		s.Require().Equal(due.Id, schedules[0].Id)
+	})
+
+	s.Run("should skip schedules locked by another transaction", func() {
+		tx, err := s.pool.Begin(s.ctx)
+		s.Require().NoError(err)
+		defer func() { _ = tx.Rollback(s.ctx) }()
+
+		_, err = tx.Exec(s.ctx, "select id from scheduled_transfers where id = $1 for update", due.Id)
+		s.Require().NoError(err)
+
+		schedules, err := s.scheduleRepo.FindDueForUpdate(s.ctx, 10)
+		s.Require().NoError(err)
+		s.Require().Empty(schedules)
+	})
+}
+
+func (s *PGScheduleRepoTestSuite) TestUpdateAndRuns() {
+	sender := uuid.New()
+	receiver := uuid.New()
+	s.insertEmployee(sender, "sender")
+	s.insertEmployee(receiver, "receiver")
+
+	schedule := s.newSchedule(sender, receiver, time.Now().Add(-time.Minute))
+	s.Require().NoError(s.scheduleRepo.Save(s.ctx, schedule))
+
+	s.Run("should record failed run and retry state", func() {
+		run := &model.ScheduledTransferRun{
+			Id:          uuid.New(),
+			ScheduleId:  schedule.Id,
+			ScheduledAt: schedule.NextRunAt,
+			Attempt:     1,
+			Status:      model.RunFailed,
+			Reason:      "not enough coins",
+		}
+		s.Require().NoError(s.scheduleRepo.SaveRun(s.ctx, run))
+
+		schedule.Attempts = 1
+		schedule.LastError = "not enough coins"
+		schedule.NextAttemptAt = time.Now().Add(time.Hour)
+		s.Require().NoError(s.scheduleRepo.Update(s.ctx, schedule))
+
+		found, err := s.scheduleRepo.FindById(s.ctx, schedule.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(1, found.Attempts)
+		s.Require().Equal("not enough coins", found.LastError)
+
+		runs, err := s.scheduleRepo.FindRuns(s.ctx, schedule.Id)
+		s.Require().NoError(err)
+		s.Require().Len(runs, 1)
+		s.Require().Equal(model.RunFailed, runs[0].Status)
+		s.Require().Equal("not enough coins", runs[0].Reason)
+	})
+
+	s.Run("should reject failed run without reason", func() {
+		run := &model.ScheduledTransferRun{
+			Id:          uuid.New(),
+			ScheduleId:  schedule.Id,
+			ScheduledAt: schedule.NextRunAt,
+			Attempt:     2,
+			Status:      model.RunFailed,
+		}
+		s.Require().Error(s.scheduleRepo.SaveRun(s.ctx, run))
+	})
+
+	s.Run("should clear last error", func() {
+		schedule.Status = model.ScheduleCompleted
+		schedule.Attempts = 0
+		schedule.LastError = ""
+		s.Require().NoError(s.scheduleRepo.Update(s.ctx, schedule))
+
+		found, err := s.scheduleRepo.FindById(s.ctx, schedule.Id)
+		s.Require().NoError(err)
+		s.Require().Equal(model.ScheduleCompleted, found.Status)
+		s.Require().Empty(found.LastError)
+	})
+
+	s.Run("should return error for unknown schedule", func() {
+		err := s.scheduleRepo.Update(s.ctx, &model.ScheduledTransfer{Id: uuid.New(), Status: model.ScheduleActive})
+		s.Require().ErrorIs(err, repo.ErrScheduleNotFound)
+	})
+}
+
+func (s *PGScheduleRepoTestSuite) newSchedule(
+	senderId uuid.UUID, receiverId uuid.UUID, runAt time.Time) *model.ScheduledTransfer {
+	return &model.ScheduledTransfer{
+		Id:            uuid.New(),
+		FromEmployee:  senderId,
+		ToEmployee:    receiverId,
+		Amount:        10,
+		Recurrence:    model.RecurrenceOnce,
+		Status:        model.ScheduleActive,
+		StartAt:       runAt,
+		NextRunAt:     runAt,
+		NextAttemptAt: runAt,
+	}
+}
+
+func (s *PGScheduleRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 1000)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
@@ -0,0 +1,137 @@
+package repo
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo"
+	"avito-shop/internal/repo/pgdb"
+	"context"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PGSessionRepoTestSuite struct {
+	PGDBTestSuite
+	ctx              context.Context
+	sessionRepo      *pgdb.PGSessionRepo
+	refreshTokenRepo *pgdb.PGRefreshTokenRepo
+}
+
+func (s *PGSessionRepoTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    s.pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	s.sessionRepo = pgdb.NewPGSessionRepo(pg, trmpgx.DefaultCtxGetter)
+	s.refreshTokenRepo = pgdb.NewPGRefreshTokenRepo(pg, trmpgx.DefaultCtxGetter)
+
+	_, err := s.pool.Exec(s.ctx,
+		`truncate table sessions restart identity cascade;
+		      truncate table employees restart identity cascade;`)
+	s.Require().NoError(err)
+}
+
+func TestPGSessionRepo(t *testing.T) {
+	suite.Run(t, new(PGSessionRepoTestSuite))
+}
+
+func (s *PGSessionRepoTestSuite) TestSessionLifecycle() {
+	employeeId := uuid.New()
+	s.insertEmployee(employeeId, "employee")
+
+	session := &model.Session{Id: uuid.New(), EmployeeId: employeeId}
+
+	s.Run("should save active session", func() {
+		s.Require().NoError(s.sessionRepo.Save(s.ctx, session))
+		s.Require().False(session.CreatedAt.IsZero())
+
+		active, err := s.sessionRepo.IsActive(s.ctx, session.Id)
+		s.Require().NoError(err)
+		s.Require().True(active)
+	})
+
+	s.Run("should revoke session", func() {
+		s.Require().NoError(s.sessionRepo.Revoke(s.ctx, session.Id))
+
+		found, err := s.sessionRepo.FindByIdForUpdate(s.ctx, session.Id)
+		s.Require().NoError(err)
+		s.Require().NotNil(found.RevokedAt)
+
+		active, err := s.sessionRepo.IsActive(s.ctx, session.Id)
+		s.Require().NoError(err)
+		s.Require().False(active)
+	})
+
+	s.Run("should not find unknown session", func() {
+		_, err := s.sessionRepo.FindByIdForUpdate(s.ctx, uuid.New())
+		s.Require().ErrorIs(err, repo.ErrSessionNotFound)
+
+		active, err := s.sessionRepo.IsActive(s.ctx, uuid.New())
+		s.Require().NoError(err)
+		s.Require().False(active)
+	})
+}
+
+func (s *PGSessionRepoTestSuite) TestDeactivatedEmployeeSessionIsInactive() {
+	employeeId := uuid.New()
+	s.insertEmployee(employeeId, "employee")
+
+	session := &model.Session{Id: uuid.New(), EmployeeId: employeeId}
+	s.Require().NoError(s.sessionRepo.Save(s.ctx, session))
+
+	_, err := s.pool.Exec(s.ctx, "update employees set status = 'deactivated' where id = $1", employeeId)
+	s.Require().NoError(err)
+
+	active, err := s.sessionRepo.IsActive(s.ctx, session.Id)
+	s.Require().NoError(err)
+	s.Require().False(active)
+}
+
+func (s *PGSessionRepoTestSuite) TestRefreshTokens() {
+	employeeId := uuid.New()
+	s.insertEmployee(employeeId, "employee")
+
+	session := &model.Session{Id: uuid.New(), EmployeeId: employeeId}
+	s.Require().NoError(s.sessionRepo.Save(s.ctx, session))
+
+	token := &model.RefreshToken{
+		Id:        uuid.New(),
+		SessionId: session.Id,
+		TokenHash: "hash",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	s.Run("should save refresh token", func() {
+		s.Require().NoError(s.refreshTokenRepo.Save(s.ctx, token))
+
+		found, err := s.refreshTokenRepo.FindByHashForUpdate(s.ctx, "hash")
+		s.Require().NoError(err)
+		s.Require().Equal(token.Id, found.Id)
+		s.Require().Equal(session.Id, found.SessionId)
+		s.Require().Nil(found.UsedAt)
+	})
+
+	s.Run("should mark refresh token used", func() {
+		s.Require().NoError(s.refreshTokenRepo.MarkUsed(s.ctx, token.Id))
+
+		found, err := s.refreshTokenRepo.FindByHashForUpdate(s.ctx, "hash")
+		s.Require().NoError(err)
+		s.Require().NotNil(found.UsedAt)
+	})
+
+	s.Run("should not find unknown hash", func() {
+		_, err := s.refreshTokenRepo.FindByHashForUpdate(s.ctx, "unknown")
+		s.Require().ErrorIs(err, repo.ErrRefreshTokenNotFound)
+	})
+}
+
+func (s *PGSessionRepoTestSuite) insertEmployee(employeeId uuid.UUID, username string) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into employees (id, username, password_hash, balance) VALUES ($1, $2, 'hash', 1000)",
+		employeeId, username)
+	s.Require().NoError(err)
+}
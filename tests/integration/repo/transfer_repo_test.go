@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type PGTransferRepoTestSuite struct {
@@ -61,6 +62,149 @@ func (s *PGTransferRepoTestSuite) TestSave() {
 		s.Require().Equal(testTransfer.FromEmployee, savedTransfer.FromEmployee)
 		s.Require().Equal(testTransfer.ToEmployee, savedTransfer.ToEmployee)
 		s.Require().Equal(testTransfer.Amount, savedTransfer.Amount)
+		s.Require().Empty(savedTransfer.Message)
+		s.Require().Empty(savedTransfer.Category)
+	})
+
+	s.Run("should save transfer memo", func() {
+		withMemo := model.Transfer{
+			Id:           uuid.New(),
+			FromEmployee: senderId,
+			ToEmployee:   receiverId,
+			Amount:       5,
+			Message:      "thanks for the review",
+			Category:     model.CategoryThanks,
+		}
+		s.Require().NoError(s.transferRepo.Save(s.ctx, &withMemo))
+
+		savedTransfer := s.selectTransferById(withMemo.Id)
+		s.Require().Equal(withMemo.Message, savedTransfer.Message)
+		s.Require().Equal(withMemo.Category, savedTransfer.Category)
+	})
+
+	s.Run("should reject unknown category", func() {
+		invalid := model.Transfer{
+			Id: uuid.New(), FromEmployee: senderId, ToEmployee: receiverId, Amount: 1, Category: "bribe",
+		}
+		s.Require().Error(s.transferRepo.Save(s.ctx, &invalid))
+	})
+}
+
+func (s *PGTransferRepoTestSuite) TestFindKudos() {
+	alice := uuid.New()
+	bob := uuid.New()
+	s.insertEmployee(alice, "alice")
+	s.insertEmployee(bob, "bob")
+
+	transfers := []model.Transfer{
+		{Id: uuid.New(), FromEmployee: alice, ToEmployee: bob, Amount: 10, Category: model.CategoryThanks},
+		{Id: uuid.New(), FromEmployee: bob, ToEmployee: alice, Amount: 5, Message: "great demo"},
+		{Id: uuid.New(), FromEmployee: alice, ToEmployee: bob, Amount: 1},
+	}
+	for _, transfer := range transfers {
+		s.Require().NoError(s.transferRepo.Save(s.ctx, &transfer))
+	}
+
+	s.Run("should return only transfers with a memo", func() {
+		kudos, err := s.transferRepo.FindKudos(s.ctx, model.KudosFilter{Limit: 10})
+		s.Require().NoError(err)
+		s.Require().Len(kudos, 2)
+	})
+
+	s.Run("should filter by category", func() {
+		kudos, err := s.transferRepo.FindKudos(s.ctx, model.KudosFilter{Category: model.CategoryThanks, Limit: 10})
+		s.Require().NoError(err)
+		s.Require().Len(kudos, 1)
+		s.Require().Equal("alice", kudos[0].From)
+		s.Require().Equal("bob", kudos[0].To)
+		s.Require().Equal(10, kudos[0].Amount)
+	})
+
+	s.Run("should continue after cursor", func() {
+		first, err := s.transferRepo.FindKudos(s.ctx, model.KudosFilter{Limit: 1})
+		s.Require().NoError(err)
+		s.Require().Len(first, 1)
+
+		cursor := &model.Cursor{CreatedAt: first[0].CreatedAt, Id: first[0].Id}
+		rest, err := s.transferRepo.FindKudos(s.ctx, model.KudosFilter{Cursor: cursor, Limit: 10})
+		s.Require().NoError(err)
+		s.Require().Len(rest, 1)
+		s.Require().NotEqual(first[0].Id, rest[0].Id)
+	})
+}
+
+func (s *PGTransferRepoTestSuite) TestFindVelocity() {
+	alice := uuid.New()
+	bob := uuid.New()
+	carol := uuid.New()
+	s.insertEmployee(alice, "alice")
+	s.insertEmployee(bob, "bob")
+	s.insertEmployee(carol, "carol")
+
+	now := time.Now()
+	s.insertTransferAt(alice, bob, 10, now.Add(-10*time.Minute))
+	s.insertTransferAt(alice, carol, 20, now.Add(-3*time.Hour))
+	s.insertTransferAt(alice, bob, 40, now.AddDate(0, 0, -10))
+	s.insertTransferAt(alice, bob, 80, now.AddDate(0, 0, -40))
+	s.insertTransferAt(bob, alice, 160, now.Add(-time.Minute))
+
+	pendingId := uuid.New()
+	_, err := s.pool.Exec(s.ctx,
+		`insert into pending_transfers (id, from_employee, to_employee, amount, status, created_at)
+		VALUES ($1, $2, $3, 5, 'pending', now()), ($4, $2, $3, 7, 'rejected', now()),
+		       ($5, $2, $3, 3, 'pending', now() - interval '20 days')`,
+		pendingId, alice, bob, uuid.New(), uuid.New())
+	s.Require().NoError(err)
+
+	itemId := uuid.New()
+	_, err = s.pool.Exec(s.ctx, "insert into items (id, name, price) VALUES ($1, $2, 20)", itemId, itemId.String())
+	s.Require().NoError(err)
+	_, err = s.pool.Exec(s.ctx,
+		`insert into listings (id, seller_id, item_id, quantity, price, status, buyer_id, expires_at, closed_at)
+		VALUES ($1, $2, $3, 1, 9, 'sold', $4, now(), now() - interval '5 minutes'),
+		       ($5, $2, $3, 1, 11, 'sold', $4, now(), now() - interval '2 days')`,
+		uuid.New(), carol, itemId, alice, uuid.New())
+	s.Require().NoError(err)
+
+	s.Run("should sum outgoing transfers in each window", func() {
+		velocity, err := s.transferRepo.FindVelocity(s.ctx, model.VelocityQuery{
+			FromEmployee: alice,
+			ToEmployee:   bob,
+			DaySince:     now.Add(-24 * time.Hour),
+			HourSince:    now.Add(-time.Hour),
+			MonthSince:   now.AddDate(0, 0, -30),
+		})
+		s.Require().NoError(err)
+		s.Require().Equal(47, velocity.DailyAmount)
+		s.Require().Equal(4, velocity.HourlyCount)
+		s.Require().Equal(58, velocity.MonthlyRecipientAmount)
+	})
+
+	s.Run("should skip the excluded pending transfer", func() {
+		velocity, err := s.transferRepo.FindVelocity(s.ctx, model.VelocityQuery{
+			FromEmployee:   alice,
+			ToEmployee:     bob,
+			DaySince:       now.Add(-24 * time.Hour),
+			HourSince:      now.Add(-time.Hour),
+			MonthSince:     now.AddDate(0, 0, -30),
+			ExcludePending: pendingId,
+		})
+		s.Require().NoError(err)
+		s.Require().Equal(42, velocity.DailyAmount)
+		s.Require().Equal(3, velocity.HourlyCount)
+		s.Require().Equal(53, velocity.MonthlyRecipientAmount)
+	})
+
+	s.Run("should return zeros without transfers", func() {
+		velocity, err := s.transferRepo.FindVelocity(s.ctx, model.VelocityQuery{
+			FromEmployee: carol,
+			ToEmployee:   bob,
+			DaySince:     now.Add(-24 * time.Hour),
+			HourSince:    now.Add(-time.Hour),
+			MonthSince:   now.AddDate(0, 0, -30),
+		})
+		s.Require().NoError(err)
+		s.Require().Equal(model.TransferVelocity{}, *velocity)
 	})
 }
 
@@ -161,10 +305,21 @@ func (s *PGTransferRepoTestSuite) insertTransfer(transfer *model.Transfer) {
 	s.Require().NoError(err)
 }
 
+func (s *PGTransferRepoTestSuite) insertTransferAt(from, to uuid.UUID, amount int, createdAt time.Time) {
+	_, err := s.pool.Exec(s.ctx,
+		"insert into transfers (id, from_employee, to_employee, amount, created_at) VALUES ($1, $2, $3, $4, $5)",
+		uuid.New(), from, to, amount, createdAt)
+
+	s.Require().NoError(err)
+}
+
 func (s *PGTransferRepoTestSuite) selectTransferById(id uuid.UUID) *model.Transfer {
 	var savedTransfer model.Transfer
-	err := s.pool.QueryRow(s.ctx, "select id, from_employee, to_employee, amount from transfers where id = $1", id).
-		Scan(&savedTransfer.Id, &savedTransfer.FromEmployee, &savedTransfer.ToEmployee, &savedTransfer.Amount)
+	err := s.pool.QueryRow(s.ctx,
+		`select id, from_employee, to_employee, amount, coalesce(message, ''), coalesce(category, '')
+		 from transfers where id = $1`, id).
+		Scan(&savedTransfer.Id, &savedTransfer.FromEmployee, &savedTransfer.ToEmployee, &savedTransfer.Amount,
+			&savedTransfer.Message, &savedTransfer.Category)
 	s.Require().NoError(err)
 	return &savedTransfer
 }
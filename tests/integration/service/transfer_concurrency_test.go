@@ -0,0 +1,217 @@
+package service
+
+import (
+	"avito-shop/internal/model"
+	"avito-shop/internal/repo/pgdb"
+	"avito-shop/internal/service"
+	"avito-shop/tests/setup"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+const (
+	employeesCount  = 10
+	initialBalance  = 1000
+	transfersCount  = 500
+	maxTransferSize = 300
+)
+
+func TestTransferService_SendCoinsConcurrently(t *testing.T) {
+	pool, cleanup := setup.TestPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	trManager := manager.Must(trmpgx.NewDefaultFactory(pool))
+	employeeRepo := pgdb.NewPGEmployeeRepo(pg, trmpgx.DefaultCtxGetter)
+	ledgerService := service.NewLedgerService(employeeRepo, pgdb.NewPGLedgerRepo(pg, trmpgx.DefaultCtxGetter),
+		pgdb.NewPGCoinLotRepo(pg, trmpgx.DefaultCtxGetter))
+	transferService := service.NewTransferService(
+		trManager,
+		employeeRepo,
+		pgdb.NewPGTransferRepo(pg, trmpgx.DefaultCtxGetter),
+		pgdb.NewPGPendingTransferRepo(pg, trmpgx.DefaultCtxGetter),
+		ledgerService,
+		0,
+		model.TransferLimits{},
+	)
+
+	usernames := make([]string, employeesCount)
+	for i := range usernames {
+		usernames[i] = fmt.Sprintf("employee-%d", i)
+		employee := &model.Employee{
+			Id: uuid.New(), Username: usernames[i], PasswordHash: "hash", Role: model.RoleEmployee}
+		err := trManager.Do(ctx, func(ctx context.Context) error {
+			if err := employeeRepo.Save(ctx, employee); err != nil {
+				return err
+			}
+			return ledgerService.Grant(ctx, uuid.New(), employee, initialBalance)
+		})
+		require.NoError(t, err)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		failures  []error
+	)
+
+	for i := 0; i < transfersCount; i++ {
+		from := usernames[i%employeesCount]
+		to := usernames[(i+1)%employeesCount]
+		if i%2 == 1 {
+			// every other transfer mirrors the previous one to provoke lock-order deadlocks
+			from, to = to, from
+		}
+		amount := rand.Intn(maxTransferSize) + 1
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := transferService.SendCoins(ctx, from, to, amount, model.TransferMemo{})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case !errors.Is(err, service.ErrNotEnoughCoins):
+				failures = append(failures, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Empty(t, failures)
+
+	var total, negative int
+	err := pool.QueryRow(ctx,
+		"select coalesce(sum(balance), 0), count(*) filter (where balance < 0) from employees").
+		Scan(&total, &negative)
+	require.NoError(t, err)
+	require.Equal(t, employeesCount*initialBalance, total)
+	require.Zero(t, negative)
+
+	var transfers int
+	err = pool.QueryRow(ctx, "select count(*) from transfers").Scan(&transfers)
+	require.NoError(t, err)
+	require.Equal(t, succeeded, transfers)
+
+	for _, username := range usernames {
+		employee, err := employeeRepo.FindByUsername(ctx, username)
+		require.NoError(t, err)
+		require.NoError(t, ledgerService.Verify(ctx, employee))
+	}
+}
+
+func TestTransferService_SendCoinsBatchConcurrently(t *testing.T) {
+	const batchesCount = 100
+
+	pool, cleanup := setup.TestPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	pg := &pgdb.Postgres{
+		Pool:    pool,
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+	trManager := manager.Must(trmpgx.NewDefaultFactory(pool))
+	employeeRepo := pgdb.NewPGEmployeeRepo(pg, trmpgx.DefaultCtxGetter)
+	ledgerService := service.NewLedgerService(employeeRepo, pgdb.NewPGLedgerRepo(pg, trmpgx.DefaultCtxGetter),
+		pgdb.NewPGCoinLotRepo(pg, trmpgx.DefaultCtxGetter))
+	transferService := service.NewTransferService(
+		trManager,
+		employeeRepo,
+		pgdb.NewPGTransferRepo(pg, trmpgx.DefaultCtxGetter),
+		pgdb.NewPGPendingTransferRepo(pg, trmpgx.DefaultCtxGetter),
+		ledgerService,
+		0,
+		model.TransferLimits{},
+	)
+
+	usernames := make([]string, employeesCount)
+	for i := range usernames {
+		usernames[i] = fmt.Sprintf("employee-%d", i)
+		employee := &model.Employee{
+			Id: uuid.New(), Username: usernames[i], PasswordHash: "hash", Role: model.RoleEmployee,
+		}
+		err := trManager.Do(ctx, func(ctx context.Context) error {
+			if err := employeeRepo.Save(ctx, employee); err != nil {
+				return err
+			}
+			return ledgerService.Grant(ctx, uuid.New(), employee, initialBalance)
+		})
+		require.NoError(t, err)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		failures  []error
+	)
+
+	for i := 0; i < batchesCount; i++ {
+		from := usernames[i%employeesCount]
+		lines := []model.TransferLine{
+			{ToUser: usernames[(i+1)%employeesCount], Amount: rand.Intn(maxTransferSize) + 1},
+			{ToUser: usernames[(i+2)%employeesCount], Amount: rand.Intn(maxTransferSize) + 1},
+		}
+		if i%5 == 0 {
+			// an unknown recipient at the end of the batch must roll back the lines before it
+			lines = append(lines, model.TransferLine{ToUser: "zz-unknown", Amount: 1})
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			applied, err := transferService.SendCoinsBatch(ctx, from, lines, model.TransferMemo{})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded += len(applied)
+			case !errors.Is(err, service.ErrNotEnoughCoins) && !errors.Is(err, service.ErrReceiverNotFound):
+				failures = append(failures, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Empty(t, failures)
+
+	var total, negative int
+	err := pool.QueryRow(ctx,
+		"select coalesce(sum(balance), 0), count(*) filter (where balance < 0) from employees").
+		Scan(&total, &negative)
+	require.NoError(t, err)
+	require.Equal(t, employeesCount*initialBalance, total)
+	require.Zero(t, negative)
+
+	var transfers int
+	err = pool.QueryRow(ctx, "select count(*) from transfers").Scan(&transfers)
+	require.NoError(t, err)
+	require.Equal(t, succeeded, transfers)
+
+	for _, username := range usernames {
+		employee, err := employeeRepo.FindByUsername(ctx, username)
+		require.NoError(t, err)
+		require.NoError(t, ledgerService.Verify(ctx, employee))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/docker/go-connections/nat"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/stretchr/testify/require"
@@ -18,7 +19,7 @@ import (
 func TestPostgres(t *testing.T) (*pgxpool.Pool, func()) {
 	ctx := context.Background()
 
-	networkName := "test-network"
+	networkName := "test-network-" + uuid.NewString()
 	network, err := createNetwork(ctx, networkName)
 	require.NoError(t, err)
 